@@ -116,10 +116,11 @@ func initCommands(
 			// making individual HTTP requests.
 			return newRegistryHTTPClient(ctx)
 		},
-		ModulePackageFetcher: modulePkgFetcher,
-		ProviderSource:       providerSrc,
-		ProviderDevOverrides: providerDevOverrides,
-		UnmanagedProviders:   unmanagedProviders,
+		ModulePackageFetcher:   modulePkgFetcher,
+		ProviderSource:         providerSrc,
+		ProviderDevOverrides:   providerDevOverrides,
+		UnmanagedProviders:     unmanagedProviders,
+		OCIRepositoryPushStore: ociRepositoryPushStore(config.OCICredentialsPolicy),
 
 		AllowExperimentalFeatures: experimentsAreAllowed(),
 	}
@@ -150,6 +151,30 @@ func initCommands(
 			}, nil
 		},
 
+		"drift": func() (cli.Command, error) {
+			return &command.DriftCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"encryption": func() (cli.Command, error) {
+			return &command.EncryptionCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"encryption migrate": func() (cli.Command, error) {
+			return &command.EncryptionMigrateCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"encryption status": func() (cli.Command, error) {
+			return &command.EncryptionStatusCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"env": func() (cli.Command, error) {
 			return &command.WorkspaceCommand{
 				Meta:       meta,
@@ -239,6 +264,24 @@ func initCommands(
 			}, nil
 		},
 
+		"modules": func() (cli.Command, error) {
+			return &command.ModulesCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"modules package": func() (cli.Command, error) {
+			return &command.ModulesPackageCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"modules publish": func() (cli.Command, error) {
+			return &command.ModulesPublishCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"output": func() (cli.Command, error) {
 			return &command.OutputCommand{
 				Meta: meta,
@@ -438,6 +481,24 @@ func initCommands(
 			}, nil
 		},
 
+		"state history": func() (cli.Command, error) {
+			return &command.StateHistoryCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"state rewrap": func() (cli.Command, error) {
+			return &command.StateRewrapCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"state rollback": func() (cli.Command, error) {
+			return &command.StateRollbackCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"state show": func() (cli.Command, error) {
 			return &command.StateShowCommand{
 				Meta: meta,
@@ -171,7 +171,7 @@ func realMain() int {
 	}
 	services := newServiceDiscovery(ctx, credsSrc)
 
-	modulePkgFetcher := remoteModulePackageFetcher(ctx, config.OCICredentialsPolicy)
+	modulePkgFetcher := remoteModulePackageFetcher(ctx, config.OCICredentialsPolicy, config.ModuleCacheDir)
 
 	providerSrc, diags := providerSource(config.ProviderInstallation, services, config.OCICredentialsPolicy)
 	if len(diags) > 0 {
@@ -12,16 +12,18 @@ import (
 	"github.com/opentofu/opentofu/internal/getmodules"
 )
 
-func remoteModulePackageFetcher(ctx context.Context, getOCICredsPolicy ociCredsPolicyBuilder) *getmodules.PackageFetcher {
+func remoteModulePackageFetcher(ctx context.Context, getOCICredsPolicy ociCredsPolicyBuilder, moduleCacheDir string) *getmodules.PackageFetcher {
 	// TODO: Pass in a real getmodules.PackageFetcherEnvironment here,
 	// which knows how to make use of the OCI authentication policy.
 	return getmodules.NewPackageFetcher(ctx, &modulePackageFetcherEnvironment{
 		getOCICredsPolicy: getOCICredsPolicy,
+		moduleCacheDir:    moduleCacheDir,
 	})
 }
 
 type modulePackageFetcherEnvironment struct {
 	getOCICredsPolicy ociCredsPolicyBuilder
+	moduleCacheDir    string
 }
 
 // OCIRepositoryStore implements getmodules.PackageFetcherEnvironment.
@@ -38,3 +40,8 @@ func (m *modulePackageFetcherEnvironment) OCIRepositoryStore(ctx context.Context
 	}
 	return getOCIRepositoryStore(ctx, registryDomainName, repositoryPath, credsPolicy)
 }
+
+// ModuleCacheDir implements getmodules.PackageFetcherEnvironment.
+func (m *modulePackageFetcherEnvironment) ModuleCacheDir() string {
+	return m.moduleCacheDir
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/internal/ocipush"
 	"github.com/opentofu/opentofu/internal/tracing"
 )
 
@@ -153,15 +154,30 @@ func getOCIRepositoryORASClient(ctx context.Context, registryDomain, repositoryN
 	}, nil
 }
 
-// ociRepositoryStore represents the combined needs of both
-// [getproviders.OCIRepositoryStore] and [getmodules.OCIRepositoryStore],
-// both of which are intentionally defined to be subsets of the API
-// used by ORAS-Go so that we can use the implementations from that
-// library without directly exposing any ORAS-Go symbols in the
+// ociRepositoryPushStore returns a function that instantiates a
+// [ocipush.RepositoryStore] implementation for a given repository,
+// using the OCI credentials policy returned by getOCICredsPolicy.
+func ociRepositoryPushStore(getOCICredsPolicy ociCredsPolicyBuilder) func(ctx context.Context, registryDomain, repositoryName string) (ocipush.RepositoryStore, error) {
+	return func(ctx context.Context, registryDomain, repositoryName string) (ocipush.RepositoryStore, error) {
+		credsPolicy, err := getOCICredsPolicy(ctx)
+		if err != nil {
+			// This deals with only a small number of errors that we can't catch during CLI config validation
+			return nil, fmt.Errorf("invalid credentials configuration for OCI registries: %w", err)
+		}
+		return getOCIRepositoryStore(ctx, registryDomain, repositoryName, credsPolicy)
+	}
+}
+
+// ociRepositoryStore represents the combined needs of
+// [getproviders.OCIRepositoryStore], [ocipush.RepositoryStore],
+// and [getmodules.OCIRepositoryStore], all of which are intentionally
+// defined to be subsets of the API used by ORAS-Go so that we can use
+// the implementations from that library without directly exposing any ORAS-Go symbols in the
 // public API of any of our packages, since we want to reserve the
 // ability to switch to other implementations in future if needed.
 type ociRepositoryStore interface {
 	getproviders.OCIRepositoryStore
+	ocipush.RepositoryStore
 	getmodules.OCIRepositoryStore
 }
 
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 
 	"github.com/apparentlymart/go-userdirs/userdirs"
 	"github.com/hashicorp/terraform-svchost/disco"
@@ -47,7 +48,12 @@ func explicitProviderSource(config *cliconfig.ProviderInstallation, services *di
 
 	log.Printf("[DEBUG] Explicit provider installation configuration is set")
 	for _, methodConfig := range config.Methods {
-		source, moreDiags := providerSourceForCLIConfigLocation(methodConfig.Location, services, getOCICredsPolicy)
+		sigstorePolicy, moreDiags := sigstorePolicyForCLIConfig(methodConfig.Sigstore)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		source, moreDiags := providerSourceForCLIConfigLocation(methodConfig.Location, services, getOCICredsPolicy, sigstorePolicy)
 		diags = diags.Append(moreDiags)
 		if moreDiags.HasErrors() {
 			continue
@@ -193,7 +199,7 @@ func implicitProviderSource(services *disco.Disco) getproviders.Source {
 	return getproviders.MultiSource(searchRules)
 }
 
-func providerSourceForCLIConfigLocation(loc cliconfig.ProviderInstallationLocation, services *disco.Disco, makeOCICredsPolicy ociCredsPolicyBuilder) (getproviders.Source, tfdiags.Diagnostics) {
+func providerSourceForCLIConfigLocation(loc cliconfig.ProviderInstallationLocation, services *disco.Disco, makeOCICredsPolicy ociCredsPolicyBuilder, sigstorePolicy *getproviders.SigstorePolicy) (getproviders.Source, tfdiags.Diagnostics) {
 	if loc == cliconfig.ProviderInstallationDirect {
 		return getproviders.NewMemoizeSource(
 			getproviders.NewRegistrySource(services, newRegistryHTTPClient(context.TODO())),
@@ -230,7 +236,7 @@ func providerSourceForCLIConfigLocation(loc cliconfig.ProviderInstallationLocati
 		// this client is not suitable for the HTTP mirror source, so we
 		// don't use this client directly.
 		httpTimeout := newRegistryHTTPClient(context.TODO()).HTTPClient.Timeout
-		return getproviders.NewHTTPMirrorSource(url, services.CredentialsSource(), httpTimeout), nil
+		return getproviders.NewHTTPMirrorSource(url, services.CredentialsSource(), httpTimeout, sigstorePolicy), nil
 
 	case cliconfig.ProviderInstallationOCIMirror:
 		mappingFunc := loc.RepositoryMapping
@@ -249,6 +255,7 @@ func providerSourceForCLIConfigLocation(loc cliconfig.ProviderInstallationLocati
 				}
 				return getOCIRepositoryStore(ctx, registryDomain, repositoryName, credsPolicy)
 			},
+			sigstorePolicy,
 		), nil
 
 	default:
@@ -259,6 +266,80 @@ func providerSourceForCLIConfigLocation(loc cliconfig.ProviderInstallationLocati
 	}
 }
 
+// sigstorePolicyForCLIConfig loads the public keys and trusted root
+// referenced by a "sigstore" block in the CLI configuration, returning the
+// policy that provider packages from the corresponding installation method
+// must conform to.
+//
+// Returns nil without any diagnostics if cfg is nil, in which case there
+// is no Sigstore policy for the installation method.
+func sigstorePolicyForCLIConfig(cfg *cliconfig.ProviderInstallationSigstore) (*getproviders.SigstorePolicy, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if cfg == nil {
+		return nil, diags
+	}
+
+	policy := &getproviders.SigstorePolicy{}
+	for _, filename := range cfg.PublicKeyFiles {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid Sigstore configuration",
+				fmt.Sprintf("Cannot read Sigstore public key file %s: %s.", filename, err),
+			))
+			continue
+		}
+		key, err := getproviders.ParseSigstorePublicKey(src)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid Sigstore configuration",
+				fmt.Sprintf("Invalid Sigstore public key file %s: %s.", filename, err),
+			))
+			continue
+		}
+		policy.PublicKeys = append(policy.PublicKeys, key)
+	}
+	if cfg.TrustedRootFile != "" {
+		src, err := os.ReadFile(cfg.TrustedRootFile)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid Sigstore configuration",
+				fmt.Sprintf("Cannot read Sigstore trusted root file %s: %s.", cfg.TrustedRootFile, err),
+			))
+		} else {
+			root, err := getproviders.ParseSigstoreTrustedRoot(src)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid Sigstore configuration",
+					fmt.Sprintf("Invalid Sigstore trusted root file %s: %s.", cfg.TrustedRootFile, err),
+				))
+			}
+			policy.TrustedRoot = root
+		}
+	}
+	for _, identity := range cfg.Identities {
+		// The cliconfig package already checked that the regular expression
+		// is valid, so MustCompile can't panic here.
+		var subjectRegexp *regexp.Regexp
+		if identity.SubjectRegexp != "" {
+			subjectRegexp = regexp.MustCompile(identity.SubjectRegexp)
+		}
+		policy.Identities = append(policy.Identities, getproviders.SigstoreIdentity{
+			Issuer:        identity.Issuer,
+			Subject:       identity.Subject,
+			SubjectRegexp: subjectRegexp,
+		})
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return policy, diags
+}
+
 func providerDevOverrides(configs []*cliconfig.ProviderInstallation) map[addrs.Provider]getproviders.PackageLocalDir {
 	if len(configs) == 0 {
 		return nil
@@ -79,6 +79,16 @@ func ImpliedMoveStatementEndpoint(addr AbsResourceInstance, rng tfdiags.SourceRa
 	}
 }
 
+// ImpliedModuleMoveStatementEndpoint is like ImpliedMoveStatementEndpoint
+// but for an endpoint referring to a single module instance.
+func ImpliedModuleMoveStatementEndpoint(addr ModuleInstance, rng tfdiags.SourceRange) *MoveEndpointInModule {
+	return &MoveEndpointInModule{
+		SourceRange: rng,
+		module:      RootModule,
+		relSubject:  addr,
+	}
+}
+
 func (e *MoveEndpointInModule) ObjectKind() MoveEndpointKind {
 	return absMoveableEndpointKind(e.relSubject)
 }
@@ -29,6 +29,8 @@ func (r Resource) String() string {
 		return fmt.Sprintf("%s.%s", r.Type, r.Name)
 	case DataResourceMode:
 		return fmt.Sprintf("data.%s.%s", r.Type, r.Name)
+	case EphemeralResourceMode:
+		return fmt.Sprintf("ephemeral.%s.%s", r.Type, r.Name)
 	default:
 		// Should never happen, but we'll return a string here rather than
 		// crashing just in case it does.
@@ -43,7 +45,9 @@ func (r Resource) Equal(o Resource) bool {
 func (r Resource) Less(o Resource) bool {
 	switch {
 	case r.Mode != o.Mode:
-		return r.Mode == DataResourceMode
+		// The mode runes happen to sort data resources first, then
+		// ephemeral resources, and then managed resources.
+		return r.Mode < o.Mode
 
 	case r.Type != o.Type:
 		return r.Type < o.Type
@@ -511,4 +515,10 @@ const (
 	// DataResourceMode indicates a data resource, as defined by
 	// "data" blocks in configuration.
 	DataResourceMode ResourceMode = 'D'
+
+	// EphemeralResourceMode indicates an ephemeral resource, as defined by
+	// "ephemeral" blocks in configuration. Ephemeral resources are opened
+	// and closed during each graph walk and are never persisted in the state
+	// or in a saved plan.
+	EphemeralResourceMode ResourceMode = 'E'
 )
@@ -27,6 +27,7 @@ import (
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -217,6 +218,13 @@ type LocalRun struct {
 	//
 	// This is nil when we're not applying a saved plan.
 	Plan *plans.Plan
+
+	// Policies are the policies recorded in a saved plan file, which were
+	// evaluated against the plan when it was created and must be evaluated
+	// again before it is applied.
+	//
+	// This is nil when we're not applying a saved plan.
+	Policies []*policy.Policy
 }
 
 // An operation represents an operation for OpenTofu to execute.
@@ -320,6 +328,16 @@ type Operation struct {
 	// for unmatched import targets and where any generated config should be
 	// written to.
 	GenerateConfigOut string
+
+	// Policies are evaluated against the plan once it has been created, and
+	// before it is applied. A failing mandatory policy prevents the plan from
+	// being applied.
+	Policies []*policy.Policy
+
+	// Interactive asks the user to accept or skip each planned resource
+	// instance change before applying, and then applies only the accepted
+	// changes. This replaces the usual approval of the whole plan.
+	Interactive bool
 }
 
 // HasConfig returns true if and only if the operation has a ConfigDir value
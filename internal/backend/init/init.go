@@ -21,6 +21,7 @@ import (
 	backendAzure "github.com/opentofu/opentofu/internal/backend/remote-state/azure"
 	backendConsul "github.com/opentofu/opentofu/internal/backend/remote-state/consul"
 	backendCos "github.com/opentofu/opentofu/internal/backend/remote-state/cos"
+	backendEtcdv3 "github.com/opentofu/opentofu/internal/backend/remote-state/etcdv3"
 	backendGCS "github.com/opentofu/opentofu/internal/backend/remote-state/gcs"
 	backendHTTP "github.com/opentofu/opentofu/internal/backend/remote-state/http"
 	backendInmem "github.com/opentofu/opentofu/internal/backend/remote-state/inmem"
@@ -28,6 +29,7 @@ import (
 	backendOSS "github.com/opentofu/opentofu/internal/backend/remote-state/oss"
 	backendPg "github.com/opentofu/opentofu/internal/backend/remote-state/pg"
 	backendS3 "github.com/opentofu/opentofu/internal/backend/remote-state/s3"
+	backendSQLite "github.com/opentofu/opentofu/internal/backend/remote-state/sqlite"
 	backendCloud "github.com/opentofu/opentofu/internal/cloud"
 )
 
@@ -66,6 +68,7 @@ func Init(services *disco.Disco) {
 		"azurerm":    func(enc encryption.StateEncryption) backend.Backend { return backendAzure.New(enc) },
 		"consul":     func(enc encryption.StateEncryption) backend.Backend { return backendConsul.New(enc) },
 		"cos":        func(enc encryption.StateEncryption) backend.Backend { return backendCos.New(enc) },
+		"etcdv3":     func(enc encryption.StateEncryption) backend.Backend { return backendEtcdv3.New(enc) },
 		"gcs":        func(enc encryption.StateEncryption) backend.Backend { return backendGCS.New(enc) },
 		"http":       func(enc encryption.StateEncryption) backend.Backend { return backendHTTP.New(enc) },
 		"inmem":      func(enc encryption.StateEncryption) backend.Backend { return backendInmem.New(enc) },
@@ -73,6 +76,7 @@ func Init(services *disco.Disco) {
 		"oss":        func(enc encryption.StateEncryption) backend.Backend { return backendOSS.New(enc) },
 		"pg":         func(enc encryption.StateEncryption) backend.Backend { return backendPg.New(enc) },
 		"s3":         func(enc encryption.StateEncryption) backend.Backend { return backendS3.New(enc) },
+		"sqlite":     func(enc encryption.StateEncryption) backend.Backend { return backendSQLite.New(enc) },
 
 		// Terraform Cloud 'backend'
 		// This is an implementation detail only, used for the cloud package
@@ -83,7 +87,6 @@ func Init(services *disco.Disco) {
 		"artifactory": `The "artifactory" backend is not supported in OpenTofu v1.3 or later.`,
 		"azure":       `The "azure" backend name has been removed, please use "azurerm".`,
 		"etcd":        `The "etcd" backend is not supported in OpenTofu v1.3 or later.`,
-		"etcdv3":      `The "etcdv3" backend is not supported in OpenTofu v1.3 or later.`,
 		"manta":       `The "manta" backend is not supported in OpenTofu v1.3 or later.`,
 		"swift":       `The "swift" backend is not supported in OpenTofu v1.3 or later.`,
 	}
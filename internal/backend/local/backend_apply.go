@@ -19,6 +19,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -142,9 +143,17 @@ func (b *Local) opApply(
 
 		trivialPlan := !plan.CanApply()
 		hasUI := op.UIOut != nil && op.UIIn != nil
-		mustConfirm := hasUI && !op.AutoApprove && !trivialPlan
+		// An interactive apply asks about each change below instead.
+		mustConfirm := hasUI && !op.AutoApprove && !trivialPlan && !op.Interactive
 		op.View.Plan(plan, schemas)
 
+		moreDiags = b.evaluatePolicies(op, op.Policies, lr.Config, plan, schemas)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+
 		if testHookStopPlanApply != nil {
 			testHookStopPlanApply()
 		}
@@ -212,7 +221,7 @@ func (b *Local) opApply(
 				runningOp.Result = backend.OperationFailure
 				return
 			}
-		} else {
+		} else if !op.Interactive {
 			// If we didn't ask for confirmation from the user, and they have
 			// included any failing checks in their configuration, then they
 			// will see a very confusing output after the apply operation
@@ -256,6 +265,39 @@ func (b *Local) opApply(
 				op.View.PlannedChange(change)
 			}
 		}
+
+		// The policies recorded in the plan file are always evaluated again
+		// before applying it, along with any additional policies given to
+		// the apply command. The policy files may have changed since the plan
+		// was created, in which case both versions must pass.
+		policies := policy.Merge(lr.Policies, op.Policies)
+		moreDiags = b.evaluatePolicies(op, policies, lr.Config, plan, schemas)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+	}
+
+	if op.Interactive && plan.CanApply() {
+		// We'll show any accumulated warnings before we ask about each
+		// change, so the user can consider them when deciding.
+		if len(diags) > 0 {
+			op.View.Diagnostics(diags)
+			diags = nil
+		}
+
+		accepted, moreDiags := b.reviewChanges(ctx, stopCtx, op, lr, plan)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+		if !accepted {
+			op.View.Cancelled(op.PlanMode)
+			runningOp.Result = backend.OperationFailure
+			return
+		}
 	}
 
 	// Set up our hook for continuous state updates
@@ -0,0 +1,153 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// reviewChanges asks the user whether to accept or skip each of the planned
+// resource instance changes, and adds the skipped changes to the plan's
+// exclude addresses so that only the accepted changes are applied.
+//
+// Skipping a change also skips all of the changes that depend on it. We find
+// those using the same apply graph, and so the same targeting rules, as the
+// apply operation itself.
+//
+// The result is false if no changes remain to be applied after the review.
+func (b *Local) reviewChanges(ctx, stopCtx context.Context, op *backend.Operation, lr *backend.LocalRun, plan *plans.Plan) (bool, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if op.UIIn == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Interactive apply requires input",
+			"OpenTofu cannot ask about each planned change because input is not available.",
+		))
+		return false, diags
+	}
+	if len(plan.TargetAddrs) != 0 {
+		// The targeting transformer ignores the exclude addresses when there
+		// are target addresses, so we can't skip any changes in this case.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Interactive apply of a targeted plan",
+			"The -interactive option cannot be used with a plan that was created with the -target option. Use the -exclude option instead to leave out changes when creating the plan.",
+		))
+		return false, diags
+	}
+
+	pending, moreDiags := lr.Core.PlannedChangesToApply(ctx, plan, lr.Config)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return false, diags
+	}
+
+	// A resource instance can have more than one change, such as when it
+	// also has a deposed object to destroy, but we exclude whole resource
+	// instances and so only ask about the first change of each.
+	var changes []*plans.ResourceInstanceChangeSrc
+	seen := addrs.MakeSet[addrs.AbsResourceInstance]()
+	for _, change := range plan.Changes.Resources {
+		if change.Action == plans.NoOp && change.Importing == nil {
+			continue
+		}
+		if seen.Has(change.Addr) {
+			continue
+		}
+		seen.Add(change.Addr)
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Addr.Less(changes[j].Addr)
+	})
+	if len(changes) == 0 {
+		// There are only changes to outputs, which we don't ask about.
+		return true, diags
+	}
+	log.Printf("[INFO] backend/local: reviewing %d planned changes", len(changes))
+
+	for _, change := range changes {
+		if !pending.Has(change.Addr) {
+			// This change was already skipped, because it depends on a change
+			// that the user skipped earlier.
+			continue
+		}
+
+		v, err := op.UIIn.Input(stopCtx, &tofu.InputOpts{
+			Id:          "approve-change",
+			Query:       fmt.Sprintf("\nDo you want to %s %s?", interactiveChangeVerb(change), change.Addr),
+			Description: "Only 'yes' will be accepted to apply this change. Any other answer skips it, along with the changes that depend on it.",
+		})
+		if err != nil {
+			diags = diags.Append(fmt.Errorf("error asking for approval: %w", err))
+			return false, diags
+		}
+		if v == "yes" {
+			continue
+		}
+
+		plan.ExcludeAddrs = append(plan.ExcludeAddrs, change.Addr)
+		remaining, moreDiags := lr.Core.PlannedChangesToApply(ctx, plan, lr.Config)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			return false, diags
+		}
+
+		// The changes are in address order rather than dependency order, so
+		// a skipped change can also take out changes that the user already
+		// accepted. We report those along with the ones still to review.
+		var dependents []addrs.AbsResourceInstance
+		for _, other := range changes {
+			if other.Addr.Equal(change.Addr) {
+				continue
+			}
+			if pending.Has(other.Addr) && !remaining.Has(other.Addr) {
+				dependents = append(dependents, other.Addr)
+			}
+		}
+		if len(dependents) != 0 {
+			op.View.DependentChangesSkipped(change.Addr, dependents)
+		}
+		pending = remaining
+	}
+
+	for _, change := range changes {
+		if pending.Has(change.Addr) {
+			return true, diags
+		}
+	}
+	return false, diags
+}
+
+// interactiveChangeVerb describes the given planned change as a verb for the
+// question asked about it during an interactive apply.
+func interactiveChangeVerb(change *plans.ResourceInstanceChangeSrc) string {
+	switch change.Action {
+	case plans.NoOp:
+		return "import"
+	case plans.Create:
+		return "create"
+	case plans.Update:
+		return "update"
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		return "replace"
+	case plans.Delete:
+		return "destroy"
+	case plans.Forget:
+		return "forget"
+	default:
+		return strings.ToLower(change.Action.String())
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -31,6 +32,7 @@ import (
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/terminal"
 	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
 )
 
 func TestLocal_applyBasic(t *testing.T) {
@@ -353,6 +355,90 @@ func (s failingState) WriteState(state *states.State) error {
 	return errors.New("fake failure")
 }
 
+func TestLocal_applyInteractive(t *testing.T) {
+	b := TestLocal(t)
+
+	p := TestLocalProvider(t, b, "test", applyFixtureSchema())
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		return providers.ApplyResourceChangeResponse{
+			NewState: cty.ObjectVal(map[string]cty.Value{
+				"id":  cty.StringVal("yes"),
+				"ami": req.PlannedState.GetAttr("ami"),
+			}),
+		}
+	}
+
+	op, done := testOperationApply(t, "./testdata/apply-interactive")
+	op.Interactive = true
+
+	// We skip test_instance.a, which must also skip test_instance.b without
+	// asking about it, and accept test_instance.c.
+	var queries []string
+	op.UIIn = &tofu.MockUIInput{
+		InputFn: func(opts *tofu.InputOpts) (string, error) {
+			queries = append(queries, strings.TrimSpace(opts.Query))
+			if strings.Contains(opts.Query, "test_instance.a") {
+				return "no", nil
+			}
+			return "yes", nil
+		},
+	}
+
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	output := done(t)
+	if run.Result != backend.OperationSuccess {
+		t.Fatalf("operation failed\n%s", output.All())
+	}
+
+	wantQueries := []string{
+		"Do you want to create test_instance.a?",
+		"Do you want to create test_instance.c?",
+	}
+	if diff := cmp.Diff(wantQueries, queries); diff != "" {
+		t.Fatalf("wrong queries\n%s", diff)
+	}
+
+	if got, want := output.Stdout(), "The following changes depend on test_instance.a and will also be skipped:"; !strings.Contains(got, want) {
+		t.Fatalf("missing dependents message %q in output:\n%s", want, got)
+	}
+
+	checkState(t, b.StateOutPath, `
+test_instance.c:
+  ID = yes
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+  ami = c
+`)
+}
+
+func TestLocal_applyInteractiveSkipAll(t *testing.T) {
+	b := TestLocal(t)
+
+	p := TestLocalProvider(t, b, "test", applyFixtureSchema())
+
+	op, done := testOperationApply(t, "./testdata/apply-interactive")
+	op.Interactive = true
+	op.UIIn = &tofu.MockUIInput{InputReturnString: "no"}
+
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	if run.Result == backend.OperationSuccess {
+		t.Fatal("expected apply operation to be cancelled")
+	}
+	if p.ApplyResourceChangeCalled {
+		t.Fatal("apply should not be called")
+	}
+	if got, want := done(t).Stdout(), "Apply cancelled."; !strings.Contains(got, want) {
+		t.Fatalf("expected %q in output:\n%s", want, got)
+	}
+}
+
 func testOperationApply(t *testing.T, configDir string) (*backend.Operation, func(*testing.T) *terminal.TestOutput) {
 	t.Helper()
 
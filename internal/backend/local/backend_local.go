@@ -18,6 +18,7 @@ import (
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -72,6 +73,13 @@ func (b *Local) localRun(ctx context.Context, op *backend.Operation) (*backend.L
 		diags = diags.Append(fmt.Errorf("error loading state: %w", err))
 		return nil, nil, nil, diags
 	}
+	if r, ok := s.(statemgr.EncryptionStatusReporter); ok && r.StateEncryptionStatus() == encryption.StatusRotation {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"State encryption key rotation pending",
+			"The latest state snapshot was encrypted with a key that exceeds the maximum age of its key provider's rotation policy. OpenTofu will encrypt the state with a new key the next time it saves the state.",
+		))
+	}
 
 	ret := &backend.LocalRun{}
 
@@ -342,6 +350,16 @@ func (b *Local) localRunForPlanFile(ctx context.Context, op *backend.Operation,
 		))
 	}
 
+	// The policies that were evaluated against the plan when it was created
+	// must also pass when it is applied, regardless of which policies are
+	// given to the apply command.
+	policies, moreDiags := pf.ReadPolicies()
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, snap, diags
+	}
+	run.Policies = policies
+
 	// A plan file also contains a snapshot of the prior state the changes
 	// are intended to apply to.
 	priorStateFile, err := pf.ReadStateFile()
@@ -16,6 +16,7 @@ import (
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -145,8 +146,28 @@ func (b *Local) opPlan(
 	// Record whether this plan includes any side-effects that could be applied.
 	runningOp.PlanEmpty = !plan.CanApply()
 
+	schemas, moreDiags := lr.Core.Schemas(ctx, lr.Config, lr.InputState)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		op.ReportResult(runningOp, diags)
+		return
+	}
+
+	// Policies are only meaningful for a complete plan. We evaluate them
+	// before saving the plan so that a plan which fails a mandatory policy
+	// is never written to disk, where it could subsequently be applied.
+	var policyResults []*policy.Result
+	policyFailed := false
+	if !plan.Errored {
+		policyResults, moreDiags = b.policyResults(op.Policies, lr.Config, plan, schemas)
+		diags = diags.Append(moreDiags)
+		policyFailed = moreDiags.HasErrors()
+	}
+
 	// Save the plan to disk
-	if path := op.PlanOutPath; path != "" {
+	if path := op.PlanOutPath; path != "" && policyFailed {
+		log.Printf("[INFO] backend/local: not writing plan output to %s because it failed a mandatory policy", path)
+	} else if path != "" {
 		if op.PlanOutBackend == nil {
 			// This is always a bug in the operation caller; it's not valid
 			// to set PlanOutPath without also setting PlanOutBackend.
@@ -182,6 +203,7 @@ func (b *Local) opPlan(
 			StateFile:            plannedStateFile,
 			Plan:                 plan,
 			DependencyLocks:      op.DependencyLocks,
+			Policies:             policy.Files(op.Policies),
 		}, op.Encryption.Plan())
 		if err != nil {
 			diags = diags.Append(tfdiags.Sourceless(
@@ -194,15 +216,6 @@ func (b *Local) opPlan(
 		}
 	}
 
-	// Render the plan, if we produced one.
-	// (This might potentially be a partial plan with Errored set to true)
-	schemas, moreDiags := lr.Core.Schemas(ctx, lr.Config, lr.InputState)
-	diags = diags.Append(moreDiags)
-	if moreDiags.HasErrors() {
-		op.ReportResult(runningOp, diags)
-		return
-	}
-
 	// Write out any generated config, before we render the plan.
 	wroteConfig, moreDiags := maybeWriteGeneratedConfig(plan, op.GenerateConfigOut)
 	diags = diags.Append(moreDiags)
@@ -211,7 +224,12 @@ func (b *Local) opPlan(
 		return
 	}
 
+	// Render the plan, if we produced one.
+	// (This might potentially be a partial plan with Errored set to true)
 	op.View.Plan(plan, schemas)
+	if len(policyResults) != 0 {
+		op.View.PolicyResults(policyResults)
+	}
 
 	// If we've accumulated any diagnostics along the way then we'll show them
 	// here just before we show the summary and next steps. This can potentially
@@ -220,7 +238,9 @@ func (b *Local) opPlan(
 	// creating it.
 	op.ReportResult(runningOp, diags)
 
-	if !runningOp.PlanEmpty {
+	// A plan that failed a mandatory policy can't be applied, so there is no
+	// next step to suggest.
+	if !runningOp.PlanEmpty && !policyFailed {
 		if wroteConfig {
 			op.View.PlanNextStep(op.PlanOutPath, op.GenerateConfigOut)
 		} else {
@@ -0,0 +1,70 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/policy"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// evaluatePolicies evaluates the given policies against the given plan and
+// renders their results.
+//
+// The returned diagnostics contain an error for each failed mandatory policy,
+// in which case the plan must not be applied, and a warning for each failed
+// advisory policy.
+func (b *Local) evaluatePolicies(op *backend.Operation, policies []*policy.Policy, config *configs.Config, plan *plans.Plan, schemas *tofu.Schemas) tfdiags.Diagnostics {
+	results, diags := b.policyResults(policies, config, plan, schemas)
+	if len(results) != 0 {
+		op.View.PolicyResults(results)
+	}
+	return diags
+}
+
+// policyResults is like evaluatePolicies but returns the results instead of
+// rendering them, for callers that need to act on the outcome before
+// showing it.
+func (b *Local) policyResults(policies []*policy.Policy, config *configs.Config, plan *plans.Plan, schemas *tofu.Schemas) ([]*policy.Result, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if len(policies) == 0 {
+		return nil, diags
+	}
+
+	log.Printf("[INFO] backend/local: evaluating %d policies", len(policies))
+
+	// Policies are written against the same representation of the plan as
+	// "tofu show -json" produces, so that they are independent of our
+	// internal data structures.
+	planJSON, err := jsonplan.Marshal(config, plan, statefile.New(plan.PriorState, "", 0), schemas)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to evaluate policies",
+			fmt.Sprintf("The plan could not be prepared for policy evaluation: %s.", err),
+		))
+		return nil, diags
+	}
+	input, err := policy.PlanValue(planJSON)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to evaluate policies",
+			fmt.Sprintf("The plan could not be prepared for policy evaluation: %s.", err),
+		))
+		return nil, diags
+	}
+
+	results := policy.Evaluate(policies, input)
+	return results, diags.Append(policy.Diagnostics(results))
+}
@@ -82,6 +82,7 @@ func (b *Backend) StateMgr(ctx context.Context, name string) (statemgr.Full, err
 
 	client := &RemoteClient{
 		giovanniBlobClient: *blobClient,
+		armClient:          b.armClient,
 		containerName:      b.containerName,
 		keyName:            b.path(name),
 		accountName:        b.accountName,
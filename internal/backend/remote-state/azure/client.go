@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -19,16 +20,22 @@ import (
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/tombuildsstuff/giovanni/storage/2018-11-09/blob/blobs"
+	"github.com/tombuildsstuff/giovanni/storage/2018-11-09/blob/containers"
 )
 
 const (
 	leaseHeader = "x-ms-lease-id"
 	// Must be lower case
 	lockInfoMetaKey = "terraformlockid"
+
+	// currentVersionID is the version ID we use for the base blob, which
+	// unlike its snapshots has no snapshot timestamp to identify it.
+	currentVersionID = "current"
 )
 
 type RemoteClient struct {
 	giovanniBlobClient blobs.Client
+	armClient          *ArmClient
 	accountName        string
 	containerName      string
 	keyName            string
@@ -103,6 +110,113 @@ func (c *RemoteClient) Delete() error {
 	return nil
 }
 
+// Versions returns the base blob along with the snapshots that were taken of
+// it before each write when the "snapshot" argument is enabled.
+func (c *RemoteClient) Versions() ([]*remote.Version, error) {
+	ctx, ctxCancel := c.getContextWithTimeout()
+	defer ctxCancel()
+
+	if c.armClient == nil {
+		return nil, fmt.Errorf("listing state versions is not supported by this client")
+	}
+	client, err := c.armClient.getContainersClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	initialMarker := ""
+	params := containers.ListBlobsInput{
+		Prefix:  &c.keyName,
+		Marker:  &initialMarker,
+		Include: &[]containers.Dataset{containers.Snapshots},
+	}
+
+	var ret []*remote.Version
+	for {
+		resp, err := client.ListBlobs(ctx, c.accountName, c.containerName, params)
+		if err != nil {
+			return nil, fmt.Errorf("error listing snapshots of Blob %q (Container %q / Account %q): %w", c.keyName, c.containerName, c.accountName, err)
+		}
+
+		params.Marker = resp.NextMarker
+		for _, obj := range resp.Blobs.Blobs {
+			// The prefix also matches the states of other workspaces.
+			if obj.Name != c.keyName {
+				continue
+			}
+
+			v := &remote.Version{
+				ID: currentVersionID,
+			}
+			if obj.Snapshot != nil {
+				v.ID = *obj.Snapshot
+			} else {
+				v.IsLatest = true
+			}
+			if obj.Properties != nil && obj.Properties.LastModified != nil {
+				// A snapshot retains the last modified time of the base blob
+				// at the time it was taken, which is when that version of
+				// the state was written.
+				if t, err := time.Parse(time.RFC1123, *obj.Properties.LastModified); err == nil {
+					v.LastModified = t
+				}
+			}
+			ret = append(ret, v)
+		}
+
+		if params.Marker == nil || *params.Marker == "" {
+			break
+		}
+	}
+
+	// Last modified times only have a resolution of one second, so we order
+	// the snapshots by their snapshot timestamps instead, which sort
+	// lexically, with the base blob first.
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].IsLatest != ret[j].IsLatest {
+			return ret[i].IsLatest
+		}
+		return ret[i].ID > ret[j].ID
+	})
+	return ret, nil
+}
+
+func (c *RemoteClient) GetVersion(id string) (*remote.Payload, error) {
+	if id == currentVersionID {
+		return c.Get()
+	}
+
+	ctx, ctxCancel := c.getContextWithTimeout()
+	defer ctxCancel()
+
+	// The blobs client has no option to read a snapshot, so we add the
+	// snapshot query parameter to the request it prepares.
+	client := c.giovanniBlobClient
+	req, err := client.GetPreparer(ctx, c.accountName, c.containerName, c.keyName, blobs.GetInput{})
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	query.Set("snapshot", id)
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := client.GetSender(req)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot %q of Blob %q (Container %q / Account %q): %w", id, c.keyName, c.containerName, c.accountName, err)
+	}
+	blob, err := client.GetResponder(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot %q of Blob %q (Container %q / Account %q): %w", id, c.keyName, c.containerName, c.accountName, err)
+	}
+
+	if len(blob.Contents) == 0 {
+		return nil, nil
+	}
+	return &remote.Payload{
+		Data: blob.Contents,
+	}, nil
+}
+
 func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
 	stateName := fmt.Sprintf("%s/%s", c.containerName, c.keyName)
 	info.Path = stateName
@@ -14,6 +14,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -76,6 +77,72 @@ func (c *remoteClient) Delete() error {
 	return c.deleteObject(c.stateFile)
 }
 
+// Versions returns the versions of the remote state file retained by a
+// bucket with versioning enabled
+func (c *remoteClient) Versions() ([]*remote.Version, error) {
+	log.Printf("[DEBUG] list versions of remote state file %s", c.stateFile)
+
+	var ret []*remote.Version
+	opt := &cos.BucketGetObjectVersionsOptions{Prefix: c.stateFile}
+	for {
+		res, rsp, err := c.cosClient.Bucket.GetObjectVersions(c.cosContext, opt)
+		if rsp != nil {
+			rsp.Body.Close()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions of %v: %w", c.stateFile, err)
+		}
+
+		for _, v := range res.Version {
+			// the prefix also matches the state files of other workspaces
+			if v.Key != c.stateFile {
+				continue
+			}
+			lastModified, err := time.Parse(time.RFC3339, v.LastModified)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse modification time of %v version %s: %w", c.stateFile, v.VersionId, err)
+			}
+			ret = append(ret, &remote.Version{
+				ID:           v.VersionId,
+				LastModified: lastModified,
+				IsLatest:     v.IsLatest,
+			})
+		}
+
+		if !res.IsTruncated {
+			break
+		}
+		opt.KeyMarker = res.NextKeyMarker
+		opt.VersionIdMarker = res.NextVersionIdMarker
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].LastModified.After(ret[j].LastModified)
+	})
+	return ret, nil
+}
+
+// GetVersion returns the given version of the remote state file
+func (c *remoteClient) GetVersion(id string) (*remote.Payload, error) {
+	log.Printf("[DEBUG] get remote state file %s version %s", c.stateFile, id)
+
+	exists, data, checksum, err := c.getObject(c.stateFile, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("version %s of %v does not exist", id, c.stateFile)
+	}
+
+	payload := &remote.Payload{
+		Data: data,
+		MD5:  []byte(checksum),
+	}
+
+	return payload, nil
+}
+
 // Lock lock remote state file for writing
 func (c *remoteClient) Lock(info *statemgr.LockInfo) (string, error) {
 	log.Printf("[DEBUG] lock remote state file %s", c.lockFile)
@@ -175,9 +242,9 @@ func (c *remoteClient) lockInfo() (*statemgr.LockInfo, error) {
 	return info, nil
 }
 
-// getObject get remote object
-func (c *remoteClient) getObject(cosFile string) (exists bool, data []byte, checksum string, err error) {
-	rsp, err := c.cosClient.Object.Get(c.cosContext, cosFile, nil)
+// getObject get remote object, optionally at the given version
+func (c *remoteClient) getObject(cosFile string, versionId ...string) (exists bool, data []byte, checksum string, err error) {
+	rsp, err := c.cosClient.Object.Get(c.cosContext, cosFile, nil, versionId...)
 	if rsp == nil {
 		log.Printf("[DEBUG] getObject %s: error: %v", cosFile, err)
 		err = fmt.Errorf("failed to open file at %v: %w", cosFile, err)
@@ -0,0 +1,161 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package etcdv3
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/legacy/helper/schema"
+)
+
+const (
+	// defaultChunkSize keeps each state chunk well below the 1.5 MiB default
+	// request size limit of etcd.
+	defaultChunkSize = 1024 * 1024
+
+	dialTimeout = 5 * time.Second
+)
+
+// New creates a new backend for etcd v3 remote state.
+func New(enc encryption.StateEncryption) backend.Backend {
+	s := &schema.Backend{
+		Schema: map[string]*schema.Schema{
+			"endpoints": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				MinItems:    1,
+				Required:    true,
+				Description: "Endpoints for the etcd cluster",
+			},
+
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Username used to connect to the etcd cluster",
+				DefaultFunc: schema.EnvDefaultFunc("ETCDV3_USERNAME", ""),
+			},
+
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Password used to connect to the etcd cluster",
+				DefaultFunc: schema.EnvDefaultFunc("ETCDV3_PASSWORD", ""),
+			},
+
+			"prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An optional prefix to be added to keys when storing state in etcd",
+				Default:     "",
+			},
+
+			"lock": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Whether to lock state access",
+				Default:     true,
+			},
+
+			"cacert_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The path to a PEM-encoded CA bundle with which to verify certificates of TLS-enabled etcd servers",
+				Default:     "",
+			},
+
+			"cert_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The path to a PEM-encoded certificate to provide to etcd for secure client identification",
+				Default:     "",
+			},
+
+			"key_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The path to a PEM-encoded key to provide to etcd for secure client identification",
+				Default:     "",
+			},
+
+			"max_request_bytes": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The max request size to send to etcd",
+				Default:     0,
+			},
+
+			"chunk_size": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "States larger than this size in bytes are split in multiple keys",
+				Default:     defaultChunkSize,
+			},
+		},
+	}
+
+	result := &Backend{Backend: s, encryption: enc}
+	result.Backend.ConfigureFunc = result.configure
+	return result
+}
+
+type Backend struct {
+	*schema.Backend
+	encryption encryption.StateEncryption
+
+	// The fields below are set from configure
+	client    *clientv3.Client
+	prefix    string
+	lock      bool
+	chunkSize int
+}
+
+func (b *Backend) configure(ctx context.Context) error {
+	// Grab the resource data
+	data := schema.FromContextBackendConfig(ctx)
+
+	b.prefix = data.Get("prefix").(string)
+	b.lock = data.Get("lock").(bool)
+	b.chunkSize = data.Get("chunk_size").(int)
+	if b.chunkSize <= 0 {
+		return fmt.Errorf("chunk_size must be positive")
+	}
+
+	config := clientv3.Config{
+		DialTimeout:        dialTimeout,
+		Username:           data.Get("username").(string),
+		Password:           data.Get("password").(string),
+		MaxCallSendMsgSize: data.Get("max_request_bytes").(int),
+	}
+	for _, endpoint := range data.Get("endpoints").([]interface{}) {
+		config.Endpoints = append(config.Endpoints, endpoint.(string))
+	}
+
+	tlsInfo := transport.TLSInfo{
+		TrustedCAFile: data.Get("cacert_path").(string),
+		CertFile:      data.Get("cert_path").(string),
+		KeyFile:       data.Get("key_path").(string),
+	}
+	if !tlsInfo.Empty() || tlsInfo.TrustedCAFile != "" {
+		tlsConfig, err := tlsInfo.ClientConfig()
+		if err != nil {
+			return fmt.Errorf("invalid TLS configuration for etcd: %w", err)
+		}
+		config.TLS = tlsConfig
+	}
+
+	client, err := clientv3.New(config)
+	if err != nil {
+		return err
+	}
+
+	b.client = client
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package etcdv3
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/remote"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func (b *Backend) Workspaces(ctx context.Context) ([]string, error) {
+	res, err := b.client.Get(ctx, b.prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 1, len(res.Kvs)+1)
+	result[0] = backend.DefaultStateName
+	for _, kv := range res.Kvs {
+		name := strings.TrimPrefix(string(kv.Key), b.prefix)
+
+		// The chunks and locks of a state are stored below its key, and
+		// workspace names can't contain a "/".
+		if name == backend.DefaultStateName || strings.Contains(name, "/") {
+			continue
+		}
+		result = append(result, name)
+	}
+	sort.Strings(result[1:])
+
+	return result, nil
+}
+
+func (b *Backend) DeleteWorkspace(_ context.Context, name string, _ bool) error {
+	if name == backend.DefaultStateName || name == "" {
+		return fmt.Errorf("can't delete default state")
+	}
+
+	// Delete it. We just delete it without any locking since
+	// the DeleteState API is documented as such.
+	return b.remoteClient(name).Delete()
+}
+
+func (b *Backend) StateMgr(_ context.Context, name string) (statemgr.Full, error) {
+	// Build the state client
+	var stateMgr = remote.NewState(b.remoteClient(name), b.encryption)
+
+	if !b.lock {
+		stateMgr.DisableLocks()
+	}
+
+	// the default state always exists
+	if name == backend.DefaultStateName {
+		return stateMgr, nil
+	}
+
+	// Grab a lock, we use this to write an empty state if one doesn't
+	// exist already. We have to write an empty state as a sentinel value
+	// so Workspaces() knows it exists.
+	lockInfo := statemgr.NewLockInfo()
+	lockInfo.Operation = "init"
+	lockId, err := stateMgr.Lock(lockInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock state in etcd: %w", err)
+	}
+
+	// Local helper function so we can call it multiple places
+	lockUnlock := func(parent error) error {
+		if err := stateMgr.Unlock(lockId); err != nil {
+			return fmt.Errorf(strings.TrimSpace(errStateUnlock), lockId, err)
+		}
+
+		return parent
+	}
+
+	// Grab the value
+	if err := stateMgr.RefreshState(); err != nil {
+		err = lockUnlock(err)
+		return nil, err
+	}
+
+	// If we have no state, we have to create an empty state
+	if v := stateMgr.State(); v == nil {
+		if err := stateMgr.WriteState(states.NewState()); err != nil {
+			err = lockUnlock(err)
+			return nil, err
+		}
+		if err := stateMgr.PersistState(nil); err != nil {
+			err = lockUnlock(err)
+			return nil, err
+		}
+	}
+
+	// Unlock, the state should now be initialized
+	if err := lockUnlock(nil); err != nil {
+		return nil, err
+	}
+
+	return stateMgr, nil
+}
+
+func (b *Backend) remoteClient(name string) *RemoteClient {
+	return &RemoteClient{
+		Client:    b.client,
+		Key:       b.prefix + name,
+		ChunkSize: b.chunkSize,
+		lockState: b.lock,
+	}
+}
+
+const errStateUnlock = `
+Error unlocking etcd state. Lock ID: %s
+
+Error: %w
+
+You may have to force-unlock this state in order to use it again.
+The etcd backend acquires a lock during initialization to ensure
+the minimum required key/values are prepared.
+`
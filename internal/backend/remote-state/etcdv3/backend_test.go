@@ -0,0 +1,193 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package etcdv3
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.etcd.io/etcd/server/v3/embed"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
+)
+
+func TestBackend_impl(t *testing.T) {
+	var _ backend.Backend = new(Backend)
+}
+
+// etcdv3Endpoints are the client endpoints of the etcd server that the tests
+// run against.
+var etcdv3Endpoints []string
+
+// TestMain starts an embedded etcd server for the tests, unless
+// TF_ETCDV3_ENDPOINTS gives the endpoints of an existing etcd cluster as a
+// comma-separated list.
+func TestMain(m *testing.M) {
+	if endpoints := os.Getenv("TF_ETCDV3_ENDPOINTS"); endpoints != "" {
+		for _, endpoint := range strings.Split(endpoints, ",") {
+			etcdv3Endpoints = append(etcdv3Endpoints, strings.TrimSpace(endpoint))
+		}
+		os.Exit(m.Run())
+	}
+
+	server, err := startEmbeddedEtcd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start the embedded etcd server: %s\n", err)
+		os.Exit(1)
+	}
+	for _, u := range server.Config().AdvertiseClientUrls {
+		etcdv3Endpoints = append(etcdv3Endpoints, u.String())
+	}
+
+	code := m.Run()
+	server.Close()
+	os.RemoveAll(server.Config().Dir)
+	os.Exit(code)
+}
+
+// startEmbeddedEtcd starts a single-member etcd cluster in this process,
+// which stores its data in a temporary directory.
+func startEmbeddedEtcd() (*embed.Etcd, error) {
+	dir, err := os.MkdirTemp("", "tofu-etcdv3-test-")
+	if err != nil {
+		return nil, err
+	}
+
+	clientURL, err := freeLocalURL()
+	if err != nil {
+		return nil, err
+	}
+	peerURL, err := freeLocalURL()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := embed.NewConfig()
+	cfg.Dir = dir
+	cfg.LogLevel = "error"
+	cfg.ListenClientUrls = []url.URL{*clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{*clientURL}
+	cfg.ListenPeerUrls = []url.URL{*peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{*peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	server, err := embed.StartEtcd(cfg)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	select {
+	case <-server.Server.ReadyNotify():
+		return server, nil
+	case err := <-server.Err():
+		server.Close()
+		os.RemoveAll(dir)
+		return nil, err
+	case <-time.After(time.Minute):
+		server.Close()
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("the server did not become ready in time")
+	}
+}
+
+// freeLocalURL returns a URL on the loopback interface with a port that is
+// currently free.
+func freeLocalURL() (*url.URL, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+	return url.Parse("http://" + l.Addr().String())
+}
+
+// etcdv3TestEndpoints returns the client endpoints of the etcd server to run
+// the tests against, in the form of the "endpoints" backend argument.
+func etcdv3TestEndpoints(t *testing.T) []interface{} {
+	t.Helper()
+
+	var ret []interface{}
+	for _, endpoint := range etcdv3Endpoints {
+		ret = append(ret, endpoint)
+	}
+	return ret
+}
+
+// etcdv3TestPrefix returns a key prefix that is unique to this run of the
+// test, so that tests don't see the keys left behind by earlier runs.
+func etcdv3TestPrefix(t *testing.T) string {
+	return fmt.Sprintf("tofu-unit/%s/%s/", t.Name(), time.Now().Format(time.RFC3339Nano))
+}
+
+func testBackendConfig(t *testing.T, config map[string]interface{}) *Backend {
+	t.Helper()
+
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(config)).(*Backend)
+	t.Cleanup(func() {
+		b.client.Close()
+	})
+	return b
+}
+
+func TestBackend(t *testing.T) {
+	endpoints := etcdv3TestEndpoints(t)
+	prefix := etcdv3TestPrefix(t)
+
+	// Get the backend. We need two to test locking.
+	b1 := testBackendConfig(t, map[string]interface{}{
+		"endpoints": endpoints,
+		"prefix":    prefix,
+	})
+	b2 := testBackendConfig(t, map[string]interface{}{
+		"endpoints": endpoints,
+		"prefix":    prefix,
+	})
+
+	// Test
+	backend.TestBackendStates(t, b1)
+	backend.TestBackendStateLocks(t, b1, b2)
+	backend.TestBackendStateForceUnlock(t, b1, b2)
+}
+
+func TestBackend_lockDisabled(t *testing.T) {
+	endpoints := etcdv3TestEndpoints(t)
+	prefix := etcdv3TestPrefix(t)
+
+	// Get the backend. We need two to test locking.
+	b1 := testBackendConfig(t, map[string]interface{}{
+		"endpoints": endpoints,
+		"prefix":    prefix,
+		"lock":      false,
+	})
+	b2 := testBackendConfig(t, map[string]interface{}{
+		"endpoints": endpoints,
+		"prefix":    prefix + "2/",
+		"lock":      false,
+	})
+
+	// Test
+	backend.TestBackendStates(t, b1)
+	backend.TestBackendStateLocks(t, b1, b2)
+}
+
+func TestBackend_chunked(t *testing.T) {
+	endpoints := etcdv3TestEndpoints(t)
+	prefix := etcdv3TestPrefix(t)
+
+	// Every state is split in chunks, so the chunks must not show up as
+	// workspaces.
+	b := testBackendConfig(t, map[string]interface{}{
+		"endpoints":  endpoints,
+		"prefix":     prefix,
+		"chunk_size": 16,
+	})
+
+	backend.TestBackendStates(t, b)
+}
@@ -0,0 +1,380 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package etcdv3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+
+	"github.com/opentofu/opentofu/internal/states/remote"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+const (
+	chunksSuffix   = "/.chunks/"
+	lockSuffix     = "/.lock"
+	lockInfoSuffix = "/.lockinfo"
+
+	// lockAcquireTimeout is how long to wait for etcd when trying to acquire
+	// the lock, which fails right away if the lock is held by someone else.
+	lockAcquireTimeout = 10 * time.Second
+)
+
+// lockTTL is the time to live in seconds of the lease that holds the lock.
+// The lease is kept alive while OpenTofu is running, so the lock is only
+// released by etcd when the process holding it dies. This is a variable to
+// speed up the tests.
+var lockTTL = 15
+
+// RemoteClient is a remote client that stores data in etcd.
+type RemoteClient struct {
+	Client    *clientv3.Client
+	Key       string
+	ChunkSize int
+
+	mu sync.Mutex
+	// lockState is true if we're using locks
+	lockState bool
+
+	session *concurrency.Session
+	mutex   *concurrency.Mutex
+	info    *statemgr.LockInfo
+}
+
+// chunkManifest is stored at the state key in place of the state when the
+// state is split in chunks.
+type chunkManifest struct {
+	Hash   string `json:"current-hash"`
+	Chunks int    `json:"chunks"`
+}
+
+func (c *RemoteClient) Get() (*remote.Payload, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ctx := context.TODO()
+
+	res, err := c.Client.Get(ctx, c.Key)
+	if err != nil {
+		return nil, err
+	}
+	if res.Count == 0 {
+		return nil, nil
+	}
+
+	payload := res.Kvs[0].Value
+	if manifest, ok := parseChunkManifest(payload); ok {
+		// Read the chunks at the revision of the manifest, so they always
+		// belong to the same state.
+		payload = nil
+		for i := 0; i < manifest.Chunks; i++ {
+			key := c.chunkKey(manifest.Hash, i)
+			chunk, err := c.Client.Get(ctx, key, clientv3.WithRev(res.Header.Revision))
+			if err != nil {
+				return nil, err
+			}
+			if chunk.Count == 0 {
+				return nil, fmt.Errorf("Key %q could not be found", key)
+			}
+			payload = append(payload, chunk.Kvs[0].Value...)
+		}
+		if fmt.Sprintf("%x", md5.Sum(payload)) != manifest.Hash {
+			return nil, fmt.Errorf("The remote state does not match the expected hash")
+		}
+	}
+
+	md5 := md5.Sum(payload)
+	return &remote.Payload{
+		Data: payload,
+		MD5:  md5[:],
+	}, nil
+}
+
+func (c *RemoteClient) Put(data []byte) error {
+	// A state larger than the chunk size is stored in chunks at
+	// "<key>/.chunks/<md5>/<index>", and the key of the state stores a
+	// manifest with the MD5 sum of the state and the number of chunks. The
+	// chunks are written first, then the manifest replaces the previous value
+	// of the key and the chunks of the previous state are removed in a single
+	// transaction, so readers always see a complete state. The chunks can't
+	// be written in the same transaction as the manifest because etcd limits
+	// the size of a request, so if the state can't be written then its chunks
+	// are removed again.
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ctx := context.TODO()
+
+	// First we determine whether the previous state was stored in chunks
+	var oldHash string
+	res, err := c.Client.Get(ctx, c.Key)
+	if err != nil {
+		return err
+	}
+	if res.Count > 0 {
+		if manifest, ok := parseChunkManifest(res.Kvs[0].Value); ok {
+			oldHash = manifest.Hash
+		}
+	}
+
+	value := data
+	var hash string
+	if len(data) > c.ChunkSize {
+		hash = fmt.Sprintf("%x", md5.Sum(data))
+		chunks := split(data, c.ChunkSize)
+		value, err = json.Marshal(chunkManifest{
+			Hash:   hash,
+			Chunks: len(chunks),
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, chunk := range chunks {
+			if err := c.commit(ctx, clientv3.OpPut(c.chunkKey(hash, i), string(chunk))); err != nil {
+				return c.discardChunks(ctx, hash, oldHash, value, err)
+			}
+		}
+	}
+
+	ops := []clientv3.Op{clientv3.OpPut(c.Key, string(value))}
+	if oldHash != "" && oldHash != hash {
+		ops = append(ops, clientv3.OpDelete(c.chunksPrefix(oldHash), clientv3.WithPrefix()))
+	}
+	if err := c.commit(ctx, ops...); err != nil {
+		return c.discardChunks(ctx, hash, oldHash, value, err)
+	}
+
+	return nil
+}
+
+// commit runs the given operations in a transaction. When holding the lock,
+// the operations are only run if the lock wasn't lost in the meantime.
+func (c *RemoteClient) commit(ctx context.Context, ops ...clientv3.Op) error {
+	txn := c.Client.Txn(ctx)
+	if c.mutex != nil {
+		txn = txn.If(c.mutex.IsOwner())
+	}
+	res, err := txn.Then(ops...).Commit()
+	if err != nil {
+		return err
+	}
+	if !res.Succeeded {
+		return fmt.Errorf("the lock on the etcd state %q was lost", c.Key)
+	}
+	return nil
+}
+
+// discardChunks removes the chunks that were written for a state that could
+// not be written, and returns the error that prevented writing it.
+//
+// The chunks are kept if they belong to the previous state, or if the state
+// key refers to them because someone else wrote the same state.
+func (c *RemoteClient) discardChunks(ctx context.Context, hash, oldHash string, manifest []byte, err error) error {
+	if hash == "" || hash == oldHash {
+		return err
+	}
+	_, delErr := c.Client.Txn(ctx).If(
+		clientv3.Compare(clientv3.Value(c.Key), "=", string(manifest)),
+	).Else(
+		clientv3.OpDelete(c.chunksPrefix(hash), clientv3.WithPrefix()),
+	).Commit()
+	if delErr != nil {
+		return multierror.Append(err, fmt.Errorf("failed to remove the chunks of the state: %w", delErr))
+	}
+	return err
+}
+
+func (c *RemoteClient) Delete() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.Client.Txn(context.TODO()).Then(
+		clientv3.OpDelete(c.Key),
+		clientv3.OpDelete(c.Key+chunksSuffix, clientv3.WithPrefix()),
+	).Commit()
+	return err
+}
+
+func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.lockState {
+		return "", nil
+	}
+
+	if c.session != nil {
+		select {
+		case <-c.session.Done():
+			// The lease of our previous lock expired or was revoked by
+			// force-unlock, so there is nothing left to release.
+			c.session = nil
+			c.mutex = nil
+			c.info = nil
+		default:
+			// we have an active lock already
+			return "", fmt.Errorf("state %q already locked", c.Key)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lockAcquireTimeout)
+	defer cancel()
+
+	// The session keeps its lease alive until it is closed, so the lock and
+	// its info are removed by etcd if OpenTofu dies while holding them.
+	session, err := concurrency.NewSession(c.Client, concurrency.WithTTL(lockTTL))
+	if err != nil {
+		return "", &statemgr.LockError{Info: info, Err: err}
+	}
+
+	mutex := concurrency.NewMutex(session, c.Key+lockSuffix)
+	if err := mutex.TryLock(ctx); err != nil {
+		lockErr := &statemgr.LockError{Err: err}
+		if errors.Is(err, concurrency.ErrLocked) {
+			lockInfo, infoErr := c.getLockInfo(ctx)
+			if infoErr != nil {
+				lockErr.Err = multierror.Append(lockErr.Err, infoErr)
+			}
+			lockErr.Info = lockInfo
+		}
+		if closeErr := session.Close(); closeErr != nil {
+			lockErr.Err = multierror.Append(lockErr.Err, closeErr)
+		}
+		return "", lockErr
+	}
+
+	// A random lock ID has been generated but we override it with the lease
+	// ID, which force-unlock revokes to release the lock.
+	info.ID = strconv.FormatInt(int64(session.Lease()), 16)
+	info.Path = c.Key
+	info.Created = time.Now().UTC()
+
+	_, err = c.Client.Put(ctx, c.Key+lockInfoSuffix, string(info.Marshal()), clientv3.WithLease(session.Lease()))
+	if err != nil {
+		if closeErr := session.Close(); closeErr != nil {
+			err = multierror.Append(err, closeErr)
+		}
+		return "", &statemgr.LockError{Info: info, Err: err}
+	}
+
+	c.session = session
+	c.mutex = mutex
+	c.info = info
+
+	return info.ID, nil
+}
+
+func (c *RemoteClient) Unlock(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.lockState {
+		return nil
+	}
+
+	if c.session == nil || c.info.ID != id {
+		// The user called `tofu force-unlock <lock_id>`, so we revoke the
+		// lease of the lock, which removes the lock and its info.
+		return c.forceUnlock(id)
+	}
+
+	// Closing the session revokes its lease, which removes the lock and its
+	// info.
+	err := c.session.Close()
+	c.session = nil
+	c.mutex = nil
+	c.info = nil
+	if err != nil {
+		return &statemgr.LockError{Err: err}
+	}
+
+	return nil
+}
+
+func (c *RemoteClient) forceUnlock(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), lockAcquireTimeout)
+	defer cancel()
+
+	lockInfo, err := c.getLockInfo(ctx)
+	if err != nil {
+		return &statemgr.LockError{Err: err}
+	}
+	if lockInfo == nil {
+		return &statemgr.LockError{Err: fmt.Errorf("state %q is not locked", c.Key)}
+	}
+	if lockInfo.ID != id {
+		return &statemgr.LockError{Info: lockInfo, Err: fmt.Errorf("lock id %q does not match existing lock", id)}
+	}
+
+	lease, err := strconv.ParseInt(id, 16, 64)
+	if err != nil {
+		return &statemgr.LockError{Info: lockInfo, Err: fmt.Errorf("invalid lock id %q: %w", id, err)}
+	}
+	if _, err := c.Client.Revoke(ctx, clientv3.LeaseID(lease)); err != nil {
+		return &statemgr.LockError{Info: lockInfo, Err: err}
+	}
+
+	return nil
+}
+
+func (c *RemoteClient) getLockInfo(ctx context.Context) (*statemgr.LockInfo, error) {
+	res, err := c.Client.Get(ctx, c.Key+lockInfoSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if res.Count == 0 {
+		return nil, nil
+	}
+
+	li := &statemgr.LockInfo{}
+	err = json.Unmarshal(res.Kvs[0].Value, li)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling lock info: %w", err)
+	}
+
+	return li, nil
+}
+
+func (c *RemoteClient) chunksPrefix(hash string) string {
+	return c.Key + chunksSuffix + hash + "/"
+}
+
+func (c *RemoteClient) chunkKey(hash string, index int) string {
+	return c.chunksPrefix(hash) + strconv.Itoa(index)
+}
+
+// parseChunkManifest returns the manifest stored at the state key if the
+// state is stored in chunks.
+func parseChunkManifest(value []byte) (chunkManifest, bool) {
+	var manifest chunkManifest
+	if err := json.Unmarshal(value, &manifest); err != nil {
+		return manifest, false
+	}
+	return manifest, manifest.Hash != ""
+}
+
+func split(payload []byte, limit int) [][]byte {
+	var chunk []byte
+	chunks := make([][]byte, 0, len(payload)/limit+1)
+	for len(payload) >= limit {
+		chunk, payload = payload[:limit], payload[limit:]
+		chunks = append(chunks, chunk)
+	}
+	if len(payload) > 0 {
+		chunks = append(chunks, payload[:])
+	}
+	return chunks
+}
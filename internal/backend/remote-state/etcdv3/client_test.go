@@ -0,0 +1,384 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package etcdv3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/states/remote"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func TestRemoteClient_impl(t *testing.T) {
+	var _ remote.Client = new(RemoteClient)
+	var _ remote.ClientLocker = new(RemoteClient)
+}
+
+func TestRemoteClient(t *testing.T) {
+	endpoints := etcdv3TestEndpoints(t)
+	prefix := etcdv3TestPrefix(t)
+
+	b := testBackendConfig(t, map[string]interface{}{
+		"endpoints": endpoints,
+		"prefix":    prefix,
+	})
+
+	s, err := b.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestClient(t, s.(*remote.State).Client)
+}
+
+func TestRemoteClient_chunked(t *testing.T) {
+	endpoints := etcdv3TestEndpoints(t)
+	prefix := etcdv3TestPrefix(t)
+
+	b := testBackendConfig(t, map[string]interface{}{
+		"endpoints":  endpoints,
+		"prefix":     prefix,
+		"chunk_size": 1024,
+	})
+	client := b.remoteClient(backend.DefaultStateName)
+
+	for _, size := range []int{10, 1024, 1025, 4000, 10} {
+		data := bytes.Repeat([]byte{byte('a' + size%26)}, size)
+		if err := client.Put(data); err != nil {
+			t.Fatal(err)
+		}
+
+		payload, err := client.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(payload.Data, data) {
+			t.Fatalf("wrong state of %d bytes read back", size)
+		}
+
+		// The chunks of the previous state must have been removed
+		want := int64(0)
+		if size > 1024 {
+			want = int64(len(split(data, 1024)))
+		}
+		if got := countChunks(t, b, client.Key+chunksSuffix); got != want {
+			t.Fatalf("wrong number of chunks for %d bytes: got %d, want %d", size, got, want)
+		}
+	}
+
+	if err := client.Put(bytes.Repeat([]byte("x"), 3000)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if got := countChunks(t, b, client.Key+chunksSuffix); got != 0 {
+		t.Fatalf("%d chunks left after deleting the state", got)
+	}
+}
+
+func TestRemoteClient_chunkedLockLost(t *testing.T) {
+	endpoints := etcdv3TestEndpoints(t)
+	prefix := etcdv3TestPrefix(t)
+	config := map[string]interface{}{
+		"endpoints":  endpoints,
+		"prefix":     prefix,
+		"chunk_size": 1024,
+	}
+
+	b := testBackendConfig(t, config)
+	c1 := b.remoteClient(backend.DefaultStateName)
+	c2 := testBackendConfig(t, config).remoteClient(backend.DefaultStateName)
+
+	info := statemgr.NewLockInfo()
+	info.Operation = "test"
+	id, err := c1.Lock(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c2.Unlock(id); err != nil {
+		t.Fatalf("failed to force-unlock: %s", err)
+	}
+
+	// The state can't be written without the lock, and no chunks of it
+	// may be left behind.
+	err = c1.Put(bytes.Repeat([]byte("x"), 3000))
+	if err == nil || !strings.Contains(err.Error(), "was lost") {
+		t.Fatalf("expected an error about the lost lock, got: %v", err)
+	}
+	if got := countChunks(t, b, c1.Key+chunksSuffix); got != 0 {
+		t.Fatalf("%d chunks left after failing to write the state", got)
+	}
+}
+
+func TestEtcdv3_stateLock(t *testing.T) {
+	endpoints := etcdv3TestEndpoints(t)
+	prefix := etcdv3TestPrefix(t)
+
+	s1, err := testBackendConfig(t, map[string]interface{}{
+		"endpoints": endpoints,
+		"prefix":    prefix,
+	}).StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s2, err := testBackendConfig(t, map[string]interface{}{
+		"endpoints": endpoints,
+		"prefix":    prefix,
+	}).StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
+}
+
+func TestEtcdv3_lockExpires(t *testing.T) {
+	// Use the shortest lease etcd allows, so the lock expires quickly.
+	defaultLockTTL := lockTTL
+	lockTTL = 1
+	defer func() {
+		lockTTL = defaultLockTTL
+	}()
+
+	endpoints := etcdv3TestEndpoints(t)
+	prefix := etcdv3TestPrefix(t)
+	config := map[string]interface{}{
+		"endpoints": endpoints,
+		"prefix":    prefix,
+	}
+
+	b1 := testBackendConfig(t, config)
+	b2 := testBackendConfig(t, config)
+	c1 := b1.remoteClient(backend.DefaultStateName)
+	c2 := b2.remoteClient(backend.DefaultStateName)
+
+	info := statemgr.NewLockInfo()
+	info.Operation = "test"
+	if _, err := c1.Lock(info); err != nil {
+		t.Fatal(err)
+	}
+
+	// Closing the client stops keeping the lease alive, as if the process
+	// holding the lock died.
+	if err := b1.client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(20 * time.Second)
+	for {
+		id, err := c2.Lock(info)
+		if err == nil {
+			if err := c2.Unlock(id); err != nil {
+				t.Fatal(err)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("lock was not released after its holder stopped: %s", err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := map[string]struct {
+		size  int
+		limit int
+		want  []int
+	}{
+		"empty":          {0, 4, nil},
+		"smaller":        {3, 4, []int{3}},
+		"exact":          {4, 4, []int{4}},
+		"exact multiple": {8, 4, []int{4, 4}},
+		"remainder":      {10, 4, []int{4, 4, 2}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			payload := make([]byte, test.size)
+			for i := range payload {
+				payload[i] = byte(i)
+			}
+
+			chunks := split(payload, test.limit)
+			var got []int
+			for _, chunk := range chunks {
+				got = append(got, len(chunk))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(test.want) {
+				t.Fatalf("wrong chunk sizes %v; want %v", got, test.want)
+			}
+			if joined := bytes.Join(chunks, nil); !bytes.Equal(joined, payload) {
+				t.Fatalf("chunks don't add up to the payload")
+			}
+		})
+	}
+}
+
+func TestParseChunkManifest(t *testing.T) {
+	tests := map[string]struct {
+		value  string
+		want   chunkManifest
+		wantOk bool
+	}{
+		"manifest": {
+			value:  `{"current-hash":"abc","chunks":3}`,
+			want:   chunkManifest{Hash: "abc", Chunks: 3},
+			wantOk: true,
+		},
+		"state": {
+			value: `{"version":4,"serial":1,"lineage":"abc"}`,
+		},
+		"not json": {
+			value: `encrypted`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := parseChunkManifest([]byte(test.value))
+			if ok != test.wantOk {
+				t.Fatalf("wrong result %t; want %t", ok, test.wantOk)
+			}
+			if ok && got != test.want {
+				t.Fatalf("wrong manifest %#v; want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRemoteClient_chunkedPutFailure(t *testing.T) {
+	endpoints := etcdv3TestEndpoints(t)
+	prefix := etcdv3TestPrefix(t)
+
+	b := testBackendConfig(t, map[string]interface{}{
+		"endpoints":  endpoints,
+		"prefix":     prefix,
+		"chunk_size": 1024,
+	})
+	client := b.remoteClient(backend.DefaultStateName)
+	old := bytes.Repeat([]byte("o"), 3000)
+	if err := client.Put(old); err != nil {
+		t.Fatal(err)
+	}
+	oldChunks := countChunks(t, b, client.Key+chunksSuffix)
+
+	// etcd rejects requests larger than 1.5 MiB by default, so the chunks
+	// of this client can't be written.
+	large := testBackendConfig(t, map[string]interface{}{
+		"endpoints":  endpoints,
+		"prefix":     prefix,
+		"chunk_size": 2 << 20,
+	}).remoteClient(backend.DefaultStateName)
+	if err := large.Put(bytes.Repeat([]byte("n"), 3<<20)); err == nil {
+		t.Fatal("succeeded; want error")
+	}
+
+	// The previous state and its chunks must be intact, and none of the
+	// chunks of the new state may be left behind.
+	payload, err := client.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(payload.Data, old) {
+		t.Fatalf("previous state was not kept")
+	}
+	if got := countChunks(t, b, client.Key+chunksSuffix); got != oldChunks {
+		t.Fatalf("wrong number of chunks after failing to write the state: got %d, want %d", got, oldChunks)
+	}
+}
+
+func TestRemoteClient_discardChunks(t *testing.T) {
+	endpoints := etcdv3TestEndpoints(t)
+	writeErr := errors.New("write failed")
+	manifest := []byte(`{"current-hash":"new","chunks":1}`)
+
+	newClient := func(t *testing.T) (*Backend, *RemoteClient) {
+		t.Helper()
+		b := testBackendConfig(t, map[string]interface{}{
+			"endpoints": endpoints,
+			"prefix":    etcdv3TestPrefix(t),
+		})
+		return b, b.remoteClient(backend.DefaultStateName)
+	}
+
+	t.Run("new chunks", func(t *testing.T) {
+		b, client := newClient(t)
+		for _, key := range []string{client.chunkKey("new", 0), client.chunkKey("old", 0)} {
+			if _, err := b.client.Put(t.Context(), key, "chunk"); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := client.discardChunks(t.Context(), "new", "old", manifest, writeErr)
+		if !errors.Is(err, writeErr) {
+			t.Fatalf("wrong error: %v", err)
+		}
+		if got := countChunks(t, b, client.chunksPrefix("new")); got != 0 {
+			t.Fatalf("%d chunks of the new state left", got)
+		}
+		if got := countChunks(t, b, client.chunksPrefix("old")); got != 1 {
+			t.Fatalf("chunks of the previous state removed")
+		}
+	})
+	t.Run("chunks of the previous state", func(t *testing.T) {
+		b, client := newClient(t)
+		if _, err := b.client.Put(t.Context(), client.chunkKey("new", 0), "chunk"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := client.discardChunks(t.Context(), "new", "new", manifest, writeErr); !errors.Is(err, writeErr) {
+			t.Fatalf("wrong error: %v", err)
+		}
+		if got := countChunks(t, b, client.chunksPrefix("new")); got != 1 {
+			t.Fatalf("chunks of the previous state removed")
+		}
+	})
+	t.Run("chunks written by someone else", func(t *testing.T) {
+		b, client := newClient(t)
+		for key, value := range map[string]string{client.Key: string(manifest), client.chunkKey("new", 0): "chunk"} {
+			if _, err := b.client.Put(t.Context(), key, value); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := client.discardChunks(t.Context(), "new", "", manifest, writeErr); !errors.Is(err, writeErr) {
+			t.Fatalf("wrong error: %v", err)
+		}
+		if got := countChunks(t, b, client.chunksPrefix("new")); got != 1 {
+			t.Fatalf("chunks referred to by the state removed")
+		}
+	})
+	t.Run("removal failure", func(t *testing.T) {
+		b, client := newClient(t)
+		if err := b.client.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		err := client.discardChunks(t.Context(), "new", "", manifest, writeErr)
+		if !errors.Is(err, writeErr) || !strings.Contains(err.Error(), "failed to remove the chunks of the state") {
+			t.Fatalf("wrong error: %v", err)
+		}
+	})
+}
+
+// countChunks returns the number of keys with the given prefix.
+func countChunks(t *testing.T, b *Backend, prefix string) int64 {
+	t.Helper()
+	res, err := b.client.Get(t.Context(), prefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Count
+}
@@ -114,6 +114,34 @@ func TestRemoteClientWithEncryption(t *testing.T) {
 	remote.TestClient(t, rs.Client)
 }
 
+func TestRemoteClientVersions(t *testing.T) {
+	t.Parallel()
+
+	bucket := bucketName(t)
+	be := setupBackend(t, bucket, noPrefix, noEncryptionKey, noKmsKeyName)
+	defer teardownBackend(t, be, noPrefix)
+
+	gcsBE := be.(*Backend)
+	_, err := gcsBE.storageClient.Bucket(bucket).Update(gcsBE.storageContext, storage.BucketAttrsToUpdate{
+		VersioningEnabled: true,
+	})
+	if err != nil {
+		t.Fatalf("enabling versioning on bucket %q failed: %v", bucket, err)
+	}
+
+	ss, err := be.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatalf("be.StateMgr(%q) = %v", backend.DefaultStateName, err)
+	}
+
+	rs, ok := ss.(*remote.State)
+	if !ok {
+		t.Fatalf("be.StateMgr(): got a %T, want a *remote.State", ss)
+	}
+
+	remote.TestClientVersions(t, rs.Client.(remote.ClientVersioner))
+}
+
 func TestRemoteLocks(t *testing.T) {
 	t.Parallel()
 
@@ -386,10 +414,12 @@ func teardownBackend(t *testing.T, be backend.Backend, prefix string) {
 	ctx := gcsBE.storageContext
 
 	bucket := gcsBE.storageClient.Bucket(gcsBE.bucketName)
-	objs := bucket.Objects(ctx, nil)
+	// We include noncurrent generations so that buckets with object
+	// versioning enabled are emptied too.
+	objs := bucket.Objects(ctx, &storage.Query{Versions: true})
 
 	for o, err := objs.Next(); err == nil; o, err = objs.Next() {
-		if err := bucket.Object(o.Name).Delete(ctx); err != nil {
+		if err := bucket.Object(o.Name).Generation(o.Generation).Delete(ctx); err != nil {
 			log.Printf("Error trying to delete object: %s %s\n\n", o.Name, err)
 		} else {
 			log.Printf("Object deleted: %s", o.Name)
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 
 	"cloud.google.com/go/storage"
@@ -17,11 +18,12 @@ import (
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"golang.org/x/net/context"
+	"google.golang.org/api/iterator"
 )
 
 // remoteClient is used by "state/remote".State to read and write
 // blobs representing state.
-// Implements "state/remote".ClientLocker
+// Implements "state/remote".ClientLocker and "state/remote".ClientVersioner
 type remoteClient struct {
 	storageContext context.Context
 	storageClient  *storage.Client
@@ -87,6 +89,78 @@ func (c *remoteClient) Delete() error {
 	return nil
 }
 
+// Versions returns the generations of the state file that are retained by
+// the bucket. Generations other than the live one are only retained when
+// object versioning is enabled on the bucket.
+func (c *remoteClient) Versions() ([]*remote.Version, error) {
+	objs := c.storageClient.Bucket(c.bucketName).Objects(c.storageContext, &storage.Query{
+		Prefix:   c.stateFilePath,
+		Versions: true,
+	})
+
+	var ret []*remote.Version
+	for {
+		attrs, err := objs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list versions of state file %v: %w", c.stateFileURL(), err)
+		}
+
+		// The prefix also matches other objects whose name starts with
+		// the path of our state file.
+		if attrs.Name != c.stateFilePath {
+			continue
+		}
+		// Each generation is created when the state is written, whereas
+		// its update time also changes when only its metadata changes.
+		ret = append(ret, &remote.Version{
+			ID:           strconv.FormatInt(attrs.Generation, 10),
+			LastModified: attrs.Created,
+			// Noncurrent generations have a deletion time set.
+			IsLatest: attrs.Deleted.IsZero(),
+		})
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].LastModified.After(ret[j].LastModified)
+	})
+	return ret, nil
+}
+
+func (c *remoteClient) GetVersion(id string) (*remote.Payload, error) {
+	gen, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("State version should be a numerical generation, got '%s'", id)
+	}
+
+	stateFile := c.stateFile().Generation(gen)
+	stateFileReader, err := stateFile.NewReader(c.storageContext)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open generation %d of state file at %v: %w", gen, c.stateFileURL(), err)
+	}
+	defer stateFileReader.Close()
+
+	stateFileContents, err := io.ReadAll(stateFileReader)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read generation %d of state file from %v: %w", gen, c.stateFileURL(), err)
+	}
+	if len(stateFileContents) == 0 {
+		return nil, nil
+	}
+
+	stateFileAttrs, err := stateFile.Attrs(c.storageContext)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read generation %d of state file attrs from %v: %w", gen, c.stateFileURL(), err)
+	}
+
+	return &remote.Payload{
+		Data: stateFileContents,
+		MD5:  stateFileAttrs.MD5,
+	}, nil
+}
+
 // Lock writes to a lock file, ensuring file creation. Returns the generation
 // number, which must be passed to Unlock().
 func (c *remoteClient) Lock(info *statemgr.LockInfo) (string, error) {
@@ -7,6 +7,9 @@ package inmem
 
 import (
 	"crypto/md5"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/states/remote"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
@@ -17,6 +20,16 @@ type RemoteClient struct {
 	Data []byte
 	MD5  []byte
 	Name string
+
+	// history retains every state written by Put, emulating a storage
+	// service with object versioning enabled.
+	history []inmemVersion
+}
+
+type inmemVersion struct {
+	id           string
+	data         []byte
+	lastModified time.Time
 }
 
 func (c *RemoteClient) Get() (*remote.Payload, error) {
@@ -35,15 +48,51 @@ func (c *RemoteClient) Put(data []byte) error {
 
 	c.Data = data
 	c.MD5 = md5[:]
+	c.history = append(c.history, inmemVersion{
+		id:           strconv.Itoa(len(c.history) + 1),
+		data:         data,
+		lastModified: time.Now(),
+	})
 	return nil
 }
 
 func (c *RemoteClient) Delete() error {
 	c.Data = nil
 	c.MD5 = nil
+	c.history = nil
 	return nil
 }
 
+func (c *RemoteClient) Versions() ([]*remote.Version, error) {
+	ret := make([]*remote.Version, 0, len(c.history))
+	for i := len(c.history) - 1; i >= 0; i-- {
+		v := c.history[i]
+		ret = append(ret, &remote.Version{
+			ID:           v.id,
+			LastModified: v.lastModified,
+			IsLatest:     i == len(c.history)-1,
+		})
+	}
+	return ret, nil
+}
+
+func (c *RemoteClient) GetVersion(id string) (*remote.Payload, error) {
+	for _, v := range c.history {
+		if v.id != id {
+			continue
+		}
+		if len(v.data) == 0 {
+			return nil, nil
+		}
+		md5 := md5.Sum(v.data)
+		return &remote.Payload{
+			Data: v.data,
+			MD5:  md5[:],
+		}, nil
+	}
+	return nil, fmt.Errorf("state version %q does not exist", id)
+}
+
 func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
 	return locks.lock(c.Name, info)
 }
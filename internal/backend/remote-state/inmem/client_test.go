@@ -17,6 +17,7 @@ import (
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientVersioner = new(RemoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -40,3 +41,15 @@ func TestInmemLocks(t *testing.T) {
 
 	remote.TestRemoteLocks(t, s.(*remote.State).Client, s.(*remote.State).Client)
 }
+
+func TestRemoteClientVersions(t *testing.T) {
+	defer Reset()
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), hcl.EmptyBody())
+
+	s, err := b.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestClientVersions(t, s.(*remote.State).Client.(remote.ClientVersioner))
+}
@@ -54,6 +54,7 @@ type Backend struct {
 	workspaceKeyPrefix    string
 	skipS3Checksum        bool
 	useLockfile           bool
+	useConditionalWrites  bool
 }
 
 // ConfigSchema returns a description of the expected configuration
@@ -459,6 +460,11 @@ See details: https://cs.opensource.google/go/x/net/+/refs/tags/v0.17.0:http/http
 				Optional:    true,
 				Description: "Manage locking in the same configured S3 bucket",
 			},
+			"use_conditional_writes": {
+				Type:        cty.Bool,
+				Optional:    true,
+				Description: "Write the state object only if it has not changed since it was read, and manage locking in the same configured S3 bucket",
+			},
 		},
 	}
 }
@@ -678,6 +684,7 @@ func (b *Backend) Configure(ctx context.Context, obj cty.Value) tfdiags.Diagnost
 	b.kmsKeyID = stringAttr(obj, "kms_key_id")
 	b.ddbTable = stringAttr(obj, "dynamodb_table")
 	b.useLockfile = boolAttr(obj, "use_lockfile")
+	b.useConditionalWrites = boolAttr(obj, "use_conditional_writes")
 	b.skipS3Checksum = boolAttr(obj, "skip_s3_checksum")
 
 	if customerKey, ok := stringAttrOk(obj, "sse_customer_key"); ok {
@@ -144,6 +144,7 @@ func (b *Backend) remoteClient(name string) (*RemoteClient, error) {
 		ddbTable:              b.ddbTable,
 		skipS3Checksum:        b.skipS3Checksum,
 		useLockfile:           b.useLockfile,
+		useConditionalWrites:  b.useConditionalWrites,
 	}
 
 	return client, nil
@@ -17,13 +17,17 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	dtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	multierror "github.com/hashicorp/go-multierror"
 	uuid "github.com/hashicorp/go-uuid"
 
@@ -55,6 +59,15 @@ type RemoteClient struct {
 	skipS3Checksum bool
 
 	useLockfile bool
+
+	// useConditionalWrites enables compare-and-swap writes of the state
+	// object, using the ETag of the state object as last read or written.
+	useConditionalWrites bool
+	// stateRead is true once the state object was read or written, so that
+	// stateETag is known. stateETag is empty if the state object did not
+	// exist.
+	stateRead bool
+	stateETag string
 }
 
 var (
@@ -141,6 +154,7 @@ func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
 
 		var nk *types.NotFound
 		if errors.As(err, &nk) {
+			c.recordStateETag(nil)
 			return nil, nil
 		}
 
@@ -167,6 +181,7 @@ func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
 
 		var nk *types.NoSuchKey
 		if errors.As(err, &nk) {
+			c.recordStateETag(nil)
 			return nil, nil
 		}
 
@@ -179,6 +194,7 @@ func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
 	if _, err := io.Copy(buf, output.Body); err != nil {
 		return nil, fmt.Errorf("Failed to read remote state: %w", err)
 	}
+	c.recordStateETag(output.ETag)
 
 	sum := md5.Sum(buf.Bytes())
 	payload := &remote.Payload{
@@ -194,6 +210,86 @@ func (c *RemoteClient) get(ctx context.Context) (*remote.Payload, error) {
 	return payload, nil
 }
 
+func (c *RemoteClient) Versions() ([]*remote.Version, error) {
+	ctx, _ := attachLoggerToContext(context.TODO())
+
+	input := &s3.ListObjectVersionsInput{
+		Bucket: &c.bucketName,
+		Prefix: &c.path,
+	}
+
+	var ret []*remote.Version
+	paginator := s3.NewListObjectVersionsPaginator(c.s3Client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx, s3optDisableDefaultChecksum(c.skipS3Checksum))
+		if err != nil {
+			var nb *types.NoSuchBucket
+			if errors.As(err, &nb) {
+				return nil, fmt.Errorf(errS3NoSuchBucket, err)
+			}
+			return nil, fmt.Errorf("failed to list state versions: %w", err)
+		}
+
+		for _, v := range page.Versions {
+			// The prefix also matches other objects whose key starts with
+			// our path, such as the states of other workspaces.
+			if aws.ToString(v.Key) != c.path {
+				continue
+			}
+			ret = append(ret, &remote.Version{
+				ID:           aws.ToString(v.VersionId),
+				LastModified: aws.ToTime(v.LastModified),
+				IsLatest:     aws.ToBool(v.IsLatest),
+			})
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].LastModified.After(ret[j].LastModified)
+	})
+	return ret, nil
+}
+
+func (c *RemoteClient) GetVersion(id string) (*remote.Payload, error) {
+	ctx, _ := attachLoggerToContext(context.TODO())
+
+	input := &s3.GetObjectInput{
+		Bucket:    &c.bucketName,
+		Key:       &c.path,
+		VersionId: aws.String(id),
+	}
+
+	if c.serverSideEncryption && c.customerEncryptionKey != nil {
+		input.SSECustomerKey = aws.String(base64.StdEncoding.EncodeToString(c.customerEncryptionKey))
+		input.SSECustomerAlgorithm = aws.String(s3EncryptionAlgorithm)
+		input.SSECustomerKeyMD5 = aws.String(c.getSSECustomerKeyMD5())
+	}
+
+	output, err := c.s3Client.GetObject(ctx, input, s3optDisableDefaultChecksum(c.skipS3Checksum))
+	if err != nil {
+		var nb *types.NoSuchBucket
+		if errors.As(err, &nb) {
+			return nil, fmt.Errorf(errS3NoSuchBucket, err)
+		}
+		return nil, fmt.Errorf("failed to read state version %q: %w", id, err)
+	}
+	defer output.Body.Close()
+
+	buf := bytes.NewBuffer(nil)
+	if _, err := io.Copy(buf, output.Body); err != nil {
+		return nil, fmt.Errorf("Failed to read remote state: %w", err)
+	}
+	if buf.Len() == 0 {
+		return nil, nil
+	}
+
+	sum := md5.Sum(buf.Bytes())
+	return &remote.Payload{
+		Data: buf.Bytes(),
+		MD5:  sum[:],
+	}, nil
+}
+
 func (c *RemoteClient) Put(data []byte) error {
 	contentLength := int64(len(data))
 
@@ -234,15 +330,32 @@ func (c *RemoteClient) Put(data []byte) error {
 		i.ACL = types.ObjectCannedACL(c.acl)
 	}
 
+	if c.useConditionalWrites {
+		switch {
+		case !c.stateRead:
+			log.Printf("[WARN] Uploading remote state to S3 without a condition, because it was not read before")
+		case c.stateETag == "":
+			// Only create the state object if nobody else did in the meantime.
+			i.IfNoneMatch = aws.String("*")
+		default:
+			// Only replace the state object if nobody else did in the meantime.
+			i.IfMatch = aws.String(c.stateETag)
+		}
+	}
+
 	log.Printf("[DEBUG] Uploading remote state to S3: %#v", i)
 
 	ctx := context.TODO()
 	ctx, _ = attachLoggerToContext(ctx)
 
-	_, err := c.s3Client.PutObject(ctx, i, s3optDisableDefaultChecksum(c.skipS3Checksum))
+	output, err := c.s3Client.PutObject(ctx, i, s3optDisableDefaultChecksum(c.skipS3Checksum))
 	if err != nil {
+		if c.useConditionalWrites && isConditionalWriteConflict(err) {
+			return fmt.Errorf(errConditionalWriteConflict, c.path, c.bucketName, err)
+		}
 		return fmt.Errorf("failed to upload state: %w", err)
 	}
+	c.recordStateETag(output.ETag)
 
 	sum := md5.Sum(data)
 	if err := c.putMD5(ctx, sum[:]); err != nil {
@@ -255,6 +368,35 @@ func (c *RemoteClient) Put(data []byte) error {
 	return nil
 }
 
+// recordStateETag records the ETag of the state object as last read or
+// written. A nil ETag means that the state object does not exist.
+func (c *RemoteClient) recordStateETag(etag *string) {
+	c.stateRead = true
+	c.stateETag = aws.ToString(etag)
+}
+
+// isConditionalWriteConflict returns true if the error is caused by the
+// condition of a conditional write not being met, either because the object
+// changed (412 Precondition Failed) or because of a concurrent conditional
+// write of the same object (409 Conflict).
+func isConditionalWriteConflict(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "PreconditionFailed", "ConditionalRequestConflict":
+			return true
+		}
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		switch respErr.HTTPStatusCode() {
+		case http.StatusPreconditionFailed, http.StatusConflict:
+			return true
+		}
+	}
+	return false
+}
+
 func (c *RemoteClient) Delete() error {
 	ctx := context.TODO()
 	ctx, _ = attachLoggerToContext(ctx)
@@ -267,6 +409,7 @@ func (c *RemoteClient) Delete() error {
 	if err != nil {
 		return err
 	}
+	c.recordStateETag(nil)
 
 	if err := c.deleteMD5(ctx); err != nil {
 		log.Printf("error deleting state md5: %s", err)
@@ -337,7 +480,7 @@ func (c *RemoteClient) dynamoDBLock(info *statemgr.LockInfo) error {
 
 // s3Lock expects the statemgr.LockInfo#ID to be filled already
 func (c *RemoteClient) s3Lock(info *statemgr.LockInfo) error {
-	if !c.useLockfile {
+	if !c.lockfileEnabled() {
 		return nil
 	}
 
@@ -372,7 +515,7 @@ func (c *RemoteClient) s3Lock(info *statemgr.LockInfo) error {
 	ctx, _ = attachLoggerToContext(ctx)
 	_, err := c.s3Client.PutObject(ctx, putParams, s3optDisableDefaultChecksum(c.skipS3Checksum))
 	if err != nil {
-		lockInfo, infoErr := c.getLockInfoFromS3(ctx)
+		lockInfo, _, infoErr := c.getLockInfoFromS3(ctx)
 		if infoErr != nil {
 			err = multierror.Append(err, infoErr)
 		}
@@ -499,7 +642,9 @@ func (c *RemoteClient) getLockInfoFromDynamoDB(ctx context.Context) (*statemgr.L
 	return lockInfo, nil
 }
 
-func (c *RemoteClient) getLockInfoFromS3(ctx context.Context) (*statemgr.LockInfo, error) {
+// getLockInfoFromS3 returns the lock info stored in the lock object, together
+// with the ETag of the lock object.
+func (c *RemoteClient) getLockInfoFromS3(ctx context.Context) (*statemgr.LockInfo, string, error) {
 	getParams := &s3.GetObjectInput{
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(c.lockFilePath()),
@@ -509,19 +654,19 @@ func (c *RemoteClient) getLockInfoFromS3(ctx context.Context) (*statemgr.LockInf
 	if err != nil {
 		var nb *types.NoSuchBucket
 		if errors.As(err, &nb) {
-			return nil, fmt.Errorf(errS3NoSuchBucket, err)
+			return nil, "", fmt.Errorf(errS3NoSuchBucket, err)
 		}
 
-		return nil, err
+		return nil, "", err
 	}
 
 	lockInfo := &statemgr.LockInfo{}
 	err = json.NewDecoder(resp.Body).Decode(lockInfo)
 	if err != nil {
-		return nil, fmt.Errorf("unable to json parse the lock info %q from bucket %q: %w", c.lockFilePath(), c.bucketName, err)
+		return nil, "", fmt.Errorf("unable to json parse the lock info %q from bucket %q: %w", c.lockFilePath(), c.bucketName, err)
 	}
 
-	return lockInfo, nil
+	return lockInfo, aws.ToString(resp.ETag), nil
 }
 
 func (c *RemoteClient) Unlock(id string) error {
@@ -539,7 +684,7 @@ func (c *RemoteClient) Unlock(id string) error {
 		}
 		return s3Err
 	case dynamoDBErr != nil:
-		if c.useLockfile {
+		if c.lockfileEnabled() {
 			return fmt.Errorf("s3 lock released but dynamoDB failed: %w", dynamoDBErr)
 		}
 		return dynamoDBErr
@@ -548,14 +693,14 @@ func (c *RemoteClient) Unlock(id string) error {
 }
 
 func (c *RemoteClient) s3Unlock(id string) *statemgr.LockError {
-	if !c.useLockfile {
+	if !c.lockfileEnabled() {
 		return nil
 	}
 	lockErr := &statemgr.LockError{}
 	ctx := context.TODO()
 	ctx, _ = attachLoggerToContext(ctx)
 
-	lockInfo, err := c.getLockInfoFromS3(ctx)
+	lockInfo, etag, err := c.getLockInfoFromS3(ctx)
 	if err != nil {
 		lockErr.Err = fmt.Errorf("failed to retrieve s3 lock info: %w", err)
 		return lockErr
@@ -571,9 +716,19 @@ func (c *RemoteClient) s3Unlock(id string) *statemgr.LockError {
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(c.lockFilePath()),
 	}
+	// With conditional writes, only delete the lock object we have just read,
+	// so that we never release a lock that someone else acquired in the
+	// meantime. Not all S3-compatible services support conditional deletes,
+	// so this is only done when conditional writes are enabled.
+	if c.useConditionalWrites && etag != "" {
+		params.IfMatch = aws.String(etag)
+	}
 
 	_, err = c.s3Client.DeleteObject(ctx, params, s3optDisableDefaultChecksum(c.skipS3Checksum))
 	if err != nil {
+		if isConditionalWriteConflict(err) {
+			err = fmt.Errorf("lock %q in bucket %q changed while it was being released: %w", c.lockFilePath(), c.bucketName, err)
+		}
 		lockErr.Err = err
 		return lockErr
 	}
@@ -630,7 +785,13 @@ func (c *RemoteClient) getSSECustomerKeyMD5() string {
 }
 
 func (c *RemoteClient) IsLockingEnabled() bool {
-	return c.ddbTable != "" || c.useLockfile
+	return c.ddbTable != "" || c.lockfileEnabled()
+}
+
+// lockfileEnabled returns true if the lock is stored in the bucket, as an
+// object created with a conditional write.
+func (c *RemoteClient) lockfileEnabled() bool {
+	return c.useLockfile || c.useConditionalWrites
 }
 
 func (c *RemoteClient) lockFilePath() string {
@@ -660,6 +821,18 @@ to manually verify the remote state and update the Digest value stored in the
 DynamoDB table to the following value: %x
 `
 
+const errConditionalWriteConflict = `the state in S3 was modified by another process since it was read.
+
+OpenTofu writes the state object %q in bucket %q only if it has not changed
+since it was last read, because use_conditional_writes is enabled. Another
+process must have written the state in the meantime. Your changes have not
+been stored: please check the state, for example with "tofu state pull", and
+run the operation again. To avoid this, make sure that locking is not
+disabled.
+
+Error: %w
+`
+
 const errS3NoSuchBucket = `S3 bucket does not exist.
 
 The referenced S3 bucket must have been previously created. If the S3 bucket
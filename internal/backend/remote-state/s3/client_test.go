@@ -30,6 +30,7 @@ import (
 func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.Client = new(RemoteClient)
 	var _ remote.ClientLocker = new(RemoteClient)
+	var _ remote.ClientVersioner = new(RemoteClient)
 }
 
 func TestRemoteClient(t *testing.T) {
@@ -54,6 +55,58 @@ func TestRemoteClient(t *testing.T) {
 	remote.TestClient(t, state.(*remote.State).Client)
 }
 
+func TestRemoteClientVersions(t *testing.T) {
+	testACC(t)
+	bucketName := fmt.Sprintf("%s-%x", testBucketPrefix, time.Now().Unix())
+	keyName := "testState"
+
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), backend.TestWrapConfig(map[string]interface{}{
+		"bucket":  bucketName,
+		"key":     keyName,
+		"encrypt": true,
+	})).(*Backend)
+
+	ctx := t.Context()
+	createS3Bucket(ctx, t, b.s3Client, bucketName, b.awsConfig.Region)
+	defer deleteS3Bucket(ctx, t, b.s3Client, bucketName)
+
+	_, err := b.s3Client.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+		Bucket: &bucketName,
+		VersioningConfiguration: &types.VersioningConfiguration{
+			Status: types.BucketVersioningStatusEnabled,
+		},
+	})
+	if err != nil {
+		t.Fatal("failed to enable bucket versioning:", err)
+	}
+	defer func() {
+		// deleteS3Bucket only removes the current objects, so we must remove
+		// the older versions first.
+		out, err := b.s3Client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{Bucket: &bucketName})
+		if err != nil {
+			t.Logf("failed to list object versions: %s", err)
+			return
+		}
+		for _, v := range out.Versions {
+			_, err := b.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+				Bucket:    &bucketName,
+				Key:       v.Key,
+				VersionId: v.VersionId,
+			})
+			if err != nil {
+				t.Logf("failed to delete object version: %s", err)
+			}
+		}
+	}()
+
+	state, err := b.StateMgr(ctx, backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestClientVersions(t, state.(*remote.State).Client.(remote.ClientVersioner))
+}
+
 func TestRemoteClientLocks(t *testing.T) {
 	testACC(t)
 	bucketName := fmt.Sprintf("%s-%x", testBucketPrefix, time.Now().Unix())
@@ -716,10 +769,11 @@ func TestRemoteClient_stateChecksum(t *testing.T) {
 // It checks if locking is enabled based on the ddbTable field.
 func TestRemoteClient_IsLockingEnabled(t *testing.T) {
 	tests := []struct {
-		name        string
-		ddbTable    string
-		useLockfile bool
-		wantResult  bool
+		name                 string
+		ddbTable             string
+		useLockfile          bool
+		useConditionalWrites bool
+		wantResult           bool
 	}{
 		{
 			name:       "Locking enabled when ddbTable is set",
@@ -749,13 +803,20 @@ func TestRemoteClient_IsLockingEnabled(t *testing.T) {
 			useLockfile: true,
 			wantResult:  true,
 		},
+		{
+			name:                 "Locking enabled when ddbTable is empty and useConditionalWrites enabled",
+			ddbTable:             "",
+			useConditionalWrites: true,
+			wantResult:           true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			client := &RemoteClient{
-				ddbTable:    tt.ddbTable,
-				useLockfile: tt.useLockfile,
+				ddbTable:             tt.ddbTable,
+				useLockfile:          tt.useLockfile,
+				useConditionalWrites: tt.useConditionalWrites,
 			}
 
 			gotResult := client.IsLockingEnabled()
@@ -766,6 +827,123 @@ func TestRemoteClient_IsLockingEnabled(t *testing.T) {
 	}
 }
 
+func newConditionalWritesTestClient(s3Client *s3.Client) *RemoteClient {
+	return &RemoteClient{
+		s3Client:             s3Client,
+		bucketName:           "tofu-test",
+		path:                 "testState",
+		skipS3Checksum:       true,
+		useConditionalWrites: true,
+	}
+}
+
+func TestRemoteClient_conditionalWrites(t *testing.T) {
+	s3Client := newFakeS3Client(t)
+	a := newConditionalWritesTestClient(s3Client)
+	b := newConditionalWritesTestClient(s3Client)
+
+	remote.TestClient(t, a)
+
+	// Both clients find no state, so only the first one can create it.
+	for _, c := range []*RemoteClient{a, b} {
+		if p, err := c.Get(); err != nil || p != nil {
+			t.Fatalf("unexpected state: %v, %v", p, err)
+		}
+	}
+	if err := a.Put([]byte(`{"serial": 1}`)); err != nil {
+		t.Fatalf("put: %s", err)
+	}
+	if err := b.Put([]byte(`{"serial": 1, "other": true}`)); err == nil || !strings.Contains(err.Error(), "modified by another process") {
+		t.Fatalf("expected a conflict creating the state, got: %v", err)
+	}
+
+	// The client that wrote the state last can write it again.
+	if p, err := b.Get(); err != nil || string(p.Data) != `{"serial": 1}` {
+		t.Fatalf("unexpected state: %v, %v", p, err)
+	}
+	if err := a.Put([]byte(`{"serial": 2}`)); err != nil {
+		t.Fatalf("put: %s", err)
+	}
+	if err := a.Put([]byte(`{"serial": 3}`)); err != nil {
+		t.Fatalf("put: %s", err)
+	}
+
+	// The other client has read an outdated state.
+	if err := b.Put([]byte(`{"serial": 2, "other": true}`)); err == nil || !strings.Contains(err.Error(), "modified by another process") {
+		t.Fatalf("expected a conflict replacing the state, got: %v", err)
+	}
+	if p, err := b.Get(); err != nil || string(p.Data) != `{"serial": 3}` {
+		t.Fatalf("unexpected state: %v, %v", p, err)
+	}
+	if err := b.Put([]byte(`{"serial": 4, "other": true}`)); err != nil {
+		t.Fatalf("put after refresh: %s", err)
+	}
+}
+
+func TestRemoteClient_conditionalWritesLocks(t *testing.T) {
+	s3Client := newFakeS3Client(t)
+	remote.TestRemoteLocks(t, newConditionalWritesTestClient(s3Client), newConditionalWritesTestClient(s3Client))
+}
+
+func TestRemoteClient_unlockTakenOverLock(t *testing.T) {
+	fake, s3Client := newFakeS3(t)
+	a := newConditionalWritesTestClient(s3Client)
+	b := newConditionalWritesTestClient(s3Client)
+
+	infoA := statemgr.NewLockInfo()
+	infoA.Operation = "test"
+	idA, err := a.Lock(infoA)
+	if err != nil {
+		t.Fatalf("unable to get the initial lock: %s", err)
+	}
+
+	// Simulate the lock being released and acquired by b between a reading
+	// the lock object and deleting it.
+	infoB := statemgr.NewLockInfo()
+	infoB.Operation = "test"
+	var lockKey string
+	fake.beforeDelete = func(key string) {
+		fake.beforeDelete = nil
+		lockKey = key
+		fake.objects[key] = infoB.Marshal()
+	}
+
+	if err := a.Unlock(idA); err == nil {
+		t.Fatal("expected an error releasing a lock that was taken over")
+	}
+	if _, ok := fake.objects[lockKey]; !ok {
+		t.Fatal("the lock taken over by another client was deleted")
+	}
+	if err := b.Unlock(infoB.ID); err != nil {
+		t.Fatalf("unable to release the lock taken over: %s", err)
+	}
+}
+
+func TestRemoteClient_unlockWithoutConditionalWrites(t *testing.T) {
+	fake, s3Client := newFakeS3(t)
+	fake.noConditionalDeletes = true
+	c := &RemoteClient{
+		s3Client:       s3Client,
+		bucketName:     "tofu-test",
+		path:           "testState",
+		skipS3Checksum: true,
+		useLockfile:    true,
+	}
+
+	info := statemgr.NewLockInfo()
+	info.Operation = "test"
+	id, err := c.Lock(info)
+	if err != nil {
+		t.Fatalf("unable to get the lock: %s", err)
+	}
+	if err := c.Unlock(id); err != nil {
+		t.Fatalf("unable to release the lock: %s", err)
+	}
+	if len(fake.objects) != 0 {
+		t.Fatalf("the lock object was not deleted: %v", fake.objects)
+	}
+}
+
 // TestS3ChecksumsHeaders is testing the compatibility with aws-sdk when it comes to the defaults baked inside the sdk
 // related to checksums.
 // This test was introduced during upgrading the version of the sdk including a breaking change that could
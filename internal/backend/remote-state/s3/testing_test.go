@@ -6,6 +6,18 @@
 package s3
 
 import (
+	"crypto/md5"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -37,3 +49,109 @@ func diagnosticSummaryComparer(l, r tfdiags.Diagnostic) bool {
 	rd := r.Description()
 	return ld.Summary == rd.Summary
 }
+
+// fakeS3 is a minimal stand-in for an S3-compatible service, such as MinIO,
+// that supports conditional writes. It stores the objects of any bucket in
+// memory, addressed in path style.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+
+	// beforeDelete, if set, is called with the key of each object before
+	// it is deleted, while holding the lock on the objects.
+	beforeDelete func(key string)
+
+	// noConditionalDeletes makes the fake reject conditional deletes, like
+	// S3-compatible services that don't support them.
+	noConditionalDeletes bool
+}
+
+// newFakeS3Client starts a fakeS3 server and returns a client for it.
+func newFakeS3Client(t *testing.T) *s3.Client {
+	t.Helper()
+
+	_, client := newFakeS3(t)
+	return client
+}
+
+// newFakeS3 starts a fakeS3 server and returns it together with a client
+// for it.
+func newFakeS3(t *testing.T) (*fakeS3, *s3.Client) {
+	t.Helper()
+
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	return fake, s3.New(s3.Options{
+		BaseEndpoint: aws.String(srv.URL),
+		UsePathStyle: true,
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+	})
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := r.URL.Path
+	data, exists := f.objects[key]
+	etag := fmt.Sprintf("%q", fmt.Sprintf("%x", md5.Sum(data)))
+
+	switch r.Method {
+	case http.MethodHead, http.MethodGet:
+		if !exists {
+			f.error(w, r, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case http.MethodPut:
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			f.error(w, r, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && (!exists || ifMatch != etag) {
+			f.error(w, r, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			f.error(w, r, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		f.objects[key] = body
+		w.Header().Set("ETag", fmt.Sprintf("%q", fmt.Sprintf("%x", md5.Sum(body))))
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		if f.noConditionalDeletes && r.Header.Get("If-Match") != "" {
+			f.error(w, r, http.StatusNotImplemented, "NotImplemented")
+			return
+		}
+		if f.beforeDelete != nil {
+			f.beforeDelete(key)
+			data, exists = f.objects[key]
+			etag = fmt.Sprintf("%q", fmt.Sprintf("%x", md5.Sum(data)))
+		}
+		if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && exists && ifMatch != etag {
+			f.error(w, r, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.error(w, r, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (f *fakeS3) error(w http.ResponseWriter, r *http.Request, status int, code string) {
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/legacy/helper/schema"
+)
+
+func defaultBoolFunc(k string, dv bool) schema.SchemaDefaultFunc {
+	return func() (interface{}, error) {
+		if v := os.Getenv(k); v != "" {
+			return strconv.ParseBool(v)
+		}
+
+		return dv, nil
+	}
+}
+
+// New creates a new backend for SQLite state storage.
+func New(enc encryption.StateEncryption) backend.Backend {
+	s := &schema.Backend{
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to the SQLite database file that stores the states",
+				DefaultFunc: schema.EnvDefaultFunc("SQLITE_PATH", nil),
+			},
+
+			"wal_mode": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "If set to `true`, the database uses write-ahead logging",
+				DefaultFunc: defaultBoolFunc("SQLITE_WAL_MODE", false),
+			},
+
+			"busy_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Milliseconds to wait for the database to be released by another process",
+				DefaultFunc: schema.EnvDefaultFunc("SQLITE_BUSY_TIMEOUT", 5000),
+			},
+		},
+	}
+
+	result := &Backend{Backend: s, encryption: enc}
+	result.Backend.ConfigureFunc = result.configure
+	return result
+}
+
+type Backend struct {
+	*schema.Backend
+	encryption encryption.StateEncryption
+
+	// The fields below are set from configure
+	db   *sql.DB
+	path string
+}
+
+func (b *Backend) configure(ctx context.Context) error {
+	// Grab the resource data
+	data := schema.FromContextBackendConfig(ctx)
+
+	b.path = data.Get("path").(string)
+	if b.path == "" {
+		return fmt.Errorf("the path of the SQLite database must be set")
+	}
+	walMode := data.Get("wal_mode").(bool)
+	busyTimeout := data.Get("busy_timeout").(int)
+	if busyTimeout < 0 {
+		return fmt.Errorf("busy_timeout must not be negative")
+	}
+
+	journalMode := "DELETE"
+	if walMode {
+		journalMode = "WAL"
+	}
+	// Transactions take the write lock when they begin, so that reading and
+	// updating a lock row happen atomically across processes.
+	params := url.Values{
+		"_pragma": []string{
+			fmt.Sprintf("busy_timeout(%d)", busyTimeout),
+			fmt.Sprintf("journal_mode(%s)", journalMode),
+		},
+		"_txlock": []string{"immediate"},
+	}
+
+	db, err := sql.Open("sqlite", b.path+"?"+params.Encode())
+	if err != nil {
+		return err
+	}
+
+	query := `CREATE TABLE IF NOT EXISTS states (
+		name TEXT PRIMARY KEY,
+		data BLOB NOT NULL
+	)`
+	if _, err = db.Exec(query); err != nil {
+		db.Close()
+		return err
+	}
+
+	query = `CREATE TABLE IF NOT EXISTS locks (
+		name TEXT PRIMARY KEY,
+		info TEXT NOT NULL
+	)`
+	if _, err = db.Exec(query); err != nil {
+		db.Close()
+		return err
+	}
+
+	// Assign db after its schema is prepared.
+	b.db = db
+
+	return nil
+}
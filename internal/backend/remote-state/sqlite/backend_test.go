@@ -0,0 +1,87 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlite
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
+)
+
+func testBackendConfig(t *testing.T, path string, walMode bool) backend.Backend {
+	t.Helper()
+
+	config := backend.TestWrapConfig(map[string]interface{}{
+		"path":     path,
+		"wal_mode": walMode,
+	})
+	b := backend.TestBackendConfig(t, New(encryption.StateEncryptionDisabled()), config)
+	t.Cleanup(func() {
+		b.(*Backend).db.Close()
+	})
+	return b
+}
+
+func TestBackend_impl(t *testing.T) {
+	var _ backend.Backend = new(Backend)
+}
+
+func TestBackendConfig_missingPath(t *testing.T) {
+	t.Setenv("SQLITE_PATH", "")
+
+	b := New(encryption.StateEncryptionDisabled())
+	schema := b.ConfigSchema()
+	spec := schema.DecoderSpec()
+	obj, decDiags := hcldec.Decode(backend.TestWrapConfig(map[string]interface{}{}), spec, nil)
+	if decDiags.HasErrors() {
+		t.Fatal(decDiags.Error())
+	}
+
+	diags := b.Configure(t.Context(), obj)
+	if !diags.HasErrors() {
+		t.Fatal("expected an error for the missing path")
+	}
+	if got, want := diags.Err().Error(), "path of the SQLite database must be set"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestBackendStates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "states.db")
+
+	b := testBackendConfig(t, path, false)
+	backend.TestBackendStates(t, b)
+}
+
+func TestBackendStateLocks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "states.db")
+
+	b1 := testBackendConfig(t, path, false)
+	b2 := testBackendConfig(t, path, false)
+	backend.TestBackendStateLocks(t, b1, b2)
+	backend.TestBackendStateForceUnlock(t, b1, b2)
+}
+
+func TestBackendWALMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "states.db")
+
+	b1 := testBackendConfig(t, path, true)
+	b2 := testBackendConfig(t, path, true)
+
+	var mode string
+	if err := b1.(*Backend).db.QueryRow(`PRAGMA journal_mode`).Scan(&mode); err != nil {
+		t.Fatal(err)
+	}
+	if mode != "wal" {
+		t.Fatalf("wrong journal mode %q; want %q", mode, "wal")
+	}
+
+	backend.TestBackendStates(t, b1)
+	backend.TestBackendStateLocks(t, b1, b2)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlite
+
+import (
+	"crypto/md5"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	uuid "github.com/hashicorp/go-uuid"
+
+	"github.com/opentofu/opentofu/internal/states/remote"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// RemoteClient is a remote client that stores data in a SQLite database
+type RemoteClient struct {
+	Client *sql.DB
+	Name   string
+	Path   string
+}
+
+func (c *RemoteClient) Get() (*remote.Payload, error) {
+	row := c.Client.QueryRow(`SELECT data FROM states WHERE name = ?`, c.Name)
+	var data []byte
+	err := row.Scan(&data)
+	switch {
+	case err == sql.ErrNoRows:
+		// No existing state returns empty.
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		md5 := md5.Sum(data)
+		return &remote.Payload{
+			Data: data,
+			MD5:  md5[:],
+		}, nil
+	}
+}
+
+func (c *RemoteClient) Put(data []byte) error {
+	query := `INSERT INTO states (name, data) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET data = excluded.data`
+	_, err := c.Client.Exec(query, c.Name, data)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *RemoteClient) Delete() error {
+	_, err := c.Client.Exec(`DELETE FROM states WHERE name = ?`, c.Name)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
+	if info.ID == "" {
+		lockID, err := uuid.GenerateUUID()
+		if err != nil {
+			return "", err
+		}
+		info.ID = lockID
+	}
+	info.Path = c.Path
+
+	// The transaction holds the database write lock from the start, so no
+	// other process can take the lock between the check and the insert.
+	tx, err := c.Client.Begin()
+	if err != nil {
+		return "", &statemgr.LockError{Info: info, Err: err}
+	}
+	defer tx.Rollback()
+
+	existing, err := c.lockInfo(tx)
+	if err != nil {
+		return "", &statemgr.LockError{Info: info, Err: err}
+	}
+	if existing != nil {
+		return "", &statemgr.LockError{Info: existing, Err: fmt.Errorf("Workspace is already locked: %s", c.Name)}
+	}
+
+	if _, err := tx.Exec(`INSERT INTO locks (name, info) VALUES (?, ?)`, c.Name, string(info.Marshal())); err != nil {
+		return "", &statemgr.LockError{Info: info, Err: err}
+	}
+	if err := tx.Commit(); err != nil {
+		return "", &statemgr.LockError{Info: info, Err: err}
+	}
+
+	return info.ID, nil
+}
+
+func (c *RemoteClient) Unlock(id string) error {
+	tx, err := c.Client.Begin()
+	if err != nil {
+		return &statemgr.LockError{Err: err}
+	}
+	defer tx.Rollback()
+
+	existing, err := c.lockInfo(tx)
+	if err != nil {
+		return &statemgr.LockError{Err: err}
+	}
+	if existing == nil {
+		return &statemgr.LockError{Err: fmt.Errorf("Workspace is not locked: %s", c.Name)}
+	}
+	if existing.ID != id {
+		return &statemgr.LockError{Info: existing, Err: fmt.Errorf("lock id %q does not match existing lock", id)}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM locks WHERE name = ?`, c.Name); err != nil {
+		return &statemgr.LockError{Info: existing, Err: err}
+	}
+	if err := tx.Commit(); err != nil {
+		return &statemgr.LockError{Info: existing, Err: err}
+	}
+
+	return nil
+}
+
+// lockInfo returns the lock currently held on the workspace, if any.
+func (c *RemoteClient) lockInfo(tx *sql.Tx) (*statemgr.LockInfo, error) {
+	var raw string
+	err := tx.QueryRow(`SELECT info FROM locks WHERE name = ?`, c.Name).Scan(&raw)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	info := &statemgr.LockInfo{}
+	if err := json.Unmarshal([]byte(raw), info); err != nil {
+		return nil, fmt.Errorf("invalid lock info for workspace %s: %w", c.Name, err)
+	}
+	return info, nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/states/remote"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func TestRemoteClient_impl(t *testing.T) {
+	var _ remote.Client = new(RemoteClient)
+	var _ remote.ClientLocker = new(RemoteClient)
+}
+
+func TestRemoteClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "states.db")
+
+	b := testBackendConfig(t, path, false)
+	s, err := b.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestClient(t, s.(*remote.State).Client)
+}
+
+func TestRemoteLocks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "states.db")
+
+	b1 := testBackendConfig(t, path, false)
+	s1, err := b1.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b2 := testBackendConfig(t, path, false)
+	s2, err := b2.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote.TestRemoteLocks(t, s1.(*remote.State).Client, s2.(*remote.State).Client)
+}
+
+func TestRemoteClient_unlockWrongID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "states.db")
+
+	b := testBackendConfig(t, path, false)
+	s, err := b.StateMgr(t.Context(), backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := s.(*remote.State).Client.(*RemoteClient)
+
+	info := statemgr.NewLockInfo()
+	info.Operation = "test"
+	id, err := client.Lock(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Unlock("not-" + id); err == nil {
+		t.Fatal("expected an error when unlocking with the wrong lock ID")
+	}
+	if err := client.Unlock(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Unlock(id); err == nil {
+		t.Fatal("expected an error when unlocking a workspace that is not locked")
+	}
+}
@@ -170,6 +170,22 @@ func (b *Remote) opApply(ctx context.Context, stopCtx, cancelCtx context.Context
 		))
 	}
 
+	if len(op.Policies) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-policy option is not supported",
+			"The -policy option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.Interactive {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-interactive option is not supported",
+			"The -interactive option is not currently supported for remote applies.",
+		))
+	}
+
 	// Return if there are any errors.
 	if diags.HasErrors() {
 		return nil, diags.Err()
@@ -182,6 +182,38 @@ func (p *Provider) ValidateResourceConfig(_ context.Context, req providers.Valid
 	return validateDataStoreResourceConfig(req)
 }
 
+// ValidateEphemeralResourceConfig is used to validate the ephemeral resource
+// configuration values. This provider has no ephemeral resource types.
+func (p *Provider) ValidateEphemeralResourceConfig(_ context.Context, req providers.ValidateEphemeralResourceConfigRequest) providers.ValidateEphemeralResourceConfigResponse {
+	var resp providers.ValidateEphemeralResourceConfigResponse
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource type %q", req.TypeName))
+	return resp
+}
+
+// OpenEphemeralResource opens an ephemeral resource. This provider has no
+// ephemeral resource types.
+func (p *Provider) OpenEphemeralResource(_ context.Context, req providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse {
+	var resp providers.OpenEphemeralResourceResponse
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource type %q", req.TypeName))
+	return resp
+}
+
+// RenewEphemeralResource renews an ephemeral resource. This provider has no
+// ephemeral resource types.
+func (p *Provider) RenewEphemeralResource(_ context.Context, req providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse {
+	var resp providers.RenewEphemeralResourceResponse
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource type %q", req.TypeName))
+	return resp
+}
+
+// CloseEphemeralResource closes an ephemeral resource. This provider has no
+// ephemeral resource types.
+func (p *Provider) CloseEphemeralResource(_ context.Context, req providers.CloseEphemeralResourceRequest) providers.CloseEphemeralResourceResponse {
+	var resp providers.CloseEphemeralResourceResponse
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource type %q", req.TypeName))
+	return resp
+}
+
 func (p *Provider) GetFunctions(_ context.Context) providers.GetFunctionsResponse {
 	return providers.GetFunctionsResponse{
 		Functions: p.getFunctionSpecs(),
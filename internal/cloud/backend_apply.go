@@ -87,6 +87,22 @@ func (b *Cloud) opApply(ctx, stopCtx, cancelCtx context.Context, op *backend.Ope
 		))
 	}
 
+	if len(op.Policies) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-policy option is not supported",
+			"The -policy option is not currently supported for remote plans.",
+		))
+	}
+
+	if op.Interactive {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"-interactive option is not supported",
+			"The -interactive option is not currently supported for remote applies.",
+		))
+	}
+
 	// Return if there are any errors.
 	if diags.HasErrors() {
 		return nil, diags.Err()
@@ -15,6 +15,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -126,6 +127,15 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	opReq, opDiags := c.OperationRequest(ctx, be, view, args.ViewType, planFile, args.Operation, args.AutoApprove, enc)
 	diags = diags.Append(opDiags)
 
+	if opReq != nil {
+		opReq.Interactive = args.Interactive
+
+		// Load the policies to evaluate against the plan
+		var policyDiags tfdiags.Diagnostics
+		opReq.Policies, policyDiags = policy.LoadPaths(args.PolicyPaths)
+		diags = diags.Append(policyDiags)
+	}
+
 	// Before we delegate to the backend, we'll print any warning diagnostics
 	// we've accumulated here, since the backend will start fresh with its own
 	// diagnostics.
@@ -380,6 +390,10 @@ Options:
 
   -input=true            Ask for input for variables if not directly set.
 
+  -interactive           Ask whether to accept or skip each planned change,
+                         and then apply only the accepted changes. Skipping
+                         a change also skips the changes that depend on it.
+
   -no-color              If specified, output won't contain any color.
 
   -concise               Disables progress-related messages in the output.
@@ -387,6 +401,13 @@ Options:
   -parallelism=n         Limit the number of parallel resource operations.
                          Defaults to 10.
 
+  -policy=path           Evaluate the policies in the given policy file, or
+                         in the .tfpolicy.hcl files of the given directory,
+                         against the plan before applying it. A failed
+                         mandatory policy prevents the plan from being
+                         applied. Use this option more than once to include
+                         more than one path.
+
   -state=path            Path to read and save state (unless state-out
                          is specified). Defaults to "terraform.tfstate".
 
@@ -717,6 +717,42 @@ func TestApply_plan(t *testing.T) {
 	}
 }
 
+func TestApply_planInteractive(t *testing.T) {
+	planPath := applyFixturePlanFile(t)
+	statePath := testTempFile(t)
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	// Skipping the only change in the plan cancels the apply.
+	defer testInputMap(t, map[string]string{
+		"approve-change": "no",
+	})()
+
+	args := []string{
+		"-interactive",
+		"-state-out", statePath,
+		planPath,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.All())
+	}
+	if got, want := output.Stdout(), "Apply cancelled."; !strings.Contains(got, want) {
+		t.Fatalf("expected %q in output:\n%s", want, got)
+	}
+	if p.ApplyResourceChangeCalled {
+		t.Fatal("apply should not be called")
+	}
+}
+
 func TestApply_plan_backup(t *testing.T) {
 	statePath := testTempFile(t)
 	backupPath := testTempFile(t)
@@ -1326,6 +1362,142 @@ func TestApply_sensitiveOutput(t *testing.T) {
 	}
 }
 
+func TestApply_policyMandatory(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-policy"), td)
+	t.Chdir(td)
+
+	statePath := testTempFile(t)
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-auto-approve",
+		"-policy", "policies/mandatory.tfpolicy.hcl",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected status code 1, got %d\n\n%s", code, output.Stdout())
+	}
+
+	if got, want := output.Stderr(), `Policy "forbid_bar" failed`; !strings.Contains(got, want) {
+		t.Errorf("missing policy error\ngot:\n%s\nwant substring: %s", got, want)
+	}
+	if p.ApplyResourceChangeCalled {
+		t.Fatal("ApplyResourceChange should not be called when a mandatory policy fails")
+	}
+}
+
+func TestApply_policySavedPlan(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-policy"), td)
+	t.Chdir(td)
+
+	p := applyFixtureProvider()
+	planView, planDone := testView(t)
+	planCmd := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             planView,
+		},
+	}
+	if code := planCmd.Run([]string{
+		"-out", "tfplan",
+		"-policy", "policies/advisory.tfpolicy.hcl",
+	}); code != 0 {
+		t.Fatalf("plan failed: %d\n\n%s", code, planDone(t).Stderr())
+	}
+	planDone(t)
+
+	// The policies recorded in the plan are evaluated again when applying
+	// it, even though they aren't given to the apply command.
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	code := c.Run([]string{"tfplan"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+	if got, want := output.All(), `Policy "prefer_baz" failed`; !strings.Contains(got, want) {
+		t.Errorf("missing policy warning\ngot:\n%s\nwant substring: %s", got, want)
+	}
+}
+
+func TestApply_policySavedPlanChanged(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-policy"), td)
+	t.Chdir(td)
+
+	policyPath := filepath.Join("policies", "changed.tfpolicy.hcl")
+	err := os.WriteFile(policyPath, []byte(`policy "forbid_bar" {
+  condition     = true
+  error_message = "Instances must not use the bar image."
+}
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := applyFixtureProvider()
+	planView, planDone := testView(t)
+	planCmd := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             planView,
+		},
+	}
+	if code := planCmd.Run([]string{
+		"-out", "tfplan",
+		"-policy", policyPath,
+	}); code != 0 {
+		t.Fatalf("plan failed: %d\n\n%s", code, planDone(t).Stderr())
+	}
+	planDone(t)
+
+	// A policy file that changed after the plan was created must pass in
+	// both versions.
+	src, err := os.ReadFile(filepath.Join("policies", "mandatory.tfpolicy.hcl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(policyPath, src, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	code := c.Run([]string{"-policy", policyPath, "tfplan"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected status code 1, got %d\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), `Policy "forbid_bar" failed`; !strings.Contains(got, want) {
+		t.Errorf("missing policy error\ngot:\n%s\nwant substring: %s", got, want)
+	}
+	if p.ApplyResourceChangeCalled {
+		t.Fatal("ApplyResourceChange should not be called when a mandatory policy fails")
+	}
+}
+
 func TestApply_vars(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
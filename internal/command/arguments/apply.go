@@ -37,6 +37,14 @@ type Apply struct {
 
 	// ModuleDeprecationWarnings is used to control what kind of deprecation warnings are shown.
 	ModuleDeprecationWarnings string
+
+	// PolicyPaths are the policy files and directories of policy files whose
+	// policies are evaluated against the plan before it is applied.
+	PolicyPaths []string
+
+	// Interactive asks the user to accept or skip each planned resource
+	// instance change, and then applies only the accepted changes.
+	Interactive bool
 }
 
 // ParseApply processes CLI arguments, returning an Apply value and errors.
@@ -55,6 +63,8 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 	cmdFlags.BoolVar(&apply.InputEnabled, "input", true, "input")
 	cmdFlags.BoolVar(&apply.ShowSensitive, "show-sensitive", false, "displays sensitive values")
 	cmdFlags.StringVar(&apply.ModuleDeprecationWarnings, "deprecation", "", "control the level of deprecation warnings")
+	cmdFlags.Var((*flagStringSlice)(&apply.PolicyPaths), "policy", "policy")
+	cmdFlags.BoolVar(&apply.Interactive, "interactive", false, "interactive")
 
 	var json bool
 	cmdFlags.BoolVar(&json, "json", false, "json")
@@ -97,8 +107,41 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 		))
 	}
 
+	// Interactive apply asks about each change in turn, so it needs input
+	// and replaces the usual approval.
+	if apply.Interactive {
+		switch {
+		case json:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible apply options",
+				"The -interactive and -json options are mutually-exclusive, because OpenTofu cannot ask about each change when -json is set.",
+			))
+		case !apply.InputEnabled:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible apply options",
+				"The -interactive option requires input, so it cannot be used with -input=false.",
+			))
+		case apply.AutoApprove:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible apply options",
+				"The -interactive and -auto-approve options are mutually-exclusive, because -interactive asks for approval of each change.",
+			))
+		}
+	}
+
 	diags = diags.Append(apply.Operation.Parse())
 
+	if apply.Interactive && apply.Operation.PlanMode == plans.RefreshOnlyMode {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible apply options",
+			"The -interactive option is not valid with -refresh-only, because a refresh-only plan has no resource changes to accept or skip.",
+		))
+	}
+
 	switch {
 	case json:
 		apply.ViewType = ViewJSON
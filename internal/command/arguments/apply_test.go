@@ -701,6 +701,61 @@ func TestParseApply_replace(t *testing.T) {
 	}
 }
 
+func TestParseApply_policies(t *testing.T) {
+	got, diags := ParseApply([]string{"-policy=policies", "-policy=extra.tfpolicy.hcl"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	want := []string{"policies", "extra.tfpolicy.hcl"}
+	if !cmp.Equal(want, got.PolicyPaths) {
+		t.Fatalf("unexpected result\n%s", cmp.Diff(want, got.PolicyPaths))
+	}
+}
+
+func TestParseApply_interactive(t *testing.T) {
+	got, diags := ParseApply([]string{"-interactive", "saved.tfplan"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if !got.Interactive {
+		t.Fatal("expected Interactive to be set")
+	}
+
+	testCases := map[string]struct {
+		args    []string
+		wantErr string
+	}{
+		"json": {
+			[]string{"-interactive", "-json", "saved.tfplan"},
+			"The -interactive and -json options are mutually-exclusive",
+		},
+		"no input": {
+			[]string{"-interactive", "-input=false"},
+			"The -interactive option requires input",
+		},
+		"auto-approve": {
+			[]string{"-interactive", "-auto-approve"},
+			"The -interactive and -auto-approve options are mutually-exclusive",
+		},
+		"refresh-only": {
+			[]string{"-interactive", "-refresh-only"},
+			"The -interactive option is not valid with -refresh-only",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, diags := ParseApply(tc.args)
+			if len(diags) == 0 {
+				t.Fatal("expected diags but got none")
+			}
+			if got, want := diags.Err().Error(), tc.wantErr; !strings.Contains(got, want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
 func TestParseApply_vars(t *testing.T) {
 	testCases := map[string]struct {
 		args []string
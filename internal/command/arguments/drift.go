@@ -0,0 +1,114 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Drift represents the command-line arguments for the drift command.
+type Drift struct {
+	// State, Operation, and Vars are the common extended flags
+	State     *State
+	Operation *Operation
+	Vars      *Vars
+
+	// DetailedExitCode enables different exit codes for drift detected and
+	// no drift detected.
+	DetailedExitCode bool
+
+	// InputEnabled is used to disable interactive input for unspecified
+	// variable and backend config values. Default is true.
+	InputEnabled bool
+
+	// ReportPath is an optional path to write the JSON drift report to, in
+	// addition to rendering it with the selected view.
+	ReportPath string
+
+	// RespectIgnoreChanges leaves the attributes listed in the ignore_changes
+	// lifecycle argument of each resource out of the report.
+	RespectIgnoreChanges bool
+
+	// ViewType specifies which output format to use
+	ViewType ViewType
+}
+
+// ParseDrift processes CLI arguments, returning a Drift value and errors.
+// If errors are encountered, a Drift value is still returned representing
+// the best effort interpretation of the arguments.
+func ParseDrift(args []string) (*Drift, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	drift := &Drift{
+		State:     &State{},
+		Operation: &Operation{},
+		Vars:      &Vars{},
+	}
+
+	cmdFlags := extendedFlagSet("drift", drift.State, drift.Operation, drift.Vars)
+	cmdFlags.BoolVar(&drift.DetailedExitCode, "detailed-exitcode", false, "detailed-exitcode")
+	cmdFlags.BoolVar(&drift.InputEnabled, "input", true, "input")
+	cmdFlags.StringVar(&drift.ReportPath, "report", "", "report")
+	cmdFlags.BoolVar(&drift.RespectIgnoreChanges, "respect-ignore-changes", false, "respect-ignore-changes")
+
+	var json bool
+	cmdFlags.BoolVar(&json, "json", false, "json")
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	args = cmdFlags.Args()
+	if len(args) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Too many command line arguments",
+			"To specify a working directory for drift detection, use the global -chdir flag.",
+		))
+	}
+
+	diags = diags.Append(drift.Operation.Parse())
+
+	// Drift detection is always a refresh-only plan, so the options that
+	// select other planning modes or skip refreshing make no sense here.
+	switch {
+	case drift.Operation.PlanMode == plans.DestroyMode:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible planning mode",
+			"The -destroy option is not valid for drift detection.",
+		))
+	case !drift.Operation.Refresh:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible refresh options",
+			"Drift detection requires refreshing, so the -refresh=false option is not valid.",
+		))
+	case len(drift.Operation.ForceReplace) > 0:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible planning options",
+			"The -replace option is not valid for drift detection.",
+		))
+	}
+	drift.Operation.PlanMode = plans.RefreshOnlyMode
+
+	// JSON view currently does not support input, so we disable it here
+	if json {
+		drift.InputEnabled = false
+	}
+
+	switch {
+	case json:
+		drift.ViewType = ViewJSON
+	default:
+		drift.ViewType = ViewHuman
+	}
+
+	return drift, diags
+}
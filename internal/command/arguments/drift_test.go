@@ -0,0 +1,105 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+func TestParseDrift_basicValid(t *testing.T) {
+	testCases := map[string]struct {
+		args []string
+		want *Drift
+	}{
+		"defaults": {
+			nil,
+			&Drift{
+				InputEnabled: true,
+				ViewType:     ViewHuman,
+			},
+		},
+		"all options": {
+			[]string{"-detailed-exitcode", "-input=false", "-report=drift.json", "-respect-ignore-changes"},
+			&Drift{
+				DetailedExitCode:     true,
+				InputEnabled:         false,
+				ReportPath:           "drift.json",
+				RespectIgnoreChanges: true,
+				ViewType:             ViewHuman,
+			},
+		},
+		"JSON view disables input": {
+			[]string{"-json"},
+			&Drift{
+				InputEnabled: false,
+				ViewType:     ViewJSON,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParseDrift(tc.args)
+			if len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			}
+			if got.Operation.PlanMode != plans.RefreshOnlyMode {
+				t.Fatalf("wrong plan mode %s; want %s", got.Operation.PlanMode, plans.RefreshOnlyMode)
+			}
+			// Ignore the extended arguments for simplicity
+			got.State = nil
+			got.Operation = nil
+			got.Vars = nil
+			if *got != *tc.want {
+				t.Fatalf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseDrift_invalid(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		wantErr string
+	}{
+		"unknown flag": {
+			[]string{"-frob"},
+			"flag provided but not defined",
+		},
+		"too many arguments": {
+			[]string{"foo"},
+			"Too many command line arguments",
+		},
+		"destroy": {
+			[]string{"-destroy"},
+			"The -destroy option is not valid for drift detection",
+		},
+		"no refresh": {
+			[]string{"-refresh=false"},
+			"the -refresh=false option is not valid",
+		},
+		"replace": {
+			[]string{"-replace=test_instance.foo"},
+			"The -replace option is not valid for drift detection",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParseDrift(tc.args)
+			if len(diags) == 0 {
+				t.Fatal("expected diags but got none")
+			}
+			if got, want := diags.Err().Error(), tc.wantErr; !strings.Contains(got, want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+			}
+			if got.ViewType != ViewHuman {
+				t.Fatalf("wrong view type, got %#v, want %#v", got.ViewType, ViewHuman)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@
 package arguments
 
 import (
+	"fmt"
+
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -40,8 +42,29 @@ type Plan struct {
 
 	// ModuleDeprecationWarnLevel stores the level that will be used for selecting what deprecation warnings to show.
 	ModuleDeprecationWarnLevel string
+
+	// PolicyPaths are the policy files and directories of policy files whose
+	// policies are evaluated against the plan.
+	PolicyPaths []string
+
+	// Summary selects an aggregated summary of the planned changes to render
+	// alongside the plan, if any.
+	Summary PlanSummary
 }
 
+// PlanSummary represents the kinds of aggregated summary that can be rendered
+// alongside a plan.
+type PlanSummary string
+
+const (
+	// PlanSummaryNone renders no aggregated summary.
+	PlanSummaryNone PlanSummary = ""
+
+	// PlanSummaryModule counts the planned changes by module instance and
+	// provider.
+	PlanSummaryModule PlanSummary = "module"
+)
+
 // ParsePlan processes CLI arguments, returning a Plan value and errors.
 // If errors are encountered, a Plan value is still returned representing
 // the best effort interpretation of the arguments.
@@ -60,6 +83,10 @@ func ParsePlan(args []string) (*Plan, tfdiags.Diagnostics) {
 	cmdFlags.StringVar(&plan.GenerateConfigPath, "generate-config-out", "", "generate-config-out")
 	cmdFlags.BoolVar(&plan.ShowSensitive, "show-sensitive", false, "displays sensitive values")
 	cmdFlags.StringVar(&plan.ModuleDeprecationWarnLevel, "deprecation", "", "control the level of deprecation warnings")
+	cmdFlags.Var((*flagStringSlice)(&plan.PolicyPaths), "policy", "policy")
+
+	var summary string
+	cmdFlags.StringVar(&summary, "summary", "", "summary")
 
 	var json bool
 	cmdFlags.BoolVar(&json, "json", false, "json")
@@ -84,6 +111,17 @@ func ParsePlan(args []string) (*Plan, tfdiags.Diagnostics) {
 
 	diags = diags.Append(plan.Operation.Parse())
 
+	switch PlanSummary(summary) {
+	case PlanSummaryNone, PlanSummaryModule:
+		plan.Summary = PlanSummary(summary)
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid summary",
+			fmt.Sprintf("The -summary option must be set to %q, but was %q.", PlanSummaryModule, summary),
+		))
+	}
+
 	// JSON view currently does not support input, so we disable it here
 	if json {
 		plan.InputEnabled = false
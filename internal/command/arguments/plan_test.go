@@ -602,6 +602,32 @@ func TestParsePlan_excludeAndTarget(t *testing.T) {
 	}
 }
 
+func TestParsePlan_policies(t *testing.T) {
+	got, diags := ParsePlan([]string{"-policy=policies", "-policy=extra.tfpolicy.hcl"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	want := []string{"policies", "extra.tfpolicy.hcl"}
+	if !cmp.Equal(want, got.PolicyPaths) {
+		t.Fatalf("unexpected result\n%s", cmp.Diff(want, got.PolicyPaths))
+	}
+}
+
+func TestParsePlan_summary(t *testing.T) {
+	got, diags := ParsePlan([]string{"-summary=module"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if got.Summary != PlanSummaryModule {
+		t.Fatalf("wrong summary %q; want %q", got.Summary, PlanSummaryModule)
+	}
+
+	_, diags = ParsePlan([]string{"-summary=resource"})
+	if got, want := diags.Err().Error(), "Invalid summary"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
 func TestParsePlan_vars(t *testing.T) {
 	testCases := map[string]struct {
 		args []string
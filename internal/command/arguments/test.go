@@ -32,6 +32,18 @@ type Test struct {
 	// human-readable format or JSON for each run step depending on the
 	// ViewType.
 	Verbose bool
+
+	// Parallelism is the maximum number of test files to execute
+	// concurrently. The default of 1 executes the files one at a time.
+	Parallelism int
+
+	// JUnitXMLFile, if set, is the path of a file to write a JUnit XML report
+	// of the test results into, in addition to the main view output.
+	JUnitXMLFile string
+
+	// SARIFFile, if set, is the path of a file to write a SARIF report of the
+	// failed and errored test runs into, in addition to the main view output.
+	SARIFFile string
 }
 
 func ParseTest(args []string) (*Test, tfdiags.Diagnostics) {
@@ -47,6 +59,9 @@ func ParseTest(args []string) (*Test, tfdiags.Diagnostics) {
 	cmdFlags.StringVar(&test.TestDirectory, "test-directory", configs.DefaultTestDirectory, "test-directory")
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.BoolVar(&test.Verbose, "verbose", false, "verbose")
+	cmdFlags.IntVar(&test.Parallelism, "parallelism", 1, "parallelism")
+	cmdFlags.StringVar(&test.JUnitXMLFile, "junit-xml", "", "junit-xml")
+	cmdFlags.StringVar(&test.SARIFFile, "sarif", "", "sarif")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -55,6 +70,14 @@ func ParseTest(args []string) (*Test, tfdiags.Diagnostics) {
 			err.Error()))
 	}
 
+	if test.Parallelism < 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid parallelism",
+			"The -parallelism option must be at least 1.",
+		))
+	}
+
 	switch {
 	case jsonOutput:
 		test.ViewType = ViewJSON
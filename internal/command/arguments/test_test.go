@@ -79,6 +79,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "tests",
 				ViewType:      ViewHuman,
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -89,6 +90,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        []string{"one.tftest.hcl", "two.tftest.hcl"},
 				TestDirectory: "tests",
 				ViewType:      ViewHuman,
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -99,6 +101,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "tests",
 				ViewType:      ViewJSON,
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -109,6 +112,7 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "other",
 				ViewType:      ViewHuman,
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
@@ -119,16 +123,67 @@ func TestParseTest(t *testing.T) {
 				Filter:        nil,
 				TestDirectory: "tests",
 				ViewType:      ViewHuman,
+				Parallelism:   1,
 				Verbose:       true,
 				Vars:          &Vars{},
 			},
 		},
+		"junit-xml": {
+			args: []string{"-junit-xml=results.xml"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallelism:   1,
+				JUnitXMLFile:  "results.xml",
+				Vars:          &Vars{},
+			},
+		},
+		"sarif": {
+			args: []string{"-sarif=results.sarif"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallelism:   1,
+				SARIFFile:     "results.sarif",
+				Vars:          &Vars{},
+			},
+		},
+		"parallelism": {
+			args: []string{"-parallelism=4"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallelism:   4,
+				Vars:          &Vars{},
+			},
+		},
+		"invalid parallelism": {
+			args: []string{"-parallelism=0"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallelism:   0,
+				Vars:          &Vars{},
+			},
+			wantDiags: tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid parallelism",
+					"The -parallelism option must be at least 1.",
+				),
+			},
+		},
 		"unknown flag": {
 			args: []string{"-boop"},
 			want: &Test{
 				Filter:        nil,
 				TestDirectory: "tests",
 				ViewType:      ViewHuman,
+				Parallelism:   1,
 				Vars:          &Vars{},
 			},
 			wantDiags: tfdiags.Diagnostics{
@@ -127,6 +127,14 @@ func TestEnvConfig(t *testing.T) {
 				PluginCacheMayBreakDependencyLockFile: true,
 			},
 		},
+		"TF_MODULE_CACHE_DIR=boop": {
+			map[string]string{
+				"TF_MODULE_CACHE_DIR": "boop",
+			},
+			&Config{
+				ModuleCacheDir: "boop",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -365,6 +373,12 @@ func TestConfigValidate(t *testing.T) {
 			},
 			1, // The specified plugin cache dir %s cannot be opened
 		},
+		"module_cache_dir does not exist": {
+			&Config{
+				ModuleCacheDir: "fake",
+			},
+			1, // The specified module cache dir %s cannot be opened
+		},
 	}
 
 	for name, test := range tests {
@@ -462,6 +476,7 @@ func TestConfig_Merge(t *testing.T) {
 			},
 		},
 		PluginCacheMayBreakDependencyLockFile: true,
+		ModuleCacheDir:                        "/tmp/modules",
 		OCIDefaultCredentials: []*OCIDefaultCredentials{
 			{
 				DefaultDockerCredentialHelper: "osxkeychain",
@@ -528,6 +543,7 @@ func TestConfig_Merge(t *testing.T) {
 			},
 		},
 		PluginCacheMayBreakDependencyLockFile: true,
+		ModuleCacheDir:                        "/tmp/modules",
 		OCIDefaultCredentials: []*OCIDefaultCredentials{
 			{
 				DiscoverAmbientCredentials: false,
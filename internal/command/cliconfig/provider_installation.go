@@ -8,6 +8,7 @@ package cliconfig
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
 
 	"github.com/hashicorp/hcl"
 	hclast "github.com/hashicorp/hcl/hcl/ast"
@@ -149,6 +150,11 @@ func decodeProviderInstallationFromConfig(hclFile *hclast.File) ([]*ProviderInst
 			methodTypeStr := methodBlock.Keys[0].Token.Value().(string)
 			var location ProviderInstallationLocation
 			var include, exclude []string
+			sigstore, moreDiags := decodeProviderInstallationSigstoreBlock(methodTypeStr, methodBody)
+			diags = diags.Append(moreDiags)
+			if moreDiags.HasErrors() {
+				continue
+			}
 			switch methodTypeStr {
 			case "direct":
 				type BodyContent struct {
@@ -285,10 +291,25 @@ func decodeProviderInstallationFromConfig(hclFile *hclast.File) ([]*ProviderInst
 				continue
 			}
 
+			switch location.(type) {
+			case ProviderInstallationNetworkMirror, ProviderInstallationOCIMirror:
+				// Sigstore verification is supported for these methods
+			default:
+				if sigstore != nil {
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Error,
+						"Invalid provider_installation method block",
+						fmt.Sprintf("Invalid %s block at %s: Sigstore verification is supported only for network_mirror and oci_mirror.", methodTypeStr, methodBlock.Pos()),
+					))
+					continue
+				}
+			}
+
 			pi.Methods = append(pi.Methods, &ProviderInstallationMethod{
 				Location: location,
 				Include:  include,
 				Exclude:  exclude,
+				Sigstore: sigstore,
 			})
 		}
 
@@ -564,6 +585,158 @@ type ProviderInstallationMethod struct {
 	Location ProviderInstallationLocation
 	Include  []string `hcl:"include"`
 	Exclude  []string `hcl:"exclude"`
+
+	// Sigstore, if non-nil, describes the Sigstore signatures that are
+	// required for the packages installed using this method.
+	Sigstore *ProviderInstallationSigstore
+}
+
+// ProviderInstallationSigstore represents a "sigstore" block inside a
+// network_mirror or oci_mirror block.
+//
+// The files it refers to are not read until the provider installation
+// method is instantiated.
+type ProviderInstallationSigstore struct {
+	// PublicKeyFiles are the paths of PEM-encoded public keys that may
+	// sign packages directly.
+	PublicKeyFiles []string
+
+	// TrustedRootFile is the path of a Sigstore trusted root file, which
+	// is required for keyless signing.
+	TrustedRootFile string
+
+	// Identities are the certificate identities that may sign packages
+	// using keyless signing.
+	Identities []ProviderInstallationSigstoreIdentity
+}
+
+// ProviderInstallationSigstoreIdentity represents an "identity" block inside
+// a "sigstore" block.
+type ProviderInstallationSigstoreIdentity struct {
+	Issuer string
+
+	// Exactly one of Subject or SubjectRegexp is set.
+	Subject       string
+	SubjectRegexp string
+}
+
+// decodeProviderInstallationSigstoreBlock decodes the optional "sigstore"
+// block from inside an installation method block, returning nil if there
+// is no such block.
+func decodeProviderInstallationSigstoreBlock(methodTypeStr string, methodBody *hclast.ObjectType) (*ProviderInstallationSigstore, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	blocks := methodBody.List.Filter("sigstore")
+	if len(blocks.Items) == 0 {
+		return nil, diags
+	}
+	if len(blocks.Items) > 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid provider_installation method block",
+			fmt.Sprintf("Invalid %s block at %s: only one sigstore block is allowed.", methodTypeStr, methodBody.Pos()),
+		))
+		return nil, diags
+	}
+	block := blocks.Items[0]
+	blockBody, ok := block.Val.(*hclast.ObjectType)
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid provider_installation method block",
+			fmt.Sprintf("Invalid %s block at %s: sigstore must be a block.", methodTypeStr, methodBody.Pos()),
+		))
+		return nil, diags
+	}
+
+	type BodyContent struct {
+		PublicKeyFiles  []string `hcl:"public_key_files"`
+		TrustedRootFile string   `hcl:"trusted_root_file"`
+	}
+	var bodyContent BodyContent
+	if err := hcl.DecodeObject(&bodyContent, blockBody); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid provider_installation method block",
+			fmt.Sprintf("Invalid sigstore block at %s: %s.", blockBody.Pos(), err),
+		))
+		return nil, diags
+	}
+
+	ret := &ProviderInstallationSigstore{
+		PublicKeyFiles:  bodyContent.PublicKeyFiles,
+		TrustedRootFile: bodyContent.TrustedRootFile,
+	}
+
+	// The HCL 1 decoder can't reliably decode a sequence of nested blocks
+	// into a slice, so we decode each of the identity blocks separately.
+	identityBlocks := blockBody.List.Filter("identity")
+	if len(bodyContent.PublicKeyFiles) == 0 && len(identityBlocks.Items) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid provider_installation method block",
+			fmt.Sprintf("Invalid sigstore block at %s: at least one of the \"public_key_files\" argument or an \"identity\" block is required.", blockBody.Pos()),
+		))
+	}
+	if len(identityBlocks.Items) != 0 && bodyContent.TrustedRootFile == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid provider_installation method block",
+			fmt.Sprintf("Invalid sigstore block at %s: the \"trusted_root_file\" argument is required when using \"identity\" blocks.", blockBody.Pos()),
+		))
+	}
+	for _, identityBlock := range identityBlocks.Items {
+		type IdentityContent struct {
+			Issuer        string `hcl:"issuer"`
+			Subject       string `hcl:"subject"`
+			SubjectRegexp string `hcl:"subject_regexp"`
+		}
+		var identity IdentityContent
+		pos := identityBlock.Val.Pos()
+		if err := hcl.DecodeObject(&identity, identityBlock.Val); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid provider_installation method block",
+				fmt.Sprintf("Invalid identity block at %s: %s.", pos, err),
+			))
+			continue
+		}
+		if identity.Issuer == "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid provider_installation method block",
+				fmt.Sprintf("Invalid identity block at %s: \"issuer\" argument is required.", pos),
+			))
+			continue
+		}
+		if (identity.Subject == "") == (identity.SubjectRegexp == "") {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid provider_installation method block",
+				fmt.Sprintf("Invalid identity block at %s: exactly one of the \"subject\" or \"subject_regexp\" arguments is required.", pos),
+			))
+			continue
+		}
+		if identity.SubjectRegexp != "" {
+			if _, err := regexp.Compile(identity.SubjectRegexp); err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid provider_installation method block",
+					fmt.Sprintf("Invalid identity block at %s: invalid \"subject_regexp\": %s.", pos, err),
+				))
+				continue
+			}
+		}
+		ret.Identities = append(ret.Identities, ProviderInstallationSigstoreIdentity{
+			Issuer:        identity.Issuer,
+			Subject:       identity.Subject,
+			SubjectRegexp: identity.SubjectRegexp,
+		})
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return ret, diags
 }
 
 // ProviderInstallationLocation is an interface type representing the
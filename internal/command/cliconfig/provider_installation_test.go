@@ -275,3 +275,57 @@ func TestLoadConfig_providerInstallationOCIMirrorErrors(t *testing.T) {
 		}
 	})
 }
+
+func TestLoadConfig_providerInstallationSigstore(t *testing.T) {
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "provider-installation-sigstore"))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Err().Error())
+	}
+
+	gotMethods := got.ProviderInstallation[0].Methods
+	if got, want := len(gotMethods), 3; got != want {
+		t.Fatalf("wrong number of provider installation methods %d; want %d", got, want)
+	}
+	want := []*ProviderInstallationSigstore{
+		{
+			PublicKeyFiles: []string{"/tmp/cosign.pub"},
+		},
+		{
+			TrustedRootFile: "/tmp/trusted_root.json",
+			Identities: []ProviderInstallationSigstoreIdentity{
+				{
+					Issuer:  "https://token.actions.githubusercontent.com",
+					Subject: "https://github.com/example/foo/.github/workflows/release.yml@refs/heads/main",
+				},
+				{
+					Issuer:        "https://accounts.example.com",
+					SubjectRegexp: `^.*@example\.com$`,
+				},
+			},
+		},
+		nil,
+	}
+	for i, method := range gotMethods {
+		if diff := cmp.Diff(want[i], method.Sigstore); diff != "" {
+			t.Errorf("wrong sigstore configuration for method %d\n%s", i, diff)
+		}
+	}
+}
+
+func TestLoadConfig_providerInstallationSigstoreErrors(t *testing.T) {
+	_, diags := loadConfigFile(filepath.Join(fixtureDir, "provider-installation-sigstore-errors"))
+	wantErrs := []string{
+		`Invalid direct block at 2:3: Sigstore verification is supported only for network_mirror and oci_mirror.`,
+		`at least one of the "public_key_files" argument or an "identity" block is required.`,
+		`the "trusted_root_file" argument is required when using "identity" blocks.`,
+		`"issuer" argument is required.`,
+		`exactly one of the "subject" or "subject_regexp" arguments is required.`,
+		`invalid "subject_regexp"`,
+	}
+	got := diags.Err().Error()
+	for _, want := range wantErrs {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing expected error\ngot: %s\nwant substring: %s", got, want)
+		}
+	}
+}
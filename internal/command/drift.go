@@ -0,0 +1,340 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/jsondrift"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// DriftCommand is a Command implementation that detects changes made to
+// managed resources outside of OpenTofu.
+type DriftCommand struct {
+	Meta
+}
+
+func (c *DriftCommand) Run(rawArgs []string) int {
+	ctx := c.CommandContext()
+
+	// Parse and apply global view arguments
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+
+	// Propagate -no-color for legacy use of Ui. The remote backend and
+	// cloud package use this; it should be removed when/if they are
+	// migrated to views.
+	c.Meta.color = !common.NoColor
+	c.Meta.Color = c.Meta.color
+
+	// Parse and validate flags
+	args, diags := arguments.ParseDrift(rawArgs)
+
+	// Instantiate the view, even if there are flag errors, so that we render
+	// diagnostics according to the desired view
+	view := views.NewDrift(args.ViewType, c.View)
+
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		view.HelpPrompt()
+		return 1
+	}
+
+	// Check for user-supplied plugin path
+	var err error
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		diags = diags.Append(err)
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// FIXME: the -input and -parallelism flags are needed to initialize the
+	// backend and the operation, so as with the plan command we continue to
+	// mutate the Meta object state for now.
+	c.Meta.input = args.InputEnabled
+	c.Meta.parallelism = args.Operation.Parallelism
+
+	diags = diags.Append(c.providerDevOverrideRuntimeWarnings())
+
+	// Inject variables from args into meta for static evaluation
+	c.GatherVariables(args.Vars)
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption(ctx)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Prepare the backend with the backend-specific arguments
+	be, beDiags := c.PrepareBackend(ctx, args.State, args.ViewType, enc)
+	diags = diags.Append(beDiags)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// We require a backend.Local to build a context, so that we can inspect
+	// the resulting plan rather than only rendering it.
+	local, ok := be.(backend.Local)
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported backend",
+			ErrUnsupportedLocalOp,
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Build the operation request
+	opReq, opDiags := c.OperationRequest(ctx, be, view, args.ViewType, args.Operation, enc)
+	diags = diags.Append(opDiags)
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Check remote OpenTofu version is compatible
+	diags = diags.Append(c.remoteVersionCheck(be, opReq.Workspace))
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	lr, _, ctxDiags := local.LocalRun(ctx, opReq)
+	diags = diags.Append(ctxDiags)
+	if ctxDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Successfully creating the context can result in a lock, so ensure we release it
+	defer func() {
+		diags := opReq.StateLocker.Unlock()
+		if diags.HasErrors() {
+			view.Diagnostics(diags)
+		}
+	}()
+
+	plan, planDiags := lr.Core.Plan(ctx, lr.Config, lr.InputState, lr.PlanOpts)
+	diags = diags.Append(planDiags)
+	if planDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	schemas, schemaDiags := lr.Core.Schemas(ctx, lr.Config, plan.PriorState)
+	diags = diags.Append(schemaDiags)
+	if schemaDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	report, err := jsondrift.NewReport(plan, lr.Config, schemas, args.RespectIgnoreChanges)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to build drift report",
+			err.Error(),
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	if args.ReportPath != "" {
+		if err := writeDriftReport(args.ReportPath, report); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write drift report",
+				fmt.Sprintf("Could not write the drift report to %s: %s.", args.ReportPath, err),
+			))
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+
+	if err := view.Report(report); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to render drift report",
+			err.Error(),
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+	view.Diagnostics(diags)
+
+	if args.DetailedExitCode && report.DriftDetected {
+		return 2
+	}
+	return 0
+}
+
+func writeDriftReport(path string, report *jsondrift.Report) error {
+	src, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(src, '\n'), 0644)
+}
+
+func (c *DriftCommand) PrepareBackend(ctx context.Context, args *arguments.State, viewType arguments.ViewType, enc encryption.Encryption) (backend.Enhanced, tfdiags.Diagnostics) {
+	// FIXME: we need to apply the state arguments to the meta object here
+	// because they are later used when initializing the backend.
+	c.Meta.applyStateArguments(args)
+
+	backendConfig, diags := c.loadBackendConfig(ctx, ".")
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// Load the backend
+	be, beDiags := c.Backend(ctx, &BackendOpts{
+		Config:   backendConfig,
+		ViewType: viewType,
+	}, enc.State())
+	diags = diags.Append(beDiags)
+	if beDiags.HasErrors() {
+		return nil, diags
+	}
+
+	return be, diags
+}
+
+func (c *DriftCommand) OperationRequest(
+	ctx context.Context,
+	be backend.Enhanced,
+	view views.Drift,
+	viewType arguments.ViewType,
+	args *arguments.Operation,
+	enc encryption.Encryption,
+) (*backend.Operation, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// Build the operation
+	opReq := c.Operation(ctx, be, viewType, enc)
+	opReq.ConfigDir = "."
+	opReq.PlanMode = args.PlanMode
+	opReq.Hooks = view.Hooks()
+	opReq.PlanRefresh = true
+	opReq.Targets = args.Targets
+	opReq.Excludes = args.Excludes
+	opReq.Type = backend.OperationTypePlan
+
+	var err error
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to initialize config loader: %w", err))
+		return nil, diags
+	}
+
+	// These are usually prepared by Meta.RunOperation, which we don't use
+	// because we need the resulting plan.
+	var varDiags, callDiags tfdiags.Diagnostics
+	opReq.Variables, varDiags = c.collectVariableValues()
+	opReq.RootCall, callDiags = c.rootModuleCall(ctx, opReq.ConfigDir)
+	diags = diags.Append(varDiags).Append(callDiags)
+
+	return opReq, diags
+}
+
+func (c *DriftCommand) GatherVariables(args *arguments.Vars) {
+	// FIXME the arguments package currently trivially gathers variable related
+	// arguments in a heterogeneous slice, in order to minimize the number of
+	// code paths gathering variables during the transition to this structure.
+	// Once all commands that gather variables have been converted to this
+	// structure, we could move the variable gathering code to the arguments
+	// package directly, removing this shim layer.
+
+	varArgs := args.All()
+	items := make([]rawFlag, len(varArgs))
+	for i := range varArgs {
+		items[i].Name = varArgs[i].Name
+		items[i].Value = varArgs[i].Value
+	}
+	c.Meta.variableArgs = rawFlags{items: &items}
+}
+
+func (c *DriftCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] drift [options]
+
+  Detects changes made to managed resources outside of OpenTofu.
+
+  This command creates a refresh-only plan, which reads the current settings
+  of all remote objects, and reports each attribute that no longer matches
+  the OpenTofu state. It doesn't modify the state or any remote objects.
+
+  Use -report to also write the report in JSON format to a file, for
+  example from a scheduled drift detection job.
+
+Options:
+
+  -compact-warnings        If OpenTofu produces any warnings that are not
+                           accompanied by errors, show them in a more compact
+                           form that includes only the summary messages.
+
+  -detailed-exitcode       Return detailed exit codes when the command exits.
+                           This will change the meaning of exit codes to:
+                           0 - Succeeded, no drift detected
+                           1 - Errored
+                           2 - Succeeded, drift detected
+
+  -exclude=resource        Limit drift detection to not operate on the given
+                           resource or module, and any of its dependencies.
+                           Use this option multiple times to exclude more
+                           than one object.
+
+  -input=false             Disable prompting for required input variables
+                           that are not set some other way.
+
+  -json                    Produce the report in a machine-readable JSON
+                           format instead of human-readable text.
+
+  -lock=false              Don't hold a state lock during the operation.
+
+  -lock-timeout=duration   Duration to retry a state lock.
+
+  -no-color                Disable virtual terminal escape sequences.
+
+  -parallelism=n           Limit the number of concurrent operations.
+                           Defaults to 10.
+
+  -report=path             Write the report in JSON format to the given file.
+
+  -respect-ignore-changes  Leave the attributes listed in the ignore_changes
+                           lifecycle argument of each resource out of the
+                           report.
+
+  -target=resource         Limit drift detection to only the given resource
+                           or module, and any of its dependencies. Use this
+                           option multiple times to include more than one
+                           object.
+
+  -var 'foo=bar'           Set a value for one of the input variables in the
+                           root module of the configuration. Use this option
+                           more than once to set more than one variable.
+
+  -var-file=filename       Load variable values from the given file, in
+                           addition to the default files terraform.tfvars and
+                           *.auto.tfvars. Use this option more than once to
+                           include more than one variables file.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DriftCommand) Synopsis() string {
+	return "Detect changes made outside of OpenTofu"
+}
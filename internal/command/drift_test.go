@@ -0,0 +1,177 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/command/jsondrift"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func testDriftProvider() *tofu.MockProvider {
+	p := testProvider()
+	p.GetProviderSchemaResponse = refreshFixtureSchema()
+	p.ReadResourceFn = nil
+	p.ReadResourceResponse = &providers.ReadResourceResponse{
+		NewState: cty.ObjectVal(map[string]cty.Value{
+			"id":  cty.StringVal("bar"),
+			"ami": cty.StringVal("baz"),
+		}),
+	}
+	return p
+}
+
+func TestDrift(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("drift"), td)
+	t.Chdir(td)
+
+	statePath := testStateFile(t, testState())
+
+	p := testDriftProvider()
+	view, done := testView(t)
+	c := &DriftCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-detailed-exitcode",
+		"-no-color",
+		"-report", "drift.json",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 2 {
+		t.Fatalf("expected status code 2, got %d\n\n%s", code, output.Stderr())
+	}
+
+	if !p.ReadResourceCalled {
+		t.Fatal("ReadResource should have been called")
+	}
+	for _, want := range []string{
+		"Drift detected in 1 resource:",
+		"# test_instance.foo has changed",
+		`~ ami: null -> "baz"`,
+	} {
+		if got := output.Stdout(); !strings.Contains(got, want) {
+			t.Errorf("wrong output\ngot:\n%s\nwant substring: %s", got, want)
+		}
+	}
+
+	src, err := os.ReadFile("drift.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report jsondrift.Report
+	if err := json.Unmarshal(src, &report); err != nil {
+		t.Fatal(err)
+	}
+	if !report.DriftDetected || len(report.Resources) != 1 || report.Resources[0].Address != "test_instance.foo" {
+		t.Fatalf("wrong report\n%s", src)
+	}
+
+	// Drift detection must never change the state.
+	if got, want := testStateRead(t, statePath).String(), testState().String(); got != want {
+		t.Fatalf("state was modified\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDrift_respectIgnoreChanges(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("drift"), td)
+	t.Chdir(td)
+
+	statePath := testStateFile(t, testState())
+
+	p := testDriftProvider()
+	view, done := testView(t)
+	c := &DriftCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-detailed-exitcode",
+		"-respect-ignore-changes",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("expected status code 0, got %d\n\n%s", code, output.Stderr())
+	}
+	if got, want := output.Stdout(), "No drift detected."; !strings.Contains(got, want) {
+		t.Errorf("wrong output\ngot:\n%s\nwant substring: %s", got, want)
+	}
+}
+
+func TestDrift_json(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("drift"), td)
+	t.Chdir(td)
+
+	statePath := testStateFile(t, testState())
+
+	p := testDriftProvider()
+	view, done := testView(t)
+	c := &DriftCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-json",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("expected status code 0, got %d\n\n%s", code, output.Stderr())
+	}
+
+	var report jsondrift.Report
+	if err := json.Unmarshal([]byte(output.Stdout()), &report); err != nil {
+		t.Fatalf("output is not a JSON report: %s\n\n%s", err, output.Stdout())
+	}
+	if len(report.Resources) != 1 {
+		t.Fatalf("wrong number of resources in report\n%s", output.Stdout())
+	}
+	attrs := report.Resources[0].Attributes
+	if len(attrs) != 1 || string(attrs[0].After) != `"baz"` {
+		t.Fatalf("wrong attributes in report\n%s", output.Stdout())
+	}
+}
+
+func TestDrift_invalidFlags(t *testing.T) {
+	view, done := testView(t)
+	c := &DriftCommand{
+		Meta: Meta{
+			View: view,
+		},
+	}
+
+	code := c.Run([]string{"-destroy"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected status code 1, got %d", code)
+	}
+	if got, want := output.Stderr(), "The -destroy option is not valid for drift detection"; !strings.Contains(got, want) {
+		t.Errorf("wrong output\ngot:\n%s\nwant substring: %s", got, want)
+	}
+}
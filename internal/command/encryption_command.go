@@ -0,0 +1,86 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// EncryptionCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type EncryptionCommand struct {
+	Meta
+}
+
+func (c *EncryptionCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *EncryptionCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] encryption <subcommand> [options] [args]
+
+  This command has subcommands for inspecting and managing the encryption of
+  state and plan files.
+
+  The subcommands read the encryption configuration of the current working
+  directory and apply to all workspaces of the configured backend.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EncryptionCommand) Synopsis() string {
+	return "Inspect and migrate state and plan encryption"
+}
+
+// encryptionWorkspace is a workspace of the configured backend, along with its
+// state manager, as used by the encryption subcommands.
+type encryptionWorkspace struct {
+	Name     string
+	StateMgr statemgr.Full
+}
+
+// encryptionBackend loads the encryption configuration and the backend of the
+// current working directory, and returns all of the backend's workspaces.
+func (c *Meta) encryptionBackend(ctx context.Context) (encryption.Encryption, []encryptionWorkspace, bool) {
+	enc, encDiags := c.Encryption(ctx)
+	c.showDiagnostics(encDiags)
+	if encDiags.HasErrors() {
+		return nil, nil, false
+	}
+
+	b, backendDiags := c.Backend(ctx, nil, enc.State())
+	c.showDiagnostics(backendDiags)
+	if backendDiags.HasErrors() {
+		return nil, nil, false
+	}
+
+	names, err := b.Workspaces(ctx)
+	if err == backend.ErrWorkspacesNotSupported {
+		names = []string{backend.DefaultStateName}
+	} else if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to list workspaces: %s", err))
+		return nil, nil, false
+	}
+
+	workspaces := make([]encryptionWorkspace, 0, len(names))
+	for _, name := range names {
+		stateMgr, err := b.StateMgr(ctx, name)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to load the state of workspace %q: %s", name, err))
+			return nil, nil, false
+		}
+		workspaces = append(workspaces, encryptionWorkspace{Name: name, StateMgr: stateMgr})
+	}
+	return enc, workspaces, true
+}
@@ -0,0 +1,163 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// EncryptionMigrateCommand is a Command implementation that encrypts the
+// state of all workspaces with the primary encryption method.
+type EncryptionMigrateCommand struct {
+	Meta
+}
+
+func (c *EncryptionMigrateCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("encryption migrate")
+	c.Meta.varFlagSet(cmdFlags)
+	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	args = cmdFlags.Args()
+
+	if len(args) != 0 {
+		c.Ui.Error("This command takes no arguments.\n")
+		return cli.RunResultHelp
+	}
+
+	if diags := c.Meta.checkRequiredVersion(ctx); diags != nil {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	_, workspaces, ok := c.encryptionBackend(ctx)
+	if !ok {
+		return 1
+	}
+
+	failed := 0
+	migrated := 0
+	for _, ws := range workspaces {
+		done, err := c.migrate(ws)
+		switch {
+		case err != nil:
+			c.Ui.Error(fmt.Sprintf("Failed to migrate the state of workspace %q: %s", ws.Name, err))
+			failed++
+		case done:
+			c.Ui.Output(fmt.Sprintf("Workspace %q: encrypted with the primary method.", ws.Name))
+			migrated++
+		default:
+			c.Ui.Output(fmt.Sprintf("Workspace %q: no migration needed.", ws.Name))
+		}
+	}
+
+	if failed != 0 {
+		c.Ui.Error(fmt.Sprintf("\nFailed to migrate %d of %d workspaces.", failed, len(workspaces)))
+		return 1
+	}
+	c.Ui.Output(fmt.Sprintf("\nMigrated %d of %d workspaces.", migrated, len(workspaces)))
+	return 0
+}
+
+// migrate encrypts the state of the given workspace again if only a fallback
+// method can decrypt it. The result is false if there was nothing to do.
+func (c *EncryptionMigrateCommand) migrate(ws encryptionWorkspace) (bool, error) {
+	describer, ok := ws.StateMgr.(statemgr.EncryptionDescriber)
+	if !ok {
+		return false, fmt.Errorf("the backend does not support describing the state encryption")
+	}
+
+	if c.stateLock {
+		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		if diags := stateLocker.Lock(ws.StateMgr, "encryption-migrate"); diags.HasErrors() {
+			return false, diags.Err()
+		}
+		defer func() {
+			if diags := stateLocker.Unlock(); diags.HasErrors() {
+				c.showDiagnostics(diags)
+			}
+		}()
+	}
+
+	// We check the stored state while holding the lock, so that we don't
+	// write a state that somebody else has just migrated.
+	desc, err := describer.DescribeStateEncryption()
+	if err != nil {
+		return false, err
+	}
+	if desc == nil || (desc.Status != encryption.StatusMigration && desc.Status != encryption.StatusRotation) {
+		return false, nil
+	}
+
+	if err := ws.StateMgr.RefreshState(); err != nil {
+		return false, fmt.Errorf("failed to read the state: %w", err)
+	}
+	state := ws.StateMgr.State()
+	if state == nil {
+		return false, nil
+	}
+	if err := ws.StateMgr.WriteState(state); err != nil {
+		return false, fmt.Errorf("failed to write the state: %w", err)
+	}
+	// Encryption is not supported with cloud backends, which are the only
+	// ones that need schemas here.
+	if err := ws.StateMgr.PersistState(nil); err != nil {
+		return false, fmt.Errorf("failed to persist the state: %w", err)
+	}
+	return true, nil
+}
+
+func (c *EncryptionMigrateCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] encryption migrate [options]
+
+  Encrypt the state of all workspaces of the configured backend with the
+  primary encryption method.
+
+  When you change the encryption configuration, OpenTofu keeps the previous
+  method as a fallback and only encrypts each state with the primary method
+  the next time it writes that state. This command does so for all
+  workspaces at once, locking each state in turn, so that you can remove the
+  fallback afterwards. States that the primary method can already decrypt
+  are left unchanged, unless one of their keys exceeds the maximum age of its
+  key provider's rotation policy.
+
+Options:
+
+  -lock=false         Don't hold a state lock during the operation. This is
+                      dangerous if others might concurrently run commands
+                      against the same workspace.
+
+  -lock-timeout=0s    Duration to retry a state lock.
+
+  -var 'foo=bar'      Set a value for one of the input variables in the root
+                      module of the configuration. Use this option more than
+                      once to set more than one variable.
+
+  -var-file=filename  Load variable values from the given file, in addition
+                      to the default files terraform.tfvars and *.auto.tfvars.
+                      Use this option more than once to include more than one
+                      variables file.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EncryptionMigrateCommand) Synopsis() string {
+	return "Encrypt the state of all workspaces with the primary method"
+}
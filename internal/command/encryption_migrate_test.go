@@ -0,0 +1,91 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	backendLocal "github.com/opentofu/opentofu/internal/backend/local"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func TestEncryptionMigrate(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+	testEncryptionWorkspaces(t)
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &EncryptionMigrateCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	got := ui.OutputWriter.String()
+	for _, want := range []string{
+		`Workspace "default": encrypted with the primary method.`,
+		`Workspace "empty": no migration needed.`,
+		`Workspace "new": no migration needed.`,
+		"Migrated 1 of 3 workspaces.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing output\ngot:  %s\nwant: %s", got, want)
+		}
+	}
+
+	// All states can now be read with only the new key provider.
+	for _, path := range []string{
+		"terraform.tfstate",
+		filepath.Join(backendLocal.DefaultWorkspaceDir, "new", DefaultStateFilename),
+	} {
+		mgr := statemgr.NewFilesystem(path, testStateRewrapEncryption(t, testEncryptionNew))
+		if err := mgr.RefreshState(); err != nil {
+			t.Fatalf("%s: %s", path, err)
+		}
+		if !mgr.State().Equal(testState()) {
+			t.Fatalf("%s: wrong state after migration\n%s", path, mgr.State())
+		}
+	}
+
+	// Running it again has nothing left to do.
+	ui = new(cli.MockUi)
+	c = &EncryptionMigrateCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "Migrated 0 of 3 workspaces."; !strings.Contains(got, want) {
+		t.Fatalf("wrong output\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestEncryptionMigrate_locked(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+	testEncryptionWorkspaces(t)
+
+	unlock, err := testLockState(t, testDataDir, "terraform.tfstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &EncryptionMigrateCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), `Failed to migrate the state of workspace "default"`; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
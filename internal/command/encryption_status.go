@@ -0,0 +1,248 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// EncryptionStatusCommand is a Command implementation that describes how the
+// state of each workspace, or a given plan file, is encrypted.
+type EncryptionStatusCommand struct {
+	Meta
+}
+
+// encryptionStatusFormatVersion is the version of the JSON output of the
+// encryption status command.
+const encryptionStatusFormatVersion = "1.0"
+
+// encryptionStatusJSON is the JSON output of the encryption status command.
+type encryptionStatusJSON struct {
+	FormatVersion string                      `json:"format_version"`
+	Workspaces    []encryptionStatusEntryJSON `json:"workspaces,omitempty"`
+	Plan          *encryptionStatusEntryJSON  `json:"plan,omitempty"`
+}
+
+type encryptionStatusEntryJSON struct {
+	Workspace string `json:"workspace,omitempty"`
+	Path      string `json:"path,omitempty"`
+	// Exists is false if the workspace has no state yet.
+	Exists    bool   `json:"exists"`
+	Encrypted bool   `json:"encrypted"`
+	Envelope  bool   `json:"envelope"`
+	Method    string `json:"method,omitempty"`
+	// Fallback is true if only a fallback method can decrypt the file, so it
+	// needs to be migrated to the primary method.
+	Fallback bool `json:"fallback"`
+	// RotationDue is true if one of the keys exceeds the maximum age of its
+	// key provider's rotation policy.
+	RotationDue  bool                       `json:"rotation_due"`
+	KeyProviders map[string]json.RawMessage `json:"key_providers,omitempty"`
+	Error        string                     `json:"error,omitempty"`
+}
+
+func (c *EncryptionStatusCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+	var jsonOutput bool
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("encryption status")
+	c.Meta.varFlagSet(cmdFlags)
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	args = cmdFlags.Args()
+
+	if len(args) > 1 {
+		c.Ui.Error("This command takes at most one argument: the path to a plan file.\n")
+		return cli.RunResultHelp
+	}
+
+	if diags := c.Meta.checkRequiredVersion(ctx); diags != nil {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	var result encryptionStatusJSON
+	result.FormatVersion = encryptionStatusFormatVersion
+	failed := false
+
+	if len(args) == 1 {
+		enc, encDiags := c.Encryption(ctx)
+		c.showDiagnostics(encDiags)
+		if encDiags.HasErrors() {
+			return 1
+		}
+
+		entry := encryptionStatusEntryJSON{Path: args[0]}
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read the plan file: %s", err))
+			return 1
+		}
+		desc, err := enc.Plan().DescribePlan(data)
+		entry.describe(&desc, err)
+		failed = err != nil
+		result.Plan = &entry
+	} else {
+		_, workspaces, ok := c.encryptionBackend(ctx)
+		if !ok {
+			return 1
+		}
+
+		for _, ws := range workspaces {
+			entry := encryptionStatusEntryJSON{Workspace: ws.Name}
+			describer, ok := ws.StateMgr.(statemgr.EncryptionDescriber)
+			if !ok {
+				entry.Error = "the backend does not support describing the state encryption"
+				failed = true
+				result.Workspaces = append(result.Workspaces, entry)
+				continue
+			}
+			desc, err := describer.DescribeStateEncryption()
+			if desc == nil && err == nil {
+				result.Workspaces = append(result.Workspaces, entry)
+				continue
+			}
+			entry.describe(desc, err)
+			if err != nil {
+				failed = true
+			}
+			result.Workspaces = append(result.Workspaces, entry)
+		}
+	}
+
+	if jsonOutput {
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to encode the encryption status as JSON: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(out))
+	} else {
+		if result.Plan != nil {
+			c.Ui.Output(result.Plan.human(fmt.Sprintf("Plan file %q", result.Plan.Path)))
+		}
+		for _, entry := range result.Workspaces {
+			c.Ui.Output(entry.human(fmt.Sprintf("Workspace %q", entry.Workspace)))
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+func (e *encryptionStatusEntryJSON) describe(desc *encryption.Description, err error) {
+	e.Exists = true
+	if desc != nil {
+		e.Encrypted = desc.Encrypted
+		e.Envelope = desc.Envelope
+		e.Method = string(desc.Method)
+		e.Fallback = desc.Status == encryption.StatusMigration
+		e.RotationDue = desc.Status == encryption.StatusRotation
+		if len(desc.KeyProviderMeta) != 0 {
+			e.KeyProviders = make(map[string]json.RawMessage, len(desc.KeyProviderMeta))
+			for key, meta := range desc.KeyProviderMeta {
+				e.KeyProviders[string(key)] = meta
+			}
+		}
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+}
+
+func (e *encryptionStatusEntryJSON) human(title string) string {
+	var b strings.Builder
+	b.WriteString(title + ":\n")
+	switch {
+	case !e.Exists:
+		b.WriteString("  No state.\n")
+		return b.String()
+	case e.Error != "" && !e.Encrypted:
+		fmt.Fprintf(&b, "  Error: %s\n", e.Error)
+		return b.String()
+	case !e.Encrypted:
+		b.WriteString("  Not encrypted.\n")
+	case e.Envelope:
+		b.WriteString("  Envelope encrypted.\n")
+	default:
+		b.WriteString("  Encrypted.\n")
+	}
+	if e.Method != "" {
+		fmt.Fprintf(&b, "  Method:        %s\n", e.Method)
+	}
+	if len(e.KeyProviders) != 0 {
+		keys := make([]string, 0, len(e.KeyProviders))
+		for key := range e.KeyProviders {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Fprintf(&b, "  Key providers: %s\n", strings.Join(keys, ", "))
+	}
+	if e.Encrypted && e.Error != "" {
+		fmt.Fprintf(&b, "  Error:         %s\n", e.Error)
+	}
+	switch {
+	case e.Fallback && e.Workspace != "":
+		b.WriteString("  Not encrypted with the primary method. Run \"tofu encryption migrate\" to encrypt it again.\n")
+	case e.Fallback:
+		b.WriteString("  Not encrypted with the primary method.\n")
+	case e.RotationDue && e.Workspace != "":
+		b.WriteString("  Key rotation due. Run \"tofu encryption migrate\" to encrypt it with a new key.\n")
+	case e.RotationDue:
+		b.WriteString("  Key rotation due.\n")
+	}
+	return b.String()
+}
+
+func (c *EncryptionStatusCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] encryption status [options] [PLANFILE]
+
+  Describe how the state of each workspace of the configured backend is
+  encrypted, or how the given plan file is encrypted.
+
+  For each state or plan file, this shows the encryption method that can
+  decrypt it according to the current encryption configuration, the key
+  providers whose metadata it contains, and whether only a fallback method can
+  decrypt it. Run "tofu encryption migrate" to encrypt the state of all
+  workspaces with the primary method.
+
+  The exit status is non-zero if a state or plan file cannot be decrypted
+  with the current configuration.
+
+Options:
+
+  -json               Produce output in a machine-readable JSON format,
+                      including the key provider metadata.
+
+  -var 'foo=bar'      Set a value for one of the input variables in the root
+                      module of the configuration. Use this option more than
+                      once to set more than one variable.
+
+  -var-file=filename  Load variable values from the given file, in addition
+                      to the default files terraform.tfvars and *.auto.tfvars.
+                      Use this option more than once to include more than one
+                      variables file.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EncryptionStatusCommand) Synopsis() string {
+	return "Show how the state and plan files are encrypted"
+}
@@ -0,0 +1,219 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	backendLocal "github.com/opentofu/opentofu/internal/backend/local"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// testEncryptionMigrationConfig uses the new key provider, with the old one
+// as a fallback.
+const testEncryptionMigrationConfig = `terraform {
+  encryption {
+    key_provider "pbkdf2" "old" {
+      passphrase = "the old state passphrase"
+    }
+    key_provider "pbkdf2" "new" {
+      passphrase = "the new state passphrase"
+    }
+    method "aes_gcm" "old" {
+      keys = key_provider.pbkdf2.old
+    }
+    method "aes_gcm" "new" {
+      keys = key_provider.pbkdf2.new
+    }
+    state {
+      method = method.aes_gcm.new
+      fallback {
+        method = method.aes_gcm.old
+      }
+    }
+  }
+}
+`
+
+const testEncryptionOld = `
+key_provider "pbkdf2" "old" {
+	passphrase = "the old state passphrase"
+}
+method "aes_gcm" "old" {
+	keys = key_provider.pbkdf2.old
+}
+state {
+	method = method.aes_gcm.old
+}
+`
+
+const testEncryptionNew = `
+key_provider "pbkdf2" "new" {
+	passphrase = "the new state passphrase"
+}
+method "aes_gcm" "new" {
+	keys = key_provider.pbkdf2.new
+}
+state {
+	method = method.aes_gcm.new
+}
+`
+
+// testEncryptionWorkspaces writes the state of the default workspace with
+// the old key provider and the state of the "new" workspace with the new one.
+// The "empty" workspace has no state.
+func testEncryptionWorkspaces(t *testing.T) {
+	t.Helper()
+
+	if err := os.WriteFile("main.tf", []byte(testEncryptionMigrationConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for path, src := range map[string]string{
+		"terraform.tfstate": testEncryptionOld,
+		filepath.Join(backendLocal.DefaultWorkspaceDir, "new", DefaultStateFilename): testEncryptionNew,
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		mgr := statemgr.NewFilesystem(path, testStateRewrapEncryption(t, src))
+		if err := mgr.WriteState(testState()); err != nil {
+			t.Fatal(err)
+		}
+		if err := mgr.PersistState(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(backendLocal.DefaultWorkspaceDir, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEncryptionStatus(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+	testEncryptionWorkspaces(t)
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &EncryptionStatusCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run([]string{"-json"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	var got encryptionStatusJSON
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &got); err != nil {
+		t.Fatalf("invalid JSON output: %s\n\n%s", err, ui.OutputWriter.String())
+	}
+	if len(got.Workspaces) != 3 {
+		t.Fatalf("wrong number of workspaces: %d\n\n%s", len(got.Workspaces), ui.OutputWriter.String())
+	}
+	byName := map[string]encryptionStatusEntryJSON{}
+	for _, ws := range got.Workspaces {
+		byName[ws.Workspace] = ws
+	}
+
+	def := byName["default"]
+	if !def.Exists || !def.Encrypted || def.Method != "method.aes_gcm.old" || !def.Fallback {
+		t.Errorf("wrong status for the default workspace: %#v", def)
+	}
+	if _, ok := def.KeyProviders["key_provider.pbkdf2.old"]; !ok {
+		t.Errorf("missing key provider metadata for the default workspace: %#v", def.KeyProviders)
+	}
+
+	newWs := byName["new"]
+	if !newWs.Exists || !newWs.Encrypted || newWs.Method != "method.aes_gcm.new" || newWs.Fallback {
+		t.Errorf("wrong status for the new workspace: %#v", newWs)
+	}
+
+	if empty := byName["empty"]; empty.Exists {
+		t.Errorf("wrong status for the empty workspace: %#v", empty)
+	}
+}
+
+func TestEncryptionStatus_human(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+	testEncryptionWorkspaces(t)
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &EncryptionStatusCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	got := ui.OutputWriter.String()
+	for _, want := range []string{
+		"Workspace \"default\":\n  Encrypted.\n  Method:        method.aes_gcm.old\n  Key providers: key_provider.pbkdf2.old\n  Not encrypted with the primary method.",
+		"Workspace \"empty\":\n  No state.",
+		"Workspace \"new\":\n  Encrypted.\n  Method:        method.aes_gcm.new\n  Key providers: key_provider.pbkdf2.new\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing output\ngot:  %s\nwant: %s", got, want)
+		}
+	}
+}
+
+func TestEncryptionStatus_undecryptable(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	// Without encryption configured, the encrypted state cannot be read.
+	mgr := statemgr.NewFilesystem("terraform.tfstate", testStateRewrapEncryption(t, testEncryptionOld))
+	if err := mgr.WriteState(testState()); err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.PersistState(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &EncryptionStatusCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, ui.OutputWriter.String())
+	}
+	got := ui.OutputWriter.String()
+	for _, want := range []string{"Key providers: key_provider.pbkdf2.old", "state encryption is not configured"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing output\ngot:  %s\nwant: %s", got, want)
+		}
+	}
+}
+
+func TestEncryptionStatus_plan(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	if err := os.WriteFile("main.tf", []byte(testEncryptionMigrationConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The configuration has no plan encryption, so an unencrypted plan file
+	// is fine.
+	planPath := testPlanFileNoop(t)
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &EncryptionStatusCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run([]string{planPath}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "  Not encrypted.\n"; !strings.Contains(got, want) {
+		t.Fatalf("wrong output\ngot:  %s\nwant: %s", got, want)
+	}
+}
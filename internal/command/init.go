@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"reflect"
 	"sort"
 	"strings"
@@ -270,8 +271,15 @@ func (c *InitCommand) Run(args []string) int {
 		state = sMgr.State()
 	}
 
+	// Installing the modules already records them in the dependency lock
+	// file, so we must check beforehand whether this is the first time the
+	// lock file is created, for the message about it after installing the
+	// providers.
+	_, err = os.Stat(dependencyLockFilename)
+	lockFileExisted := err == nil
+
 	if flagGet {
-		modsOutput, modsAbort, modsDiags := c.getModules(ctx, path, testsDirectory, rootModEarly, flagUpgrade)
+		modsOutput, modsAbort, modsDiags := c.getModules(ctx, path, testsDirectory, rootModEarly, flagUpgrade, flagLockfile)
 		diags = diags.Append(modsDiags)
 		if modsAbort || modsDiags.HasErrors() {
 			c.showDiagnostics(diags)
@@ -351,7 +359,7 @@ func (c *InitCommand) Run(args []string) int {
 	}
 
 	// Now that we have loaded all modules, check the module tree for missing providers.
-	providersOutput, providersAbort, providerDiags := c.getProviders(ctx, config, state, flagUpgrade, flagPluginPath, flagLockfile)
+	providersOutput, providersAbort, providerDiags := c.getProviders(ctx, config, state, flagUpgrade, flagPluginPath, flagLockfile, lockFileExisted)
 	diags = diags.Append(providerDiags)
 	if providersAbort || providerDiags.HasErrors() {
 		c.showDiagnostics(diags)
@@ -392,7 +400,7 @@ func (c *InitCommand) Run(args []string) int {
 	return 0
 }
 
-func (c *InitCommand) getModules(ctx context.Context, path, testsDir string, earlyRoot *configs.Module, upgrade bool) (output bool, abort bool, diags tfdiags.Diagnostics) {
+func (c *InitCommand) getModules(ctx context.Context, path, testsDir string, earlyRoot *configs.Module, upgrade bool, flagLockfile string) (output bool, abort bool, diags tfdiags.Diagnostics) {
 	testModules := false // We can also have modules buried in test files.
 	for _, file := range earlyRoot.Tests {
 		for _, run := range file.Runs {
@@ -423,7 +431,7 @@ func (c *InitCommand) getModules(ctx context.Context, path, testsDir string, ear
 		ShowLocalPaths: true,
 	}
 
-	installAbort, installDiags := c.installModules(ctx, path, testsDir, upgrade, false, hooks)
+	installAbort, installDiags := c.installModules(ctx, path, testsDir, upgrade, false, flagLockfile == "readonly", hooks)
 	diags = diags.Append(installDiags)
 
 	// At this point, installModules may have generated error diags or been
@@ -559,7 +567,10 @@ the backend configuration is present and valid.
 
 // Load the complete module tree, and fetch any missing providers.
 // This method outputs its own Ui.
-func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config, state *states.State, upgrade bool, pluginDirs []string, flagLockfile string) (output, abort bool, diags tfdiags.Diagnostics) {
+//
+// lockFileExisted is whether the dependency lock file existed before "tofu
+// init" started, since it's created when installing modules if necessary.
+func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config, state *states.State, upgrade bool, pluginDirs []string, flagLockfile string, lockFileExisted bool) (output, abort bool, diags tfdiags.Diagnostics) {
 	ctx, span := tracing.Tracer().Start(ctx, "Get Providers")
 	defer span.End()
 
@@ -894,9 +905,11 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 			var keyID string
 			if authResult != nil && authResult.Signed() {
 				keyID = authResult.GPGKeyIDsString()
-			}
-			if keyID != "" {
-				keyID = c.Colorize().Color(fmt.Sprintf(", key ID [reset][bold]%s[reset]", keyID))
+				if keyID != "" {
+					keyID = c.Colorize().Color(fmt.Sprintf(", key ID [reset][bold]%s[reset]", keyID))
+				} else if signers := authResult.SigstoreIdentitiesString(); signers != "" {
+					keyID = c.Colorize().Color(fmt.Sprintf(", by [reset][bold]%s[reset]", signers))
+				}
 			}
 
 			if authResult != nil && authResult.SigningSkipped() {
@@ -985,11 +998,8 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 
 	// If the provider dependencies have changed since the last run then we'll
 	// say a little about that in case the reader wasn't expecting a change.
-	// (When we later integrate module dependencies into the lock file we'll
-	// probably want to refactor this so that we produce one lock-file related
-	// message for all changes together, but this is here for now just because
-	// it's the smallest change relative to what came before it, which was
-	// a hidden JSON file specifically for tracking providers.)
+	// Changes to the module packages recorded in the same lock file are
+	// reported separately when installing the modules, in installModules.
 	if !newLocks.Equal(previousLocks) {
 		// if readonly mode
 		if flagLockfile == "readonly" {
@@ -1029,13 +1039,15 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 					getproviders.CurrentPlatform.String())))
 		}
 
-		if previousLocks.Empty() {
-			// A change from empty to non-empty is special because it suggests
-			// we're running "tofu init" for the first time against a
-			// new configuration. In that case we'll take the opportunity to
-			// say a little about what the dependency lock file is, for new
-			// users or those who are upgrading from a previous Terraform
-			// version that didn't have dependency lock files.
+		if !lockFileExisted {
+			// Creating the lock file is special because it suggests we're
+			// running "tofu init" for the first time against a new
+			// configuration. In that case we'll take the opportunity to say a
+			// little about what the dependency lock file is, for new users or
+			// those who are upgrading from a previous Terraform version that
+			// didn't have dependency lock files. The lock file might already
+			// contain the module packages installed earlier in this run, so
+			// we consider whether it existed before "tofu init" started.
 			c.Ui.Output(c.Colorize().Color(`
 OpenTofu has created a lock file [bold].terraform.lock.hcl[reset] to record the provider
 selections it made above. Include this file in your version control repository
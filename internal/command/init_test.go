@@ -1697,6 +1697,66 @@ func TestInit_getProviderDetectedDuplicate(t *testing.T) {
 
 }
 
+func TestInit_moduleLocksCreateLockFile(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-module-locks"), td)
+	t.Chdir(td)
+
+	// The module is given by its absolute path, so that it's a remote
+	// package that's recorded in the dependency lock file.
+	src, err := os.ReadFile("main.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src = bytes.ReplaceAll(src, []byte("%%BASE%%"), []byte(filepath.ToSlash(td)))
+	if err := os.WriteFile("main.tf", src, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"test": {"1.2.3"},
+	})
+	defer close()
+
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides:     metaOverridesForProvider(testProvider()),
+			Ui:                   ui,
+			View:                 view,
+			ProviderSource:       providerSource,
+			ModulePackageFetcher: getmodules.NewPackageFetcher(t.Context(), nil),
+		},
+	}
+
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	// The module packages are recorded in the lock file before installing
+	// the providers, but this is still the first time the lock file is
+	// created.
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, "OpenTofu has created a lock file") {
+		t.Errorf("missing message about creating the lock file:\n%s", output)
+	}
+	if strings.Contains(output, "made some changes to the provider dependency selections") {
+		t.Errorf("unexpected message about changing the lock file:\n%s", output)
+	}
+
+	locks, diags := depsfile.LoadLocksFromFile(dependencyLockFilename)
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if got := len(locks.AllModules()); got != 1 {
+		t.Errorf("wrong number of module locks %d; want 1", got)
+	}
+	if locks.Provider(addrs.NewDefaultProvider("test")) == nil {
+		t.Errorf("no lock for provider test")
+	}
+}
+
 func TestInit_providerSource(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
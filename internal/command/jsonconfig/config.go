@@ -54,6 +54,7 @@ type moduleCall struct {
 	Expressions       map[string]interface{} `json:"expressions,omitempty"`
 	CountExpression   *expression            `json:"count_expression,omitempty"`
 	ForEachExpression *expression            `json:"for_each_expression,omitempty"`
+	EnabledExpression *expression            `json:"enabled_expression,omitempty"`
 	Module            module                 `json:"module,omitempty"`
 	VersionConstraint string                 `json:"version_constraint,omitempty"`
 	DependsOn         []string               `json:"depends_on,omitempty"`
@@ -101,11 +102,13 @@ type resource struct {
 	// "values" property conforms to.
 	SchemaVersion uint64 `json:"schema_version"`
 
-	// CountExpression and ForEachExpression describe the expressions given for
-	// the corresponding meta-arguments in the resource configuration block.
-	// These are omitted if the corresponding argument isn't set.
+	// CountExpression, ForEachExpression and EnabledExpression describe the
+	// expressions given for the corresponding meta-arguments in the resource
+	// configuration block. These are omitted if the corresponding argument
+	// isn't set.
 	CountExpression   *expression `json:"count_expression,omitempty"`
 	ForEachExpression *expression `json:"for_each_expression,omitempty"`
+	EnabledExpression *expression `json:"enabled_expression,omitempty"`
 
 	DependsOn []string `json:"depends_on,omitempty"`
 }
@@ -321,7 +324,13 @@ func marshalModule(c *configs.Config, schemas *tofu.Schemas, addr string) (modul
 		return module, err
 	}
 
+	ephemeralResources, err := marshalResources(c.Module.EphemeralResources, schemas, addr)
+	if err != nil {
+		return module, err
+	}
+
 	rs = append(managedResources, dataResources...)
+	rs = append(rs, ephemeralResources...)
 	module.Resources = rs
 
 	outputs := make(map[string]output)
@@ -414,6 +423,10 @@ func marshalModuleCall(c *configs.Config, mc *configs.ModuleCall, schemas *tofu.
 		if !fExp.Empty() {
 			ret.ForEachExpression = &fExp
 		}
+		eExp := marshalExpression(mc.Enabled)
+		if !eExp.Empty() {
+			ret.EnabledExpression = &eExp
+		}
 	}
 
 	schema := &configschema.Block{}
@@ -463,6 +476,8 @@ func marshalResources(resources map[string]*configs.Resource, schemas *tofu.Sche
 			r.Mode = "managed"
 		case addrs.DataResourceMode:
 			r.Mode = "data"
+		case addrs.EphemeralResourceMode:
+			r.Mode = "ephemeral"
 		default:
 			return rs, fmt.Errorf("resource %s has an unsupported mode %s", r.Address, v.Mode.String())
 		}
@@ -475,6 +490,10 @@ func marshalResources(resources map[string]*configs.Resource, schemas *tofu.Sche
 			if !fExp.Empty() {
 				r.ForEachExpression = &fExp
 			}
+			eExp := marshalExpression(v.Enabled)
+			if !eExp.Empty() {
+				r.EnabledExpression = &eExp
+			}
 		}
 
 		schema, schemaVer := schemas.ResourceTypeConfig(
@@ -0,0 +1,359 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+// Package jsondrift implements the machine-readable report of the drift
+// detected by "tofu drift", which describes the changes made to managed
+// resources outside of OpenTofu.
+package jsondrift
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/tofu"
+	"github.com/opentofu/opentofu/version"
+)
+
+// FormatVersion represents the version of the json format and will be
+// incremented for any change to this format that requires changes to a
+// consuming parser.
+const FormatVersion = "1.0"
+
+// Report is the top-level representation of the detected drift.
+type Report struct {
+	FormatVersion    string `json:"format_version"`
+	TerraformVersion string `json:"terraform_version"`
+
+	// DriftDetected is true if at least one resource has drifted.
+	DriftDetected bool `json:"drift_detected"`
+
+	Resources []Resource `json:"resources"`
+}
+
+// Resource describes a single managed resource instance whose remote object
+// has changed outside of OpenTofu.
+type Resource struct {
+	Address         string `json:"address"`
+	PreviousAddress string `json:"previous_address,omitempty"`
+	ModuleAddress   string `json:"module_address,omitempty"`
+	Type            string `json:"type"`
+	Name            string `json:"name"`
+	ProviderName    string `json:"provider_name"`
+
+	// Action is "update" if the remote object has changed, or "delete" if it
+	// no longer exists.
+	Action string `json:"action"`
+
+	// Attributes are the individual attributes of the remote object that
+	// have changed. This is empty if the remote object no longer exists.
+	Attributes []Attribute `json:"attributes"`
+}
+
+// Attribute describes a change to a single attribute of a remote object.
+type Attribute struct {
+	// Path is the path to the attribute within the object, as a sequence of
+	// attribute names, map keys and list indices.
+	Path []interface{} `json:"path"`
+
+	// Before and After are the values of the attribute as recorded in the
+	// state and as read from the remote object, respectively. They are
+	// omitted if the attribute is sensitive.
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// NewReport builds a report from the drifted resources of the given plan,
+// which should be created in refresh-only mode.
+//
+// If respectIgnoreChanges is set, changes to the attributes listed in the
+// ignore_changes lifecycle argument of each resource are left out of the
+// report, and resources without any other changes are omitted.
+func NewReport(plan *plans.Plan, config *configs.Config, schemas *tofu.Schemas, respectIgnoreChanges bool) (*Report, error) {
+	report := &Report{
+		FormatVersion:    FormatVersion,
+		TerraformVersion: version.String(),
+		Resources:        []Resource{},
+	}
+
+	for _, rc := range plan.DriftedResources {
+		addr := rc.Addr
+		if addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+			continue
+		}
+		// Changes that only move resources to new addresses are not drift.
+		if rc.Action != plans.Update && rc.Action != plans.Delete {
+			continue
+		}
+
+		schema, _ := schemas.ResourceTypeConfig(
+			rc.ProviderAddr.Provider,
+			addr.Resource.Resource.Mode,
+			addr.Resource.Resource.Type,
+		)
+		if schema == nil {
+			return nil, fmt.Errorf("no schema found for %s (in provider %s)", addr, rc.ProviderAddr.Provider)
+		}
+		change, err := rc.Decode(schema.ImpliedType())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode change for %s: %w", addr, err)
+		}
+
+		var ignore []cty.Path
+		ignoreAll := false
+		if respectIgnoreChanges {
+			ignore, ignoreAll = ignoreChanges(config, addr)
+		}
+
+		r := Resource{
+			Address:      addr.String(),
+			Type:         addr.Resource.Resource.Type,
+			Name:         addr.Resource.Resource.Name,
+			ProviderName: rc.ProviderAddr.Provider.String(),
+			Action:       "delete",
+			Attributes:   []Attribute{},
+		}
+		if !addr.Module.IsRoot() {
+			r.ModuleAddress = addr.Module.String()
+		}
+		if !rc.PrevRunAddr.Equal(addr) {
+			r.PreviousAddress = rc.PrevRunAddr.String()
+		}
+
+		if rc.Action == plans.Update {
+			if ignoreAll {
+				continue
+			}
+			r.Action = "update"
+
+			before, beforeMarks := change.Before.UnmarkDeepWithPaths()
+			after, afterMarks := change.After.UnmarkDeepWithPaths()
+			sensitive := append(beforeMarks, afterMarks...)
+			sensitive = append(sensitive, schema.ValueMarks(before, nil)...)
+			sensitive = append(sensitive, schema.ValueMarks(after, nil)...)
+
+			var diffErr error
+			diffValues(nil, before, after, func(path cty.Path, before, after cty.Value) {
+				if diffErr != nil || pathIgnored(path, ignore) {
+					return
+				}
+				attr, err := newAttribute(path, before, after, pathSensitive(path, sensitive))
+				if err != nil {
+					diffErr = fmt.Errorf("failed to encode change for %s: %w", addr, err)
+					return
+				}
+				r.Attributes = append(r.Attributes, attr)
+			})
+			if diffErr != nil {
+				return nil, diffErr
+			}
+			if len(r.Attributes) == 0 {
+				continue
+			}
+		}
+
+		report.Resources = append(report.Resources, r)
+	}
+
+	sort.Slice(report.Resources, func(i, j int) bool {
+		return report.Resources[i].Address < report.Resources[j].Address
+	})
+	report.DriftDetected = len(report.Resources) > 0
+
+	return report, nil
+}
+
+// diffValues calls emit for each of the innermost values that differ between
+// before and after, descending into objects, maps and lists of the same
+// length.
+func diffValues(path cty.Path, before, after cty.Value, emit func(path cty.Path, before, after cty.Value)) {
+	if before.RawEquals(after) {
+		return
+	}
+	if !before.IsKnown() || !after.IsKnown() || before.IsNull() || after.IsNull() || !before.Type().Equals(after.Type()) {
+		emit(path, before, after)
+		return
+	}
+
+	ty := before.Type()
+	switch {
+	case ty.IsObjectType():
+		names := make([]string, 0, len(ty.AttributeTypes()))
+		for name := range ty.AttributeTypes() {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			diffValues(path.GetAttr(name), before.GetAttr(name), after.GetAttr(name), emit)
+		}
+	case ty.IsMapType():
+		keys := make(map[string]struct{})
+		for _, v := range []cty.Value{before, after} {
+			for it := v.ElementIterator(); it.Next(); {
+				k, _ := it.Element()
+				keys[k.AsString()] = struct{}{}
+			}
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			key := cty.StringVal(k)
+			b, a := cty.NullVal(ty.ElementType()), cty.NullVal(ty.ElementType())
+			if before.HasIndex(key).True() {
+				b = before.Index(key)
+			}
+			if after.HasIndex(key).True() {
+				a = after.Index(key)
+			}
+			diffValues(path.Index(key), b, a, emit)
+		}
+	case (ty.IsListType() || ty.IsTupleType()) && before.LengthInt() == after.LengthInt():
+		for i := 0; i < before.LengthInt(); i++ {
+			idx := cty.NumberIntVal(int64(i))
+			diffValues(path.Index(idx), before.Index(idx), after.Index(idx), emit)
+		}
+	default:
+		emit(path, before, after)
+	}
+}
+
+func newAttribute(path cty.Path, before, after cty.Value, sensitive bool) (Attribute, error) {
+	attr := Attribute{
+		Path:      encodePath(path),
+		Sensitive: sensitive,
+	}
+	if sensitive {
+		return attr, nil
+	}
+
+	var err error
+	if attr.Before, err = encodeValue(before); err != nil {
+		return attr, err
+	}
+	if attr.After, err = encodeValue(after); err != nil {
+		return attr, err
+	}
+	return attr, nil
+}
+
+func encodeValue(v cty.Value) (json.RawMessage, error) {
+	if !v.IsWhollyKnown() {
+		return json.RawMessage("null"), nil
+	}
+	return ctyjson.Marshal(v, v.Type())
+}
+
+func encodePath(path cty.Path) []interface{} {
+	steps := make([]interface{}, 0, len(path))
+	for _, step := range path {
+		switch s := step.(type) {
+		case cty.GetAttrStep:
+			steps = append(steps, s.Name)
+		case cty.IndexStep:
+			if s.Key.Type() == cty.Number {
+				i, _ := s.Key.AsBigFloat().Int64()
+				steps = append(steps, i)
+			} else {
+				steps = append(steps, s.Key.AsString())
+			}
+		}
+	}
+	return steps
+}
+
+// pathSensitive returns true if the value at the given path is sensitive or
+// contains sensitive values.
+func pathSensitive(path cty.Path, pvms []cty.PathValueMarks) bool {
+	for _, pvm := range pvms {
+		if _, ok := pvm.Marks[marks.Sensitive]; !ok {
+			continue
+		}
+		if path.HasPrefix(pvm.Path) || pvm.Path.HasPrefix(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoreChanges returns the paths listed in the ignore_changes lifecycle
+// argument of the configuration of the given resource instance, and whether
+// all changes are ignored.
+func ignoreChanges(config *configs.Config, addr addrs.AbsResourceInstance) ([]cty.Path, bool) {
+	if config == nil {
+		return nil, false
+	}
+	modCfg := config.DescendentForInstance(addr.Module)
+	if modCfg == nil {
+		return nil, false
+	}
+	rc := modCfg.Module.ResourceByAddr(addr.Resource.Resource)
+	if rc == nil || rc.Managed == nil {
+		return nil, false
+	}
+
+	paths := make([]cty.Path, 0, len(rc.Managed.IgnoreChanges))
+	for _, traversal := range rc.Managed.IgnoreChanges {
+		paths = append(paths, traversalToPath(traversal))
+	}
+	return paths, rc.Managed.IgnoreAllChanges
+}
+
+func traversalToPath(traversal hcl.Traversal) cty.Path {
+	path := make(cty.Path, 0, len(traversal))
+	for _, step := range traversal {
+		switch ts := step.(type) {
+		case hcl.TraverseRoot:
+			path = path.GetAttr(ts.Name)
+		case hcl.TraverseAttr:
+			path = path.GetAttr(ts.Name)
+		case hcl.TraverseIndex:
+			path = path.Index(ts.Key)
+		}
+	}
+	return path
+}
+
+// pathIgnored returns true if the given path is within any of the ignored
+// paths.
+//
+// Map elements can be referred to by either attribute or index syntax in
+// ignore_changes, so attribute names and string keys are treated alike.
+func pathIgnored(path cty.Path, ignore []cty.Path) bool {
+Ignored:
+	for _, prefix := range ignore {
+		if len(prefix) > len(path) {
+			continue
+		}
+		for i, step := range prefix {
+			if !stepKey(step).RawEquals(stepKey(path[i])) {
+				continue Ignored
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func stepKey(step cty.PathStep) cty.Value {
+	switch s := step.(type) {
+	case cty.GetAttrStep:
+		return cty.StringVal(s.Name)
+	case cty.IndexStep:
+		return s.Key
+	default:
+		return cty.NilVal
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package jsondrift
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func TestNewReport(t *testing.T) {
+	plan := &plans.Plan{
+		UIMode: plans.RefreshOnlyMode,
+		DriftedResources: []*plans.ResourceInstanceChangeSrc{
+			testChange(t, "a", plans.Update,
+				cty.ObjectVal(map[string]cty.Value{
+					"id":       cty.StringVal("a"),
+					"size":     cty.NumberIntVal(1),
+					"password": cty.StringVal("secret"),
+					"tags": cty.MapVal(map[string]cty.Value{
+						"Name":  cty.StringVal("a"),
+						"Owner": cty.StringVal("alice"),
+					}),
+					"ports": cty.ListVal([]cty.Value{cty.NumberIntVal(80), cty.NumberIntVal(443)}),
+				}),
+				cty.ObjectVal(map[string]cty.Value{
+					"id":       cty.StringVal("a"),
+					"size":     cty.NumberIntVal(2),
+					"password": cty.StringVal("hunter2"),
+					"tags": cty.MapVal(map[string]cty.Value{
+						"Name":  cty.StringVal("a"),
+						"Owner": cty.StringVal("bob"),
+						"Env":   cty.StringVal("prod"),
+					}),
+					"ports": cty.ListVal([]cty.Value{cty.NumberIntVal(80), cty.NumberIntVal(8443)}),
+				}),
+			),
+			testChange(t, "b", plans.Delete,
+				cty.ObjectVal(map[string]cty.Value{
+					"id":       cty.StringVal("b"),
+					"size":     cty.NumberIntVal(1),
+					"password": cty.NullVal(cty.String),
+					"tags":     cty.NullVal(cty.Map(cty.String)),
+					"ports":    cty.NullVal(cty.List(cty.Number)),
+				}),
+				cty.NullVal(testSchema().ImpliedType()),
+			),
+		},
+	}
+
+	report, err := NewReport(plan, nil, testSchemas(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Report{
+		FormatVersion:    FormatVersion,
+		TerraformVersion: report.TerraformVersion,
+		DriftDetected:    true,
+		Resources: []Resource{
+			{
+				Address:      "test_thing.a",
+				Type:         "test_thing",
+				Name:         "a",
+				ProviderName: "registry.opentofu.org/hashicorp/test",
+				Action:       "update",
+				Attributes: []Attribute{
+					{Path: []interface{}{"password"}, Sensitive: true},
+					{Path: []interface{}{"ports", int64(1)}, Before: json.RawMessage("443"), After: json.RawMessage("8443")},
+					{Path: []interface{}{"size"}, Before: json.RawMessage("1"), After: json.RawMessage("2")},
+					{Path: []interface{}{"tags", "Env"}, Before: json.RawMessage("null"), After: json.RawMessage(`"prod"`)},
+					{Path: []interface{}{"tags", "Owner"}, Before: json.RawMessage(`"alice"`), After: json.RawMessage(`"bob"`)},
+				},
+			},
+			{
+				Address:      "test_thing.b",
+				Type:         "test_thing",
+				Name:         "b",
+				ProviderName: "registry.opentofu.org/hashicorp/test",
+				Action:       "delete",
+				Attributes:   []Attribute{},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, report); diff != "" {
+		t.Fatalf("wrong report\n%s", diff)
+	}
+}
+
+func TestNewReport_ignoreChanges(t *testing.T) {
+	before := cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.StringVal("a"),
+		"size":     cty.NumberIntVal(1),
+		"password": cty.NullVal(cty.String),
+		"tags":     cty.MapVal(map[string]cty.Value{"Owner": cty.StringVal("alice")}),
+		"ports":    cty.NullVal(cty.List(cty.Number)),
+	})
+	after := cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.StringVal("a"),
+		"size":     cty.NumberIntVal(2),
+		"password": cty.NullVal(cty.String),
+		"tags":     cty.MapVal(map[string]cty.Value{"Owner": cty.StringVal("bob")}),
+		"ports":    cty.NullVal(cty.List(cty.Number)),
+	})
+	plan := &plans.Plan{
+		UIMode: plans.RefreshOnlyMode,
+		DriftedResources: []*plans.ResourceInstanceChangeSrc{
+			testChange(t, "a", plans.Update, before, after),
+			testChange(t, "b", plans.Update, before, after),
+			testChange(t, "c", plans.Update, before, after),
+		},
+	}
+
+	config := configs.NewEmptyConfig()
+	config.Module = &configs.Module{
+		ManagedResources: map[string]*configs.Resource{
+			"test_thing.a": testResourceConfig("a", &configs.ManagedResource{
+				IgnoreChanges: []hcl.Traversal{
+					{hcl.TraverseRoot{Name: "tags"}, hcl.TraverseIndex{Key: cty.StringVal("Owner")}},
+				},
+			}),
+			"test_thing.b": testResourceConfig("b", &configs.ManagedResource{
+				IgnoreChanges: []hcl.Traversal{
+					{hcl.TraverseRoot{Name: "size"}},
+					{hcl.TraverseRoot{Name: "tags"}, hcl.TraverseAttr{Name: "Owner"}},
+				},
+			}),
+			"test_thing.c": testResourceConfig("c", &configs.ManagedResource{
+				IgnoreAllChanges: true,
+			}),
+		},
+	}
+
+	// Without respecting ignore_changes, all of the changes are reported.
+	report, err := NewReport(plan, config, testSchemas(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(report.Resources), 3; got != want {
+		t.Fatalf("wrong number of resources %d; want %d", got, want)
+	}
+
+	report, err = NewReport(plan, config, testSchemas(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Resource{
+		{
+			Address:      "test_thing.a",
+			Type:         "test_thing",
+			Name:         "a",
+			ProviderName: "registry.opentofu.org/hashicorp/test",
+			Action:       "update",
+			Attributes: []Attribute{
+				{Path: []interface{}{"size"}, Before: json.RawMessage("1"), After: json.RawMessage("2")},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, report.Resources); diff != "" {
+		t.Fatalf("wrong resources\n%s", diff)
+	}
+}
+
+func TestNewReport_noDrift(t *testing.T) {
+	report, err := NewReport(&plans.Plan{UIMode: plans.RefreshOnlyMode}, nil, testSchemas(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.DriftDetected {
+		t.Error("unexpected drift")
+	}
+
+	got, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"format_version":"1.0","terraform_version":"` + report.TerraformVersion + `","drift_detected":false,"resources":[]}`
+	if string(got) != want {
+		t.Errorf("wrong JSON\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func testChange(t *testing.T, name string, action plans.Action, before, after cty.Value) *plans.ResourceInstanceChangeSrc {
+	t.Helper()
+
+	addr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_thing",
+		Name: name,
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+
+	change := &plans.ResourceInstanceChange{
+		Addr:        addr,
+		PrevRunAddr: addr,
+		ProviderAddr: addrs.AbsProviderConfig{
+			Provider: addrs.NewDefaultProvider("test"),
+			Module:   addrs.RootModule,
+		},
+		Change: plans.Change{
+			Action: action,
+			Before: before,
+			After:  after,
+		},
+	}
+	src, err := change.Encode(testSchema().ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+func testResourceConfig(name string, managed *configs.ManagedResource) *configs.Resource {
+	return &configs.Resource{
+		Mode:    addrs.ManagedResourceMode,
+		Type:    "test_thing",
+		Name:    name,
+		Managed: managed,
+	}
+}
+
+func testSchema() *configschema.Block {
+	return &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"id":       {Type: cty.String, Computed: true},
+			"size":     {Type: cty.Number, Optional: true},
+			"password": {Type: cty.String, Optional: true, Sensitive: true},
+			"tags":     {Type: cty.Map(cty.String), Optional: true},
+			"ports":    {Type: cty.List(cty.Number), Optional: true},
+		},
+	}
+}
+
+func testSchemas() *tofu.Schemas {
+	return &tofu.Schemas{
+		Providers: map[addrs.Provider]providers.ProviderSchema{
+			addrs.NewDefaultProvider("test"): {
+				ResourceTypes: map[string]providers.Schema{
+					"test_thing": {Block: testSchema()},
+				},
+			},
+		},
+	}
+}
@@ -22,6 +22,7 @@ type Attribute struct {
 	Optional            bool            `json:"optional,omitempty"`
 	Computed            bool            `json:"computed,omitempty"`
 	Sensitive           bool            `json:"sensitive,omitempty"`
+	WriteOnly           bool            `json:"write_only,omitempty"`
 }
 
 type NestedType struct {
@@ -46,6 +47,7 @@ func marshalAttribute(attr *configschema.Attribute) *Attribute {
 		Optional:        attr.Optional,
 		Computed:        attr.Computed,
 		Sensitive:       attr.Sensitive,
+		WriteOnly:       attr.WriteOnly,
 		Deprecated:      attr.Deprecated,
 	}
 
@@ -38,6 +38,7 @@ import (
 	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	legacy "github.com/opentofu/opentofu/internal/legacy/tofu"
+	"github.com/opentofu/opentofu/internal/ocipush"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/provisioners"
 	"github.com/opentofu/opentofu/internal/states"
@@ -140,6 +141,14 @@ type Meta struct {
 	// unit testing.
 	ModulePackageFetcher *getmodules.PackageFetcher
 
+	// OCIRepositoryPushStore returns a store for adding content to the given
+	// repository in an OCI registry, which is used by the
+	// "tofu providers mirror -oci" and "tofu modules publish" commands.
+	//
+	// Leaving this nil means that pushing to OCI registries is not supported,
+	// which is only reasonable for unit testing.
+	OCIRepositoryPushStore func(ctx context.Context, registryDomain, repositoryName string) (ocipush.RepositoryStore, error)
+
 	// MakeRegistryHTTPClient is a function called each time a command needs
 	// an HTTP client that will be used to make requests to a module or
 	// provider registry.
@@ -275,7 +275,11 @@ func (m *Meta) loadHCLFile(filename string) (hcl.Body, tfdiags.Diagnostics) {
 // can then be relayed to the end-user. The uiModuleInstallHooks type in
 // this package has a reasonable implementation for displaying notifications
 // via a provided cli.Ui.
-func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upgrade, installErrsOnly bool, hooks initwd.ModuleInstallHooks) (abort bool, diags tfdiags.Diagnostics) {
+//
+// The packages of the remote modules are selected and verified using the
+// dependency lock file, which is then updated to record any new selections
+// unless readonlyLocks is set, in which case any change is an error.
+func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upgrade, installErrsOnly, readonlyLocks bool, hooks initwd.ModuleInstallHooks) (abort bool, diags tfdiags.Diagnostics) {
 	rootDir = m.normalizePath(rootDir)
 
 	err := os.MkdirAll(m.modulesDir(), os.ModePerm)
@@ -298,7 +302,15 @@ func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upg
 		return true, diags
 	}
 
-	_, moreDiags := inst.InstallModules(ctx, rootDir, testsDir, upgrade, installErrsOnly, hooks, call)
+	previousLocks, lockDiags := m.lockedDependencies()
+	if lockDiags.HasErrors() {
+		diags = diags.Append(lockDiags)
+		return true, diags
+	}
+	// Any warnings about the lock file are reported when installing the
+	// providers, which reads it again.
+
+	_, newLocks, moreDiags := inst.InstallModulesWithLocks(ctx, rootDir, testsDir, previousLocks, upgrade, installErrsOnly, hooks, call)
 	diags = diags.Append(moreDiags)
 
 	if ctx.Err() == context.Canceled {
@@ -307,6 +319,31 @@ func (m *Meta) installModules(ctx context.Context, rootDir, testsDir string, upg
 		return true, diags
 	}
 
+	if diags.HasErrors() || newLocks.Equal(previousLocks) {
+		return false, diags
+	}
+	if readonlyLocks {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			`Module dependency changes detected`,
+			`Changes to the selected module packages were detected, but the lock file is read-only. To use and record these selections, run "tofu init" without the "-lockfile=readonly" flag.`,
+		))
+		return true, diags
+	}
+
+	if len(previousLocks.AllModules()) == 0 {
+		m.Ui.Output(m.Colorize().Color(`
+OpenTofu has recorded the module packages it selected above in the lock file
+[bold].terraform.lock.hcl[reset], so that "tofu init" can verify that the same
+packages are installed in the future.`))
+	} else {
+		m.Ui.Output(m.Colorize().Color(`
+OpenTofu has made some changes to the module packages recorded in the
+.terraform.lock.hcl file. Review those changes and commit them to your
+version control system if they represent changes you intended to make.`))
+	}
+	diags = diags.Append(m.replaceLockedDependencies(ctx, newLocks))
+
 	return false, diags
 }
 
@@ -0,0 +1,35 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// ModulesCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type ModulesCommand struct {
+	Meta
+}
+
+func (c *ModulesCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *ModulesCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] modules <subcommand> [options] [args]
+
+  This command has subcommands for preparing module packages for
+  distribution through OCI registries.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ModulesCommand) Synopsis() string {
+	return "Package and publish modules"
+}
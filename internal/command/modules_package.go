@@ -0,0 +1,99 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/getmodules"
+	"github.com/opentofu/opentofu/internal/replacefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ModulesPackageCommand is a Command implementation that implements the
+// "tofu modules package" command, which writes a module directory to an
+// archive in the format expected for module packages in OCI registries.
+type ModulesPackageCommand struct {
+	Meta
+}
+
+func (c *ModulesPackageCommand) Synopsis() string {
+	return "Create a module package archive"
+}
+
+func (c *ModulesPackageCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("modules package")
+	var optDir string
+	cmdFlags.StringVar(&optDir, "dir", ".", "module directory")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No output file specified",
+			"The modules package command requires the path of the archive file to create as a command-line argument.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	outputPath := args[0]
+
+	// We build the archive in memory, rather than writing directly to the
+	// output file, so that the output file isn't included in its own archive
+	// if it's inside the module directory.
+	var buf bytes.Buffer
+	if err := getmodules.WriteModulePackageArchive(optDir, &buf); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to create module package",
+			fmt.Sprintf("Could not create a module package from %s: %s.", optDir, err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	if err := replacefile.AtomicWriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write module package",
+			fmt.Sprintf("Could not write the module package to %s: %s.", outputPath, err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	c.showDiagnostics(diags)
+	c.Ui.Output(fmt.Sprintf("Created module package %s.", outputPath))
+	return 0
+}
+
+func (c *ModulesPackageCommand) Help() string {
+	return `
+Usage: tofu [global options] modules package [options] <output-file>
+
+  Creates a .zip archive of a module package, suitable for pushing to an
+  OCI registry for use with "oci:" module source addresses.
+
+  Files are excluded from the package if they match the rules in a
+  .tofuignore file in the root of the module directory, or in a
+  .terraformignore file if there is no .tofuignore file. The .git and
+  .terraform directories are always excluded unless re-included by a rule.
+
+  Use "tofu modules publish" to create a package and push it to an OCI
+  registry in a single step.
+
+Options:
+
+  -dir=path  The directory containing the module package to archive.
+             Defaults to the current working directory.
+`
+}
@@ -0,0 +1,85 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestModulesPackage(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		srcDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(srcDir, "main.tf"), []byte(`variable "foo" {}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(srcDir, ".tofuignore"), []byte("*.zip\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// The output file is inside the module directory, and so would be
+		// included in a subsequent package if not for the ignore file.
+		outputPath := filepath.Join(srcDir, "package.zip")
+
+		ui := new(cli.MockUi)
+		c := &ModulesPackageCommand{
+			Meta: Meta{Ui: ui},
+		}
+		for range 2 {
+			code := c.Run([]string{"-dir=" + srcDir, outputPath})
+			if code != 0 {
+				t.Fatalf("wrong exit code. expected 0, got %d\n%s", code, ui.ErrorWriter.String())
+			}
+		}
+
+		zr, err := zip.OpenReader(outputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zr.Close()
+		var got []string
+		for _, f := range zr.File {
+			got = append(got, f.Name)
+		}
+		if want := []string{".tofuignore", "main.tf"}; strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("wrong archive content %q; want %q", got, want)
+		}
+	})
+
+	t.Run("missing arg error", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		c := &ModulesPackageCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+
+		got := ui.ErrorWriter.String()
+		if !strings.Contains(got, "Error: No output file specified") {
+			t.Fatalf("missing output file error from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("missing directory error", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		c := &ModulesPackageCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{"-dir=" + filepath.Join(t.TempDir(), "nonexist"), filepath.Join(t.TempDir(), "package.zip")})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+
+		got := ui.ErrorWriter.String()
+		if !strings.Contains(got, "Error: Failed to create module package") {
+			t.Fatalf("missing package error from output, got:\n%s\n", got)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/opentofu/opentofu/internal/command/cliconfig/ociauthconfig"
+	"github.com/opentofu/opentofu/internal/getmodules"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ModulesPublishCommand is a Command implementation that implements the
+// "tofu modules publish" command, which packages a module directory and
+// pushes it to a repository in an OCI registry.
+type ModulesPublishCommand struct {
+	Meta
+}
+
+func (c *ModulesPublishCommand) Synopsis() string {
+	return "Push a module package to an OCI registry"
+}
+
+func (c *ModulesPublishCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("modules publish")
+	var optDir, optTag string
+	cmdFlags.StringVar(&optDir, "dir", ".", "module directory")
+	cmdFlags.StringVar(&optTag, "tag", "latest", "tag name")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No repository address specified",
+			"The modules publish command requires the address of an OCI repository as a command-line argument, such as example.com/modules/foo.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	registryDomain, repositoryName, err := ociauthconfig.ParseRepositoryAddressPrefix(args[0])
+	if err == nil && repositoryName == "" {
+		err = fmt.Errorf("repository name is required")
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid OCI repository address",
+			fmt.Sprintf("The string %q is not a valid OCI repository address: %s.", args[0], err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// The package archive is written to a temporary directory outside of the
+	// module directory, so that it isn't included in its own archive.
+	tempDir, err := os.MkdirTemp("", "tofu-modules-publish")
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to create temporary directory",
+			fmt.Sprintf("Could not create a temporary directory for the module package: %s.", err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	defer os.RemoveAll(tempDir)
+	archivePath := filepath.Join(tempDir, "package.zip")
+	if err := writeModulePackageArchiveFile(optDir, archivePath); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to create module package",
+			fmt.Sprintf("Could not create a module package from %s: %s.", optDir, err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	ctx, done := c.InterruptibleContext(c.CommandContext())
+	defer done()
+
+	digest, err := c.pushOCIModulePackage(ctx, registryDomain, repositoryName, archivePath, optTag)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot push module package",
+			fmt.Sprintf("Failed to push the module package to the OCI repository %s/%s: %s.", registryDomain, repositoryName, err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	c.showDiagnostics(diags)
+	c.Ui.Output(fmt.Sprintf(
+		"Published module package to %s/%s:%s with digest %s.\n\nTo use exactly this package, use the following module source address:\n  oci://%s/%s?digest=%s",
+		registryDomain, repositoryName, optTag, digest,
+		registryDomain, repositoryName, digest,
+	))
+	return 0
+}
+
+func (c *ModulesPublishCommand) pushOCIModulePackage(ctx context.Context, registryDomain, repositoryName, archivePath, tagName string) (string, error) {
+	if c.OCIRepositoryPushStore == nil {
+		// Should not get here in normal use, because package main always
+		// sets this field.
+		return "", fmt.Errorf("pushing to OCI registries is not available in this context")
+	}
+	store, err := c.OCIRepositoryPushStore(ctx, registryDomain, repositoryName)
+	if err != nil {
+		return "", err
+	}
+	desc, err := getmodules.PushOCIModulePackage(ctx, store, archivePath, tagName)
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
+
+func writeModulePackageArchiveFile(srcDir, archivePath string) error {
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	if err := getmodules.WriteModulePackageArchive(srcDir, f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (c *ModulesPublishCommand) Help() string {
+	return `
+Usage: tofu [global options] modules publish [options] <repository>
+
+  Creates a module package from a module directory and pushes it to a
+  repository in an OCI registry, such as "example.com/modules/foo", so
+  that it can be used with "oci:" module source addresses.
+
+  The package is created in the same way as for "tofu modules package",
+  and is pushed using the OCI registry credentials from the CLI
+  configuration.
+
+Options:
+
+  -dir=path  The directory containing the module package to publish.
+             Defaults to the current working directory.
+
+  -tag=name  The tag to associate with the pushed package, replacing any
+             existing association for that tag. Defaults to "latest".
+`
+}
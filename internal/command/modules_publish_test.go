@@ -0,0 +1,93 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	orasOCI "oras.land/oras-go/v2/content/oci"
+
+	"github.com/opentofu/opentofu/internal/ocipush"
+)
+
+func TestModulesPublish(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		srcDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(srcDir, "main.tf"), []byte(`variable "foo" {}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// A local-filesystem-based repository is a stand-in for a remote
+		// registry.
+		store, err := orasOCI.NewWithContext(t.Context(), t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ui := new(cli.MockUi)
+		c := &ModulesPublishCommand{
+			Meta: Meta{
+				Ui: ui,
+				OCIRepositoryPushStore: func(ctx context.Context, registryDomain, repositoryName string) (ocipush.RepositoryStore, error) {
+					if registryDomain != "example.com" || repositoryName != "modules/foo" {
+						return nil, fmt.Errorf("unexpected repository %s/%s", registryDomain, repositoryName)
+					}
+					return store, nil
+				},
+			},
+		}
+		code := c.Run([]string{"-dir=" + srcDir, "-tag=v1.0.0", "example.com/modules/foo"})
+		if code != 0 {
+			t.Fatalf("wrong exit code. expected 0, got %d\n%s", code, ui.ErrorWriter.String())
+		}
+
+		desc, err := store.Resolve(t.Context(), "v1.0.0")
+		if err != nil {
+			t.Fatalf("tag was not created: %s", err)
+		}
+		got := ui.OutputWriter.String()
+		if want := "oci://example.com/modules/foo?digest=" + desc.Digest.String(); !strings.Contains(got, want) {
+			t.Errorf("output does not include the source address %q, got:\n%s", want, got)
+		}
+	})
+
+	t.Run("missing arg error", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		c := &ModulesPublishCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+
+		got := ui.ErrorWriter.String()
+		if !strings.Contains(got, "Error: No repository address specified") {
+			t.Fatalf("missing repository error from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("invalid repository error", func(t *testing.T) {
+		for _, addr := range []string{"example.com", "example.com/Invalid:Repository", "example.com/modules/foo:latest"} {
+			ui := new(cli.MockUi)
+			c := &ModulesPublishCommand{
+				Meta: Meta{Ui: ui},
+			}
+			code := c.Run([]string{addr})
+			if code != 1 {
+				t.Fatalf("wrong exit code for %q. expected 1, got %d", addr, code)
+			}
+
+			got := ui.ErrorWriter.String()
+			if !strings.Contains(got, "Error: Invalid OCI repository address") {
+				t.Fatalf("missing invalid repository error for %q from output, got:\n%s\n", addr, got)
+			}
+		}
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -40,6 +41,7 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	args, diags := arguments.ParsePlan(rawArgs)
 
 	c.View.SetShowSensitive(args.ShowSensitive)
+	c.View.SetPlanSummary(args.Summary)
 
 	// Instantiate the view, even if there are flag errors, so that we render
 	// diagnostics according to the desired view
@@ -96,6 +98,13 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	// Build the operation request
 	opReq, opDiags := c.OperationRequest(ctx, be, view, args.ViewType, args.Operation, args.OutPath, args.GenerateConfigPath, enc)
 	diags = diags.Append(opDiags)
+
+	if opReq != nil {
+		// Load the policies to evaluate against the plan
+		var policyDiags tfdiags.Diagnostics
+		opReq.Policies, policyDiags = policy.LoadPaths(args.PolicyPaths)
+		diags = diags.Append(policyDiags)
+	}
 	if diags.HasErrors() {
 		view.Diagnostics(diags)
 		return 1
@@ -318,6 +327,12 @@ Other Options:
   -parallelism=n               Limit the number of concurrent operations.
                                Defaults to 10.
 
+  -policy=path                 Evaluate the policies in the given policy file,
+                               or in the .tfpolicy.hcl files of the given
+                               directory, against the plan. A failed mandatory
+                               policy causes planning to fail. Use this option
+                               more than once to include more than one path.
+
   -state=statefile             A legacy option used for the local backend only.
                                Refer to the local backend's documentation for
                                more information.
@@ -325,6 +340,11 @@ Other Options:
   -show-sensitive              If specified, sensitive values will not be
                                redacted in te UI output.
 
+  -summary=module              After the plan, show a table counting the
+                               planned changes by module instance and
+                               provider, including the reasons for any
+                               replacements.
+
   -json                        Produce output in a machine-readable JSON
                                format, suitable for use in text editor
                                integrations and other automated systems.
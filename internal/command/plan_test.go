@@ -762,6 +762,138 @@ func TestPlan_validate(t *testing.T) {
 	}
 }
 
+func TestPlan_policyAdvisory(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-policy"), td)
+	t.Chdir(td)
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-policy", "policies/advisory.tfpolicy.hcl",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	if got, want := output.Stdout(), "0 passed, 1 failed."; !strings.Contains(got, want) {
+		t.Errorf("wrong output\ngot:\n%s\nwant substring: %s", got, want)
+	}
+	if got, want := output.All(), `Policy "prefer_baz" failed`; !strings.Contains(got, want) {
+		t.Errorf("missing policy warning\ngot:\n%s\nwant substring: %s", got, want)
+	}
+}
+
+func TestPlan_policyMandatory(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-policy"), td)
+	t.Chdir(td)
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-policy", "policies",
+		"-out", "tfplan",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected status code 1, got %d\n\n%s", code, output.Stdout())
+	}
+
+	if got, want := output.Stdout(), "0 passed, 2 failed."; !strings.Contains(got, want) {
+		t.Errorf("wrong output\ngot:\n%s\nwant substring: %s", got, want)
+	}
+	if got, want := output.Stderr(), "Instances must not use the bar image."; !strings.Contains(got, want) {
+		t.Errorf("missing policy error\ngot:\n%s\nwant substring: %s", got, want)
+	}
+
+	// A plan that failed a mandatory policy must not be saved, because
+	// it could then be applied.
+	if _, err := os.Stat("tfplan"); !os.IsNotExist(err) {
+		t.Errorf("plan file was written for a plan that failed a mandatory policy")
+	}
+	if got, notWant := output.Stdout(), "tofu apply"; strings.Contains(got, notWant) {
+		t.Errorf("output suggests applying a plan that failed a mandatory policy\ngot:\n%s", got)
+	}
+}
+
+func TestPlan_policyInvalidPath(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-policy"), td)
+	t.Chdir(td)
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-policy", "nonexistent",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected status code 1, got %d\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Failed to read policy path"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:\n%s\nwant substring: %s", got, want)
+	}
+}
+
+func TestPlan_summaryModule(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	t.Chdir(td)
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-summary=module",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	for _, want := range []string{
+		"Changes by module:",
+		"(root)  hashicorp/test  1       0       0        0       0       0",
+	} {
+		if got := output.Stdout(); !strings.Contains(got, want) {
+			t.Errorf("wrong output\ngot:\n%s\nwant substring: %s", got, want)
+		}
+	}
+}
+
 func TestPlan_vars(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
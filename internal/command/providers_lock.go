@@ -139,7 +139,7 @@ func (c *ProvidersLockCommand) Run(args []string) int {
 		// this client is not suitable for the HTTP mirror source, so we
 		// don't use this client directly.
 		httpTimeout := c.registryHTTPClient(ctx).HTTPClient.Timeout
-		source = getproviders.NewHTTPMirrorSource(u, c.Services.CredentialsSource(), httpTimeout)
+		source = getproviders.NewHTTPMirrorSource(u, c.Services.CredentialsSource(), httpTimeout, nil)
 	default:
 		// With no special options we consult upstream registries directly,
 		// because that gives us the most information to produce as complete
@@ -6,15 +6,19 @@
 package command
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 
 	"github.com/apparentlymart/go-versions/versions"
 	"github.com/hashicorp/go-getter"
 
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/cliconfig/ociauthconfig"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	"github.com/opentofu/opentofu/internal/httpclient"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -37,7 +41,9 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	cmdFlags := c.Meta.defaultFlagSet("providers mirror")
 	c.Meta.varFlagSet(cmdFlags)
 	var optPlatforms FlagStringSlice
+	var optOCI string
 	cmdFlags.Var(&optPlatforms, "platform", "target platform")
+	cmdFlags.StringVar(&optOCI, "oci", "", "OCI repository address prefix")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -47,7 +53,29 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	var diags tfdiags.Diagnostics
 
 	args = cmdFlags.Args()
-	if len(args) != 1 {
+	var ociRegistryDomain, ociRepositoryPrefix string
+	if optOCI != "" {
+		if len(args) != 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Unexpected output directory",
+				"The providers mirror command does not accept an output directory when pushing to an OCI registry with the -oci option.",
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+		var err error
+		ociRegistryDomain, ociRepositoryPrefix, err = ociauthconfig.ParseRepositoryAddressPrefix(optOCI)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid OCI repository address",
+				fmt.Sprintf("The string %q given in the -oci option is not a valid OCI repository address prefix: %s.", optOCI, err),
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+	} else if len(args) != 1 {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
 			"No output directory specified",
@@ -56,7 +84,27 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 		c.showDiagnostics(diags)
 		return 1
 	}
-	outputDir := args[0]
+
+	var outputDir string
+	if optOCI != "" {
+		// When pushing to an OCI registry we download the packages into a
+		// temporary directory first, so that we can authenticate them before
+		// pushing them.
+		tempDir, err := os.MkdirTemp("", "tofu-providers-mirror")
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to create temporary directory",
+				fmt.Sprintf("Could not create a temporary directory to download provider packages into: %s.", err),
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+		defer os.RemoveAll(tempDir)
+		outputDir = tempDir
+	} else {
+		outputDir = args[0]
+	}
 
 	var platforms []getproviders.Platform
 	if len(optPlatforms) == 0 {
@@ -179,6 +227,7 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 		} else {
 			c.Ui.Output(fmt.Sprintf("  - Selected v%s with no constraints", selected.String()))
 		}
+		var ociPackages []getproviders.OCIMirrorPackage
 		for _, platform := range platforms {
 			c.Ui.Output(fmt.Sprintf("  - Downloading package for %s...", platform.String()))
 			meta, err := source.PackageMeta(ctx, provider, selected, platform)
@@ -230,8 +279,9 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 				))
 				continue
 			}
+			var authResult *getproviders.PackageAuthenticationResult
 			if meta.Authentication != nil {
-				result, err := meta.Authentication.AuthenticatePackage(getproviders.PackageLocalArchive(stagingPath))
+				authResult, err = meta.Authentication.AuthenticatePackage(getproviders.PackageLocalArchive(stagingPath))
 				if err != nil {
 					diags = diags.Append(tfdiags.Sourceless(
 						tfdiags.Error,
@@ -240,7 +290,7 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 					))
 					continue
 				}
-				c.Ui.Output(fmt.Sprintf("  - Package authenticated: %s", result))
+				c.Ui.Output(fmt.Sprintf("  - Package authenticated: %s", authResult))
 			}
 			os.Remove(targetPath) // okay if it fails because we're going to try to rename over it next anyway
 			err = os.Rename(stagingPath, targetPath)
@@ -252,7 +302,46 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 				))
 				continue
 			}
+			if optOCI != "" {
+				pkg := getproviders.NewOCIMirrorPackage(meta, getproviders.PackageLocalArchive(targetPath))
+				if pkg.SigstoreBundle == nil {
+					pkg.SigstoreBundle, err = getproviders.FetchSigstoreBundle(ctx, httpGetter.Client, urlObj)
+					if err != nil {
+						diags = diags.Append(tfdiags.Sourceless(
+							tfdiags.Error,
+							"Cannot download provider release",
+							fmt.Sprintf("Failed to download the Sigstore bundle for %s v%s for %s: %s.", provider.String(), selected.String(), platform.String(), err),
+						))
+						continue
+					}
+				}
+				ociPackages = append(ociPackages, pkg)
+			}
+		}
+
+		// Pushing a provider version replaces its existing tag, so we push
+		// only if we obtained the packages for all of the requested platforms.
+		if optOCI != "" && len(ociPackages) == len(platforms) {
+			repositoryName := path.Join(ociRepositoryPrefix, provider.Hostname.String(), provider.Namespace, provider.Type)
+			c.Ui.Output(fmt.Sprintf("  - Pushing v%s to %s/%s...", selected.String(), ociRegistryDomain, repositoryName))
+			if err := c.pushOCIProviderMirrorVersion(ctx, ociRegistryDomain, repositoryName, provider, selected, ociPackages); err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Cannot push provider release",
+					fmt.Sprintf("Failed to push %s v%s to the OCI repository %s/%s: %s.", provider.String(), selected.String(), ociRegistryDomain, repositoryName, err),
+				))
+			}
+		}
+	}
+
+	if optOCI != "" {
+		// The JSON index files are only for network mirrors, and so there's
+		// nothing more to do for an OCI registry.
+		c.showDiagnostics(diags)
+		if diags.HasErrors() {
+			return 1
 		}
+		return 0
 	}
 
 	// Now we'll generate or update the JSON index files in the directory.
@@ -362,9 +451,24 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	return 0
 }
 
+func (c *ProvidersMirrorCommand) pushOCIProviderMirrorVersion(ctx context.Context, registryDomain, repositoryName string, provider addrs.Provider, version getproviders.Version, packages []getproviders.OCIMirrorPackage) error {
+	if c.OCIRepositoryPushStore == nil {
+		// Should not get here in normal use, because package main always
+		// sets this field.
+		return fmt.Errorf("pushing to OCI registries is not available in this context")
+	}
+	store, err := c.OCIRepositoryPushStore(ctx, registryDomain, repositoryName)
+	if err != nil {
+		return err
+	}
+	_, err = getproviders.PushOCIProviderMirrorVersion(ctx, store, provider, version, packages)
+	return err
+}
+
 func (c *ProvidersMirrorCommand) Help() string {
 	return `
 Usage: tofu [global options] providers mirror [options] <target-dir>
+       tofu [global options] providers mirror [options] -oci=<repository-prefix>
 
   Populates a local directory with copies of the provider plugins needed for
   the current configuration, so that the directory can be used either directly
@@ -376,8 +480,20 @@ Usage: tofu [global options] providers mirror [options] <target-dir>
   a network mirror. Those index files will be ignored if the directory is
   used instead as a local filesystem mirror.
 
+  Alternatively, the -oci option pushes the provider plugins to repositories
+  in an OCI registry instead, so that the registry can be used as an
+  OCI mirror.
+
 Options:
 
+  -oci=repo-prefix   Push the provider packages to an OCI registry rather
+                     than saving them to a directory. The value is an
+                     OCI repository address prefix, such as
+                     "example.com/opentofu-providers", and each provider is
+                     pushed to a repository whose name is the prefix
+                     followed by the provider's hostname, namespace, and
+                     type, separated by slashes.
+
   -platform=os_arch  Choose which target platform to build a mirror for.
                      By default OpenTofu will obtain plugin packages
                      suitable for the platform where you run this command.
@@ -38,4 +38,44 @@ func TestProvidersMirror(t *testing.T) {
 			t.Fatalf("missing directory error from output, got:\n%s\n", got)
 		}
 	})
+
+	t.Run("oci with output directory error", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		c := &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{"-oci=example.com/providers", "."})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+
+		got := ui.ErrorWriter.String()
+		if !strings.Contains(got, "Error: Unexpected output directory") {
+			t.Fatalf("missing output directory error from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("invalid oci repository error", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		c := &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{"-oci=example.com/Invalid:Repository"})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+
+		got := ui.ErrorWriter.String()
+		if !strings.Contains(got, "Error: Invalid OCI repository address") {
+			t.Fatalf("missing invalid repository error from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("oci noop", func(t *testing.T) {
+		c := &ProvidersMirrorCommand{}
+		code := c.Run([]string{"-oci=example.com/providers"})
+		if code != 0 {
+			t.Fatalf("wrong exit code. expected 0, got %d", code)
+		}
+	})
 }
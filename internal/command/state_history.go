@@ -0,0 +1,177 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states/remote"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// StateHistoryCommand is a Command implementation that lists the previous
+// versions of the state retained by the backend.
+type StateHistoryCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateHistoryCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("state history")
+	c.Meta.varFlagSet(cmdFlags)
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	if diags := c.Meta.checkRequiredVersion(ctx); diags != nil {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption(ctx)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(encDiags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(ctx, nil, enc.State())
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	// This is a read-only command
+	c.ignoreRemoteVersionConflict(b)
+
+	// Get the state manager for the current workspace
+	env, err := c.Workspace(ctx)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+	stateMgr, err := b.StateMgr(ctx, env)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+		return 1
+	}
+
+	versioner, ok := stateVersioner(stateMgr)
+	if !ok {
+		c.Ui.Error(errStateVersionsUnsupported)
+		return 1
+	}
+
+	versions, err := versioner.Versions()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to list state versions: %s", err))
+		return 1
+	}
+	if len(versions) == 0 {
+		c.Ui.Output("No state versions found.")
+		return 0
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSERIAL\tLINEAGE\tLAST MODIFIED\t")
+	for _, v := range versions {
+		serial, lineage := "-", "-"
+		sf, err := readStateVersion(versioner, v.ID, enc.State())
+		switch {
+		case err != nil:
+			// We still list versions that we can't decode, such as those
+			// encrypted with a key that is no longer configured.
+			c.Ui.Warn(fmt.Sprintf("Failed to read state version %s: %s", v.ID, err))
+			serial, lineage = "?", "?"
+		case sf != nil:
+			serial = fmt.Sprintf("%d", sf.Serial)
+			lineage = sf.Lineage
+		}
+
+		id := v.ID
+		if v.IsLatest {
+			id += " (latest)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", id, serial, lineage, v.LastModified.UTC().Format(time.RFC3339))
+	}
+	w.Flush()
+
+	c.Ui.Output(strings.TrimSuffix(buf.String(), "\n"))
+	return 0
+}
+
+func (c *StateHistoryCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state history [options]
+
+  List the previous versions of the state retained by the backend.
+
+  This command is only available for backends whose storage retains the
+  previous versions of the state, such as the s3, gcs and cos backends
+  with bucket versioning enabled, or the azurerm backend with snapshots
+  enabled. Each version is listed with its serial, lineage and the time
+  at which it was written, most recent first.
+
+  Any of the listed versions can be restored using "tofu state rollback".
+
+Options:
+
+  -var 'foo=bar'      Set a value for one of the input variables in the root
+                      module of the configuration. Use this option more than
+                      once to set more than one variable.
+
+  -var-file=filename  Load variable values from the given file, in addition
+                      to the default files terraform.tfvars and *.auto.tfvars.
+                      Use this option more than once to include more than one
+                      variables file.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateHistoryCommand) Synopsis() string {
+	return "List the previous versions of the state"
+}
+
+// stateVersioner returns the client of the given state manager if it can
+// retrieve previous versions of the state.
+func stateVersioner(stateMgr statemgr.Full) (remote.ClientVersioner, bool) {
+	rs, ok := stateMgr.(*remote.State)
+	if !ok {
+		return nil, false
+	}
+	versioner, ok := rs.Client.(remote.ClientVersioner)
+	return versioner, ok
+}
+
+// readStateVersion reads and decodes the state stored in the given version,
+// returning nil if that version is empty.
+func readStateVersion(versioner remote.ClientVersioner, id string, enc encryption.StateEncryption) (*statefile.File, error) {
+	payload, err := versioner.GetVersion(id)
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		return nil, nil
+	}
+	return statefile.Read(bytes.NewReader(payload.Data), enc)
+}
+
+const errStateVersionsUnsupported = `The configured backend does not retain previous versions of the state.
+
+Listing and restoring state versions requires a backend whose storage keeps
+previous versions, such as the s3, gcs or cos backends with bucket versioning
+enabled, or the azurerm backend with "snapshot" enabled.`
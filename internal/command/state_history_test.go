@@ -0,0 +1,128 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/backend/remote-state/inmem"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func TestStateHistory(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("inmem-backend"), td)
+	t.Chdir(td)
+	defer inmem.Reset()
+
+	testStateHistoryInit(t)
+	stateMgr := testStateHistoryWrite(t, "one", "two", "three")
+	lineage := stateMgr.(statemgr.PersistentMeta).StateSnapshotMeta().Lineage
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateHistoryCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	lines := strings.Split(ui.OutputWriter.String(), "\n")
+	if got, want := len(lines), 6; got != want {
+		t.Fatalf("wrong number of lines %d; want %d\n%s", got, want, ui.OutputWriter.String())
+	}
+	if !strings.HasPrefix(lines[0], "VERSION") {
+		t.Errorf("missing header:\n%s", lines[0])
+	}
+	// The first version is the empty state written when the workspace was
+	// created.
+	for i, want := range []string{"4 (latest)", "3 ", "2 "} {
+		line := lines[i+1]
+		if !strings.HasPrefix(line, want) {
+			t.Errorf("line %d should start with %q:\n%s", i+1, want, line)
+		}
+		if !strings.Contains(line, lineage) {
+			t.Errorf("line %d should contain lineage %q:\n%s", i+1, lineage, line)
+		}
+	}
+}
+
+func TestStateHistory_unsupported(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("state-pull-backend"), td)
+	t.Chdir(td)
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateHistoryCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "does not retain previous versions") {
+		t.Fatalf("wrong error:\n%s", ui.ErrorWriter.String())
+	}
+}
+
+// testStateHistoryInit initializes the inmem backend in the current
+// directory and selects a new workspace named "test". We can't use the
+// default workspace because its state is reset whenever the inmem backend
+// is configured.
+func testStateHistoryInit(t *testing.T) {
+	t.Helper()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	initCmd := &InitCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := initCmd.Run([]string{}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	ui = new(cli.MockUi)
+	newCmd := &WorkspaceNewCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := newCmd.Run([]string{"test"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+}
+
+// testStateHistoryWrite persists a separate state snapshot to the "test"
+// workspace of the inmem backend for each of the given output values.
+func testStateHistoryWrite(t *testing.T, values ...string) statemgr.Full {
+	t.Helper()
+
+	b := backend.TestBackendConfig(t, inmem.New(encryption.StateEncryptionDisabled()), nil)
+	stateMgr, err := b.StateMgr(t.Context(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range values {
+		state := states.BuildState(func(s *states.SyncState) {
+			s.SetOutputValue(
+				addrs.OutputValue{Name: "value"}.Absolute(addrs.RootModuleInstance),
+				cty.StringVal(value), false, "",
+			)
+		})
+		if err := stateMgr.WriteState(state); err != nil {
+			t.Fatal(err)
+		}
+		if err := stateMgr.PersistState(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return stateMgr
+}
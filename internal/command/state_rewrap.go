@@ -0,0 +1,155 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+// StateRewrapCommand is a Command implementation that re-encrypts the data
+// key of an envelope encrypted state under the currently-configured key
+// provider.
+type StateRewrapCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateRewrapCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("state rewrap")
+	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	args = cmdFlags.Args()
+
+	if len(args) != 0 {
+		c.Ui.Error("This command takes no arguments.\n")
+		return cli.RunResultHelp
+	}
+
+	if diags := c.Meta.checkRequiredVersion(ctx); diags != nil {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption(ctx)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(encDiags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(ctx, nil, enc.State())
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	// Determine the workspace name
+	workspace, err := c.Workspace(ctx)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+
+	// Check remote OpenTofu version is compatible
+	remoteVersionDiags := c.remoteVersionCheck(b, workspace)
+	c.showDiagnostics(remoteVersionDiags)
+	if remoteVersionDiags.HasErrors() {
+		return 1
+	}
+
+	// Get the state manager for the currently-selected workspace
+	stateMgr, err := b.StateMgr(ctx, workspace)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+		return 1
+	}
+
+	rewrapper, ok := stateMgr.(statemgr.Rewrapper)
+	if !ok {
+		c.Ui.Error(errStateRewrapUnsupported)
+		return 1
+	}
+
+	if c.stateLock {
+		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		if diags := stateLocker.Lock(stateMgr, "state-rewrap"); diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		defer func() {
+			if diags := stateLocker.Unlock(); diags.HasErrors() {
+				c.showDiagnostics(diags)
+			}
+		}()
+	}
+
+	if err := rewrapper.RewrapState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to rewrap the state data key: %s", err))
+		return 1
+	}
+
+	c.Ui.Output("Rewrapped the state data key with the configured key provider.")
+	return 0
+}
+
+func (c *StateRewrapCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state rewrap [options]
+
+  Re-encrypt the data key of an envelope encrypted state with the key
+  provider of the primary state encryption method.
+
+  When state encryption uses envelope mode, the state is encrypted with a
+  random data key and only that data key is encrypted by the configured
+  method. To change the key provider, configure the new one as the primary
+  method, with the previous one as a fallback, and run this command. Only the
+  data key is decrypted with the fallback and encrypted again, so the state
+  itself is not modified, regardless of its size.
+
+Options:
+
+  -lock=false         Don't hold a state lock during the operation. This is
+                      dangerous if others might concurrently run commands
+                      against the same workspace.
+
+  -lock-timeout=0s    Duration to retry a state lock.
+
+  -var 'foo=bar'      Set a value for one of the input variables in the root
+                      module of the configuration. Use this option more than
+                      once to set more than one variable.
+
+  -var-file=filename  Load variable values from the given file, in addition
+                      to the default files terraform.tfvars and *.auto.tfvars.
+                      Use this option more than once to include more than one
+                      variables file.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateRewrapCommand) Synopsis() string {
+	return "Re-encrypt the data key of an envelope encrypted state"
+}
+
+const errStateRewrapUnsupported = `The configured backend does not support rewrapping the state data key.
+
+Rewrapping requires a backend that stores the state as written by OpenTofu,
+such as the local backend or one of the remote state backends. Instead, you
+can run "tofu apply -refresh-only" to encrypt the whole state again with the
+primary method.`
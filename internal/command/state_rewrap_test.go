@@ -0,0 +1,145 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+const testStateRewrapOldEncryption = `
+key_provider "pbkdf2" "old" {
+	passphrase = "the old state passphrase"
+}
+method "aes_gcm" "old" {
+	keys = key_provider.pbkdf2.old
+}
+state {
+	envelope = true
+	method   = method.aes_gcm.old
+}
+`
+
+const testStateRewrapNewEncryption = `
+key_provider "pbkdf2" "new" {
+	passphrase = "the new state passphrase"
+}
+method "aes_gcm" "new" {
+	keys = key_provider.pbkdf2.new
+}
+state {
+	envelope = true
+	method   = method.aes_gcm.new
+}
+`
+
+func testStateRewrapEncryption(t *testing.T, src string) encryption.StateEncryption {
+	t.Helper()
+
+	cfg, diags := config.LoadConfigFromString("test", src)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	enc, diags := encryption.New(encryption.DefaultRegistry, cfg, configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting()))
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	return enc.State()
+}
+
+func TestStateRewrap(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	// The configuration uses the new key provider, with the old one as a
+	// fallback.
+	config := `terraform {
+  encryption {
+    key_provider "pbkdf2" "old" {
+      passphrase = "the old state passphrase"
+    }
+    key_provider "pbkdf2" "new" {
+      passphrase = "the new state passphrase"
+    }
+    method "aes_gcm" "old" {
+      keys = key_provider.pbkdf2.old
+    }
+    method "aes_gcm" "new" {
+      keys = key_provider.pbkdf2.new
+    }
+    state {
+      envelope = true
+      method   = method.aes_gcm.new
+      fallback {
+        method = method.aes_gcm.old
+      }
+    }
+  }
+}
+`
+	if err := os.WriteFile("main.tf", []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldMgr := statemgr.NewFilesystem("terraform.tfstate", testStateRewrapEncryption(t, testStateRewrapOldEncryption))
+	if err := oldMgr.WriteState(testState()); err != nil {
+		t.Fatal(err)
+	}
+	if err := oldMgr.PersistState(nil); err != nil {
+		t.Fatal(err)
+	}
+	before := oldMgr.StateSnapshotMeta()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateRewrapCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "Rewrapped the state data key"; !strings.Contains(got, want) {
+		t.Fatalf("wrong output\ngot:  %s\nwant: %s", got, want)
+	}
+
+	// The state can now be read with only the new key provider.
+	newMgr := statemgr.NewFilesystem("terraform.tfstate", testStateRewrapEncryption(t, testStateRewrapNewEncryption))
+	if err := newMgr.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+	if after := newMgr.StateSnapshotMeta(); after.Serial != before.Serial || after.Lineage != before.Lineage {
+		t.Fatalf("wrong snapshot after rewrap: %#v, want %#v", after, before)
+	}
+	if !newMgr.State().Equal(testState()) {
+		t.Fatalf("wrong state after rewrap\n%s", newMgr.State())
+	}
+}
+
+func TestStateRewrap_notEnvelope(t *testing.T) {
+	td := t.TempDir()
+	t.Chdir(td)
+
+	// Without encryption, there is no data key to rewrap.
+	testStateFileDefault(t, testState())
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateRewrapCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), "state encryption is not configured"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
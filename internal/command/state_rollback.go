@@ -0,0 +1,216 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// StateRollbackCommand is a Command implementation that restores a previous
+// version of the state retained by the backend.
+type StateRollbackCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateRollbackCommand) Run(args []string) int {
+	ctx := c.CommandContext()
+	args = c.Meta.process(args)
+	var flagForce bool
+	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("state rollback")
+	cmdFlags.BoolVar(&flagForce, "force", false, "")
+	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	args = cmdFlags.Args()
+
+	if len(args) != 1 {
+		c.Ui.Error("Exactly one argument expected.\n")
+		return cli.RunResultHelp
+	}
+	versionID := args[0]
+
+	if diags := c.Meta.checkRequiredVersion(ctx); diags != nil {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption(ctx)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(encDiags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(ctx, nil, enc.State())
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	// Determine the workspace name
+	workspace, err := c.Workspace(ctx)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+
+	// Check remote OpenTofu version is compatible
+	remoteVersionDiags := c.remoteVersionCheck(b, workspace)
+	c.showDiagnostics(remoteVersionDiags)
+	if remoteVersionDiags.HasErrors() {
+		return 1
+	}
+
+	// Get the state manager for the currently-selected workspace
+	stateMgr, err := b.StateMgr(ctx, workspace)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+		return 1
+	}
+
+	versioner, ok := stateVersioner(stateMgr)
+	if !ok {
+		c.Ui.Error(errStateVersionsUnsupported)
+		return 1
+	}
+
+	if c.stateLock {
+		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		if diags := stateLocker.Lock(stateMgr, "state-rollback"); diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		defer func() {
+			if diags := stateLocker.Unlock(); diags.HasErrors() {
+				c.showDiagnostics(diags)
+			}
+		}()
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to refresh state: %s", err))
+		return 1
+	}
+
+	srcStateFile, err := readStateVersion(versioner, versionID, enc.State())
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to read state version %s: %s", versionID, err))
+		return 1
+	}
+	if srcStateFile == nil {
+		c.Ui.Error(fmt.Sprintf("State version %s is empty and cannot be restored.", versionID))
+		return 1
+	}
+
+	srcSerial := srcStateFile.Serial
+
+	// The restored state becomes the latest snapshot of the current lineage,
+	// so unlike "tofu state push" we don't reject its older serial. We do
+	// still protect against restoring a state that belongs to an unrelated
+	// lineage, unless forced.
+	current := stateMgr.(statemgr.PersistentMeta).StateSnapshotMeta()
+	adoptLineage := current.Lineage != "" && srcStateFile.Lineage != current.Lineage
+	if adoptLineage {
+		if !flagForce {
+			c.Ui.Error(fmt.Sprintf(
+				"State version %s has lineage %q, but the current state has lineage %q.\n\n"+
+					"Use -force to restore it anyway.",
+				versionID, srcStateFile.Lineage, current.Lineage,
+			))
+			return 1
+		}
+		// Adopting the version's lineage resets the serial, so we keep the
+		// current serial to ensure that the new snapshot still supersedes
+		// the current one.
+		srcStateFile.Serial = current.Serial
+	}
+
+	// Get schemas, if possible, before writing state
+	var schemas *tofu.Schemas
+	var diags tfdiags.Diagnostics
+	if isCloudMode(b) {
+		schemas, diags = c.MaybeGetSchemas(ctx, srcStateFile.State, nil)
+	}
+
+	// Only an import can replace the lineage along with the state, so it
+	// takes the place of the usual write when adopting the version's lineage.
+	// Neither persists the state, which we do once below either way.
+	if adoptLineage {
+		err = statemgr.Import(srcStateFile, stateMgr, true)
+	} else {
+		err = stateMgr.WriteState(srcStateFile.State)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to write state: %s", err))
+		return 1
+	}
+	if err := stateMgr.PersistState(schemas); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to persist state: %s", err))
+		return 1
+	}
+
+	c.showDiagnostics(diags)
+	c.Ui.Output(fmt.Sprintf(
+		"Restored state version %s (serial %d) as serial %d.",
+		versionID, srcSerial, stateMgr.(statemgr.PersistentMeta).StateSnapshotMeta().Serial,
+	))
+	return 0
+}
+
+func (c *StateRollbackCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] state rollback [options] VERSION
+
+  Restore a previous version of the state retained by the backend.
+
+  VERSION is one of the version identifiers listed by "tofu state history".
+  The state stored in that version is written as the new latest state, with
+  a serial higher than the current one, so the versions in between are
+  retained and can themselves be restored later.
+
+  The command will protect you against restoring a version whose lineage
+  differs from the current state unless you specify the "-force" flag.
+
+Options:
+
+  -force              Restore the version even if its lineage doesn't match
+                      the current state.
+
+  -lock=false         Don't hold a state lock during the operation. This is
+                      dangerous if others might concurrently run commands
+                      against the same workspace.
+
+  -lock-timeout=0s    Duration to retry a state lock.
+
+  -var 'foo=bar'      Set a value for one of the input variables in the root
+                      module of the configuration. Use this option more than
+                      once to set more than one variable.
+
+  -var-file=filename  Load variable values from the given file, in addition
+                      to the default files terraform.tfvars and *.auto.tfvars.
+                      Use this option more than once to include more than one
+                      variables file.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateRollbackCommand) Synopsis() string {
+	return "Restore a previous version of the state"
+}
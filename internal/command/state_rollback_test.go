@@ -0,0 +1,142 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend/remote-state/inmem"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+)
+
+func TestStateRollback(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("inmem-backend"), td)
+	t.Chdir(td)
+	defer inmem.Reset()
+
+	testStateHistoryInit(t)
+	stateMgr := testStateHistoryWrite(t, "one", "two", "three")
+	before := stateMgr.(statemgr.PersistentMeta).StateSnapshotMeta()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateRollbackCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+
+	// Version 2 holds the first state we wrote, after the initial empty one.
+	if code := c.Run([]string{"2"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "Restored state version 2 (serial 2) as serial 5."; !strings.Contains(got, want) {
+		t.Fatalf("wrong output\ngot:  %s\nwant: %s", got, want)
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+	after := stateMgr.(statemgr.PersistentMeta).StateSnapshotMeta()
+	if after.Lineage != before.Lineage {
+		t.Errorf("lineage changed from %q to %q", before.Lineage, after.Lineage)
+	}
+	if after.Serial != before.Serial+1 {
+		t.Errorf("wrong serial %d; want %d", after.Serial, before.Serial+1)
+	}
+	output := stateMgr.State().OutputValue(addrs.OutputValue{Name: "value"}.Absolute(addrs.RootModuleInstance))
+	if output == nil || !output.Value.RawEquals(cty.StringVal("one")) {
+		t.Fatalf("wrong output value after rollback: %#v", output)
+	}
+}
+
+func TestStateRollback_lineageMismatch(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("inmem-backend"), td)
+	t.Chdir(td)
+	defer inmem.Reset()
+
+	testStateHistoryInit(t)
+	stateMgr := testStateHistoryWrite(t, "one")
+
+	// Replace the state with one from an unrelated lineage.
+	other := statemgr.NewStateFile()
+	other.State = states.NewState()
+	if err := statemgr.Import(other, stateMgr, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := stateMgr.PersistState(nil); err != nil {
+		t.Fatal(err)
+	}
+	lineage := stateMgr.(statemgr.PersistentMeta).StateSnapshotMeta().Lineage
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateRollbackCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run([]string{"2"}); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "Use -force to restore it anyway") {
+		t.Fatalf("wrong error:\n%s", ui.ErrorWriter.String())
+	}
+
+	versioner, _ := stateVersioner(stateMgr)
+	versionsBefore, err := versioner.Versions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ui = new(cli.MockUi)
+	c = &StateRollbackCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run([]string{"-force", "2"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	// The restored state must be persisted exactly once.
+	versionsAfter, err := versioner.Versions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(versionsAfter), len(versionsBefore)+1; got != want {
+		t.Errorf("wrong number of versions %d after rollback; want %d", got, want)
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+	if got := stateMgr.(statemgr.PersistentMeta).StateSnapshotMeta().Lineage; got == lineage {
+		t.Fatalf("lineage was not restored")
+	}
+}
+
+func TestStateRollback_unknownVersion(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("inmem-backend"), td)
+	t.Chdir(td)
+	defer inmem.Reset()
+
+	testStateHistoryInit(t)
+	testStateHistoryWrite(t, "one")
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateRollbackCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := c.Run([]string{"42"}); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "Failed to read state version 42") {
+		t.Fatalf("wrong error:\n%s", ui.ErrorWriter.String())
+	}
+}
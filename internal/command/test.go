@@ -10,9 +10,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"path"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/opentofu/opentofu/internal/lang"
@@ -24,6 +27,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/testreport"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption"
@@ -83,8 +87,17 @@ Options:
   -json                 If specified, machine readable output will be printed in
                         JSON format
 
+  -junit-xml=path       If specified, OpenTofu will also write the test results
+                        to the given file in JUnit XML format, with one test
+                        suite per test file and one test case per run block.
+
   -no-color             If specified, output won't contain any color.
 
+  -parallelism=n        Execute up to n test files concurrently. Defaults to 1.
+
+  -sarif=path           If specified, OpenTofu will also write any test failures
+                        and errors to the given file in SARIF format.
+
   -test-directory=path  Set the OpenTofu test directory, defaults to "tests". When set, the
                         test command will search for test files in the current directory and
                         in the one specified by the flag.
@@ -282,12 +295,12 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		CancelledCtx: cancelCtx,
 		StoppedCtx:   stopCtx,
 
-		// Just to be explicit, we'll set the following fields even though they
-		// default to these values.
-		Cancelled: false,
-		Stopped:   false,
-
 		Verbose: args.Verbose,
+
+		Parallelism: args.Parallelism,
+		LoadConfig: func() (*configs.Config, tfdiags.Diagnostics) {
+			return c.loadConfigWithTests(ctx, ".", args.TestDirectory)
+		},
 	}
 
 	view.Abstract(&suite)
@@ -308,7 +321,7 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		// Nice request to be cancelled.
 
 		view.Interrupted()
-		runner.Stopped = true
+		runner.Stopped.Store(true)
 		stop()
 
 		select {
@@ -317,7 +330,7 @@ func (c *TestCommand) Run(rawArgs []string) int {
 			// fast as possible.
 
 			view.FatalInterrupt()
-			runner.Cancelled = true
+			runner.Cancelled.Store(true)
 			cancel()
 
 			// We'll wait 5 seconds for this operation to finish now, regardless
@@ -334,19 +347,62 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		// tests finished normally with no interrupts.
 	}
 
-	if runner.Cancelled {
+	// We write the reports even if the test was cancelled, so that CI systems
+	// can still see which tests ran before the interruption.
+	reportDiags := c.writeTestReports(args, &suite)
+	if reportDiags.HasErrors() {
+		view.Diagnostics(nil, nil, reportDiags)
+	}
+
+	if runner.Cancelled.Load() {
 		// Don't print out the conclusion if the test was cancelled.
 		return 1
 	}
 
 	view.Conclusion(&suite)
 
-	if suite.Status != moduletest.Pass {
+	if suite.Status != moduletest.Pass || reportDiags.HasErrors() {
 		return 1
 	}
 	return 0
 }
 
+// writeTestReports writes any of the additional machine-readable reports that
+// were requested on the command line.
+func (c *TestCommand) writeTestReports(args *arguments.Test, suite *moduletest.Suite) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if args.JUnitXMLFile != "" {
+		src, err := testreport.JUnitXML(suite, c.configSources())
+		if err == nil {
+			err = os.WriteFile(args.JUnitXMLFile, src, 0644)
+		}
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write JUnit XML report",
+				fmt.Sprintf("Could not write the test results to %s: %s.", args.JUnitXMLFile, err),
+			))
+		}
+	}
+
+	if args.SARIFFile != "" {
+		src, err := testreport.SARIF(suite)
+		if err == nil {
+			err = os.WriteFile(args.SARIFFile, src, 0644)
+		}
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write SARIF report",
+				fmt.Sprintf("Could not write the test results to %s: %s.", args.SARIFFile, err),
+			))
+		}
+	}
+
+	return diags
+}
+
 // test runner
 
 type TestSuiteRunner struct {
@@ -368,8 +424,11 @@ type TestSuiteRunner struct {
 	// be left showing `pending` as the status. We will still print out the
 	// destroy summary diagnostics that tell the user what state has been left
 	// behind and needs manual clean up.
-	Stopped   bool
-	Cancelled bool
+	//
+	// They are written by the signal handler while the tests are running
+	// concurrently, so they must only be accessed atomically.
+	Stopped   atomic.Bool
+	Cancelled atomic.Bool
 
 	// StoppedCtx and CancelledCtx allow in progress OpenTofu operations to
 	// respond to external calls from the test command.
@@ -378,6 +437,16 @@ type TestSuiteRunner struct {
 
 	// Verbose tells the runner to print out plan files during each test run.
 	Verbose bool
+
+	// Parallelism is the maximum number of test files to execute
+	// concurrently. Values less than 2 execute the files one at a time.
+	Parallelism int
+
+	// LoadConfig loads a fresh copy of the configuration under test. It's
+	// only used when executing test files concurrently, so that each file
+	// can modify its own copy of the configuration.
+	LoadConfig func() (*configs.Config, tfdiags.Diagnostics)
+	configLock sync.Mutex
 }
 
 func (runner *TestSuiteRunner) Start(ctx context.Context) {
@@ -388,33 +457,62 @@ func (runner *TestSuiteRunner) Start(ctx context.Context) {
 	sort.Strings(files) // execute the files in alphabetical order
 
 	runner.Suite.Status = moduletest.Pass
+	if runner.Parallelism > 1 {
+		runner.startConcurrently(ctx, files)
+		return
+	}
+
 	for _, name := range files {
-		if runner.Cancelled {
+		if runner.Cancelled.Load() {
 			return
 		}
 
 		file := runner.Suite.Files[name]
 
-		fileRunner := &TestFileRunner{
-			Suite: runner,
-			States: map[string]*TestFileState{
-				MainStateIdentifier: {
-					Run:   nil,
-					State: states.NewState(),
-				},
-			},
-		}
-
+		fileRunner := runner.newFileRunner(runner.Config, runner.View)
 		fileRunner.ExecuteTestFile(ctx, file)
 		fileRunner.Cleanup(ctx, file)
 		runner.Suite.Status = runner.Suite.Status.Merge(file.Status)
 	}
 }
 
+func (runner *TestSuiteRunner) newFileRunner(config *configs.Config, view views.Test) *TestFileRunner {
+	return &TestFileRunner{
+		Suite:  runner,
+		Config: config,
+		View:   view,
+		States: map[string]*TestFileState{
+			MainStateIdentifier: {
+				Run:   nil,
+				State: states.NewState(),
+			},
+		},
+	}
+}
+
 type TestFileRunner struct {
 	Suite *TestSuiteRunner
 
+	// Config is the configuration under test for the run blocks of this file
+	// that don't load an alternate module. When test files are executed
+	// concurrently each file gets its own copy, since executing a run block
+	// temporarily modifies the configuration.
+	Config *configs.Config
+
+	// View receives the output for this file. When test files are executed
+	// concurrently this buffers the output until it can be printed in order.
+	View views.Test
+
 	States map[string]*TestFileState
+
+	// runViews holds the view for each run block while the run blocks of the
+	// file are executing concurrently. Use runView to find the view for the
+	// output of a particular run block.
+	runViews map[*moduletest.Run]views.Test
+
+	// statesLock guards States and the status of the file being executed,
+	// since independent run blocks may execute concurrently.
+	statesLock sync.Mutex
 }
 
 type TestFileState struct {
@@ -426,110 +524,171 @@ func (runner *TestFileRunner) ExecuteTestFile(ctx context.Context, file *modulet
 	log.Printf("[TRACE] TestFileRunner: executing test file %s", file.Name)
 
 	file.Status = file.Status.Merge(moduletest.Pass)
-	for _, run := range file.Runs {
-		if runner.Suite.Cancelled {
-			// This means a hard stop has been requested, in this case we don't
-			// even stop to mark future tests as having been skipped. They'll
-			// just show up as pending in the printed summary.
+	fileStart := time.Now()
+	defer func() {
+		file.Duration = time.Since(fileStart)
+	}()
+
+	if file.Config.Parallel {
+		if aborted := runner.executeRunsConcurrently(ctx, file); aborted || runner.Suite.Cancelled.Load() {
 			return
 		}
+	} else {
+		for _, run := range file.Runs {
+			if runner.Suite.Cancelled.Load() {
+				// This means a hard stop has been requested, in this case we don't
+				// even stop to mark future tests as having been skipped. They'll
+				// just show up as pending in the printed summary.
+				return
+			}
 
-		if runner.Suite.Stopped {
-			// Then the test was requested to be stopped, so we just mark each
-			// following test as skipped and move on.
-			run.Status = moduletest.Skip
-			continue
+			if aborted := runner.executeRun(ctx, run, file); aborted {
+				return
+			}
 		}
+	}
 
-		if file.Status == moduletest.Error {
-			// If the overall test file has errored, we don't keep trying to
-			// execute tests. Instead, we mark all remaining run blocks as
-			// skipped.
-			run.Status = moduletest.Skip
-			continue
-		}
+	runner.View.File(file)
+	for _, run := range file.Runs {
+		runner.View.Run(run, file)
+	}
+}
 
-		key := MainStateIdentifier
-		config := runner.Suite.Config
-		if run.Config.ConfigUnderTest != nil {
-			config = run.Config.ConfigUnderTest
-			// Then we need to load an alternate state and not the main one.
+// executeRun executes a single run block within the given file, updating the
+// tracked states and the status of the file accordingly.
+//
+// It returns true if the failure was severe enough that no further run blocks
+// in the file should be executed.
+func (runner *TestFileRunner) executeRun(ctx context.Context, run *moduletest.Run, file *moduletest.File) bool {
+	if runner.Suite.Stopped.Load() {
+		// Then the test was requested to be stopped, so we just mark each
+		// following test as skipped and move on.
+		run.Status = moduletest.Skip
+		return false
+	}
 
-			key = run.Config.Module.Source.String()
-			if key == MainStateIdentifier {
-				// This is bad. It means somehow the module we're loading has
-				// the same key as main state and we're about to corrupt things.
-
-				run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
-					Severity: hcl.DiagError,
-					Summary:  "Invalid module source",
-					Detail:   fmt.Sprintf("The source for the selected module evaluated to %s which should not be possible. This is a bug in OpenTofu - please report it!", key),
-					Subject:  run.Config.Module.DeclRange.Ptr(),
-				})
-
-				run.Status = moduletest.Error
-				file.Status = moduletest.Error
-				continue // Abort!
-			}
+	runner.statesLock.Lock()
+	fileStatus := file.Status
+	runner.statesLock.Unlock()
+	if fileStatus == moduletest.Error {
+		// If the overall test file has errored, we don't keep trying to
+		// execute tests. Instead, we mark all remaining run blocks as
+		// skipped.
+		run.Status = moduletest.Skip
+		return false
+	}
 
-			if _, exists := runner.States[key]; !exists {
-				runner.States[key] = &TestFileState{
-					Run:   nil,
-					State: states.NewState(),
-				}
-			}
-		}
+	key := testRunStateKey(run)
+	config := runner.Config
+	if run.Config.ConfigUnderTest != nil {
+		config = run.Config.ConfigUnderTest
+		// Then we need to load an alternate state and not the main one.
 
-		state, updatedState := runner.ExecuteTestRun(ctx, run, file, runner.States[key].State, config)
-		if updatedState {
-			var err error
-
-			// We need to simulate state serialization between multiple runs
-			// due to its side effects. One of such side effects is removal
-			// of destroyed non-root module outputs. This is not handled
-			// during graph walk since those values are not stored in the
-			// state file. This is more of a weird workaround instead of a
-			// proper fix, unfortunately.
-			state, err = simulateStateSerialization(state)
-			if err != nil {
-				run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
-					Severity: hcl.DiagError,
-					Summary:  "Failure during state serialization",
-					Detail:   err.Error(),
-				})
-
-				// We cannot reuse state later so that's a hard stop.
-				return
-			}
+		if key == MainStateIdentifier {
+			// This is bad. It means somehow the module we're loading has
+			// the same key as main state and we're about to corrupt things.
+
+			run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid module source",
+				Detail:   fmt.Sprintf("The source for the selected module evaluated to %s which should not be possible. This is a bug in OpenTofu - please report it!", key),
+				Subject:  run.Config.Module.DeclRange.Ptr(),
+			})
+
+			run.Status = moduletest.Error
+			runner.statesLock.Lock()
+			file.Status = moduletest.Error
+			runner.statesLock.Unlock()
+			return false // Abort!
+		}
+	}
 
-			// Only update the most recent run and state if the state was
-			// actually updated by this change. We want to use the run that
-			// most recently updated the tracked state as the cleanup
-			// configuration.
-			runner.States[key].State = state
-			runner.States[key].Run = run
+	runner.statesLock.Lock()
+	if _, exists := runner.States[key]; !exists {
+		runner.States[key] = &TestFileState{
+			Run:   nil,
+			State: states.NewState(),
+		}
+	}
+	state := runner.States[key].State
+	runner.statesLock.Unlock()
+
+	runStart := time.Now()
+	state, updatedState := runner.ExecuteTestRun(ctx, run, file, state, config)
+	run.Duration = time.Since(runStart)
+	if updatedState {
+		var err error
+
+		// We need to simulate state serialization between multiple runs
+		// due to its side effects. One of such side effects is removal
+		// of destroyed non-root module outputs. This is not handled
+		// during graph walk since those values are not stored in the
+		// state file. This is more of a weird workaround instead of a
+		// proper fix, unfortunately.
+		state, err = simulateStateSerialization(state)
+		if err != nil {
+			run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Failure during state serialization",
+				Detail:   err.Error(),
+			})
+
+			// We cannot reuse state later so that's a hard stop.
+			return true
 		}
 
-		file.Status = file.Status.Merge(run.Status)
+		// Only update the most recent run and state if the state was
+		// actually updated by this change. We want to use the run that
+		// most recently updated the tracked state as the cleanup
+		// configuration.
+		runner.statesLock.Lock()
+		runner.States[key].State = state
+		runner.States[key].Run = run
+		runner.statesLock.Unlock()
 	}
 
-	runner.Suite.View.File(file)
-	for _, run := range file.Runs {
-		runner.Suite.View.Run(run, file)
+	runner.statesLock.Lock()
+	file.Status = file.Status.Merge(run.Status)
+	runner.statesLock.Unlock()
+	return false
+}
+
+// testRunStateKey returns the key of the state that the given run block
+// operates on.
+func testRunStateKey(run *moduletest.Run) string {
+	if run.Config.ConfigUnderTest != nil {
+		return run.Config.Module.Source.String()
+	}
+	return MainStateIdentifier
+}
+
+// statesSnapshot returns a copy of the states tracked by the runner, which
+// can be safely read while other run blocks in the same file are executing.
+func (runner *TestFileRunner) statesSnapshot() map[string]*TestFileState {
+	runner.statesLock.Lock()
+	defer runner.statesLock.Unlock()
+
+	ret := make(map[string]*TestFileState, len(runner.States))
+	for key, state := range runner.States {
+		ret[key] = &TestFileState{
+			Run:   state.Run,
+			State: state.State,
+		}
 	}
+	return ret
 }
 
 func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletest.Run, file *moduletest.File, state *states.State, config *configs.Config) (*states.State, bool) {
 	log.Printf("[TRACE] TestFileRunner: executing run block %s/%s", file.Name, run.Name)
 
-	if runner.Suite.Cancelled {
+	if runner.Suite.Cancelled.Load() {
 		// Don't do anything, just give up and return immediately.
 		// The surrounding functions should stop this even being called, but in
 		// case of race conditions or something we can still verify this.
 		return state, false
 	}
 
-	if runner.Suite.Stopped {
+	if runner.Suite.Stopped.Load() {
 		// Basically the same as above, except we'll be a bit nicer.
 		run.Status = moduletest.Skip
 		return state, false
@@ -547,7 +706,7 @@ func (runner *TestFileRunner) ExecuteTestRun(ctx context.Context, run *moduletes
 		return state, false
 	}
 
-	evalCtx, evalDiags := buildEvalContextForProviderConfigTransform(runner.States, run, file, config, runner.Suite.GlobalVariables)
+	evalCtx, evalDiags := buildEvalContextForProviderConfigTransform(runner.statesSnapshot(), run, file, config, runner.Suite.GlobalVariables)
 	run.Diagnostics = run.Diagnostics.Append(evalDiags)
 	if evalDiags.HasErrors() {
 		run.Status = moduletest.Error
@@ -741,7 +900,7 @@ func (runner *TestFileRunner) destroy(ctx context.Context, config *configs.Confi
 
 	var diags tfdiags.Diagnostics
 
-	evalCtx, ctxDiags := getEvalContextForTest(runner.States, config, runner.Suite.GlobalVariables)
+	evalCtx, ctxDiags := getEvalContextForTest(runner.statesSnapshot(), config, runner.Suite.GlobalVariables)
 	diags = diags.Append(ctxDiags)
 
 	variables, variableDiags := buildInputVariablesForTest(run, file, config, runner.Suite.GlobalVariables, evalCtx)
@@ -807,7 +966,7 @@ func (runner *TestFileRunner) plan(ctx context.Context, config *configs.Config,
 	references, referenceDiags := run.GetReferences()
 	diags = diags.Append(referenceDiags)
 
-	evalCtx, ctxDiags := getEvalContextForTest(runner.States, config, runner.Suite.GlobalVariables)
+	evalCtx, ctxDiags := getEvalContextForTest(runner.statesSnapshot(), config, runner.Suite.GlobalVariables)
 	diags = diags.Append(ctxDiags)
 
 	variables, variableDiags := buildInputVariablesForTest(run, file, config, runner.Suite.GlobalVariables, evalCtx)
@@ -938,15 +1097,16 @@ func (runner *TestFileRunner) wait(ctx *tofu.Context, runningCtx context.Context
 	handleCancelled := func() {
 		log.Printf("[DEBUG] TestFileRunner: test execution cancelled during %s", identifier)
 
+		snapshot := runner.statesSnapshot()
 		states := make(map[*moduletest.Run]*states.State)
-		states[nil] = runner.States[MainStateIdentifier].State
-		for key, module := range runner.States {
+		states[nil] = snapshot[MainStateIdentifier].State
+		for key, module := range snapshot {
 			if key == MainStateIdentifier {
 				continue
 			}
 			states[module.Run] = module.State
 		}
-		runner.Suite.View.FatalInterruptSummary(run, file, states, created)
+		runner.runView(run).FatalInterruptSummary(run, file, states, created)
 
 		cancelled = true
 		go ctx.Stop()
@@ -990,7 +1150,7 @@ func (runner *TestFileRunner) wait(ctx *tofu.Context, runningCtx context.Context
 func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File) {
 	log.Printf("[TRACE] TestStateManager: cleaning up state for %s", file.Name)
 
-	if runner.Suite.Cancelled {
+	if runner.Suite.Cancelled.Load() {
 		// Don't try and clean anything up if the execution has been cancelled.
 		log.Printf("[DEBUG] TestStateManager: skipping state cleanup for %s due to cancellation", file.Name)
 		return
@@ -1019,7 +1179,7 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 
 			var diags tfdiags.Diagnostics
 			diags = diags.Append(tfdiags.Sourceless(tfdiags.Error, "Inconsistent state", fmt.Sprintf("Found inconsistent state while cleaning up %s. This is a bug in OpenTofu - please report it", file.Name)))
-			runner.Suite.View.DestroySummary(diags, nil, file, state.State)
+			runner.View.DestroySummary(diags, nil, file, state.State)
 			continue
 		}
 
@@ -1036,7 +1196,7 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 	for _, state := range states {
 		log.Printf("[DEBUG] TestStateManager: cleaning up state for %s/%s", file.Name, state.Run.Name)
 
-		if runner.Suite.Cancelled {
+		if runner.Suite.Cancelled.Load() {
 			// In case the cancellation came while a previous state was being
 			// destroyed.
 			log.Printf("[DEBUG] TestStateManager: skipping state cleanup for %s/%s due to cancellation", file.Name, state.Run.Name)
@@ -1048,7 +1208,7 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 
 		isMainState := state.Run.Config.Module == nil
 		if isMainState {
-			runConfig = runner.Suite.Config
+			runConfig = runner.Config
 		} else {
 			runConfig = state.Run.Config.ConfigUnderTest
 		}
@@ -1065,10 +1225,10 @@ func (runner *TestFileRunner) Cleanup(ctx context.Context, file *moduletest.File
 			updated, destroyDiags = runner.destroy(ctx, runConfig, state.State, state.Run, file)
 			diags = diags.Append(destroyDiags)
 		}
-		runner.Suite.View.DestroySummary(diags, state.Run, file, updated)
+		runner.View.DestroySummary(diags, state.Run, file, updated)
 
 		if updated.HasManagedResourceInstanceObjects() {
-			views.SaveErroredTestStateFile(updated, state.Run, file, runner.Suite.View)
+			runner.saveErroredTestStateFile(updated, state.Run, file)
 		}
 		reset()
 	}
@@ -1276,7 +1436,7 @@ func parseAndApplyDefaultValues(unparsedVariables map[string]backend.UnparsedVar
 // the config which must be called so the config can be reused going forward.
 func (runner *TestFileRunner) prepareInputVariablesForAssertions(config *configs.Config, run *moduletest.Run, file *moduletest.File, globals map[string]backend.UnparsedVariableValue) (tofu.InputValues, func(), tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
-	ctx, ctxDiags := getEvalContextForTest(runner.States, config, globals)
+	ctx, ctxDiags := getEvalContextForTest(runner.statesSnapshot(), config, globals)
 	diags = diags.Append(ctxDiags)
 
 	variables := make(map[string]backend.UnparsedVariableValue)
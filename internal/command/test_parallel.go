@@ -0,0 +1,326 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/logging"
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// startConcurrently executes the given test files with up to
+// runner.Parallelism files in progress at once.
+//
+// Each file executes against its own copy of the configuration and writes its
+// output into a buffer, which we print in the same alphabetical order used
+// for sequential execution once all the preceding files have completed. This
+// keeps the output grouped by file and deterministic.
+func (runner *TestSuiteRunner) startConcurrently(ctx context.Context, files []string) {
+	buffers := make([]*bufferedTestView, len(files))
+	done := make([]chan struct{}, len(files))
+	for i := range files {
+		buffers[i] = &bufferedTestView{Test: runner.View}
+		done[i] = make(chan struct{})
+	}
+
+	panicHandler := logging.PanicHandlerWithTraceFn()
+	go func() {
+		defer panicHandler()
+
+		sem := make(chan struct{}, runner.Parallelism)
+		for i, name := range files {
+			sem <- struct{}{}
+			go func(file *moduletest.File, view *bufferedTestView, done chan<- struct{}) {
+				defer panicHandler()
+				defer close(done)
+				defer func() { <-sem }()
+
+				if runner.Cancelled.Load() {
+					return
+				}
+
+				log.Printf("[TRACE] TestSuiteRunner: loading configuration for %s", file.Name)
+				config, diags := runner.loadConfig()
+				if diags.HasErrors() {
+					file.Diagnostics = file.Diagnostics.Append(diags)
+					file.Status = moduletest.Error
+					for _, run := range file.Runs {
+						run.Status = moduletest.Skip
+					}
+					view.File(file)
+					for _, run := range file.Runs {
+						view.Run(run, file)
+					}
+					return
+				}
+
+				fileRunner := runner.newFileRunner(config, view)
+				fileRunner.ExecuteTestFile(ctx, file)
+				fileRunner.Cleanup(ctx, file)
+			}(runner.Suite.Files[name], buffers[i], done[i])
+		}
+	}()
+
+	for i, name := range files {
+		<-done[i]
+		buffers[i].Replay(runner.View)
+		runner.Suite.Status = runner.Suite.Status.Merge(runner.Suite.Files[name].Status)
+	}
+}
+
+// loadConfig calls LoadConfig while holding a lock, since the underlying
+// configuration loader isn't safe for concurrent use.
+func (runner *TestSuiteRunner) loadConfig() (*configs.Config, tfdiags.Diagnostics) {
+	runner.configLock.Lock()
+	defer runner.configLock.Unlock()
+	return runner.LoadConfig()
+}
+
+// executeRunsConcurrently executes the run blocks within a file that opted
+// in to concurrent execution.
+//
+// A run block starts only once every earlier run block it depends on has
+// completed. A run block depends on the previous run block that uses the same
+// state, and on any run blocks whose outputs it refers to. All other run
+// blocks execute concurrently, unless the file configures its own providers.
+//
+// It returns true if the execution was aborted, in which case the caller
+// should not attempt to execute anything else within the file.
+func (runner *TestFileRunner) executeRunsConcurrently(ctx context.Context, file *moduletest.File) bool {
+	return runner.forEachRunConcurrently(file, func(run *moduletest.Run) bool {
+		return runner.executeRun(ctx, run, file)
+	})
+}
+
+// forEachRunConcurrently calls execute for each of the run blocks within the
+// given file, in the order described by testRunDependencies. If execute
+// returns true then the run blocks that haven't started yet are not executed,
+// and forEachRunConcurrently returns true.
+//
+// The output produced while executing each run block is buffered, and is
+// sent to the file's view in the order the run blocks are declared once they
+// have all completed, regardless of the order in which they completed.
+func (runner *TestFileRunner) forEachRunConcurrently(file *moduletest.File, execute func(run *moduletest.Run) bool) bool {
+	deps := testRunDependencies(file)
+
+	var aborted atomic.Bool
+	var wg sync.WaitGroup
+	done := make([]chan struct{}, len(file.Runs))
+	buffers := make([]*bufferedTestView, len(file.Runs))
+	runner.runViews = make(map[*moduletest.Run]views.Test, len(file.Runs))
+	for i, run := range file.Runs {
+		done[i] = make(chan struct{})
+		buffers[i] = &bufferedTestView{Test: runner.View}
+		runner.runViews[run] = buffers[i]
+	}
+
+	panicHandler := logging.PanicHandlerWithTraceFn()
+	for i, run := range file.Runs {
+		wg.Add(1)
+		go func(i int, run *moduletest.Run) {
+			defer panicHandler()
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, dep := range deps[i] {
+				<-done[dep]
+			}
+
+			if runner.Suite.Cancelled.Load() || aborted.Load() {
+				// As with sequential execution, run blocks that never
+				// started are left pending.
+				return
+			}
+
+			if execute(run) {
+				aborted.Store(true)
+			}
+		}(i, run)
+	}
+	wg.Wait()
+
+	runner.runViews = nil
+	for _, buffer := range buffers {
+		buffer.Replay(runner.View)
+	}
+	return aborted.Load()
+}
+
+// runView returns the view that receives the output produced while executing
+// the given run block.
+func (runner *TestFileRunner) runView(run *moduletest.Run) views.Test {
+	if view, ok := runner.runViews[run]; ok {
+		return view
+	}
+	return runner.View
+}
+
+// testRunDependencies returns, for each run block in the given file, the
+// indices of the earlier run blocks that must complete before it can start.
+func testRunDependencies(file *moduletest.File) [][]int {
+	indices := make(map[string]int, len(file.Runs))
+	for i, run := range file.Runs {
+		indices[run.Name] = i
+	}
+
+	// References from the file-level variables apply to all of the run
+	// blocks in the file.
+	var fileRefs []string
+	for _, expr := range file.Config.Variables {
+		fileRefs = append(fileRefs, runReferencesInExpr(expr)...)
+	}
+
+	// The provider blocks of a test file are updated in place as each run
+	// block executes, so that they can refer to the outputs of earlier run
+	// blocks. Sharing them between concurrent run blocks isn't safe, so
+	// in that case we fall back to executing every run block in order.
+	sequential := false
+	for _, provider := range file.Config.Providers {
+		if provider.Config != nil {
+			sequential = true
+		}
+	}
+
+	deps := make([][]int, len(file.Runs))
+	lastByKey := make(map[string]int)
+	for i, run := range file.Runs {
+		seen := make(map[int]bool)
+		add := func(j int) {
+			if j < i && !seen[j] {
+				seen[j] = true
+				deps[i] = append(deps[i], j)
+			}
+		}
+
+		if sequential && i > 0 {
+			add(i - 1)
+		}
+
+		key := testRunStateKey(run)
+		if j, ok := lastByKey[key]; ok {
+			add(j)
+		}
+		lastByKey[key] = i
+
+		refs := append([]string(nil), fileRefs...)
+		for _, expr := range run.Config.Variables {
+			refs = append(refs, runReferencesInExpr(expr)...)
+		}
+		for _, rule := range run.Config.CheckRules {
+			refs = append(refs, runReferencesInExpr(rule.Condition)...)
+			refs = append(refs, runReferencesInExpr(rule.ErrorMessage)...)
+		}
+		for _, name := range refs {
+			if j, ok := indices[name]; ok {
+				add(j)
+			}
+		}
+	}
+	return deps
+}
+
+// runReferencesInExpr returns the names of the run blocks referred to by the
+// given expression.
+func runReferencesInExpr(expr hcl.Expression) []string {
+	if expr == nil {
+		return nil
+	}
+
+	var ret []string
+	for _, traversal := range expr.Variables() {
+		if traversal.RootName() != "run" || len(traversal) < 2 {
+			continue
+		}
+		if step, ok := traversal[1].(hcl.TraverseAttr); ok {
+			ret = append(ret, step.Name)
+		}
+	}
+	return ret
+}
+
+// saveErroredTestStateFile writes the given state to the errored state file
+// via the file's view, deferring it along with the rest of the output if
+// that view is buffered.
+func (runner *TestFileRunner) saveErroredTestStateFile(state *states.State, run *moduletest.Run, file *moduletest.File) {
+	if buffered, ok := runner.View.(*bufferedTestView); ok {
+		buffered.record(func(view views.Test) {
+			views.SaveErroredTestStateFile(state, run, file, view)
+		})
+		return
+	}
+	views.SaveErroredTestStateFile(state, run, file, runner.View)
+}
+
+// bufferedTestView is a views.Test that records the output for a single test
+// file, or a single run block within a file, so that it can be printed later,
+// in order, by Replay.
+//
+// Only the methods that are called while executing a test file are
+// buffered. The others pass through to the wrapped view immediately.
+type bufferedTestView struct {
+	views.Test
+
+	mu    sync.Mutex
+	calls []func(views.Test)
+}
+
+var _ views.Test = (*bufferedTestView)(nil)
+
+func (v *bufferedTestView) record(call func(views.Test)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.calls = append(v.calls, call)
+}
+
+// Replay sends all of the recorded output to the given view, in the order it
+// was recorded.
+func (v *bufferedTestView) Replay(view views.Test) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, call := range v.calls {
+		call(view)
+	}
+	v.calls = nil
+}
+
+func (v *bufferedTestView) File(file *moduletest.File) {
+	v.record(func(view views.Test) {
+		view.File(file)
+	})
+}
+
+func (v *bufferedTestView) Run(run *moduletest.Run, file *moduletest.File) {
+	v.record(func(view views.Test) {
+		view.Run(run, file)
+	})
+}
+
+func (v *bufferedTestView) DestroySummary(diags tfdiags.Diagnostics, run *moduletest.Run, file *moduletest.File, state *states.State) {
+	v.record(func(view views.Test) {
+		view.DestroySummary(diags, run, file, state)
+	})
+}
+
+func (v *bufferedTestView) Diagnostics(run *moduletest.Run, file *moduletest.File, diags tfdiags.Diagnostics) {
+	v.record(func(view views.Test) {
+		view.Diagnostics(run, file, diags)
+	})
+}
+
+func (v *bufferedTestView) FatalInterruptSummary(run *moduletest.Run, file *moduletest.File, states map[*moduletest.Run]*states.State, created []*plans.ResourceInstanceChangeSrc) {
+	v.record(func(view views.Test) {
+		view.FatalInterruptSummary(run, file, states, created)
+	})
+}
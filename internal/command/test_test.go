@@ -6,20 +6,27 @@
 package command
 
 import (
+	"encoding/json"
+	"os"
 	"path"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/mitchellh/cli"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	testing_command "github.com/opentofu/opentofu/internal/command/testing"
 	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/moduletest"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/terminal"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
 func TestTest(t *testing.T) {
@@ -388,6 +395,58 @@ test:
 	}
 }
 
+// TestTest_DoubleInterruptParallel is TestTest_DoubleInterrupt with the test
+// files executing concurrently, so the interrupts are received while other
+// goroutines are checking whether the tests were cancelled.
+func TestTest_DoubleInterruptParallel(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "with_double_interrupt")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	interrupt := make(chan struct{})
+	provider.Interrupt = interrupt
+
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+			ShutdownCh:       interrupt,
+		},
+	}
+
+	c.Run([]string{"-parallelism=2"})
+	output := done(t).All()
+
+	if !strings.Contains(output, "Two interrupts received") {
+		t.Errorf("output didn't produce the right output:\n\n%s", output)
+	}
+
+	cleanupMessage := `OpenTofu was interrupted while executing main.tftest.hcl, and may not have
+performed the expected cleanup operations.
+
+OpenTofu has already created the following resources from the module under
+test:
+  - test_resource.primary
+  - test_resource.secondary
+  - test_resource.tertiary`
+
+	// It's really important that the above message is printed, so we're testing
+	// for it specifically and making sure it contains all the resources.
+	if !strings.Contains(output, cleanupMessage) {
+		t.Errorf("output didn't produce the right output:\n\n%s", output)
+	}
+
+	// This time the test command shouldn't have cleaned up the resource because
+	// of the hard interrupt.
+	if provider.ResourceCount() != 3 {
+		// we asked for a nice stop in this one, so it should still have tidied everything up.
+		t.Errorf("should not have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+}
+
 func TestTest_ProviderAlias(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath(path.Join("test", "with_provider_alias")), td)
@@ -642,6 +701,245 @@ Success! 2 passed, 0 failed.
 	}
 }
 
+func TestTest_Reports(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "simple_fail")), td)
+	t.Chdir(td)
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color", "-junit-xml=results.xml", "-sarif=results.sarif"})
+	done(t)
+
+	if code != 1 {
+		t.Errorf("expected status code 1 but got %d", code)
+	}
+
+	junit, err := os.ReadFile("results.xml")
+	if err != nil {
+		t.Fatalf("failed to read JUnit XML report: %s", err)
+	}
+	for _, want := range []string{
+		`<testsuite name="main.tftest.hcl" tests="1" failures="1" errors="0" skipped="0"`,
+		`<testcase name="validate_test_resource" classname="main.tftest.hcl"`,
+		`<failure message="Test assertion failed" type="assertion">`,
+		`invalid value`,
+	} {
+		if !strings.Contains(string(junit), want) {
+			t.Errorf("JUnit XML report is missing %q\n%s", want, junit)
+		}
+	}
+
+	raw, err := os.ReadFile("results.sarif")
+	if err != nil {
+		t.Fatalf("failed to read SARIF report: %s", err)
+	}
+	var sarif struct {
+		Runs []struct {
+			Results []struct {
+				RuleID  string `json:"ruleId"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(raw, &sarif); err != nil {
+		t.Fatalf("invalid SARIF report: %s", err)
+	}
+	if len(sarif.Runs) != 1 || len(sarif.Runs[0].Results) != 1 {
+		t.Fatalf("expected exactly one SARIF result\n%s", raw)
+	}
+	if got, want := sarif.Runs[0].Results[0].RuleID, "test-failed"; got != want {
+		t.Errorf("wrong SARIF rule ID %q; want %q", got, want)
+	}
+	if got, want := sarif.Runs[0].Results[0].Message.Text, "invalid value"; !strings.Contains(got, want) {
+		t.Errorf("SARIF message %q does not contain %q", got, want)
+	}
+}
+
+func TestTest_Parallel(t *testing.T) {
+	tcs := map[string]struct {
+		fixture  string
+		args     []string
+		expected string
+	}{
+		"parallel runs": {
+			fixture: "parallel_runs",
+			expected: `main.tftest.hcl... pass
+  run "setup"... pass
+  run "independent"... pass
+  run "dependent"... pass
+
+Success! 3 passed, 0 failed.
+`,
+		},
+		"parallel files": {
+			fixture: "multiple_files",
+			args:    []string{"-parallelism=2"},
+			expected: `one.tftest.hcl... pass
+  run "validate_test_resource"... pass
+two.tftest.hcl... pass
+  run "validate_test_resource"... pass
+
+Success! 2 passed, 0 failed.
+`,
+		},
+		"parallel files and runs": {
+			fixture: "parallel_runs",
+			args:    []string{"-parallelism=4"},
+			expected: `main.tftest.hcl... pass
+  run "setup"... pass
+  run "independent"... pass
+  run "dependent"... pass
+
+Success! 3 passed, 0 failed.
+`,
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			td := t.TempDir()
+			testCopyDir(t, testFixturePath(path.Join("test", tc.fixture)), td)
+			t.Chdir(td)
+
+			provider := testing_command.NewProvider(nil)
+			providerSource, closePS := newMockProviderSource(t, map[string][]string{
+				"test": {"1.0.0"},
+			})
+			defer closePS()
+
+			streams, done := terminal.StreamsForTesting(t)
+			view := views.NewView(streams)
+			ui := new(cli.MockUi)
+
+			meta := Meta{
+				testingOverrides: metaOverridesForProvider(provider.Provider),
+				Ui:               ui,
+				View:             view,
+				Streams:          streams,
+				ProviderSource:   providerSource,
+			}
+
+			init := &InitCommand{
+				Meta: meta,
+			}
+			if code := init.Run(nil); code != 0 {
+				t.Fatalf("expected status code 0 but got %d: %s", code, ui.ErrorWriter)
+			}
+
+			c := &TestCommand{
+				Meta: meta,
+			}
+
+			code := c.Run(append([]string{"-no-color"}, tc.args...))
+			output := done(t)
+
+			if code != 0 {
+				t.Errorf("expected status code 0 but got %d\n%s", code, output.All())
+			}
+
+			if diff := cmp.Diff(tc.expected, output.Stdout()); len(diff) > 0 {
+				t.Errorf("output didn't match expected:\nexpected:\n%s\nactual:\n%s\ndiff:\n%s", tc.expected, output.Stdout(), diff)
+			}
+
+			if provider.ResourceCount() > 0 {
+				t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+			}
+		})
+	}
+}
+
+func TestTestRunDependencies(t *testing.T) {
+	expr := func(src string) hcl.Expression {
+		e, diags := hclsyntax.ParseExpression([]byte(src), "test.tftest.hcl", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return e
+	}
+	moduleRun := func(name, source string) *moduletest.Run {
+		return &moduletest.Run{
+			Name: name,
+			Config: &configs.TestRun{
+				Module: &configs.TestRunModuleCall{
+					Source: addrs.ModuleSourceLocal(source),
+				},
+				ConfigUnderTest: &configs.Config{},
+			},
+		}
+	}
+
+	file := &moduletest.File{
+		Config: &configs.TestFile{},
+		Runs: []*moduletest.Run{
+			moduleRun("setup", "./setup"),
+			{Name: "first", Config: &configs.TestRun{}},
+			moduleRun("other", "./other"),
+			{
+				Name: "uses_setup",
+				Config: &configs.TestRun{
+					Variables: map[string]hcl.Expression{
+						"value": expr("run.setup.value"),
+					},
+				},
+			},
+			{
+				Name: "asserts_other",
+				Config: &configs.TestRun{
+					CheckRules: []*configs.CheckRule{
+						{
+							Condition:    expr("run.other.value == \"x\""),
+							ErrorMessage: expr("\"bad\""),
+						},
+					},
+				},
+			},
+			moduleRun("setup_again", "./setup"),
+		},
+	}
+
+	got := testRunDependencies(file)
+	want := [][]int{
+		nil,
+		nil,
+		nil,
+		{1, 0},
+		{3, 2},
+		{0},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong dependencies\n%s", diff)
+	}
+
+	// A test file that configures its own providers executes its run blocks
+	// in order.
+	file.Config.Providers = map[string]*configs.Provider{
+		"test": {Name: "test", Config: hcl.EmptyBody()},
+	}
+	got = testRunDependencies(file)
+	want = [][]int{
+		nil,
+		{0},
+		{1},
+		{2, 1, 0},
+		{3, 2},
+		{4, 0},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong dependencies with providers\n%s", diff)
+	}
+}
+
 func TestTest_ValidatesBeforeExecution(t *testing.T) {
 	tcs := map[string]struct {
 		expectedOut string
@@ -1525,3 +1823,58 @@ func TestTest_MockProviderValidation(t *testing.T) {
 		t.Fatalf("expected status code 0 but got %d: %s", code, ui.ErrorWriter)
 	}
 }
+
+func TestTestFileRunner_forEachRunConcurrently(t *testing.T) {
+	file := &moduletest.File{
+		Config: &configs.TestFile{},
+		Runs: []*moduletest.Run{
+			{Name: "first", Config: &configs.TestRun{}},
+			{
+				// This run block uses a different state from the first,
+				// so the two are independent.
+				Name: "second",
+				Config: &configs.TestRun{
+					Module: &configs.TestRunModuleCall{
+						Source: addrs.ModuleSourceLocal("./setup"),
+					},
+					ConfigUnderTest: &configs.Config{},
+				},
+			},
+		},
+	}
+	view := &runOrderTestView{}
+	runner := &TestFileRunner{
+		Suite: &TestSuiteRunner{},
+		View:  view,
+	}
+
+	// The first run block doesn't finish until the second one has, so the
+	// second run block's output is produced first.
+	secondDone := make(chan struct{})
+	runner.forEachRunConcurrently(file, func(run *moduletest.Run) bool {
+		if run.Name == "first" {
+			<-secondDone
+		} else {
+			defer close(secondDone)
+		}
+		runner.runView(run).Diagnostics(run, file, nil)
+		return false
+	})
+
+	want := []string{"first", "second"}
+	if diff := cmp.Diff(want, view.runs); diff != "" {
+		t.Errorf("wrong output order\n%s", diff)
+	}
+}
+
+// runOrderTestView is a views.Test that records the names of the run blocks
+// passed to Diagnostics, in the order it receives them.
+type runOrderTestView struct {
+	views.Test
+
+	runs []string
+}
+
+func (v *runOrderTestView) Diagnostics(run *moduletest.Run, _ *moduletest.File, _ tfdiags.Diagnostics) {
+	v.runs = append(v.runs, run.Name)
+}
@@ -0,0 +1,7 @@
+resource "test_instance" "foo" {
+  ami = "bar"
+
+  lifecycle {
+    ignore_changes = [ami]
+  }
+}
@@ -0,0 +1,8 @@
+policy "prefer_baz" {
+  enforcement_level = "advisory"
+  condition = alltrue([
+    for rc in plan.resource_changes : rc.change.after.ami == "baz"
+    if rc.type == "test_instance"
+  ])
+  error_message = "Instances should use the baz image."
+}
@@ -0,0 +1,7 @@
+policy "forbid_bar" {
+  condition = alltrue([
+    for rc in plan.resource_changes : rc.change.after.ami != "bar"
+    if rc.type == "test_instance"
+  ])
+  error_message = "Instances must not use the bar image."
+}
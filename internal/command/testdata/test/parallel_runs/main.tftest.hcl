@@ -0,0 +1,31 @@
+test {
+  parallel = true
+}
+
+run "setup" {
+  module {
+    source = "./setup"
+  }
+
+  variables {
+    value = "from setup"
+  }
+}
+
+run "independent" {
+  assert {
+    condition     = test_resource.foo.value == "main"
+    error_message = "invalid value"
+  }
+}
+
+run "dependent" {
+  variables {
+    value = run.setup.value
+  }
+
+  assert {
+    condition     = output.value == "from setup"
+    error_message = "invalid value"
+  }
+}
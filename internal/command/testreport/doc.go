@@ -0,0 +1,11 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+// Package testreport renders the results of a "tofu test" execution into
+// machine-readable report formats, such as JUnit XML and SARIF, that CI
+// systems and code scanning tools understand natively.
+//
+// The reports are produced in addition to the normal human or JSON output of
+// the test command, so this package does not participate in the views
+// machinery.
+package testreport
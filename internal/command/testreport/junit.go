@@ -0,0 +1,210 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package testreport
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// The following types describe the subset of the JUnit XML format that we
+// produce. There is no formal specification for JUnit XML, so we follow the
+// de-facto conventions that are understood by the common CI systems.
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+	SystemErr *junitText      `xml:"system-err,omitempty"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",cdata"`
+}
+
+type junitText struct {
+	Body string `xml:",cdata"`
+}
+
+// junitFileTestCaseName is the name of the test case that reports the errors
+// of a test file that don't belong to any specific run block.
+const junitFileTestCaseName = "(file)"
+
+// JUnitXML renders the results of the given test suite as a JUnit XML
+// document.
+//
+// Each test file becomes a testsuite element and each run block within it
+// becomes a testcase element. Failed and errored run blocks include their
+// diagnostics, rendered using the given configuration sources for context.
+func JUnitXML(suite *moduletest.Suite, sources map[string]*hcl.File) ([]byte, error) {
+	ret := junitTestSuites{
+		Name: "OpenTofu tests",
+	}
+
+	var total time.Duration
+	for _, name := range sortedFileNames(suite) {
+		file := suite.Files[name]
+		total += file.Duration
+
+		ts := junitTestSuite{
+			Name:  file.Name,
+			Tests: len(file.Runs),
+			Time:  junitDuration(file.Duration),
+		}
+
+		for _, run := range file.Runs {
+			tc := junitTestCase{
+				Name:      run.Name,
+				Classname: file.Name,
+				Time:      junitDuration(run.Duration),
+			}
+
+			switch run.Status {
+			case moduletest.Pending, moduletest.Skip:
+				ts.Skipped++
+				tc.Skipped = &junitSkipped{
+					Message: "Run block was not executed.",
+				}
+			case moduletest.Fail:
+				ts.Failures++
+				tc.Failure = &junitFailure{
+					Message: firstErrorSummary(run.Diagnostics, "Test assertions failed."),
+					Type:    "assertion",
+					Body:    renderDiagnostics(run.Diagnostics, sources),
+				}
+			case moduletest.Error:
+				ts.Errors++
+				tc.Error = &junitFailure{
+					Message: firstErrorSummary(run.Diagnostics, "Run block encountered an error."),
+					Type:    "error",
+					Body:    renderDiagnostics(run.Diagnostics, sources),
+				}
+			default:
+				if len(run.Diagnostics) > 0 {
+					// Passing runs can still have warnings.
+					tc.SystemOut = &junitText{
+						Body: renderDiagnostics(run.Diagnostics, sources),
+					}
+				}
+			}
+
+			ts.TestCases = append(ts.TestCases, tc)
+		}
+
+		switch {
+		case file.Diagnostics.HasErrors():
+			// Errors that don't belong to any specific run block, such as
+			// failures during cleanup, are reported as an additional test
+			// case so that CI systems don't consider the file successful.
+			ts.Tests++
+			ts.Errors++
+			ts.TestCases = append(ts.TestCases, junitTestCase{
+				Name:      junitFileTestCaseName,
+				Classname: file.Name,
+				Time:      junitDuration(0),
+				Error: &junitFailure{
+					Message: firstErrorSummary(file.Diagnostics, "Test file encountered an error."),
+					Type:    "error",
+					Body:    renderDiagnostics(file.Diagnostics, sources),
+				},
+			})
+		case len(file.Diagnostics) > 0:
+			// Warnings that don't belong to any specific run block are
+			// reported against the file itself.
+			ts.SystemErr = &junitText{
+				Body: renderDiagnostics(file.Diagnostics, sources),
+			}
+		}
+
+		ret.Tests += ts.Tests
+		ret.Failures += ts.Failures
+		ret.Errors += ts.Errors
+		ret.Skipped += ts.Skipped
+		ret.Suites = append(ret.Suites, ts)
+	}
+	ret.Time = junitDuration(total)
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(ret); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func junitDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func sortedFileNames(suite *moduletest.Suite) []string {
+	names := make([]string, 0, len(suite.Files))
+	for name := range suite.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func firstErrorSummary(diags tfdiags.Diagnostics, fallback string) string {
+	for _, diag := range diags {
+		if diag.Severity() == tfdiags.Error {
+			return diag.Description().Summary
+		}
+	}
+	return fallback
+}
+
+func renderDiagnostics(diags tfdiags.Diagnostics, sources map[string]*hcl.File) string {
+	var parts []string
+	for _, diag := range diags {
+		// A width of zero disables line wrapping, leaving that decision to
+		// whatever eventually displays the report.
+		parts = append(parts, strings.TrimSpace(format.DiagnosticPlain(diag, sources, 0)))
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,144 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package testreport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func testSuite() *moduletest.Suite {
+	return &moduletest.Suite{
+		Status: moduletest.Error,
+		Files: map[string]*moduletest.File{
+			"b.tftest.hcl": {
+				Name:     "b.tftest.hcl",
+				Status:   moduletest.Error,
+				Duration: 1500 * time.Millisecond,
+				Runs: []*moduletest.Run{
+					{
+						Name:     "broken",
+						Status:   moduletest.Error,
+						Duration: time.Second,
+						Diagnostics: tfdiags.Diagnostics{
+							tfdiags.Sourceless(tfdiags.Error, "Provider failed", "It went wrong."),
+						},
+					},
+					{
+						Name:   "never",
+						Status: moduletest.Skip,
+					},
+				},
+			},
+			"a.tftest.hcl": {
+				Name:     "a.tftest.hcl",
+				Status:   moduletest.Fail,
+				Duration: 250 * time.Millisecond,
+				Runs: []*moduletest.Run{
+					{
+						Name:     "first",
+						Status:   moduletest.Pass,
+						Duration: 100 * time.Millisecond,
+					},
+					{
+						Name:     "second",
+						Status:   moduletest.Fail,
+						Duration: 150 * time.Millisecond,
+						Diagnostics: tfdiags.Diagnostics{
+							tfdiags.Sourceless(tfdiags.Error, "Test assertion failed", "invalid value"),
+						},
+					},
+				},
+				Diagnostics: tfdiags.Diagnostics{
+					tfdiags.Sourceless(tfdiags.Warning, "Cleanup warning", "Something was left behind."),
+				},
+			},
+		},
+	}
+}
+
+func TestJUnitXML(t *testing.T) {
+	got, err := JUnitXML(testSuite(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="OpenTofu tests" tests="4" failures="1" errors="1" skipped="1" time="1.750">
+  <testsuite name="a.tftest.hcl" tests="2" failures="1" errors="0" skipped="0" time="0.250">
+    <testcase name="first" classname="a.tftest.hcl" time="0.100"></testcase>
+    <testcase name="second" classname="a.tftest.hcl" time="0.150">
+      <failure message="Test assertion failed" type="assertion"><![CDATA[Error: Test assertion failed
+
+invalid value]]></failure>
+    </testcase>
+    <system-err><![CDATA[Warning: Cleanup warning
+
+Something was left behind.]]></system-err>
+  </testsuite>
+  <testsuite name="b.tftest.hcl" tests="2" failures="0" errors="1" skipped="1" time="1.500">
+    <testcase name="broken" classname="b.tftest.hcl" time="1.000">
+      <error message="Provider failed" type="error"><![CDATA[Error: Provider failed
+
+It went wrong.]]></error>
+    </testcase>
+    <testcase name="never" classname="b.tftest.hcl" time="0.000">
+      <skipped message="Run block was not executed."></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestJUnitXML_fileErrors(t *testing.T) {
+	suite := &moduletest.Suite{
+		Status: moduletest.Error,
+		Files: map[string]*moduletest.File{
+			"main.tftest.hcl": {
+				Name:     "main.tftest.hcl",
+				Status:   moduletest.Error,
+				Duration: 100 * time.Millisecond,
+				Runs: []*moduletest.Run{
+					{
+						Name:     "first",
+						Status:   moduletest.Pass,
+						Duration: 100 * time.Millisecond,
+					},
+				},
+				Diagnostics: tfdiags.Diagnostics{
+					tfdiags.Sourceless(tfdiags.Error, "Failed to destroy resources", "The state still contains resources."),
+				},
+			},
+		},
+	}
+
+	got, err := JUnitXML(suite, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="OpenTofu tests" tests="2" failures="0" errors="1" skipped="0" time="0.100">
+  <testsuite name="main.tftest.hcl" tests="2" failures="0" errors="1" skipped="0" time="0.100">
+    <testcase name="first" classname="main.tftest.hcl" time="0.100"></testcase>
+    <testcase name="(file)" classname="main.tftest.hcl" time="0.000">
+      <error message="Failed to destroy resources" type="error"><![CDATA[Error: Failed to destroy resources
+
+The state still contains resources.]]></error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
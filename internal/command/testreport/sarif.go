@@ -0,0 +1,201 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package testreport
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/version"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	// sarifRuleFailed and sarifRuleError are the rule identifiers we use to
+	// distinguish failed assertions from run blocks that could not complete.
+	sarifRuleFailed = "test-failed"
+	sarifRuleError  = "test-error"
+)
+
+// The following types describe the subset of the SARIF 2.1.0 format that we
+// produce.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// SARIF renders the failed and errored run blocks of the given test suite as
+// a SARIF 2.1.0 log, so that code scanning tools can annotate the test files
+// and configuration that caused them.
+//
+// Every diagnostic attached to a failed or errored run block, and every
+// diagnostic attached to a test file, becomes a separate result. Passing and
+// skipped run blocks produce no results.
+func SARIF(suite *moduletest.Suite) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "OpenTofu",
+				Version:        version.String(),
+				InformationURI: "https://opentofu.org",
+				Rules: []sarifRule{
+					{
+						ID:               sarifRuleFailed,
+						ShortDescription: sarifMessage{Text: "A test run block failed its assertions."},
+					},
+					{
+						ID:               sarifRuleError,
+						ShortDescription: sarifMessage{Text: "A test run block or test file encountered an error."},
+					},
+				},
+			},
+		},
+		// Results must be an empty array rather than null when there are no
+		// results, to indicate that the tool ran successfully.
+		Results: []sarifResult{},
+	}
+
+	for _, name := range sortedFileNames(suite) {
+		file := suite.Files[name]
+
+		for _, r := range file.Runs {
+			var ruleID string
+			switch r.Status {
+			case moduletest.Fail:
+				ruleID = sarifRuleFailed
+			case moduletest.Error:
+				ruleID = sarifRuleError
+			default:
+				continue
+			}
+
+			for _, diag := range r.Diagnostics {
+				result := sarifResultForDiagnostic(ruleID, diag, file.Name)
+				result.Properties["run"] = r.Name
+				run.Results = append(run.Results, result)
+			}
+		}
+
+		for _, diag := range file.Diagnostics {
+			run.Results = append(run.Results, sarifResultForDiagnostic(sarifRuleError, diag, file.Name))
+		}
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+}
+
+func sarifResultForDiagnostic(ruleID string, diag tfdiags.Diagnostic, fileName string) sarifResult {
+	desc := diag.Description()
+	text := desc.Summary
+	if desc.Detail != "" {
+		text += "\n\n" + desc.Detail
+	}
+
+	result := sarifResult{
+		RuleID:  ruleID,
+		Level:   "error",
+		Message: sarifMessage{Text: text},
+		Properties: map[string]string{
+			"file": fileName,
+		},
+	}
+	if diag.Severity() == tfdiags.Warning {
+		result.Level = "warning"
+	}
+
+	if subject := diag.Source().Subject; subject != nil {
+		result.Locations = []sarifLocation{
+			{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{
+						URI: filepath.ToSlash(subject.Filename),
+					},
+					Region: &sarifRegion{
+						StartLine:   subject.Start.Line,
+						StartColumn: subject.Start.Column,
+						EndLine:     subject.End.Line,
+						EndColumn:   subject.End.Column,
+					},
+				},
+			},
+		}
+	} else {
+		// Without a more precise location we attribute the result to the
+		// test file as a whole.
+		result.Locations = []sarifLocation{
+			{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{
+						URI: filepath.ToSlash(fileName),
+					},
+				},
+			},
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,131 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package testreport
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestSARIF(t *testing.T) {
+	suite := testSuite()
+	suite.Files["a.tftest.hcl"].Runs[1].Diagnostics = tfdiags.Diagnostics{}.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Test assertion failed",
+		Detail:   "invalid value",
+		Subject: &hcl.Range{
+			Filename: "a.tftest.hcl",
+			Start:    hcl.Pos{Line: 3, Column: 17, Byte: 40},
+			End:      hcl.Pos{Line: 3, Column: 30, Byte: 53},
+		},
+	})
+
+	raw, err := SARIF(suite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got sarifLog
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != "2.1.0" || len(got.Runs) != 1 {
+		t.Fatalf("unexpected SARIF document:\n%s", raw)
+	}
+
+	want := []sarifResult{
+		{
+			RuleID:  sarifRuleFailed,
+			Level:   "error",
+			Message: sarifMessage{Text: "Test assertion failed\n\ninvalid value"},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "a.tftest.hcl"},
+						Region: &sarifRegion{
+							StartLine:   3,
+							StartColumn: 17,
+							EndLine:     3,
+							EndColumn:   30,
+						},
+					},
+				},
+			},
+			Properties: map[string]string{
+				"file": "a.tftest.hcl",
+				"run":  "second",
+			},
+		},
+		{
+			RuleID:  sarifRuleError,
+			Level:   "warning",
+			Message: sarifMessage{Text: "Cleanup warning\n\nSomething was left behind."},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "a.tftest.hcl"},
+					},
+				},
+			},
+			Properties: map[string]string{
+				"file": "a.tftest.hcl",
+			},
+		},
+		{
+			RuleID:  sarifRuleError,
+			Level:   "error",
+			Message: sarifMessage{Text: "Provider failed\n\nIt went wrong."},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: "b.tftest.hcl"},
+					},
+				},
+			},
+			Properties: map[string]string{
+				"file": "b.tftest.hcl",
+				"run":  "broken",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got.Runs[0].Results); diff != "" {
+		t.Errorf("wrong results\n%s", diff)
+	}
+}
+
+func TestSARIF_noResults(t *testing.T) {
+	raw, err := SARIF(&moduletest.Suite{
+		Status: moduletest.Pass,
+		Files: map[string]*moduletest.File{
+			"a.tftest.hcl": {
+				Name:   "a.tftest.hcl",
+				Status: moduletest.Pass,
+				Runs: []*moduletest.Run{
+					{Name: "ok", Status: moduletest.Pass},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Runs []struct {
+			Results []json.RawMessage `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Runs) != 1 || got.Runs[0].Results == nil || len(got.Runs[0].Results) != 0 {
+		t.Errorf("expected an empty results array\n%s", raw)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/jsondrift"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// The Drift view is used for the drift command.
+type Drift interface {
+	Hooks() []tofu.Hook
+
+	// Report renders the detected drift.
+	Report(report *jsondrift.Report) error
+
+	Diagnostics(diags tfdiags.Diagnostics)
+	HelpPrompt()
+}
+
+// NewDrift returns an initialized Drift implementation for the given ViewType.
+func NewDrift(vt arguments.ViewType, view *View) Drift {
+	switch vt {
+	case arguments.ViewJSON:
+		return &DriftJSON{view: view}
+	case arguments.ViewHuman:
+		return &DriftHuman{view: view}
+	default:
+		panic(fmt.Sprintf("unknown view type %v", vt))
+	}
+}
+
+// The DriftHuman implementation renders human-readable text logs, suitable for
+// a scrolling terminal.
+type DriftHuman struct {
+	view *View
+}
+
+var _ Drift = (*DriftHuman)(nil)
+
+func (v *DriftHuman) Hooks() []tofu.Hook {
+	return []tofu.Hook{NewUIOptionalHook(v.view)}
+}
+
+func (v *DriftHuman) Report(report *jsondrift.Report) error {
+	if !report.DriftDetected {
+		v.view.streams.Println(v.view.colorize.Color(
+			"\n[reset][bold][green]No drift detected.[reset] The remote objects match the OpenTofu state.",
+		))
+		return nil
+	}
+
+	noun := "resources"
+	if len(report.Resources) == 1 {
+		noun = "resource"
+	}
+	v.view.streams.Println(v.view.colorize.Color(fmt.Sprintf(
+		"\n[reset][bold][yellow]Drift detected in %d %s:[reset]\n",
+		len(report.Resources), noun,
+	)))
+	for _, r := range report.Resources {
+		switch r.Action {
+		case "delete":
+			v.view.streams.Println(v.view.colorize.Color(fmt.Sprintf("[bold]  # %s[reset] has been deleted", r.Address)))
+		default:
+			v.view.streams.Println(v.view.colorize.Color(fmt.Sprintf("[bold]  # %s[reset] has changed", r.Address)))
+		}
+		if r.PreviousAddress != "" {
+			v.view.streams.Printf("    (moved from %s)\n", r.PreviousAddress)
+		}
+		for _, attr := range r.Attributes {
+			change := "(sensitive value)"
+			if !attr.Sensitive {
+				change = fmt.Sprintf("%s -> %s", attr.Before, attr.After)
+			}
+			v.view.streams.Println(v.view.colorize.Color(fmt.Sprintf(
+				"      [yellow]~[reset] %s: %s", formatDriftPath(attr.Path), change,
+			)))
+		}
+	}
+	return nil
+}
+
+func (v *DriftHuman) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *DriftHuman) HelpPrompt() {
+	v.view.HelpPrompt("drift")
+}
+
+// formatDriftPath renders a path from the drift report in the same syntax
+// that ignore_changes uses.
+func formatDriftPath(path []interface{}) string {
+	var b strings.Builder
+	for i, step := range path {
+		switch s := step.(type) {
+		case string:
+			if i == 0 {
+				b.WriteString(s)
+			} else {
+				fmt.Fprintf(&b, "[%q]", s)
+			}
+		default:
+			fmt.Fprintf(&b, "[%v]", s)
+		}
+	}
+	return b.String()
+}
+
+// The DriftJSON implementation renders the drift report as a single JSON
+// document, suitable for integrating with other software.
+type DriftJSON struct {
+	view *View
+}
+
+var _ Drift = (*DriftJSON)(nil)
+
+// Hooks returns no hooks, because progress messages would interleave with
+// the report.
+func (v *DriftJSON) Hooks() []tofu.Hook {
+	return nil
+}
+
+func (v *DriftJSON) Report(report *jsondrift.Report) error {
+	src, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	v.view.streams.Println(string(src))
+	return nil
+}
+
+func (v *DriftJSON) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *DriftJSON) HelpPrompt() {
+}
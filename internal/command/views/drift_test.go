@@ -0,0 +1,88 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/jsondrift"
+	"github.com/opentofu/opentofu/internal/terminal"
+)
+
+func TestDriftHuman_report(t *testing.T) {
+	testCases := map[string]struct {
+		report *jsondrift.Report
+		want   string
+	}{
+		"no drift": {
+			&jsondrift.Report{Resources: []jsondrift.Resource{}},
+			"\nNo drift detected. The remote objects match the OpenTofu state.\n",
+		},
+		"drift": {
+			&jsondrift.Report{
+				DriftDetected: true,
+				Resources: []jsondrift.Resource{
+					{
+						Address:         "test_instance.foo",
+						PreviousAddress: "test_instance.bar",
+						Action:          "update",
+						Attributes: []jsondrift.Attribute{
+							{Path: []interface{}{"password"}, Sensitive: true},
+							{Path: []interface{}{"ports", 1}, Before: json.RawMessage("443"), After: json.RawMessage("8443")},
+							{Path: []interface{}{"tags", "Owner"}, Before: json.RawMessage(`"alice"`), After: json.RawMessage(`"bob"`)},
+						},
+					},
+					{
+						Address: "test_instance.baz",
+						Action:  "delete",
+					},
+				},
+			},
+			`
+Drift detected in 2 resources:
+
+  # test_instance.foo has changed
+    (moved from test_instance.bar)
+      ~ password: (sensitive value)
+      ~ ports[1]: 443 -> 8443
+      ~ tags["Owner"]: "alice" -> "bob"
+  # test_instance.baz has been deleted
+`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			v := NewDrift(arguments.ViewHuman, NewView(streams))
+
+			if err := v.Report(tc.report); err != nil {
+				t.Fatal(err)
+			}
+			if got := done(t).Stdout(); got != tc.want {
+				t.Errorf("wrong output\ngot:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDriftJSON_report(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewDrift(arguments.ViewJSON, NewView(streams))
+
+	report := &jsondrift.Report{
+		FormatVersion: jsondrift.FormatVersion,
+		Resources:     []jsondrift.Resource{},
+	}
+	if err := v.Report(report); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"format_version":"1.0","terraform_version":"","drift_detected":false,"resources":[]}` + "\n"
+	if got := done(t).Stdout(); got != want {
+		t.Errorf("wrong output\ngot:  %s\nwant: %s", got, want)
+	}
+}
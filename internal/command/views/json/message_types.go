@@ -17,7 +17,9 @@ const (
 	MessageResourceDrift MessageType = "resource_drift"
 	MessagePlannedChange MessageType = "planned_change"
 	MessageChangeSummary MessageType = "change_summary"
+	MessageModuleSummary MessageType = "module_summary"
 	MessageOutputs       MessageType = "outputs"
+	MessagePolicyResult  MessageType = "policy_result"
 
 	// Hook-driven messages
 	MessageApplyStart        MessageType = "apply_start"
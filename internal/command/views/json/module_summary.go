@@ -0,0 +1,119 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package json
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// ModuleSummary counts the planned changes to managed resources by module
+// instance and provider, so that it's possible to see where the changes in a
+// large plan are concentrated.
+type ModuleSummary struct {
+	Modules []*ModuleChanges `json:"modules"`
+}
+
+// ModuleChanges counts the planned changes to the managed resources of a
+// single module instance that belong to a single provider.
+type ModuleChanges struct {
+	// Module is the address of the module instance, which is empty for the
+	// root module.
+	Module   string `json:"module"`
+	Provider string `json:"provider"`
+
+	Create  int `json:"create"`
+	Update  int `json:"update"`
+	Replace int `json:"replace"`
+	Delete  int `json:"delete"`
+	Forget  int `json:"forget"`
+	Import  int `json:"import"`
+
+	// ReplaceReasons counts the replaced resource instances by the reason
+	// for their replacement.
+	ReplaceReasons map[ChangeReason]int `json:"replace_reasons,omitempty"`
+}
+
+// NewModuleSummary summarizes the given changes. Module instances without
+// any changes are omitted, and the remaining ones are sorted by module
+// address and then by provider.
+func NewModuleSummary(changes *plans.Changes) *ModuleSummary {
+	type key struct {
+		module   string
+		provider string
+	}
+	byKey := make(map[key]*ModuleChanges)
+	summary := &ModuleSummary{
+		Modules: []*ModuleChanges{},
+	}
+
+	for _, change := range changes.Resources {
+		if change.Addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+			continue
+		}
+		if change.Action == plans.NoOp && change.Importing == nil {
+			continue
+		}
+
+		k := key{
+			module:   change.Addr.Module.String(),
+			provider: change.ProviderAddr.Provider.ForDisplay(),
+		}
+		mc, ok := byKey[k]
+		if !ok {
+			mc = &ModuleChanges{
+				Module:   k.module,
+				Provider: k.provider,
+			}
+			byKey[k] = mc
+			summary.Modules = append(summary.Modules, mc)
+		}
+
+		if change.Importing != nil {
+			mc.Import++
+		}
+
+		switch change.Action {
+		case plans.Create:
+			mc.Create++
+		case plans.Update:
+			mc.Update++
+		case plans.DeleteThenCreate, plans.CreateThenDelete:
+			mc.Replace++
+			if mc.ReplaceReasons == nil {
+				mc.ReplaceReasons = make(map[ChangeReason]int)
+			}
+			reason := changeReason(change.ActionReason)
+			if reason == ReasonNone {
+				reason = ReasonUnknown
+			}
+			mc.ReplaceReasons[reason]++
+		case plans.Delete:
+			mc.Delete++
+		case plans.Forget:
+			mc.Forget++
+		}
+	}
+
+	sort.Slice(summary.Modules, func(i, j int) bool {
+		a, b := summary.Modules[i], summary.Modules[j]
+		if a.Module != b.Module {
+			return a.Module < b.Module
+		}
+		return a.Provider < b.Provider
+	})
+
+	return summary
+}
+
+func (s *ModuleSummary) String() string {
+	modules := make(map[string]struct{})
+	for _, mc := range s.Modules {
+		modules[mc.Module] = struct{}{}
+	}
+	return fmt.Sprintf("Changes by module: %d module instances with changes", len(modules))
+}
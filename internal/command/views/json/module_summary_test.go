@@ -0,0 +1,104 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package json
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+func TestNewModuleSummary(t *testing.T) {
+	child := addrs.RootModuleInstance.Child("child", addrs.IntKey(0))
+	awsProvider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("aws"),
+		Module:   addrs.RootModule,
+	}
+	randomProvider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("random"),
+		Module:   addrs.RootModule,
+	}
+
+	changes := plans.NewChanges()
+	add := func(module addrs.ModuleInstance, mode addrs.ResourceMode, name string, provider addrs.AbsProviderConfig, action plans.Action, reason plans.ResourceInstanceChangeActionReason, importing bool) {
+		addr := addrs.Resource{
+			Mode: mode,
+			Type: "test_instance",
+			Name: name,
+		}.Instance(addrs.NoKey).Absolute(module)
+		change := &plans.ResourceInstanceChangeSrc{
+			Addr:         addr,
+			PrevRunAddr:  addr,
+			ProviderAddr: provider,
+			ActionReason: reason,
+			ChangeSrc:    plans.ChangeSrc{Action: action},
+		}
+		if importing {
+			change.Importing = &plans.ImportingSrc{ID: "i-abc123"}
+		}
+		changes.Resources = append(changes.Resources, change)
+	}
+
+	root := addrs.RootModuleInstance
+	managed := addrs.ManagedResourceMode
+	noReason := plans.ResourceInstanceChangeNoReason
+	add(child, managed, "a", awsProvider, plans.DeleteThenCreate, plans.ResourceInstanceReplaceBecauseTainted, false)
+	add(child, managed, "b", awsProvider, plans.CreateThenDelete, plans.ResourceInstanceReplaceByRequest, false)
+	add(child, managed, "c", awsProvider, plans.DeleteThenCreate, plans.ResourceInstanceReplaceBecauseTainted, false)
+	add(child, managed, "d", randomProvider, plans.Delete, plans.ResourceInstanceDeleteBecauseNoResourceConfig, false)
+	add(root, managed, "e", awsProvider, plans.Create, noReason, false)
+	add(root, managed, "f", awsProvider, plans.Update, noReason, false)
+	add(root, managed, "g", awsProvider, plans.NoOp, noReason, true)
+	add(root, managed, "h", awsProvider, plans.Forget, noReason, false)
+	// Neither unchanged resources nor data resources are counted.
+	add(root, managed, "i", randomProvider, plans.NoOp, noReason, false)
+	add(root, addrs.DataResourceMode, "j", randomProvider, plans.Read, noReason, false)
+
+	got := NewModuleSummary(changes)
+	want := &ModuleSummary{
+		Modules: []*ModuleChanges{
+			{
+				Module:   "",
+				Provider: "hashicorp/aws",
+				Create:   1,
+				Update:   1,
+				Forget:   1,
+				Import:   1,
+			},
+			{
+				Module:   "module.child[0]",
+				Provider: "hashicorp/aws",
+				Replace:  3,
+				ReplaceReasons: map[ChangeReason]int{
+					ReasonTainted:   2,
+					ReasonRequested: 1,
+				},
+			},
+			{
+				Module:   "module.child[0]",
+				Provider: "hashicorp/random",
+				Delete:   1,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	if got, want := got.String(), "Changes by module: 2 module instances with changes"; got != want {
+		t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestNewModuleSummary_noChanges(t *testing.T) {
+	got := NewModuleSummary(plans.NewChanges())
+	if len(got.Modules) != 0 {
+		t.Errorf("unexpected modules: %#v", got.Modules)
+	}
+	if got, want := got.String(), "Changes by module: 0 module instances with changes"; got != want {
+		t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+	}
+}
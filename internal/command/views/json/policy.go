@@ -0,0 +1,32 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package json
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/policy"
+)
+
+// PolicyResult describes the outcome of evaluating a single policy against a
+// plan.
+type PolicyResult struct {
+	Policy           string `json:"policy"`
+	EnforcementLevel string `json:"enforcement_level"`
+	Status           string `json:"status"`
+	Message          string `json:"message,omitempty"`
+}
+
+func NewPolicyResult(r *policy.Result) *PolicyResult {
+	return &PolicyResult{
+		Policy:           r.Policy.Name,
+		EnforcementLevel: string(r.Policy.EnforcementLevel),
+		Status:           string(r.Status),
+		Message:          r.Message,
+	}
+}
+
+func (r *PolicyResult) String() string {
+	return fmt.Sprintf("Policy %s (%s): %s", r.Policy, r.EnforcementLevel, r.Status)
+}
@@ -106,6 +106,22 @@ func (v *JSONView) ResourceDrift(c *json.ResourceInstanceChange) {
 	)
 }
 
+func (v *JSONView) PolicyResult(r *json.PolicyResult) {
+	v.log.Info(
+		r.String(),
+		"type", json.MessagePolicyResult,
+		"policy_result", r,
+	)
+}
+
+func (v *JSONView) ModuleSummary(ms *json.ModuleSummary) {
+	v.log.Info(
+		ms.String(),
+		"type", json.MessageModuleSummary,
+		"module_summary", ms,
+	)
+}
+
 func (v *JSONView) ChangeSummary(cs *json.ChangeSummary) {
 	v.log.Info(
 		cs.String(),
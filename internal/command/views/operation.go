@@ -8,7 +8,9 @@ package views
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
@@ -19,6 +21,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/views/json"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -35,6 +38,8 @@ type Operation interface {
 	PlannedChange(change *plans.ResourceInstanceChangeSrc)
 	Plan(plan *plans.Plan, schemas *tofu.Schemas)
 	PlanNextStep(planPath string, genConfigPath string)
+	PolicyResults(results []*policy.Result)
+	DependentChangesSkipped(addr addrs.AbsResourceInstance, dependents []addrs.AbsResourceInstance)
 
 	Diagnostics(diags tfdiags.Diagnostics)
 }
@@ -128,6 +133,46 @@ func (v *OperationHuman) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
 	}
 
 	renderer.RenderHumanPlan(jplan, plan.UIMode, opts...)
+
+	if v.view.planSummary == arguments.PlanSummaryModule {
+		v.moduleSummary(json.NewModuleSummary(plan.Changes))
+	}
+}
+
+// moduleSummary renders a table of the planned changes by module instance and
+// provider.
+func (v *OperationHuman) moduleSummary(summary *json.ModuleSummary) {
+	if len(summary.Modules) == 0 {
+		return
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  MODULE\tPROVIDER\tCREATE\tUPDATE\tREPLACE\tDELETE\tFORGET\tIMPORT\tREPLACE REASONS")
+	for _, mc := range summary.Modules {
+		module := mc.Module
+		if module == "" {
+			module = "(root)"
+		}
+
+		reasons := make([]string, 0, len(mc.ReplaceReasons))
+		for reason, count := range mc.ReplaceReasons {
+			reasons = append(reasons, fmt.Sprintf("%s: %d", strings.ReplaceAll(string(reason), "_", " "), count))
+		}
+		sort.Strings(reasons)
+
+		fmt.Fprintf(
+			w, "  %s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n",
+			module, mc.Provider, mc.Create, mc.Update, mc.Replace, mc.Delete, mc.Forget, mc.Import, strings.Join(reasons, ", "),
+		)
+	}
+	w.Flush()
+
+	v.view.streams.Println(v.view.colorize.Color("\n[reset][bold]Changes by module:[reset]\n"))
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		// Rows without replace reasons would otherwise end with padding.
+		v.view.streams.Println(strings.TrimRight(line, " "))
+	}
 }
 
 func (v *OperationHuman) PlannedChange(change *plans.ResourceInstanceChangeSrc) {
@@ -171,6 +216,63 @@ func (v *OperationHuman) PlanNextStep(planPath string, genConfigPath string) {
 	}
 }
 
+// PolicyResults summarizes the outcome of each policy. The details of any
+// failures are reported separately as diagnostics.
+func (v *OperationHuman) PolicyResults(results []*policy.Result) {
+	if len(results) == 0 {
+		return
+	}
+
+	var passed, failed, errored int
+	for _, r := range results {
+		switch r.Status {
+		case policy.Pass:
+			passed++
+		case policy.Fail:
+			failed++
+		default:
+			errored++
+		}
+	}
+
+	summary := fmt.Sprintf("\n[reset][bold]Policy evaluation:[reset] %d passed, %d failed", passed, failed)
+	if errored > 0 {
+		summary += fmt.Sprintf(", %d errored", errored)
+	}
+	v.view.streams.Println(v.view.colorize.Color(summary + "."))
+
+	for _, r := range results {
+		var status string
+		switch r.Status {
+		case policy.Pass:
+			status = "[green]pass"
+		case policy.Fail:
+			if r.Policy.EnforcementLevel == policy.Advisory {
+				status = "[yellow]fail"
+			} else {
+				status = "[red]fail"
+			}
+		default:
+			status = "[red]error"
+		}
+		v.view.streams.Println(v.view.colorize.Color(fmt.Sprintf(
+			"  - %s (%s): %s[reset]", r.Policy.Name, r.Policy.EnforcementLevel, status,
+		)))
+	}
+}
+
+// DependentChangesSkipped tells the user which other changes an interactive
+// apply skips as a consequence of skipping the change to the given resource
+// instance.
+func (v *OperationHuman) DependentChangesSkipped(addr addrs.AbsResourceInstance, dependents []addrs.AbsResourceInstance) {
+	v.view.streams.Println(v.view.colorize.Color(fmt.Sprintf(
+		"\n[reset][yellow]The following changes depend on %s and will also be skipped:[reset]", addr,
+	)))
+	for _, dep := range dependents {
+		v.view.streams.Printf("  - %s\n", dep)
+	}
+}
+
 func (v *OperationHuman) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
@@ -259,6 +361,10 @@ func (v *OperationJSON) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
 
 	v.view.ChangeSummary(cs)
 
+	if v.view.view.planSummary == arguments.PlanSummaryModule {
+		v.view.ModuleSummary(json.NewModuleSummary(plan.Changes))
+	}
+
 	var rootModuleOutputs []*plans.OutputChangeSrc
 	for _, output := range plan.Changes.Outputs {
 		if !output.Addr.Module.IsRoot() {
@@ -284,6 +390,18 @@ func (v *OperationJSON) PlannedChange(change *plans.ResourceInstanceChangeSrc) {
 func (v *OperationJSON) PlanNextStep(planPath string, genConfigPath string) {
 }
 
+// PolicyResults logs a message for the outcome of each policy.
+func (v *OperationJSON) PolicyResults(results []*policy.Result) {
+	for _, r := range results {
+		v.view.PolicyResult(json.NewPolicyResult(r))
+	}
+}
+
+// DependentChangesSkipped does nothing for the JSON view, because an
+// interactive apply requires the human-readable UI.
+func (v *OperationJSON) DependentChangesSkipped(addr addrs.AbsResourceInstance, dependents []addrs.AbsResourceInstance) {
+}
+
 func (v *OperationJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/lang/globalref"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/terminal"
@@ -363,6 +364,30 @@ Plan: 1 to add, 0 to change, 0 to destroy.
 	}
 }
 
+func TestOperation_planModuleSummary(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	view := NewView(streams)
+	view.SetPlanSummary(arguments.PlanSummaryModule)
+	v := NewOperation(arguments.ViewHuman, true, view)
+
+	plan := testPlan(t)
+	schemas := testSchemas()
+	v.Plan(plan, schemas)
+
+	want := `
+Plan: 1 to add, 0 to change, 0 to destroy.
+
+Changes by module:
+
+  MODULE  PROVIDER        CREATE  UPDATE  REPLACE  DELETE  FORGET  IMPORT  REPLACE REASONS
+  (root)  hashicorp/test  1       0       0        0       0       0
+`
+
+	if got := done(t).Stdout(); !strings.HasSuffix(got, want) {
+		t.Errorf("unexpected output\ngot:\n%s\nwant suffix:\n%s", got, want)
+	}
+}
+
 func TestOperation_planWithDatasource(t *testing.T) {
 	streams, done := terminal.StreamsForTesting(t)
 	v := NewOperation(arguments.ViewHuman, true, NewView(streams))
@@ -476,6 +501,58 @@ func TestOperation_planNextStepInAutomation(t *testing.T) {
 	}
 }
 
+func testPolicyResults() []*policy.Result {
+	return []*policy.Result{
+		{
+			Policy: &policy.Policy{Name: "require_tags", EnforcementLevel: policy.Mandatory},
+			Status: policy.Pass,
+		},
+		{
+			Policy:  &policy.Policy{Name: "no_deletes", EnforcementLevel: policy.Advisory},
+			Status:  policy.Fail,
+			Message: "This plan deletes resources.",
+		},
+	}
+}
+
+func TestOperation_policyResults(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewOperation(arguments.ViewHuman, false, NewView(streams))
+
+	v.PolicyResults(testPolicyResults())
+
+	want := `
+Policy evaluation: 1 passed, 1 failed.
+  - require_tags (mandatory): pass
+  - no_deletes (advisory): fail
+`
+	if got := done(t).Stdout(); got != want {
+		t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestOperation_dependentChangesSkipped(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewOperation(arguments.ViewHuman, false, NewView(streams))
+
+	v.DependentChangesSkipped(
+		addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "test_instance", "a", addrs.NoKey),
+		[]addrs.AbsResourceInstance{
+			addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "test_instance", "b", addrs.NoKey),
+			addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "test_instance", "c", addrs.IntKey(0)),
+		},
+	)
+
+	want := `
+The following changes depend on test_instance.a and will also be skipped:
+  - test_instance.b
+  - test_instance.c[0]
+`
+	if got := done(t).Stdout(); got != want {
+		t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
 // Test all the trivial OperationJSON methods together. Y'know, for brevity.
 // This test is not a realistic stream of messages.
 func TestOperationJSON_logs(t *testing.T) {
@@ -591,6 +668,81 @@ func TestOperationJSON_planNoChanges(t *testing.T) {
 	testJSONViewOutputEquals(t, done(t).Stdout(), want)
 }
 
+func TestOperationJSON_policyResults(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := &OperationJSON{view: NewJSONView(NewView(streams))}
+
+	v.PolicyResults(testPolicyResults())
+
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "Policy require_tags (mandatory): pass",
+			"@module":  "tofu.ui",
+			"type":     "policy_result",
+			"policy_result": map[string]interface{}{
+				"policy":            "require_tags",
+				"enforcement_level": "mandatory",
+				"status":            "pass",
+			},
+		},
+		{
+			"@level":   "info",
+			"@message": "Policy no_deletes (advisory): fail",
+			"@module":  "tofu.ui",
+			"type":     "policy_result",
+			"policy_result": map[string]interface{}{
+				"policy":            "no_deletes",
+				"enforcement_level": "advisory",
+				"status":            "fail",
+				"message":           "This plan deletes resources.",
+			},
+		},
+	}
+
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
+
+func TestOperationJSON_planModuleSummary(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	view := NewView(streams)
+	view.SetPlanSummary(arguments.PlanSummaryModule)
+	v := &OperationJSON{view: NewJSONView(view)}
+
+	plan := &plans.Plan{
+		Changes: plans.NewChanges(),
+	}
+	v.Plan(plan, nil)
+
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "Plan: 0 to add, 0 to change, 0 to destroy.",
+			"@module":  "tofu.ui",
+			"type":     "change_summary",
+			"changes": map[string]interface{}{
+				"operation": "plan",
+				"add":       float64(0),
+				"import":    float64(0),
+				"change":    float64(0),
+				"forget":    float64(0),
+				"remove":    float64(0),
+			},
+		},
+		{
+			"@level":   "info",
+			"@message": "Changes by module: 0 module instances with changes",
+			"@module":  "tofu.ui",
+			"type":     "module_summary",
+			"module_summary": map[string]interface{}{
+				"modules": []interface{}{},
+			},
+		},
+	}
+
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
+
 func TestOperationJSON_plan(t *testing.T) {
 	streams, done := terminal.StreamsForTesting(t)
 	v := &OperationJSON{view: NewJSONView(NewView(streams))}
@@ -43,6 +43,10 @@ type View struct {
 	// showSensitive is used to display the value of variables marked as sensitive.
 	showSensitive bool
 
+	// planSummary selects an aggregated summary of the planned changes to
+	// render alongside a plan.
+	planSummary arguments.PlanSummary
+
 	// This unfortunate wart is required to enable rendering of diagnostics which
 	// have associated source code in the configuration. This function pointer
 	// will be dereferenced as late as possible when rendering diagnostics in
@@ -209,3 +213,7 @@ func (v *View) outputHorizRule() {
 func (v *View) SetShowSensitive(showSensitive bool) {
 	v.showSensitive = showSensitive
 }
+
+func (v *View) SetPlanSummary(summary arguments.PlanSummary) {
+	v.planSummary = summary
+}
@@ -123,6 +123,9 @@ func (a *Attribute) internalValidate(name, prefix string) error {
 	if a.Computed && a.Required {
 		err = multierror.Append(err, fmt.Errorf("%s%s: cannot set both Computed and Required", prefix, name))
 	}
+	if a.WriteOnly && a.Computed {
+		err = multierror.Append(err, fmt.Errorf("%s%s: cannot set both WriteOnly and Computed", prefix, name))
+	}
 
 	if a.Type == cty.NilType && a.NestedType == nil {
 		err = multierror.Append(err, fmt.Errorf("%s%s: either Type or NestedType must be defined", prefix, name))
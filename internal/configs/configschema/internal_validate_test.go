@@ -126,6 +126,31 @@ func TestBlockInternalValidate(t *testing.T) {
 			},
 			[]string{},
 		},
+		"attribute write-only and computed": {
+			&Block{
+				Attributes: map[string]*Attribute{
+					"foo": {
+						Type:      cty.String,
+						Optional:  true,
+						Computed:  true,
+						WriteOnly: true,
+					},
+				},
+			},
+			[]string{"foo: cannot set both WriteOnly and Computed"},
+		},
+		"attribute write-only and optional": {
+			&Block{
+				Attributes: map[string]*Attribute{
+					"foo": {
+						Type:      cty.String,
+						Optional:  true,
+						WriteOnly: true,
+					},
+				},
+			},
+			[]string{},
+		},
 		"attribute with missing type": {
 			&Block{
 				Attributes: map[string]*Attribute{
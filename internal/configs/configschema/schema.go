@@ -79,6 +79,12 @@ type Attribute struct {
 	// currently achieves this in a limited sense via other mechanisms.)
 	Sensitive bool
 
+	// WriteOnly, if set to true, indicates that the value of an attribute is
+	// sent to the provider but is never persisted in the plan or the state.
+	// Write-only attributes are only valid in managed resource type schemas,
+	// and must be either Optional or Required, but never Computed.
+	WriteOnly bool
+
 	Deprecated bool
 }
 
@@ -0,0 +1,95 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package configschema
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ContainsWriteOnly returns true if any of the attributes of the receiving
+// block or any of its descendent blocks are marked as write-only.
+func (b *Block) ContainsWriteOnly() bool {
+	for _, attrS := range b.Attributes {
+		if attrS.WriteOnly {
+			return true
+		}
+		if attrS.NestedType != nil && attrS.NestedType.ContainsWriteOnly() {
+			return true
+		}
+	}
+	for _, blockS := range b.BlockTypes {
+		if blockS.ContainsWriteOnly() {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsWriteOnly returns true if any of the attributes of the receiving
+// Object are marked as write-only.
+func (o *Object) ContainsWriteOnly() bool {
+	for _, attrS := range o.Attributes {
+		if attrS.WriteOnly {
+			return true
+		}
+		if attrS.NestedType != nil && attrS.NestedType.ContainsWriteOnly() {
+			return true
+		}
+	}
+	return false
+}
+
+// PathIsWriteOnly returns true if the given path refers either to a
+// write-only attribute or to some value nested inside one.
+func (b *Block) PathIsWriteOnly(path cty.Path) bool {
+	for i := range path {
+		if _, ok := path[i].(cty.GetAttrStep); !ok {
+			continue
+		}
+		if attrS := b.AttributeByPath(path[:i+1]); attrS != nil && attrS.WriteOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// NullWriteOnly returns a copy of the given value, which must conform to the
+// receiving block schema, with all of the write-only attributes set to null.
+//
+// Any marks on the given value are preserved, except for those that were
+// inside a write-only attribute, since those values no longer exist in the
+// result.
+func (b *Block) NullWriteOnly(val cty.Value) cty.Value {
+	if val.IsNull() || !val.IsKnown() || !b.ContainsWriteOnly() {
+		return val
+	}
+
+	unmarked, pvms := val.UnmarkDeepWithPaths()
+	ret, err := cty.Transform(unmarked, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		if len(path) == 0 {
+			return v, nil
+		}
+		if _, ok := path[len(path)-1].(cty.GetAttrStep); !ok {
+			return v, nil
+		}
+		if attrS := b.AttributeByPath(path); attrS != nil && attrS.WriteOnly {
+			return cty.NullVal(v.Type()), nil
+		}
+		return v, nil
+	})
+	if err != nil {
+		// The transform function never returns an error, so this is
+		// unreachable unless there is a bug in cty.
+		panic(err)
+	}
+
+	var keep []cty.PathValueMarks
+	for _, pvm := range pvms {
+		if b.PathIsWriteOnly(pvm.Path) {
+			continue
+		}
+		keep = append(keep, pvm)
+	}
+	return ret.MarkWithPaths(keep)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package configschema
+
+import (
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestBlockNullWriteOnly(t *testing.T) {
+	schema := &Block{
+		Attributes: map[string]*Attribute{
+			"normal": {
+				Type:     cty.String,
+				Optional: true,
+			},
+			"secret": {
+				Type:      cty.String,
+				Optional:  true,
+				WriteOnly: true,
+			},
+			"nested": {
+				NestedType: &Object{
+					Attributes: map[string]*Attribute{
+						"boop": {
+							Type:     cty.String,
+							Optional: true,
+						},
+						"honk": {
+							Type:      cty.String,
+							Optional:  true,
+							WriteOnly: true,
+						},
+					},
+					Nesting: NestingList,
+				},
+				Optional: true,
+			},
+		},
+		BlockTypes: map[string]*NestedBlock{
+			"list": {
+				Nesting: NestingList,
+				Block: Block{
+					Attributes: map[string]*Attribute{
+						"secret": {
+							Type:      cty.String,
+							Required:  true,
+							WriteOnly: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if !schema.ContainsWriteOnly() {
+		t.Fatal("expected schema to contain write-only attributes")
+	}
+
+	input := cty.ObjectVal(map[string]cty.Value{
+		"normal": cty.StringVal("a").Mark(marks.Sensitive),
+		"secret": cty.StringVal("b").Mark(marks.Ephemeral),
+		"nested": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"boop": cty.StringVal("c"),
+				"honk": cty.StringVal("d"),
+			}),
+		}),
+		"list": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"secret": cty.StringVal("e").Mark(marks.Ephemeral),
+			}),
+		}),
+	})
+	want := cty.ObjectVal(map[string]cty.Value{
+		"normal": cty.StringVal("a").Mark(marks.Sensitive),
+		"secret": cty.NullVal(cty.String),
+		"nested": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"boop": cty.StringVal("c"),
+				"honk": cty.NullVal(cty.String),
+			}),
+		}),
+		"list": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"secret": cty.NullVal(cty.String),
+			}),
+		}),
+	})
+
+	got := schema.NullWriteOnly(input)
+	if !got.RawEquals(want) {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestBlockPathIsWriteOnly(t *testing.T) {
+	schema := &Block{
+		Attributes: map[string]*Attribute{
+			"normal": {
+				Type:     cty.String,
+				Optional: true,
+			},
+			"secret": {
+				Type:      cty.Map(cty.String),
+				Optional:  true,
+				WriteOnly: true,
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		path cty.Path
+		want bool
+	}{
+		"normal attribute": {
+			cty.GetAttrPath("normal"),
+			false,
+		},
+		"write-only attribute": {
+			cty.GetAttrPath("secret"),
+			true,
+		},
+		"inside write-only attribute": {
+			cty.GetAttrPath("secret").Index(cty.StringVal("key")),
+			true,
+		},
+		"root": {
+			cty.Path{},
+			false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := schema.PathIsWriteOnly(test.path); got != test.want {
+				t.Errorf("wrong result %t; want %t", got, test.want)
+			}
+		})
+	}
+}
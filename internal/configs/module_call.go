@@ -38,6 +38,11 @@ type ModuleCall struct {
 	Count   hcl.Expression
 	ForEach hcl.Expression
 
+	// Enabled is the expression given for the "enabled" lifecycle argument,
+	// which is an alternative to Count and ForEach for a module call that has
+	// either exactly one instance, with no instance key, or no instances.
+	Enabled hcl.Expression
+
 	Providers []PassedProviderConfig
 
 	DependsOn []hcl.Traversal
@@ -110,8 +115,29 @@ func decodeModuleBlock(block *hcl.Block, override bool) (*ModuleCall, hcl.Diagno
 	}
 
 	var seenEscapeBlock *hcl.Block
+	var seenLifecycle *hcl.Block
 	for _, block := range content.Blocks {
 		switch block.Type {
+		case "lifecycle":
+			if seenLifecycle != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate lifecycle block",
+					Detail:   fmt.Sprintf("This module call already has a lifecycle block at %s.", seenLifecycle.DefRange),
+					Subject:  &block.DefRange,
+				})
+				continue
+			}
+			seenLifecycle = block
+
+			lcContent, lcDiags := block.Body.Content(moduleLifecycleBlockSchema)
+			diags = append(diags, lcDiags...)
+
+			if attr, exists := lcContent.Attributes["enabled"]; exists {
+				mc.Enabled = attr.Expr
+				diags = append(diags, checkEnabledConflicts(attr, mc.Count, mc.ForEach)...)
+			}
+
 		case "_":
 			if seenEscapeBlock != nil {
 				diags = append(diags, &hcl.Diagnostic{
@@ -359,14 +385,22 @@ var moduleBlockSchema = &hcl.BodySchema{
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "_"}, // meta-argument escaping block
+		{Type: "lifecycle"},
 
 		// These are all reserved for future use.
-		{Type: "lifecycle"},
 		{Type: "locals"},
 		{Type: "provider", LabelNames: []string{"type"}},
 	},
 }
 
+var moduleLifecycleBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "enabled",
+		},
+	},
+}
+
 func moduleSourceAddrEntersNewPackage(addr addrs.ModuleSource) bool {
 	switch addr.(type) {
 	case nil:
@@ -315,3 +315,38 @@ func TestModuleCallWithVersion(t *testing.T) {
 		t.Error(problem)
 	}
 }
+
+func TestModuleCallEnabled(t *testing.T) {
+	parser := testParser(map[string]string{
+		"main.tf": `
+module "foo" {
+  source = "./foo"
+
+  lifecycle {
+    enabled = var.enabled
+  }
+}
+
+module "bar" {
+  source = "./bar"
+  count  = 1
+
+  lifecycle {
+    enabled = true
+  }
+}
+`,
+	})
+
+	file, diags := parser.LoadConfigFile("main.tf")
+	assertExactDiagnostics(t, diags, []string{
+		`main.tf:15,5-12: Invalid combination of "count" and "enabled"; The "count" meta-argument and the "enabled" lifecycle argument are mutually-exclusive, only one should be used to be explicit about the number of instances to be created.`,
+	})
+
+	if len(file.ModuleCalls) != 2 {
+		t.Fatalf("wrong number of module calls %d; want 2", len(file.ModuleCalls))
+	}
+	if file.ModuleCalls[0].Enabled == nil {
+		t.Fatalf("module.foo has no enabled expression")
+	}
+}
@@ -187,6 +187,10 @@ func (mc *ModuleCall) merge(omc *ModuleCall) hcl.Diagnostics {
 		mc.ForEach = omc.ForEach
 	}
 
+	if omc.Enabled != nil {
+		mc.Enabled = omc.Enabled
+	}
+
 	if omc.VersionAttr != nil {
 		mc.VersionAttr = omc.VersionAttr
 	}
@@ -226,6 +230,9 @@ func (r *Resource) merge(or *Resource, rps map[string]*RequiredProvider) hcl.Dia
 	if or.ForEach != nil {
 		r.ForEach = or.ForEach
 	}
+	if or.Enabled != nil {
+		r.Enabled = or.Enabled
+	}
 
 	if or.ProviderConfigRef != nil {
 		r.ProviderConfigRef = or.ProviderConfigRef
@@ -292,14 +292,16 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 	for name, child := range cfg.Children {
 		mc := mod.ModuleCalls[name]
 		childNoProviderConfigRange := noProviderConfigRange
-		// if the module call has any of count, for_each or depends_on,
-		// providers are prohibited from being configured in this module, or
-		// any module beneath this module.
+		// if the module call has any of count, for_each, enabled or
+		// depends_on, providers are prohibited from being configured in this
+		// module, or any module beneath this module.
 		switch {
 		case mc.Count != nil:
 			childNoProviderConfigRange = mc.Count.Range().Ptr()
 		case mc.ForEach != nil:
 			childNoProviderConfigRange = mc.ForEach.Range().Ptr()
+		case mc.Enabled != nil:
+			childNoProviderConfigRange = mc.Enabled.Range().Ptr()
 		case mc.DependsOn != nil:
 			if len(mc.DependsOn) > 0 {
 				childNoProviderConfigRange = mc.DependsOn[0].SourceRange().Ptr()
@@ -458,6 +460,7 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 	}
 	checkImpliedProviderNames(mod.ManagedResources)
 	checkImpliedProviderNames(mod.DataResources)
+	checkImpliedProviderNames(mod.EphemeralResources)
 
 	// collect providers passed from the parent
 	if parentCall != nil {
@@ -523,6 +526,7 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 	}
 	checkProviderKeys(mod.ManagedResources)
 	checkProviderKeys(mod.DataResources)
+	checkProviderKeys(mod.EphemeralResources)
 
 	// Verify that any module calls only refer to named providers, and that
 	// those providers will have a configuration at runtime. This way we can
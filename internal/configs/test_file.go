@@ -75,7 +75,16 @@ type TestFile struct {
 	// with Providers map to use later when instantiating provider instance.
 	MockProviders map[string]*MockProvider
 
+	// Parallel is true if the run blocks within this file may be executed
+	// concurrently, as declared by the "parallel" argument of the file's
+	// "test" block.
+	//
+	// Even when set, run blocks that share a state key, or that refer to the
+	// outputs of other run blocks, still execute in order.
+	Parallel bool
+
 	VariablesDeclRange hcl.Range
+	TestDeclRange      hcl.Range
 }
 
 // Validate does a very simple and cursory check across the file blocks to look
@@ -420,8 +429,29 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 		MockProviders: make(map[string]*MockProvider),
 	}
 
+	var seenTestBlock bool
 	for _, block := range content.Blocks {
 		switch block.Type {
+		case "test":
+			if seenTestBlock {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Multiple \"test\" blocks",
+					Detail:   fmt.Sprintf("This test file already has a test block defined at %s.", tf.TestDeclRange),
+					Subject:  block.DefRange.Ptr(),
+				})
+				continue
+			}
+			seenTestBlock = true
+			tf.TestDeclRange = block.DefRange
+
+			testContent, testDiags := block.Body.Content(testBlockSchema)
+			diags = append(diags, testDiags...)
+			if attr, exists := testContent.Attributes["parallel"]; exists {
+				rawDiags := gohcl.DecodeExpression(attr.Expr, nil, &tf.Parallel)
+				diags = append(diags, rawDiags...)
+			}
+
 		case "run":
 			run, runDiags := decodeTestRunBlock(block)
 			diags = append(diags, runDiags...)
@@ -1041,6 +1071,10 @@ var testFileSchema = &hcl.BodySchema{
 			// variables block defines input variables to pass to the test.
 			Type: "variables",
 		},
+		{
+			// test block defines settings for the test file as a whole.
+			Type: "test",
+		},
 		{
 			Type: blockNameOverrideResource,
 		},
@@ -1057,6 +1091,13 @@ var testFileSchema = &hcl.BodySchema{
 	},
 }
 
+// testBlockSchema defines the structure of the test block within a test file.
+var testBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "parallel"},
+	},
+}
+
 // testRunBlockSchema defines the structure of the run block within a test,
 // including attributes like the command, expected failures, and providers.
 var testRunBlockSchema = &hcl.BodySchema{
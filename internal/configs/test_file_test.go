@@ -178,3 +178,74 @@ func TestDecodeTestRunModuleBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadTestFile_parallel(t *testing.T) {
+	tcs := map[string]struct {
+		src          string
+		wantParallel bool
+		wantDiags    []string
+	}{
+		"no test block": {
+			src: `run "a" {}`,
+		},
+		"parallel": {
+			src: `
+test {
+  parallel = true
+}
+
+run "a" {}
+`,
+			wantParallel: true,
+		},
+		"not parallel": {
+			src: `
+test {
+  parallel = false
+}
+`,
+		},
+		"invalid value": {
+			src: `
+test {
+  parallel = "sometimes"
+}
+`,
+			wantDiags: []string{"Unsuitable value type"},
+		},
+		"multiple test blocks": {
+			src: `
+test {
+  parallel = true
+}
+
+test {
+  parallel = false
+}
+`,
+			wantParallel: true,
+			wantDiags:    []string{"Multiple \"test\" blocks"},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			file, diags := loadTestFile(f.Body)
+			var gotDiags []string
+			for _, diag := range diags {
+				gotDiags = append(gotDiags, diag.Summary)
+			}
+			if diff := cmp.Diff(tc.wantDiags, gotDiags); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+			if file.Parallel != tc.wantParallel {
+				t.Errorf("wrong Parallel %t; want %t", file.Parallel, tc.wantParallel)
+			}
+		})
+	}
+}
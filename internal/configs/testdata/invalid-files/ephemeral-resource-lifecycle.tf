@@ -0,0 +1,7 @@
+ephemeral "example" "example" {
+  lifecycle {
+    # The lifecycle arguments are not valid for ephemeral resources:
+    # only the precondition and postcondition blocks are allowed.
+    create_before_destroy = true
+  }
+}
@@ -0,0 +1,8 @@
+module "foo" {
+  source   = "./foo"
+  for_each = ["a"]
+
+  lifecycle {
+    enabled = true
+  }
+}
@@ -0,0 +1,25 @@
+resource "aws_instance" "web" {
+  lifecycle {
+    enabled = var.create_web
+  }
+}
+
+data "aws_ami" "web" {
+  lifecycle {
+    enabled = var.create_web
+  }
+}
+
+ephemeral "aws_secret" "web" {
+  lifecycle {
+    enabled = var.create_web
+  }
+}
+
+module "web" {
+  source = "./web"
+
+  lifecycle {
+    enabled = var.create_web
+  }
+}
@@ -0,0 +1,18 @@
+ephemeral "random_password" "example1" {
+}
+
+ephemeral "vault_kv_secret" "example2" {
+  path = "secret/db"
+
+  count = 2
+  depends_on = [
+    ephemeral.random_password.example1,
+  ]
+
+  lifecycle {
+    postcondition {
+      condition     = self.data != null
+      error_message = "The secret must contain data."
+    }
+  }
+}
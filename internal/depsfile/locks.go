@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"sort"
 
+	version "github.com/hashicorp/go-version"
 	svchost "github.com/hashicorp/terraform-svchost"
 
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -39,11 +40,11 @@ type Locks struct {
 	// settings, environment variables, or whatever similar sources.
 	overriddenProviders map[addrs.Provider]struct{}
 
-	// TODO: In future we'll also have module locks, but the design of that
-	// still needs some more work and we're deferring that to get the
-	// provider locking capability out sooner, because it's more common to
-	// directly depend on providers maintained outside your organization than
-	// modules maintained outside your organization.
+	// modules records the packages selected for the remote modules in the
+	// configuration, keyed by the module path as used in the module
+	// manifest, such as "network.subnets". Modules with local sources are
+	// part of the package of their parent module and so are not tracked here.
+	modules map[string]*ModuleLock
 
 	// sources is a copy of the map of source buffers produced by the HCL
 	// parser during loading, which we retain only so that the caller can
@@ -56,6 +57,7 @@ type Locks struct {
 func NewLocks() *Locks {
 	return &Locks{
 		providers: make(map[addrs.Provider]*ProviderLock),
+		modules:   make(map[string]*ModuleLock),
 
 		// no "sources" here, because that's only for locks objects loaded
 		// from files.
@@ -122,6 +124,45 @@ func (l *Locks) RemoveProvider(addr addrs.Provider) {
 	delete(l.providers, addr)
 }
 
+// Module returns the stored lock for the module with the given key, or nil if
+// that module currently has no lock.
+func (l *Locks) Module(key string) *ModuleLock {
+	return l.modules[key]
+}
+
+// AllModules returns a map describing all of the module locks in the
+// receiver, keyed by module path.
+func (l *Locks) AllModules() map[string]*ModuleLock {
+	// We return a copy of our internal map so that future calls to
+	// SetModule won't modify the map we're returning, or vice-versa.
+	ret := make(map[string]*ModuleLock, len(l.modules))
+	for k, v := range l.modules {
+		ret[k] = v
+	}
+	return ret
+}
+
+// SetModule creates a new lock or replaces the existing lock for the module
+// with the given key.
+//
+// SetModule returns the newly-created module lock object, which invalidates
+// any ModuleLock object previously returned from Module or SetModule for the
+// given key.
+func (l *Locks) SetModule(key string, source string, version *version.Version, commit string, hash string) *ModuleLock {
+	new := NewModuleLock(key, source, version, commit, hash)
+	l.modules[new.key] = new
+	return new
+}
+
+// RemoveModule removes any existing lock file entry for the module with the
+// given key.
+//
+// If the given module did not already have a lock entry, RemoveModule is
+// a no-op.
+func (l *Locks) RemoveModule(key string) {
+	delete(l.modules, key)
+}
+
 // SetProviderOverridden records that this particular OpenTofu process will
 // not pay attention to the recorded lock entry for the given provider, and
 // will instead access that provider's functionality in some other special
@@ -280,6 +321,16 @@ func (l *Locks) Equal(other *Locks) bool {
 	// We don't need to worry about providers that are in "other" but not
 	// in the receiver, because we tested the lengths being equal above.
 
+	if len(l.modules) != len(other.modules) {
+		return false
+	}
+	for key, thisLock := range l.modules {
+		otherLock, ok := other.modules[key]
+		if !ok || !thisLock.equal(otherLock) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -358,7 +409,7 @@ func (l *Locks) UpgradeFromPredecessorProject() map[addrs.Provider]addrs.Provide
 // UI code might wish to use this to distinguish a lock file being
 // written for the first time from subsequent updates to that lock file.
 func (l *Locks) Empty() bool {
-	return len(l.providers) == 0
+	return len(l.providers) == 0 && len(l.modules) == 0
 }
 
 // DeepCopy creates a new Locks that represents the same information as the
@@ -379,6 +430,9 @@ func (l *Locks) DeepCopy() *Locks {
 		}
 		ret.SetProvider(addr, lock.version, lock.versionConstraints, hashes)
 	}
+	for key, lock := range l.modules {
+		ret.SetModule(key, lock.source, lock.version, lock.commit, lock.hash)
+	}
 	return ret
 }
 
@@ -497,3 +551,86 @@ func (l *ProviderLock) ContainsAll(target *ProviderLock) bool {
 func (l *ProviderLock) PreferredHashes() []getproviders.Hash {
 	return getproviders.PreferredHashes(l.hashes)
 }
+
+// ModuleLock represents lock information for the package of a specific
+// remote module.
+type ModuleLock struct {
+	// key is the path of the module this lock applies to, in the same
+	// form used as keys in the module manifest.
+	key string
+
+	// source is the source address of the module as written in the
+	// configuration. A lock is only used while the configuration still
+	// refers to the same source address.
+	source string
+
+	// version is the exact version that was selected for a module from a
+	// module registry, or nil for modules installed directly from a
+	// remote source address.
+	version *version.Version
+
+	// commit is the commit that was checked out, for packages installed from
+	// a git repository, or an empty string for any other kind of package.
+	commit string
+
+	// hash is a hash of the contents of the installed package, using the
+	// same "h1:" scheme as the provider package hashes. The ".git" metadata
+	// of packages installed from git repositories is not included.
+	hash string
+}
+
+// NewModuleLock creates a new ModuleLock object that isn't associated
+// with any Locks object.
+//
+// This is here primarily for testing. Most callers should use Locks.SetModule
+// to construct a new module lock and insert it into a Locks object at the
+// same time.
+func NewModuleLock(key string, source string, version *version.Version, commit string, hash string) *ModuleLock {
+	return &ModuleLock{
+		key:     key,
+		source:  source,
+		version: version,
+		commit:  commit,
+		hash:    hash,
+	}
+}
+
+// Key returns the path of the module this lock applies to.
+func (l *ModuleLock) Key() string {
+	return l.key
+}
+
+// Source returns the source address of the module as written in the
+// configuration when the lock was created.
+func (l *ModuleLock) Source() string {
+	return l.source
+}
+
+// Version returns the selected version for a registry module, or nil if the
+// module wasn't installed from a module registry.
+func (l *ModuleLock) Version() *version.Version {
+	return l.version
+}
+
+// Commit returns the commit that was checked out for a package installed from
+// a git repository, or an empty string otherwise.
+func (l *ModuleLock) Commit() string {
+	return l.commit
+}
+
+// Hash returns the hash of the contents of the installed package.
+func (l *ModuleLock) Hash() string {
+	return l.hash
+}
+
+func (l *ModuleLock) equal(other *ModuleLock) bool {
+	if l.key != other.key || l.source != other.source || l.commit != other.commit || l.hash != other.hash {
+		return false
+	}
+	if (l.version == nil) != (other.version == nil) {
+		return false
+	}
+	// We compare the strings rather than using "Version.Equal" because
+	// changes to the metadata are significant here, just as for providers.
+	return l.version == nil || l.version.String() == other.version.String()
+}
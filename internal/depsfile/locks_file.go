@@ -9,7 +9,9 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
+	version "github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclparse"
@@ -24,7 +26,6 @@ import (
 	"github.com/opentofu/opentofu/internal/replacefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tracing"
-	"github.com/opentofu/opentofu/version"
 )
 
 // LoadLocksFromFile reads locks from the given file, expecting it to be a
@@ -175,6 +176,27 @@ func SaveLocksToBytes(locks *Locks) ([]byte, tfdiags.Diagnostics) {
 		}
 	}
 
+	modules := make([]string, 0, len(locks.modules))
+	for key := range locks.modules {
+		modules = append(modules, key)
+	}
+	sort.Strings(modules)
+
+	for _, key := range modules {
+		lock := locks.modules[key]
+		rootBody.AppendNewline()
+		block := rootBody.AppendNewBlock("module", []string{lock.key})
+		body := block.Body()
+		body.SetAttributeValue("source", cty.StringVal(lock.source))
+		if lock.version != nil {
+			body.SetAttributeValue("version", cty.StringVal(lock.version.String()))
+		}
+		if lock.commit != "" {
+			body.SetAttributeValue("commit", cty.StringVal(lock.commit))
+		}
+		body.SetAttributeValue("hash", cty.StringVal(lock.hash))
+	}
+
 	return f.Bytes(), diags
 }
 
@@ -187,11 +209,6 @@ func decodeLocksFromHCL(locks *Locks, body hcl.Body) tfdiags.Diagnostics {
 				Type:       "provider",
 				LabelNames: []string{"source_addr"},
 			},
-
-			// "module" is just a placeholder for future enhancement, so we
-			// can mostly-ignore the this block type we intend to add in
-			// future, but warn in case someone tries to use one e.g. if they
-			// downgraded to an earlier version of OpenTofu.
 			{
 				Type:       "module",
 				LabelNames: []string{"path"},
@@ -201,7 +218,7 @@ func decodeLocksFromHCL(locks *Locks, body hcl.Body) tfdiags.Diagnostics {
 	diags = diags.Append(hclDiags)
 
 	seenProviders := make(map[addrs.Provider]hcl.Range)
-	seenModule := false
+	seenModules := make(map[string]hcl.Range)
 	for _, block := range content.Blocks {
 
 		switch block.Type {
@@ -224,19 +241,22 @@ func decodeLocksFromHCL(locks *Locks, body hcl.Body) tfdiags.Diagnostics {
 			seenProviders[lock.addr] = block.DefRange
 
 		case "module":
-			// We'll just take the first module block to use for a single warning,
-			// because that's sufficient to get the point across without swamping
-			// the output with warning noise.
-			if !seenModule {
-				currentVersion := version.SemVer.String()
+			lock, moreDiags := decodeModuleLockFromHCL(block)
+			diags = diags.Append(moreDiags)
+			if lock == nil {
+				continue
+			}
+			if previousRng, exists := seenModules[lock.key]; exists {
 				diags = diags.Append(&hcl.Diagnostic{
-					Severity: hcl.DiagWarning,
-					Summary:  "Dependency locks for modules are not yet supported",
-					Detail:   fmt.Sprintf("OpenTofu v%s only supports dependency locks for providers, not for modules. This configuration may be intended for a later version of OpenTofu that also supports dependency locks for modules.", currentVersion),
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate module lock",
+					Detail:   fmt.Sprintf("This lockfile already declared a lock for module %q at %s.", lock.key, previousRng.String()),
 					Subject:  block.TypeRange.Ptr(),
 				})
-				seenModule = true
+				continue
 			}
+			locks.modules[lock.key] = lock
+			seenModules[lock.key] = block.DefRange
 
 		default:
 			// Shouldn't get here because this should be exhaustive for
@@ -332,6 +352,99 @@ func decodeProviderLockFromHCL(block *hcl.Block) (*ProviderLock, tfdiags.Diagnos
 	return ret, diags
 }
 
+func decodeModuleLockFromHCL(block *hcl.Block) (*ModuleLock, tfdiags.Diagnostics) {
+	ret := &ModuleLock{}
+	var diags tfdiags.Diagnostics
+
+	key := block.Labels[0]
+	for _, step := range strings.Split(key, ".") {
+		if !hclsyntax.ValidIdentifier(step) {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid module path",
+				Detail:   "The module path for a module lock must be a sequence of module call names separated by dots, such as \"network.subnets\".",
+				Subject:  block.LabelRanges[0].Ptr(),
+			})
+			return nil, diags
+		}
+	}
+	ret.key = key
+
+	content, hclDiags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "source", Required: true},
+			{Name: "version"},
+			{Name: "commit"},
+			{Name: "hash", Required: true},
+		},
+	})
+	diags = diags.Append(hclDiags)
+
+	ret.source, hclDiags = decodeModuleStringArgument(content.Attributes["source"])
+	diags = diags.Append(hclDiags)
+	ret.commit, hclDiags = decodeModuleStringArgument(content.Attributes["commit"])
+	diags = diags.Append(hclDiags)
+
+	if attr := content.Attributes["version"]; attr != nil {
+		raw, hclDiags := decodeModuleStringArgument(attr)
+		diags = diags.Append(hclDiags)
+		if !hclDiags.HasErrors() {
+			v, err := version.NewVersion(raw)
+			switch {
+			case err != nil:
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid module version number",
+					Detail:   fmt.Sprintf("The selected version number for module %q is invalid: %s.", key, err),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			case v.String() != raw:
+				// Canonical forms are required in the lock file, as for
+				// the provider versions.
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid module version number",
+					Detail:   fmt.Sprintf("The selected version number for module %q must be written in normalized form: %q.", key, v.String()),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			default:
+				ret.version = v
+			}
+		}
+	}
+
+	if attr := content.Attributes["hash"]; attr != nil {
+		raw, hclDiags := decodeModuleStringArgument(attr)
+		diags = diags.Append(hclDiags)
+		if !hclDiags.HasErrors() {
+			hash, err := getproviders.ParseHash(raw)
+			if err != nil || hash.Scheme() != getproviders.HashScheme1 {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid module package hash",
+					Detail:   fmt.Sprintf("The package hash for module %q must be a hash using the \"h1:\" scheme.", key),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			} else {
+				ret.hash = raw
+			}
+		}
+	}
+
+	return ret, diags
+}
+
+func decodeModuleStringArgument(attr *hcl.Attribute) (string, hcl.Diagnostics) {
+	if attr == nil {
+		// The caller should already have generated diagnostics if this
+		// argument is required.
+		return "", nil
+	}
+	var ret string
+	diags := gohcl.DecodeExpression(attr.Expr, nil, &ret)
+	return ret, diags
+}
+
 func decodeProviderVersionArgument(provider addrs.Provider, attr *hcl.Attribute) (getproviders.Version, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	if attr == nil {
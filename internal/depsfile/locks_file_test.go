@@ -14,6 +14,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	version "github.com/hashicorp/go-version"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/getproviders"
@@ -109,6 +110,40 @@ func TestLoadLocksFromFile(t *testing.T) {
 					t.Errorf("wrong number of providers %d; want %d", got, want)
 				}
 
+			case "valid-module-locks.hcl":
+				if got, want := len(locks.modules), 2; got != want {
+					t.Errorf("wrong number of modules %d; want %d", got, want)
+				}
+
+				t.Run("registry", func(t *testing.T) {
+					lock := locks.Module("network")
+					if lock == nil {
+						t.Fatal("no lock for module network")
+					}
+					if got, want := lock.Version().String(), "1.2.0"; got != want {
+						t.Errorf("wrong version\ngot:  %s\nwant: %s", got, want)
+					}
+					if got, want := lock.Commit(), "4b825dc642cb6eb9a060e54bf8d69288fbee4904"; got != want {
+						t.Errorf("wrong commit\ngot:  %s\nwant: %s", got, want)
+					}
+				})
+
+				t.Run("remote", func(t *testing.T) {
+					lock := locks.Module("network.subnets")
+					if lock == nil {
+						t.Fatal("no lock for module network.subnets")
+					}
+					if got, want := lock.Source(), "git::https://example.com/test/subnets.git?ref=main"; got != want {
+						t.Errorf("wrong source\ngot:  %s\nwant: %s", got, want)
+					}
+					if lock.Version() != nil {
+						t.Errorf("unexpected version %s", lock.Version())
+					}
+					if got, want := lock.Hash(), "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="; got != want {
+						t.Errorf("wrong hash\ngot:  %s\nwant: %s", got, want)
+					}
+				})
+
 			case "valid-provider-locks.hcl":
 				if got, want := len(locks.providers), 3; got != want {
 					t.Errorf("wrong number of providers %d; want %d", got, want)
@@ -225,6 +260,8 @@ func TestSaveLocksToFile(t *testing.T) {
 	locks.SetProvider(barProvider, oneDotTwo, pessimisticOneDotOh, nil)
 	locks.SetProvider(bazProvider, oneDotTwo, nil, nil)
 	locks.SetProvider(booProvider, oneDotTwo, abbreviatedOneDotTwo, nil)
+	locks.SetModule("network.subnets", "git::https://example.com/subnets.git?ref=main", nil, "4b825dc642cb6eb9a060e54bf8d69288fbee4904", "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+	locks.SetModule("network", "example.com/test/network/aws", version.Must(version.NewVersion("1.2.0")), "", "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
 
 	dir := t.TempDir()
 
@@ -273,6 +310,18 @@ provider "registry.opentofu.org/test/foo" {
     "test:cccccccccccccccccccccccccccccccccccccccccccccccc",
   ]
 }
+
+module "network" {
+  source  = "example.com/test/network/aws"
+  version = "1.2.0"
+  hash    = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+}
+
+module "network.subnets" {
+  source = "git::https://example.com/subnets.git?ref=main"
+  commit = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+  hash   = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+}
 `
 	if diff := cmp.Diff(wantContent, gotContent); diff != "" {
 		t.Errorf("wrong result\n%s", diff)
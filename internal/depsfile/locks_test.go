@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	version "github.com/hashicorp/go-version"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/getproviders"
 )
@@ -85,6 +86,27 @@ func TestLocksEqual(t *testing.T) {
 		b.SetProvider(boopProvider, v2, v2EqConstraints, hashesB)
 		nonEqualBothWays(t, a, b)
 	})
+	t.Run("an extra module lock", func(t *testing.T) {
+		a := NewLocks()
+		b := NewLocks()
+		b.SetModule("boop", "example.com/boop/boop/aws", version.Must(version.NewVersion("2.0.0")), "", "h1:1")
+		nonEqualBothWays(t, a, b)
+		equalBothWays(t, b, b.DeepCopy())
+	})
+	t.Run("both have boop module with different versions", func(t *testing.T) {
+		a := NewLocks()
+		b := NewLocks()
+		a.SetModule("boop", "example.com/boop/boop/aws", version.Must(version.NewVersion("2.0.0")), "", "h1:1")
+		b.SetModule("boop", "example.com/boop/boop/aws", version.Must(version.NewVersion("2.0.0+awesomecorp.1")), "", "h1:1")
+		nonEqualBothWays(t, a, b)
+	})
+	t.Run("both have boop module with same commit but different hashes", func(t *testing.T) {
+		a := NewLocks()
+		b := NewLocks()
+		a.SetModule("boop", "git::https://example.com/boop.git", nil, "abc", "h1:1")
+		b.SetModule("boop", "git::https://example.com/boop.git", nil, "abc", "h1:2")
+		nonEqualBothWays(t, a, b)
+	})
 }
 
 func TestLocksEqualProviderAddress(t *testing.T) {
@@ -0,0 +1,20 @@
+module "network" {
+  source  = "example.com/test/network/aws"
+  version = "1.2" # ERROR: Invalid module version number
+  hash    = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+}
+
+module "network" { # ERROR: Duplicate module lock
+  source = "example.com/test/network/aws"
+  hash   = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+}
+
+module "not a path" { # ERROR: Invalid module path
+  source = "example.com/test/network/aws"
+  hash   = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+}
+
+module "unhashed" {
+  source = "example.com/test/network/aws"
+  hash   = "zh:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=" # ERROR: Invalid module package hash
+}
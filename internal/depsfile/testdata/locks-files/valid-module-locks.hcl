@@ -0,0 +1,11 @@
+module "network" {
+  source  = "example.com/test/network/aws"
+  version = "1.2.0"
+  commit  = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+  hash    = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+}
+
+module "network.subnets" {
+  source = "git::https://example.com/test/subnets.git?ref=main"
+  hash   = "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+}
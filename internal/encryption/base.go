@@ -9,6 +9,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/encryption/config"
@@ -21,6 +23,9 @@ import (
 
 const (
 	encryptionVersion = "v0"
+	// envelopeEncryptionVersion marks payloads that are encrypted with a random data key, which is in turn wrapped by
+	// the configured method and stored alongside the payload.
+	envelopeEncryptionVersion = "v1"
 )
 
 type baseEncryption struct {
@@ -29,6 +34,7 @@ type baseEncryption struct {
 	methods    []config.MethodConfig
 	encMethod  method.Method
 	encMeta    keyProviderMetadata
+	envelope   bool
 	staticEval *configs.StaticEvaluator
 }
 
@@ -37,15 +43,29 @@ type keyProviderMetamap map[keyprovider.MetaStorageKey][]byte
 type keyProviderMetadata struct {
 	input  keyProviderMetamap
 	output keyProviderMetamap
+	// expired collects the key providers whose keys in the input metadata exceeded the maximum age of their rotation
+	// policy. It may be nil if the age of the keys is not relevant.
+	expired keyProviderExpiry
 }
 
-func newBaseEncryption(enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (*baseEncryption, hcl.Diagnostics) {
+func newBaseEncryption(enc *encryption, target *config.TargetConfig, enforced bool, envelope bool, name string, staticEval *configs.StaticEvaluator) (*baseEncryption, hcl.Diagnostics) {
 	// Lookup method configs for the target, ordered by fallback precedence
 	methods, diags := methodConfigsFromTarget(enc.cfg, target, name, enforced)
 	if diags.HasErrors() {
 		return nil, diags
 	}
 
+	// The payload is encrypted with the algorithm of the primary method in envelope encryption, which requires a method
+	// that can be set up with a data key instead of a key provider.
+	if _, ok := dataKeyMethods[method.ID(methods[0].Type)]; envelope && !ok && !unencrypted.IsConfig(methods[0]) {
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported method for envelope encryption",
+			Detail:   fmt.Sprintf("Envelope encryption is not supported with the %q method of the %s. Supported methods are: %s.", methods[0].Type, name, dataKeyMethodIDs()),
+			Subject:  enc.cfg.DeclRange.Ptr(),
+		})
+	}
+
 	// Setup the encryptor
 	//
 	//     Instead of creating new encryption key data for each call to encrypt, we use the same encryptor for the given application (statefile or planfile).
@@ -95,15 +115,18 @@ func newBaseEncryption(enc *encryption, target *config.TargetConfig, enforced bo
 		methods:    methods,
 		encMethod:  encMethod,
 		encMeta:    encMeta,
+		envelope:   envelope,
 	}
 
 	return base, diags
 }
 
 type basedata struct {
-	Meta    keyProviderMetamap `json:"meta"`
-	Data    []byte             `json:"encrypted_data"`
-	Version string             `json:"encryption_version"` // This is both a sigil for a valid encrypted payload and a future compatibility field
+	Meta       keyProviderMetamap `json:"meta"`
+	Data       []byte             `json:"encrypted_data"`
+	WrappedKey []byte             `json:"wrapped_key,omitempty"` // Only present in envelope encrypted payloads
+	DataMethod method.ID          `json:"data_method,omitempty"` // Only present in envelope encrypted payloads
+	Version    string             `json:"encryption_version"`    // This is both a sigil for a valid encrypted payload and a future compatibility field
 }
 
 func IsEncryptionPayload(data []byte) (bool, error) {
@@ -124,15 +147,38 @@ func (base *baseEncryption) encrypt(data []byte, enhance func(basedata) interfac
 		return data, nil
 	}
 
-	encd, err := encryptor.Encrypt(data)
-	if err != nil {
-		return nil, fmt.Errorf("encryption failed for %s: %w", base.name, err)
-	}
-
-	es := basedata{
-		Version: encryptionVersion,
-		Meta:    base.encMeta.output,
-		Data:    encd,
+	var es basedata
+	if base.envelope {
+		dataKey, err := newDataKey()
+		if err != nil {
+			return nil, fmt.Errorf("encryption failed for %s: %w", base.name, err)
+		}
+		dataMethod := method.ID(base.methods[0].Type)
+		encd, err := sealWithDataKey(dataMethod, dataKey, data)
+		if err != nil {
+			return nil, fmt.Errorf("encryption failed for %s: %w", base.name, err)
+		}
+		wrappedKey, err := encryptor.Encrypt(dataKey)
+		if err != nil {
+			return nil, fmt.Errorf("data key encryption failed for %s: %w", base.name, err)
+		}
+		es = basedata{
+			Version:    envelopeEncryptionVersion,
+			Meta:       base.encMeta.output,
+			Data:       encd,
+			WrappedKey: wrappedKey,
+			DataMethod: dataMethod,
+		}
+	} else {
+		encd, err := encryptor.Encrypt(data)
+		if err != nil {
+			return nil, fmt.Errorf("encryption failed for %s: %w", base.name, err)
+		}
+		es = basedata{
+			Version: encryptionVersion,
+			Meta:    base.encMeta.output,
+			Data:    encd,
+		}
 	}
 
 	jsond, err := json.Marshal(enhance(es))
@@ -149,6 +195,9 @@ const (
 	StatusUnknown   EncryptionStatus = 0
 	StatusSatisfied EncryptionStatus = 1
 	StatusMigration EncryptionStatus = 2
+	// StatusRotation means that the primary method decrypted the payload, but one of its keys exceeded the maximum
+	// age of its rotation policy. Like with StatusMigration, the payload should be encrypted again.
+	StatusRotation EncryptionStatus = 3
 )
 
 // TODO Find a way to make these errors actionable / clear
@@ -189,16 +238,121 @@ func (base *baseEncryption) decrypt(data []byte, validator func([]byte) error) (
 		// Decrypted and pending migration
 		return data, StatusMigration, nil
 	}
+	switch inputData.Version {
+	case encryptionVersion:
+		return base.withMethods(inputData.Meta, func(decMethod method.Method) ([]byte, error) {
+			return decMethod.Decrypt(inputData.Data)
+		})
+	case envelopeEncryptionVersion:
+		if inputData.DataMethod == "" {
+			return nil, StatusUnknown, fmt.Errorf("invalid envelope encrypted payload for %s: missing data key method", base.name)
+		}
+		dataKey, status, err := base.withMethods(inputData.Meta, func(decMethod method.Method) ([]byte, error) {
+			return decMethod.Decrypt(inputData.WrappedKey)
+		})
+		if err != nil {
+			return nil, status, err
+		}
+		uncd, err := openWithDataKey(inputData.DataMethod, dataKey, inputData.Data)
+		if err != nil {
+			return nil, StatusUnknown, fmt.Errorf("decryption failed for %s: %w", base.name, err)
+		}
+		return uncd, status, nil
+	default:
+		return nil, StatusUnknown, fmt.Errorf("invalid encrypted payload version: %s", inputData.Version)
+	}
+}
+
+// rewrap decrypts the data key of an envelope encrypted payload with the configured methods and encrypts it again
+// with the primary method, leaving the encrypted data itself untouched.
+func (base *baseEncryption) rewrap(data []byte, enhance func(basedata) interface{}) ([]byte, error) {
+	inputData := basedata{}
+	err := json.Unmarshal(data, &inputData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data format for rewrapping: %w", err)
+	}
+
+	switch inputData.Version {
+	case envelopeEncryptionVersion:
+		if inputData.DataMethod == "" {
+			return nil, fmt.Errorf("invalid envelope encrypted payload for %s: missing data key method", base.name)
+		}
+	case "":
+		return nil, fmt.Errorf("the %s is not encrypted", base.name)
+	case encryptionVersion:
+		return nil, fmt.Errorf("the %s is not envelope encrypted, so there is no data key to rewrap", base.name)
+	default:
+		return nil, fmt.Errorf("invalid encrypted payload version: %s", inputData.Version)
+	}
+
+	if unencrypted.Is(base.encMethod) {
+		return nil, fmt.Errorf("unable to rewrap the data key for %s with the unencrypted method", base.name)
+	}
+
+	dataKey, _, err := base.withMethods(inputData.Meta, func(decMethod method.Method) ([]byte, error) {
+		return decMethod.Decrypt(inputData.WrappedKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedKey, err := base.encMethod.Encrypt(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("data key encryption failed for %s: %w", base.name, err)
+	}
+
+	es := basedata{
+		Version:    envelopeEncryptionVersion,
+		Meta:       base.encMeta.output,
+		Data:       inputData.Data,
+		WrappedKey: wrappedKey,
+		DataMethod: inputData.DataMethod,
+	}
+
+	jsond, err := json.Marshal(enhance(es))
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode encrypted data as json: %w", err)
+	}
+
+	return jsond, nil
+}
+
+// withMethods calls fn with each of the configured methods in order of precedence, set up with the given key provider
+// metadata, until one of them succeeds. The status is StatusMigration if a fallback method had to be used.
+func (base *baseEncryption) withMethods(meta keyProviderMetamap, fn func(method.Method) ([]byte, error)) ([]byte, EncryptionStatus, error) {
+	expired := make(keyProviderExpiry)
+	result, used, err := base.tryMethods(meta, expired, fn)
+	if err != nil {
+		return nil, StatusUnknown, err
+	}
+	return result, base.methodStatus(used, expired), nil
+}
+
+// methodStatus returns the status of a payload decrypted with the method at the given index of base.methods.
+func (base *baseEncryption) methodStatus(used int, expired keyProviderExpiry) EncryptionStatus {
+	if used != 0 {
+		// Used a fallback
+		return StatusMigration
+	}
+	if len(expired) != 0 {
+		for metaKey, age := range expired {
+			log.Printf("[WARN] The %s key of %s is %s old, which exceeds the maximum age of its rotation policy", metaKey, base.name, age.Round(time.Second))
+		}
+		return StatusRotation
+	}
+	// Decrypted with first method (encryption method)
+	return StatusSatisfied
+}
+
+// tryMethods is like withMethods, but returns the index of the method in base.methods that succeeded. The key
+// providers whose keys exceeded their maximum age are collected in expired.
+func (base *baseEncryption) tryMethods(meta keyProviderMetamap, expired keyProviderExpiry, fn func(method.Method) ([]byte, error)) ([]byte, int, error) {
 	// This is not actually used, only the map inside the Meta parameter is. This is because we are passing the map
 	// around.
 	outputData := basedata{
 		Meta: make(keyProviderMetamap),
 	}
 
-	if inputData.Version != encryptionVersion {
-		return nil, StatusUnknown, fmt.Errorf("invalid encrypted payload version: %s != %s", inputData.Version, encryptionVersion)
-	}
-
 	errs := make([]error, 0)
 	for i, method := range base.methods {
 		if unencrypted.IsConfig(method) {
@@ -208,23 +362,19 @@ func (base *baseEncryption) decrypt(data []byte, validator func([]byte) error) (
 
 		// TODO Discuss if we should potentially cache this based on a json-encoded version of inputData.Meta and reduce overhead dramatically
 		decMethod, diags := setupMethod(base.enc.cfg, method, keyProviderMetadata{
-			input:  inputData.Meta,
-			output: outputData.Meta,
+			input:   meta,
+			output:  outputData.Meta,
+			expired: expired,
 		}, base.enc.reg, base.staticEval)
 		if diags.HasErrors() {
 			// This cast to error here is safe as we know that at least one error exists
-			return nil, StatusUnknown, diags
+			return nil, -1, diags
 		}
 
-		uncd, err := decMethod.Decrypt(inputData.Data)
+		uncd, err := fn(decMethod)
 		if err == nil {
 			// Success
-			if i == 0 {
-				// Decrypted with first method (encryption method)
-				return uncd, StatusSatisfied, nil
-			}
-			// Used a fallback
-			return uncd, StatusMigration, nil
+			return uncd, i, nil
 		}
 		// Record the failure
 		errs = append(errs, fmt.Errorf("attempted decryption failed for %s: %w", base.name, err))
@@ -232,5 +382,5 @@ func (base *baseEncryption) decrypt(data []byte, validator func([]byte) error) (
 
 	errs = append([]error{fmt.Errorf("decryption failed for all provided methods")}, errs...)
 
-	return nil, StatusUnknown, errors.New(errors.Join(errs...).Error())
+	return nil, -1, errors.New(errors.Join(errs...).Error())
 }
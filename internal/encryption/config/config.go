@@ -6,6 +6,9 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
 	"github.com/opentofu/opentofu/internal/encryption/method"
@@ -45,10 +48,31 @@ func (c *EncryptionConfig) GetKeyProvider(kpType, kpName string) (KeyProviderCon
 // encryption. The Body field will contain the remaining undeclared fields the key provider can consume.
 type KeyProviderConfig struct {
 	// EncryptedMetadataAlias contains the key to identify the metadata by.
-	EncryptedMetadataAlias string   `hcl:"encrypted_metadata_alias,optional"`
-	Type                   string   `hcl:"type,label"`
-	Name                   string   `hcl:"name,label"`
-	Body                   hcl.Body `hcl:",remain"`
+	EncryptedMetadataAlias string `hcl:"encrypted_metadata_alias,optional"`
+	// Rotation limits the age of the keys the key provider generates.
+	Rotation *RotationConfig `hcl:"rotation,block"`
+	Type     string          `hcl:"type,label"`
+	Name     string          `hcl:"name,label"`
+	Body     hcl.Body        `hcl:",remain"`
+}
+
+// RotationConfig describes the terraform.encryption.key_provider.*.rotation block you can use to limit the age of the
+// keys a key provider generates. When the key a state was encrypted with is older than MaxAge, the state is encrypted
+// again with a new key the next time it is written.
+type RotationConfig struct {
+	MaxAge string `hcl:"max_age"`
+}
+
+// MaxAgeDuration parses the MaxAge field.
+func (r RotationConfig) MaxAgeDuration() (time.Duration, error) {
+	maxAge, err := time.ParseDuration(r.MaxAge)
+	if err != nil {
+		return 0, err
+	}
+	if maxAge <= 0 {
+		return 0, fmt.Errorf("the maximum age must be positive")
+	}
+	return maxAge, nil
 }
 
 // Addr returns a keyprovider.Addr from the current configuration.
@@ -86,8 +110,12 @@ type TargetConfig struct {
 // Note: This struct is copied because gohcl does not support embedding.
 type EnforceableTargetConfig struct {
 	Enforced bool           `hcl:"enforced,optional"`
+	Envelope bool           `hcl:"envelope,optional"`
 	Method   hcl.Expression `hcl:"method,optional"`
 	Fallback *TargetConfig  `hcl:"fallback,block"`
+
+	// Outputs is only supported in the terraform.encryption.state block.
+	Outputs *OutputsTargetConfig `hcl:"outputs,block"`
 }
 
 // AsTargetConfig converts the struct into its parent TargetConfig.
@@ -115,3 +143,19 @@ func (n NamedTargetConfig) AsTargetConfig() *TargetConfig {
 		Fallback: n.Fallback,
 	}
 }
+
+// OutputsTargetConfig describes the terraform.encryption.state.outputs block you can use to publish the root module
+// outputs in a separately encrypted part of the state file. This allows terraform_remote_state data sources to read the
+// outputs without being able to decrypt the rest of the state.
+type OutputsTargetConfig struct {
+	Method hcl.Expression `hcl:"method"`
+	// Names lists the outputs to publish. If it is not set, all root module outputs are published.
+	Names *[]string `hcl:"names,optional"`
+}
+
+// AsTargetConfig converts the struct into its parent TargetConfig.
+func (o OutputsTargetConfig) AsTargetConfig() *TargetConfig {
+	return &TargetConfig{
+		Method: o.Method,
+	}
+}
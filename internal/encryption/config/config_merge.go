@@ -67,6 +67,9 @@ func mergeKeyProviderConfigs(configs []KeyProviderConfig, overrides []KeyProvide
 			if keyProvider.Type == override.Type && keyProvider.Name == override.Name {
 				// Override the existing key provider.
 				merged[i].Body = mergeBody(keyProvider.Body, override.Body)
+				if override.Rotation != nil {
+					merged[i].Rotation = override.Rotation
+				}
 				wasOverridden = true
 				break
 			}
@@ -116,9 +119,32 @@ func mergeEnforceableTargetConfigs(cfg *EnforceableTargetConfig, override *Enfor
 	mergeTarget := mergeTargetConfigs(cfg.AsTargetConfig(), override.AsTargetConfig())
 	return &EnforceableTargetConfig{
 		Enforced: cfg.Enforced || override.Enforced,
+		Envelope: cfg.Envelope || override.Envelope,
 		Method:   mergeTarget.Method,
 		Fallback: mergeTarget.Fallback,
+		Outputs:  mergeOutputsTargetConfigs(cfg.Outputs, override.Outputs),
+	}
+}
+
+func mergeOutputsTargetConfigs(cfg *OutputsTargetConfig, override *OutputsTargetConfig) *OutputsTargetConfig {
+	if cfg == nil {
+		return override
+	}
+	if override == nil {
+		return cfg
+	}
+
+	merged := &OutputsTargetConfig{
+		Method: cfg.Method,
+		Names:  cfg.Names,
+	}
+	if override.Method != nil {
+		merged.Method = override.Method
 	}
+	if override.Names != nil {
+		merged.Names = override.Names
+	}
+	return merged
 }
 
 func mergeRemoteConfigs(cfg *RemoteConfig, override *RemoteConfig) *RemoteConfig {
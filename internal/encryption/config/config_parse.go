@@ -39,6 +39,17 @@ func DecodeConfig(body hcl.Body, rng hcl.Range) (*EncryptionConfig, hcl.Diagnost
 		// Ensure that the key_provider address is valid
 		_, kpDiags := kp.Addr()
 		diags = diags.Extend(kpDiags)
+
+		if kp.Rotation != nil {
+			if _, err := kp.Rotation.MaxAgeDuration(); err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid key rotation max_age",
+					Detail:   fmt.Sprintf("The max_age of key_provider.%s.%s must be a duration such as \"720h\": %s.", kp.Type, kp.Name, err),
+					Subject:  rng.Ptr(),
+				})
+			}
+		}
 	}
 
 	for i, m := range cfg.MethodConfigs {
@@ -75,6 +86,15 @@ func DecodeConfig(body hcl.Body, rng hcl.Range) (*EncryptionConfig, hcl.Diagnost
 		}
 	}
 
+	if cfg.Plan != nil && cfg.Plan.Outputs != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported outputs block",
+			Detail:   "The outputs block is only supported in the state block, plans have no separately encrypted outputs.",
+			Subject:  rng.Ptr(),
+		})
+	}
+
 	if diags.HasErrors() {
 		return nil, diags
 	}
@@ -0,0 +1,87 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+)
+
+// Description describes how a state or plan file is encrypted, as far as the current configuration can tell.
+type Description struct {
+	// Encrypted is false if the file is not encrypted at all.
+	Encrypted bool
+	// Envelope is true if the file is envelope encrypted.
+	Envelope bool
+	// Method is the address of the configured method that decrypts the file. It is empty if the file is not
+	// encrypted or if none of the configured methods can decrypt it.
+	Method method.Addr
+	// Status is StatusSatisfied if the primary method can decrypt the file, StatusMigration if only a fallback method
+	// can and StatusUnknown if none of them can. It is StatusRotation if the primary method can decrypt the file, but
+	// one of its keys exceeded the maximum age of its rotation policy.
+	Status EncryptionStatus
+	// KeyProviderMeta contains the key provider metadata stored in the file, by metadata key. This metadata is not
+	// secret, but it tells which key providers were used to encrypt the file.
+	KeyProviderMeta map[keyprovider.MetaStorageKey]json.RawMessage
+}
+
+// describePayload describes the encryption of the given payload without attempting to decrypt it. The result is
+// false if the payload is not encrypted.
+func describePayload(data []byte) (Description, basedata, bool) {
+	inputData := basedata{}
+	if err := json.Unmarshal(data, &inputData); err != nil || len(inputData.Version) == 0 {
+		return Description{}, inputData, false
+	}
+
+	desc := Description{
+		Encrypted:       true,
+		Envelope:        inputData.Version == envelopeEncryptionVersion,
+		KeyProviderMeta: make(map[keyprovider.MetaStorageKey]json.RawMessage, len(inputData.Meta)),
+	}
+	for key, meta := range inputData.Meta {
+		desc.KeyProviderMeta[key] = meta
+	}
+	return desc, inputData, true
+}
+
+// describe describes the encryption of the given payload and determines which of the configured methods can
+// decrypt it. The validator is used in the same way as in decrypt.
+func (base *baseEncryption) describe(data []byte, validator func([]byte) error) (Description, error) {
+	desc, inputData, encrypted := describePayload(data)
+	if !encrypted {
+		// Let decrypt check if an unencrypted payload is acceptable.
+		_, status, err := base.decrypt(data, validator)
+		desc.Status = status
+		return desc, err
+	}
+
+	var payload []byte
+	switch inputData.Version {
+	case encryptionVersion:
+		payload = inputData.Data
+	case envelopeEncryptionVersion:
+		// Decrypting the data key is enough to know which method applies.
+		payload = inputData.WrappedKey
+	default:
+		return desc, fmt.Errorf("invalid encrypted payload version: %s", inputData.Version)
+	}
+
+	expired := make(keyProviderExpiry)
+	_, used, err := base.tryMethods(inputData.Meta, expired, func(decMethod method.Method) ([]byte, error) {
+		return decMethod.Decrypt(payload)
+	})
+	if err != nil {
+		return desc, err
+	}
+	addr, diags := base.methods[used].Addr()
+	if diags.HasErrors() {
+		return desc, diags
+	}
+	desc.Method = addr
+	desc.Status = base.methodStatus(used, expired)
+	return desc, nil
+}
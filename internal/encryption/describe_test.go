@@ -0,0 +1,64 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDescribeState(t *testing.T) {
+	oldEnc := newEnvelopeTestEncryption(t, envelopeOldConfig)
+	newEnc := newEnvelopeTestEncryption(t, envelopeNewConfig)
+
+	testData := []byte(`{"serial": 42, "lineage": "magic", "terraform_version": "1.0.0"}`)
+	encryptedState, err := oldEnc.State().EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	desc, err := oldEnc.State().DescribeState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if !desc.Encrypted || !desc.Envelope || desc.Method != "method.aes_gcm.old" || desc.Status != StatusSatisfied {
+		t.Fatalf("Incorrect description: %#v", desc)
+	}
+	if _, ok := desc.KeyProviderMeta["key_provider.pbkdf2.old"]; !ok {
+		t.Fatalf("Missing key provider metadata: %#v", desc.KeyProviderMeta)
+	}
+
+	desc, err = newEnc.State().DescribeState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if desc.Method != "method.aes_gcm.old" || desc.Status != StatusMigration {
+		t.Fatalf("Incorrect description with fallback: %#v", desc)
+	}
+
+	// Without encryption, we can still tell what the state contains.
+	desc, err = StateEncryptionDisabled().DescribeState(encryptedState)
+	if err == nil || !strings.Contains(err.Error(), "state encryption is not configured") {
+		t.Fatalf("Incorrect error: %v", err)
+	}
+	if !desc.Encrypted || desc.Method != "" || desc.Status != StatusUnknown {
+		t.Fatalf("Incorrect description without encryption: %#v", desc)
+	}
+	if _, ok := desc.KeyProviderMeta["key_provider.pbkdf2.old"]; !ok {
+		t.Fatalf("Missing key provider metadata without encryption: %#v", desc.KeyProviderMeta)
+	}
+
+	desc, err = StateEncryptionDisabled().DescribeState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if desc.Encrypted || desc.Status != StatusSatisfied {
+		t.Fatalf("Incorrect description of unencrypted state: %#v", desc)
+	}
+
+	// An unencrypted state is not acceptable without an unencrypted fallback.
+	if _, err := newEnc.State().DescribeState(testData); err == nil {
+		t.Fatalf("Expected error for unencrypted state, got none.")
+	}
+}
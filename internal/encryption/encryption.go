@@ -67,22 +67,29 @@ func New(reg registry.Registry, cfg *config.EncryptionConfig, staticEval *config
 	var encDiags hcl.Diagnostics
 
 	if cfg.State != nil {
-		enc.state, encDiags = newStateEncryption(enc, cfg.State.AsTargetConfig(), cfg.State.Enforced, "state", staticEval)
+		state, encDiags := newStateEncryption(enc, cfg.State.AsTargetConfig(), cfg.State.Enforced, cfg.State.Envelope, "state", staticEval)
 		diags = append(diags, encDiags...)
+		if cfg.State.Outputs != nil {
+			state.outputs, encDiags = newOutputsEncryption(enc, cfg.State.Outputs, cfg.State.Enforced, "state.outputs", staticEval)
+			diags = append(diags, encDiags...)
+		}
+		enc.state = state
 	} else {
 		enc.state = StateEncryptionDisabled()
 	}
 
 	if cfg.Plan != nil {
-		enc.plan, encDiags = newPlanEncryption(enc, cfg.Plan.AsTargetConfig(), cfg.Plan.Enforced, "plan", staticEval)
+		enc.plan, encDiags = newPlanEncryption(enc, cfg.Plan.AsTargetConfig(), cfg.Plan.Enforced, cfg.Plan.Envelope, "plan", staticEval)
 		diags = append(diags, encDiags...)
 	} else {
 		enc.plan = PlanEncryptionDisabled()
 	}
 
 	if cfg.Remote != nil && cfg.Remote.Default != nil {
-		enc.remoteDefault, encDiags = newStateEncryption(enc, cfg.Remote.Default, false, "remote.default", staticEval)
+		remote, encDiags := newStateEncryption(enc, cfg.Remote.Default, false, false, "remote.default", staticEval)
 		diags = append(diags, encDiags...)
+		remote.readOutputs = true
+		enc.remoteDefault = remote
 	} else {
 		enc.remoteDefault = StateEncryptionDisabled()
 	}
@@ -91,8 +98,10 @@ func New(reg registry.Registry, cfg *config.EncryptionConfig, staticEval *config
 		for _, remoteTarget := range cfg.Remote.Targets {
 			// TODO the addr here should be generated in one place.
 			addr := "remote.remote_state_datasource." + remoteTarget.Name
-			enc.remotes[remoteTarget.Name], encDiags = newStateEncryption(enc, remoteTarget.AsTargetConfig(), false, addr, staticEval)
+			remote, encDiags := newStateEncryption(enc, remoteTarget.AsTargetConfig(), false, false, addr, staticEval)
 			diags = append(diags, encDiags...)
+			remote.readOutputs = true
+			enc.remotes[remoteTarget.Name] = remote
 		}
 	}
 	if diags.HasErrors() {
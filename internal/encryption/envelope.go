@@ -0,0 +1,87 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"crypto/rand"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcmsiv"
+	"github.com/opentofu/opentofu/internal/encryption/method/xchacha20poly1305"
+)
+
+// dataKeyLength is the length of the random data keys used in envelope encryption. All data key methods accept 32
+// byte keys, selecting AES-256 for the AES based methods.
+const dataKeyLength = 32
+
+// dataKeyMethods holds, by method ID, the methods that can encrypt the payload itself with a data key in envelope
+// encryption. The payload is encrypted with the same algorithm as the primary method, which only encrypts the data key.
+var dataKeyMethods = map[method.ID]func(keys keyprovider.Output) method.Config{
+	aesgcm.New().ID(): func(keys keyprovider.Output) method.Config {
+		return &aesgcm.Config{Keys: keys}
+	},
+	aesgcmsiv.New().ID(): func(keys keyprovider.Output) method.Config {
+		return &aesgcmsiv.Config{Keys: keys}
+	},
+	xchacha20poly1305.New().ID(): func(keys keyprovider.Output) method.Config {
+		return &xchacha20poly1305.Config{Keys: keys}
+	},
+}
+
+// dataKeyMethodIDs returns a human-readable list of the methods that support envelope encryption.
+func dataKeyMethodIDs() string {
+	ids := make([]string, 0, len(dataKeyMethods))
+	for id := range dataKeyMethods {
+		ids = append(ids, string(id))
+	}
+	slices.Sort(ids)
+	return strings.Join(ids, ", ")
+}
+
+// newDataKey generates a random data key for envelope encryption. A new data key is generated for each payload, so the
+// amount of data encrypted under a single data key never approaches the limits of the method.
+func newDataKey() ([]byte, error) {
+	dataKey := make([]byte, dataKeyLength)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// dataKeyMethod returns the method with the given ID used to encrypt the payload itself with a data key in envelope
+// encryption.
+func dataKeyMethod(id method.ID, dataKey []byte) (method.Method, error) {
+	newConfig, ok := dataKeyMethods[id]
+	if !ok {
+		return nil, fmt.Errorf("unsupported data key method %q, supported methods are: %s", id, dataKeyMethodIDs())
+	}
+	if len(dataKey) != dataKeyLength {
+		return nil, fmt.Errorf("invalid data key length: %d != %d", len(dataKey), dataKeyLength)
+	}
+	return newConfig(keyprovider.Output{
+		EncryptionKey: dataKey,
+		DecryptionKey: dataKey,
+	}).Build()
+}
+
+func sealWithDataKey(id method.ID, dataKey []byte, data []byte) ([]byte, error) {
+	m, err := dataKeyMethod(id, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return m.Encrypt(data)
+}
+
+func openWithDataKey(id method.ID, dataKey []byte, data []byte) ([]byte, error) {
+	m, err := dataKeyMethod(id, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return m.Decrypt(data)
+}
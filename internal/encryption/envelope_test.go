@@ -0,0 +1,339 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pbkdf2"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcmsiv"
+	externalMethod "github.com/opentofu/opentofu/internal/encryption/method/external"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/method/xchacha20poly1305"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+func newEnvelopeTestEncryption(t *testing.T, cfg string) Encryption {
+	t.Helper()
+
+	enc, diags := loadEnvelopeTestEncryption(t, cfg)
+	if diags.HasErrors() {
+		t.Fatalf("%v", diags.Error())
+	}
+	return enc
+}
+
+func loadEnvelopeTestEncryption(t *testing.T, cfg string) (Encryption, hcl.Diagnostics) {
+	t.Helper()
+
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(pbkdf2.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcmsiv.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(xchacha20poly1305.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(externalMethod.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+
+	parsedConfig, diags := config.LoadConfigFromString("test", cfg)
+	if diags.HasErrors() {
+		t.Fatalf("%v", diags.Error())
+	}
+
+	staticEval := configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting())
+
+	return New(reg, parsedConfig, staticEval)
+}
+
+const envelopeOldConfig = `key_provider "pbkdf2" "old" {
+		passphrase = "Hello world! 123"
+	}
+	method "aes_gcm" "old" {
+		keys = key_provider.pbkdf2.old
+	}
+	state {
+		envelope = true
+		method   = method.aes_gcm.old
+	}
+	plan {
+		envelope = true
+		method   = method.aes_gcm.old
+	}`
+
+const envelopeNewConfig = `key_provider "pbkdf2" "old" {
+		passphrase = "Hello world! 123"
+	}
+	key_provider "pbkdf2" "new" {
+		passphrase = "OpenTofu has Encryption"
+	}
+	method "aes_gcm" "old" {
+		keys = key_provider.pbkdf2.old
+	}
+	method "aes_gcm" "new" {
+		keys = key_provider.pbkdf2.new
+	}
+	state {
+		envelope = true
+		method   = method.aes_gcm.new
+		fallback {
+			method = method.aes_gcm.old
+		}
+	}`
+
+func TestEnvelopeEncryption(t *testing.T) {
+	enc := newEnvelopeTestEncryption(t, envelopeOldConfig)
+
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encryptedState, err := enc.State().EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var payload basedata
+	if err := json.Unmarshal(encryptedState, &payload); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if payload.Version != envelopeEncryptionVersion {
+		t.Fatalf("Incorrect encryption version: %s", payload.Version)
+	}
+	if len(payload.WrappedKey) == 0 {
+		t.Fatalf("The encrypted state has no wrapped data key.")
+	}
+	if payload.DataMethod != "aes_gcm" {
+		t.Fatalf("Incorrect data key method: %s", payload.DataMethod)
+	}
+
+	decryptedState, status, err := enc.State().DecryptState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status != StatusSatisfied {
+		t.Fatalf("Incorrect encryption status: %v", status)
+	}
+	if string(decryptedState) != string(testData) {
+		t.Fatalf("Incorrect decrypted state: %s", decryptedState)
+	}
+
+	// Each payload has its own data key.
+	again, err := enc.State().EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	var againPayload basedata
+	if err := json.Unmarshal(again, &againPayload); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if string(againPayload.WrappedKey) == string(payload.WrappedKey) {
+		t.Fatalf("The same data key was used for two payloads.")
+	}
+
+	testPlan := []byte("PK\x03\x04 plan file")
+	encryptedPlan, err := enc.Plan().EncryptPlan(testPlan)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	decryptedPlan, err := enc.Plan().DecryptPlan(encryptedPlan)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if string(decryptedPlan) != string(testPlan) {
+		t.Fatalf("Incorrect decrypted plan: %s", decryptedPlan)
+	}
+}
+
+func TestEnvelopeEncryptionDataMethod(t *testing.T) {
+	for _, methodType := range []string{"aes_gcm_siv", "xchacha20poly1305"} {
+		t.Run(methodType, func(t *testing.T) {
+			enc := newEnvelopeTestEncryption(t, strings.ReplaceAll(envelopeOldConfig, "aes_gcm", methodType))
+
+			testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+			encryptedState, err := enc.State().EncryptState(testData)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+
+			var payload basedata
+			if err := json.Unmarshal(encryptedState, &payload); err != nil {
+				t.Fatalf("%v", err)
+			}
+			if string(payload.DataMethod) != methodType {
+				t.Fatalf("Incorrect data key method: %s", payload.DataMethod)
+			}
+
+			decryptedState, _, err := enc.State().DecryptState(encryptedState)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if string(decryptedState) != string(testData) {
+				t.Fatalf("Incorrect decrypted state: %s", decryptedState)
+			}
+		})
+	}
+}
+
+func TestEnvelopeEncryptionDataMethodMissing(t *testing.T) {
+	enc := newEnvelopeTestEncryption(t, envelopeOldConfig)
+
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encryptedState, err := enc.State().EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(encryptedState, &payload); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// Envelope encrypted payloads must always record the data key method.
+	delete(payload, "data_method")
+	encryptedState, err = json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if _, _, err := enc.State().DecryptState(encryptedState); err == nil || !strings.Contains(err.Error(), "missing data key method") {
+		t.Fatalf("Incorrect error: %v", err)
+	}
+
+	// A data key method that isn't supported is an error rather than being ignored.
+	payload["data_method"] = "external"
+	encryptedState, err = json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if _, _, err := enc.State().DecryptState(encryptedState); err == nil || !strings.Contains(err.Error(), `unsupported data key method "external"`) {
+		t.Fatalf("Incorrect error: %v", err)
+	}
+}
+
+func TestEnvelopeEncryptionUnsupportedMethod(t *testing.T) {
+	_, diags := loadEnvelopeTestEncryption(t, `method "external" "foo" {
+		encrypt_command = ["./encrypt"]
+		decrypt_command = ["./decrypt"]
+	}
+	state {
+		envelope = true
+		method   = method.external.foo
+	}`)
+	if !diags.HasErrors() {
+		t.Fatalf("Expected an error, got none.")
+	}
+	if !strings.Contains(diags.Error(), "Unsupported method for envelope encryption") {
+		t.Fatalf("Incorrect error: %v", diags.Error())
+	}
+}
+
+func TestEnvelopeEncryptionRewrap(t *testing.T) {
+	oldEnc := newEnvelopeTestEncryption(t, envelopeOldConfig)
+	newEnc := newEnvelopeTestEncryption(t, envelopeNewConfig)
+
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encryptedState, err := oldEnc.State().EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// Before rewrapping, the new configuration needs its fallback.
+	_, status, err := newEnc.State().DecryptState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status != StatusMigration {
+		t.Fatalf("Incorrect encryption status: %v", status)
+	}
+
+	rewrapped, err := newEnc.State().RewrapState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var before, after struct {
+		statedata
+		basedata
+	}
+	if err := json.Unmarshal(encryptedState, &before); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := json.Unmarshal(rewrapped, &after); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if string(before.Data) != string(after.Data) {
+		t.Fatalf("The encrypted state has changed during rewrapping.")
+	}
+	if string(before.WrappedKey) == string(after.WrappedKey) {
+		t.Fatalf("The data key has not been rewrapped.")
+	}
+	if after.Serial == nil || *after.Serial != 42 || after.Lineage != "magic" {
+		t.Fatalf("The state metadata has not been preserved: %s", rewrapped)
+	}
+
+	decryptedState, status, err := newEnc.State().DecryptState(rewrapped)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status != StatusSatisfied {
+		t.Fatalf("Incorrect encryption status: %v", status)
+	}
+	if string(decryptedState) != string(testData) {
+		t.Fatalf("Incorrect decrypted state: %s", decryptedState)
+	}
+
+	// The old key provider can no longer decrypt the rewrapped state.
+	if _, _, err := oldEnc.State().DecryptState(rewrapped); err == nil {
+		t.Fatalf("The old configuration decrypted the rewrapped state.")
+	}
+}
+
+func TestEnvelopeEncryptionRewrapInvalid(t *testing.T) {
+	enc := newEnvelopeTestEncryption(t, envelopeNewConfig)
+	plainEnc := newEnvelopeTestEncryption(t, strings.ReplaceAll(envelopeOldConfig, "envelope = true", ""))
+
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encryptedState, err := plainEnc.State().EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	tests := map[string]struct {
+		input   []byte
+		wantErr string
+	}{
+		"unencrypted": {
+			input:   testData,
+			wantErr: "the state is not encrypted",
+		},
+		"not envelope encrypted": {
+			input:   encryptedState,
+			wantErr: "the state is not envelope encrypted",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := enc.State().RewrapState(tc.input)
+			if err == nil {
+				t.Fatalf("Expected error, got none.")
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("Incorrect error: %v", err)
+			}
+		})
+	}
+}
@@ -53,6 +53,37 @@ func (v valueMap) hclEvalContext(root string) *hcl.EvalContext {
 	}
 }
 
+func (v valueMap) clone() valueMap {
+	result := make(valueMap, len(v))
+	for first, values := range v {
+		for second, value := range values {
+			result.set(first, second, value)
+		}
+	}
+	return result
+}
+
+// keyProviderFailures records the diagnostics of the key providers that could not be set up, so every key provider
+// depending on a failed one sees the same outcome, regardless of the order in which they are set up.
+type keyProviderFailures map[config.KeyProviderConfig]hcl.Diagnostics
+
+// keyProviderUnavailable is set as the Extra field of diagnostics reporting that a correctly configured key provider
+// failed at runtime. Only these failures can be tolerated by key providers implementing keyprovider.FaultTolerantConfig.
+type keyProviderUnavailable struct{}
+
+// isKeyProviderUnavailable returns true if all errors in diags are runtime failures of key providers.
+func isKeyProviderUnavailable(diags hcl.Diagnostics) bool {
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		if _, ok := diag.Extra.(keyProviderUnavailable); !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // Given a set of hcl.Traversals, determine the required key provider configs and non-key_provider references
 func filterKeyProviderReferences(cfg *config.EncryptionConfig, deps []hcl.Traversal) ([]config.KeyProviderConfig, []*addrs.Reference, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
@@ -117,15 +148,43 @@ func setupKeyProviders(enc *config.EncryptionConfig, cfgs []config.KeyProviderCo
 	var diags hcl.Diagnostics
 
 	kpData := make(valueMap)
+	failures := make(keyProviderFailures)
 
 	for _, keyProviderConfig := range cfgs {
-		diags = diags.Extend(setupKeyProvider(enc, keyProviderConfig, kpData, nil, meta, reg, staticEval))
+		kpDiags := setupKeyProvider(enc, keyProviderConfig, kpData, failures, nil, meta, reg, staticEval)
+		// A failed key provider reports the same diagnostics to each of its dependents, so we only keep one copy.
+		for _, diag := range kpDiags {
+			if !containsDiagnostic(diags, diag) {
+				diags = diags.Append(diag)
+			}
+		}
 	}
 
 	return kpData.hclEvalContext("key_provider"), diags
 }
 
-func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig, kpData valueMap, stack []config.KeyProviderConfig, meta keyProviderMetadata, reg registry.Registry, staticEval *configs.StaticEvaluator) hcl.Diagnostics {
+func containsDiagnostic(diags hcl.Diagnostics, diag *hcl.Diagnostic) bool {
+	for _, d := range diags {
+		if *d == *diag {
+			return true
+		}
+	}
+	return false
+}
+
+func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig, kpData valueMap, failures keyProviderFailures, stack []config.KeyProviderConfig, meta keyProviderMetadata, reg registry.Registry, staticEval *configs.StaticEvaluator) hcl.Diagnostics {
+	// If this key provider has already failed, report the same failure again so every dependent handles it consistently.
+	if diags, failed := failures[cfg]; failed {
+		return diags
+	}
+	diags := buildKeyProvider(enc, cfg, kpData, failures, stack, meta, reg, staticEval)
+	if diags.HasErrors() {
+		failures[cfg] = diags
+	}
+	return diags
+}
+
+func buildKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig, kpData valueMap, failures keyProviderFailures, stack []config.KeyProviderConfig, meta keyProviderMetadata, reg registry.Registry, staticEval *configs.StaticEvaluator) hcl.Diagnostics {
 	// Check if we have already setup this Descriptor (due to dependency loading)
 	// if we've already setup this key provider, then we don't need to do it again
 	// and we can return early
@@ -202,14 +261,40 @@ func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig
 	}
 
 	// Ensure all key provider dependencies have been initialized
+	tolerant := false
+	if ftc, ok := keyProviderConfig.(keyprovider.FaultTolerantConfig); ok {
+		tolerant = ftc.ToleratesKeyProviderFailures()
+	}
+	var tolerated []config.KeyProviderConfig
 	for _, kp := range kpConfigs {
-		diags = diags.Extend(setupKeyProvider(enc, kp, kpData, stack, meta, reg, staticEval))
+		kpDiags := setupKeyProvider(enc, kp, kpData, failures, stack, meta, reg, staticEval)
+		if tolerant && kpDiags.HasErrors() && isKeyProviderUnavailable(kpDiags) {
+			// The key provider can do without this dependency, so we pass null in its place and only warn about it.
+			tolerated = append(tolerated, kp)
+			for _, diag := range kpDiags {
+				warning := *diag
+				warning.Severity = hcl.DiagWarning
+				warning.Summary = fmt.Sprintf("Key provider %s.%s unavailable: %s", kp.Type, kp.Name, diag.Summary)
+				diags = diags.Append(&warning)
+			}
+			continue
+		}
+		diags = diags.Extend(kpDiags)
 	}
 	if diags.HasErrors() {
 		return diags
 	}
 
-	evalCtx, evalDiags := staticEval.EvalContextWithParent(kpData.hclEvalContext("key_provider"), configs.StaticIdentifier{
+	// The null values of tolerated failures are only visible to this key provider, other dependents still see the failure.
+	depData := kpData
+	if len(tolerated) != 0 {
+		depData = kpData.clone()
+		for _, kp := range tolerated {
+			depData.set(kp.Type, kp.Name, cty.NullVal(cty.DynamicPseudoType))
+		}
+	}
+
+	evalCtx, evalDiags := staticEval.EvalContextWithParent(depData.hclEvalContext("key_provider"), configs.StaticIdentifier{
 		Module:    addrs.RootModule,
 		Subject:   fmt.Sprintf("encryption.key_provider.%s.%s", cfg.Type, cfg.Name),
 		DeclRange: enc.DeclRange,
@@ -244,9 +329,17 @@ func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig
 		})
 	}
 
+	if cfg.Rotation != nil && keyMetaIn == nil {
+		return diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Key rotation not supported",
+			Detail:   fmt.Sprintf("%s does not generate new keys, so it does not support the rotation block", metaKey),
+		})
+	}
+
 	// Add the metadata
-	if meta, ok := meta.input[metaKey]; ok {
-		err := json.Unmarshal(meta, keyMetaIn)
+	if rawMeta, ok := meta.input[metaKey]; ok {
+		err := json.Unmarshal(rawMeta, keyMetaIn)
 		if err != nil {
 			return diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
@@ -254,15 +347,31 @@ func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig
 				Detail:   fmt.Sprintf("metadata decoder for %s failed with error: %s", metaKey, err.Error()),
 			})
 		}
+
+		if cfg.Rotation != nil && meta.expired != nil {
+			if err := checkKeyAge(cfg, metaKey, rawMeta, meta.expired); err != nil {
+				return diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unable to determine the age of the encryption key",
+					Detail:   fmt.Sprintf("%s failed with error: %s", metaKey, err.Error()),
+				})
+			}
+		}
 	}
 
 	output, keyMetaOut, err := keyProvider.Provide(keyMetaIn)
 	if err != nil {
-		return diags.Append(&hcl.Diagnostic{
+		diag := &hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Unable to fetch encryption key data",
 			Detail:   fmt.Sprintf("%s failed with error: %s", metaKey, err.Error()),
-		})
+		}
+		var failure *keyprovider.ErrKeyProviderFailure
+		var failureVal keyprovider.ErrKeyProviderFailure
+		if errors.As(err, &failure) || errors.As(err, &failureVal) {
+			diag.Extra = keyProviderUnavailable{}
+		}
+		return diags.Append(diag)
 	}
 
 	if keyMetaOut != nil {
@@ -282,11 +391,22 @@ func setupKeyProvider(enc *config.EncryptionConfig, cfg config.KeyProviderConfig
 				Detail:   fmt.Sprintf("The metadata encoder for %s failed with error: %s", metaKey, err.Error()),
 			})
 		}
+
+		if cfg.Rotation != nil {
+			meta.output[metaKey], err = addKeyCreationTime(meta.output[metaKey])
+			if err != nil {
+				return diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unable to record the creation time of the encryption key",
+					Detail:   fmt.Sprintf("%s failed with error: %s", metaKey, err.Error()),
+				})
+			}
+		}
 	}
 
 	kpData.set(cfg.Type, cfg.Name, output.Cty())
 
-	return nil
+	return diags
 
 }
 
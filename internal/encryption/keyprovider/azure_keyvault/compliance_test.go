@@ -0,0 +1,183 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package azure_keyvault
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/compliancetest"
+)
+
+func getKey(t *testing.T) (vaultURI string, keyName string) {
+	if os.Getenv("TF_ACC") == "" && os.Getenv("TF_KMS_TEST") == "" {
+		return "", ""
+	}
+	return os.Getenv("TF_AZURE_KEYVAULT_URI"), os.Getenv("TF_AZURE_KEYVAULT_KEY_NAME")
+}
+
+func TestKeyProvider(t *testing.T) {
+	vaultURI, keyName := getKey(t)
+
+	if vaultURI == "" {
+		keyName = "test-key"
+		standIn := newKeyVaultStandIn(t)
+		standIn.addKeyVersion(t, keyName)
+		vaultURI = standIn.server.URL
+
+		injectStandIn(t)
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_uri = "%s"
+							key_name  = "%s"
+						}`, vaultURI, keyName),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if keyProvider.keyName != keyName {
+							return fmt.Errorf("incorrect key name returned")
+						}
+						if keyProvider.algorithm != defaultAlgorithm {
+							return fmt.Errorf("incorrect default algorithm: %s", keyProvider.algorithm)
+						}
+						if keyProvider.keyLength != defaultKeyLength {
+							return fmt.Errorf("incorrect default key length: %d", keyProvider.keyLength)
+						}
+						return nil
+					},
+				},
+				"success-full": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_uri   = "%s/"
+							key_name    = "%s"
+							key_version = "v1"
+							algorithm   = "RSA-OAEP"
+							key_length  = 16
+							tenant_id   = "00000000-0000-0000-0000-000000000000"
+							client_id   = "00000000-0000-0000-0000-000000000000"
+						}`, vaultURI, keyName),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if strings.HasSuffix(keyProvider.vaultURI, "/") {
+							return fmt.Errorf("the trailing slash was not removed from the vault URI")
+						}
+						if keyProvider.keyVersion != "v1" {
+							return fmt.Errorf("incorrect key version returned")
+						}
+						if keyProvider.algorithm != "RSA-OAEP" {
+							return fmt.Errorf("incorrect algorithm returned")
+						}
+						return nil
+					},
+				},
+				"empty": {
+					HCL:        `key_provider "azure_keyvault" "foo" {}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+				"invalid-vault-uri": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_uri = "example"
+							key_name  = "%s"
+						}`, keyName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"empty-key-name": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_uri = "%s"
+							key_name  = ""
+						}`, vaultURI),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-algorithm": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_uri = "%s"
+							key_name  = "%s"
+							algorithm = "RSA1_5"
+						}`, vaultURI, keyName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-key-length": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_uri  = "%s"
+							key_name   = "%s"
+							key_length = 256
+						}`, vaultURI, keyName),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: fmt.Sprintf(`key_provider "azure_keyvault" "foo" {
+							vault_uri = "%s"
+							key_name  = "%s"
+							foo       = "bar"
+						}`, vaultURI, keyName),
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config: &Config{
+						VaultURI: vaultURI,
+						KeyName:  keyName,
+					},
+					ValidBuild: true,
+				},
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"empty": {
+					ValidConfig: &Config{
+						VaultURI: vaultURI,
+						KeyName:  keyName,
+					},
+					Meta:      &keyMeta{},
+					IsPresent: false,
+					IsValid:   false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: &Config{
+					VaultURI: vaultURI,
+					KeyName:  keyName,
+				},
+				ValidateKeys: func(dec []byte, enc []byte) error {
+					if len(dec) == 0 {
+						return fmt.Errorf("decryption key is empty")
+					}
+					if len(enc) == 0 {
+						return fmt.Errorf("encryption key is empty")
+					}
+					return nil
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if len(meta.Ciphertext) == 0 {
+						return fmt.Errorf("ciphertext is empty")
+					}
+					if meta.KeyID == "" {
+						return fmt.Errorf("key ID is empty")
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
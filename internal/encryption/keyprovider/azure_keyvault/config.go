@@ -0,0 +1,235 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package azure_keyvault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/v7.1/keyvault"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/hashicorp/go-azure-helpers/authentication"
+	"github.com/manicminer/hamilton/environments"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/version"
+)
+
+type keyVaultClientInit func(ctx context.Context, c Config) (keyVaultClient, error)
+
+// Can be overridden for test mocking
+var newKeyVaultClient keyVaultClientInit = func(ctx context.Context, c Config) (keyVaultClient, error) {
+	authorizer, err := c.authorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newSDKClient(authorizer), nil
+}
+
+func newSDKClient(authorizer autorest.Authorizer) keyVaultClient {
+	client := keyvault.New()
+	client.Authorizer = authorizer
+	client.UserAgent = httpclient.OpenTofuUserAgent(version.Version)
+	client.Sender = &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+		},
+	}
+	return client
+}
+
+type Config struct {
+	VaultURI   string `hcl:"vault_uri"`
+	KeyName    string `hcl:"key_name"`
+	KeyVersion string `hcl:"key_version,optional"`
+	Algorithm  string `hcl:"algorithm,optional"`
+	KeyLength  int    `hcl:"key_length,optional"`
+
+	// The authentication options mirror the azurerm remote state backend.
+	Environment               string `hcl:"environment,optional"`
+	MetadataHost              string `hcl:"metadata_host,optional"`
+	TenantID                  string `hcl:"tenant_id,optional"`
+	ClientID                  string `hcl:"client_id,optional"`
+	ClientSecret              string `hcl:"client_secret,optional"`
+	ClientCertificatePath     string `hcl:"client_certificate_path,optional"`
+	ClientCertificatePassword string `hcl:"client_certificate_password,optional"`
+	UseMSI                    bool   `hcl:"use_msi,optional"`
+	MSIEndpoint               string `hcl:"msi_endpoint,optional"`
+	UseOIDC                   bool   `hcl:"use_oidc,optional"`
+	OIDCToken                 string `hcl:"oidc_token,optional"`
+	OIDCTokenFilePath         string `hcl:"oidc_token_file_path,optional"`
+	OIDCRequestURL            string `hcl:"oidc_request_url,optional"`
+	OIDCRequestToken          string `hcl:"oidc_request_token,optional"`
+}
+
+const (
+	defaultAlgorithm   = keyvault.RSAOAEP256
+	defaultKeyLength   = 32
+	defaultEnvironment = "public"
+)
+
+// validAlgorithms holds the key wrapping algorithms supported by this key provider. RSA1_5 is deliberately left out
+// because it is vulnerable to padding oracle attacks.
+var validAlgorithms = []keyvault.JSONWebKeyEncryptionAlgorithm{keyvault.RSAOAEP, keyvault.RSAOAEP256}
+
+func stringAttrEnvFallback(val string, env ...string) string {
+	if val != "" {
+		return val
+	}
+	for _, e := range env {
+		if v := os.Getenv(e); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func boolAttrEnvFallback(val bool, env string) bool {
+	if val {
+		return val
+	}
+	v, _ := strconv.ParseBool(os.Getenv(env))
+	return v
+}
+
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	// Apply env defaults if necessary
+	c.Environment = stringAttrEnvFallback(c.Environment, "ARM_ENVIRONMENT")
+	if c.Environment == "" {
+		c.Environment = defaultEnvironment
+	}
+	c.MetadataHost = stringAttrEnvFallback(c.MetadataHost, "ARM_METADATA_HOST")
+	c.TenantID = stringAttrEnvFallback(c.TenantID, "ARM_TENANT_ID")
+	c.ClientID = stringAttrEnvFallback(c.ClientID, "ARM_CLIENT_ID")
+	c.ClientSecret = stringAttrEnvFallback(c.ClientSecret, "ARM_CLIENT_SECRET")
+	c.ClientCertificatePath = stringAttrEnvFallback(c.ClientCertificatePath, "ARM_CLIENT_CERTIFICATE_PATH")
+	c.ClientCertificatePassword = stringAttrEnvFallback(c.ClientCertificatePassword, "ARM_CLIENT_CERTIFICATE_PASSWORD")
+	c.UseMSI = boolAttrEnvFallback(c.UseMSI, "ARM_USE_MSI")
+	c.MSIEndpoint = stringAttrEnvFallback(c.MSIEndpoint, "ARM_MSI_ENDPOINT")
+	c.UseOIDC = boolAttrEnvFallback(c.UseOIDC, "ARM_USE_OIDC")
+	c.OIDCToken = stringAttrEnvFallback(c.OIDCToken, "ARM_OIDC_TOKEN")
+	c.OIDCTokenFilePath = stringAttrEnvFallback(c.OIDCTokenFilePath, "ARM_OIDC_TOKEN_FILE_PATH")
+	c.OIDCRequestURL = stringAttrEnvFallback(c.OIDCRequestURL, "ARM_OIDC_REQUEST_URL", "ACTIONS_ID_TOKEN_REQUEST_URL")
+	c.OIDCRequestToken = stringAttrEnvFallback(c.OIDCRequestToken, "ARM_OIDC_REQUEST_TOKEN", "ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+
+	if c.VaultURI == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "vault_uri must be provided"}
+	}
+	vaultURL, err := url.Parse(c.VaultURI)
+	if err != nil || vaultURL.Scheme == "" || vaultURL.Host == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: fmt.Sprintf("vault_uri must be a URL, such as https://example.vault.azure.net/, got %q", c.VaultURI)}
+	}
+
+	if c.KeyName == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "key_name must be provided"}
+	}
+
+	algorithm := defaultAlgorithm
+	if c.Algorithm != "" {
+		algorithm = keyvault.JSONWebKeyEncryptionAlgorithm(c.Algorithm)
+		valid := false
+		for _, a := range validAlgorithms {
+			if a == algorithm {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			names := make([]string, len(validAlgorithms))
+			for i, a := range validAlgorithms {
+				names[i] = string(a)
+			}
+			return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: fmt.Sprintf("algorithm must be one of %s, got %q", strings.Join(names, ", "), c.Algorithm)}
+		}
+	}
+
+	if c.KeyLength == 0 {
+		c.KeyLength = defaultKeyLength
+	}
+	if c.KeyLength < 1 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "key_length must be at least 1"}
+	}
+	// RSA-OAEP with SHA-256 and a 2048 bit key, the smallest key size Key Vault supports, can wrap at most 190 bytes.
+	if c.KeyLength > 190 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "key_length must be at most 190"}
+	}
+
+	ctx := context.Background()
+
+	svc, err := newKeyVaultClient(ctx, c)
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Cause: err}
+	}
+
+	return &keyProvider{
+		svc:        svc,
+		ctx:        ctx,
+		vaultURI:   strings.TrimSuffix(c.VaultURI, "/"),
+		keyName:    c.KeyName,
+		keyVersion: c.KeyVersion,
+		algorithm:  algorithm,
+		keyLength:  c.KeyLength,
+	}, new(keyMeta), nil
+}
+
+// authorizer obtains a token for the Key Vault API, mirroring the authentication of the azurerm remote state backend.
+func (c Config) authorizer(ctx context.Context) (autorest.Authorizer, error) {
+	env, err := authentication.AzureEnvironmentByNameFromEndpoint(ctx, c.MetadataHost, c.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := authentication.Builder{
+		ClientID:     c.ClientID,
+		TenantID:     c.TenantID,
+		TenantOnly:   true,
+		MetadataHost: c.MetadataHost,
+		Environment:  c.Environment,
+
+		// Service Principal (Client Certificate)
+		ClientCertPassword: c.ClientCertificatePassword,
+		ClientCertPath:     c.ClientCertificatePath,
+
+		// Service Principal (Client Secret)
+		ClientSecret: c.ClientSecret,
+
+		// Managed Service Identity
+		MsiEndpoint: c.MSIEndpoint,
+
+		// OIDC
+		IDToken:             c.OIDCToken,
+		IDTokenFilePath:     c.OIDCTokenFilePath,
+		IDTokenRequestURL:   c.OIDCRequestURL,
+		IDTokenRequestToken: c.OIDCRequestToken,
+
+		// Feature Toggles
+		SupportsAzureCliToken:          true,
+		SupportsClientCertAuth:         true,
+		SupportsClientSecretAuth:       true,
+		SupportsManagedServiceIdentity: c.UseMSI,
+		SupportsOIDCAuth:               c.UseOIDC,
+		UseMicrosoftGraph:              true,
+	}
+	armConfig, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("error building ARM config: %w", err)
+	}
+
+	oauthConfig, err := armConfig.BuildOAuthConfig(env.ActiveDirectoryEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	hamiltonEnv, err := environments.EnvironmentFromString(c.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	return armConfig.GetMSALToken(ctx, hamiltonEnv.KeyVault, autorest.CreateSender(), oauthConfig, env.ResourceIdentifiers.KeyVault)
+}
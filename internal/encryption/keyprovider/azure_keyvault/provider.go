@@ -0,0 +1,136 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package azure_keyvault
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"path"
+
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/v7.1/keyvault"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+type keyMeta struct {
+	// KeyID is the full identifier, including the version, of the Key Vault key that wrapped the data key. This lets
+	// us unwrap the data key after the key has been rotated to a new version.
+	KeyID      string `json:"key_id"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return len(m.Ciphertext) != 0
+}
+
+type keyVaultClient interface {
+	WrapKey(ctx context.Context, vaultBaseURL string, keyName string, keyVersion string, parameters keyvault.KeyOperationsParameters) (keyvault.KeyOperationResult, error)
+	UnwrapKey(ctx context.Context, vaultBaseURL string, keyName string, keyVersion string, parameters keyvault.KeyOperationsParameters) (keyvault.KeyOperationResult, error)
+}
+
+type keyProvider struct {
+	svc        keyVaultClient
+	ctx        context.Context
+	vaultURI   string
+	keyName    string
+	keyVersion string
+	algorithm  keyvault.JSONWebKeyEncryptionAlgorithm
+	keyLength  int
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: no metadata struct provided"}
+	}
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: invalid metadata struct type"}
+	}
+
+	outMeta := &keyMeta{}
+	out := keyprovider.Output{}
+
+	// Generate new key
+	out.EncryptionKey = make([]byte, p.keyLength)
+	_, err := rand.Read(out.EncryptionKey)
+	if err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to generate key",
+			Cause:   err,
+		}
+	}
+
+	// Wrap the new encryption key using the Key Vault key
+	wrapped, err := p.svc.WrapKey(p.ctx, p.vaultURI, p.keyName, p.keyVersion, keyvault.KeyOperationsParameters{
+		Algorithm: p.algorithm,
+		Value:     encodeValue(out.EncryptionKey),
+	})
+	if err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to wrap key (check if the configuration is valid and the key vault is accessible)",
+			Cause:   err,
+		}
+	}
+	if wrapped.Kid == nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "the key vault did not return the identifier of the key used to wrap the key",
+		}
+	}
+	outMeta.KeyID = *wrapped.Kid
+	outMeta.Ciphertext, err = decodeValue(wrapped.Result)
+	if err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to decode wrapped key",
+			Cause:   err,
+		}
+	}
+
+	// We do not set the DecryptionKey here as we should only be setting the decryption key if we are decrypting
+	// and that is handled below when we check if the inMeta has a Ciphertext
+
+	if inMeta.isPresent() {
+		// The data key must be unwrapped with the same key version that wrapped it, which may no longer be the
+		// latest one.
+		keyVersion := p.keyVersion
+		if inMeta.KeyID != "" {
+			keyVersion = path.Base(inMeta.KeyID)
+		}
+
+		unwrapped, err := p.svc.UnwrapKey(p.ctx, p.vaultURI, p.keyName, keyVersion, keyvault.KeyOperationsParameters{
+			Algorithm: p.algorithm,
+			Value:     encodeValue(inMeta.Ciphertext),
+		})
+		if err != nil {
+			return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+				Message: "failed to unwrap key (check if the configuration is valid and the key vault is accessible)",
+				Cause:   err,
+			}
+		}
+
+		// Set decryption key on the output
+		out.DecryptionKey, err = decodeValue(unwrapped.Result)
+		if err != nil {
+			return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+				Message: "failed to decode unwrapped key",
+				Cause:   err,
+			}
+		}
+	}
+
+	return out, outMeta, nil
+}
+
+// encodeValue encodes a value as the unpadded URL-safe base64 string that the Key Vault API expects.
+func encodeValue(value []byte) *string {
+	encoded := base64.RawURLEncoding.EncodeToString(value)
+	return &encoded
+}
+
+func decodeValue(value *string) ([]byte, error) {
+	if value == nil {
+		return nil, fmt.Errorf("the key vault returned no value")
+	}
+	return base64.RawURLEncoding.DecodeString(*value)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package azure_keyvault
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestKeyProvider_keyRotation(t *testing.T) {
+	standIn := newKeyVaultStandIn(t)
+	oldVersion := standIn.addKeyVersion(t, "test-key")
+	injectStandIn(t)
+
+	provider, meta, err := Config{
+		VaultURI: standIn.server.URL,
+		KeyName:  "test-key",
+	}.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, firstMeta, err := provider.Provide(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(firstMeta.(*keyMeta).KeyID, "/"+oldVersion) {
+		t.Fatalf("the key was not wrapped with the latest version %s: %s", oldVersion, firstMeta.(*keyMeta).KeyID)
+	}
+
+	// After the key is rotated, new keys are wrapped with the new version, but
+	// existing keys must still be unwrapped with the version that wrapped them.
+	newVersion := standIn.addKeyVersion(t, "test-key")
+
+	second, secondMeta, err := provider.Provide(firstMeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(second.DecryptionKey, first.EncryptionKey) {
+		t.Fatalf("the unwrapped key does not match the original key")
+	}
+	if !strings.HasSuffix(secondMeta.(*keyMeta).KeyID, "/"+newVersion) {
+		t.Fatalf("the key was not wrapped with the latest version %s: %s", newVersion, secondMeta.(*keyMeta).KeyID)
+	}
+}
+
+func TestKeyProvider_keyNotFound(t *testing.T) {
+	standIn := newKeyVaultStandIn(t)
+	injectStandIn(t)
+
+	provider, meta, err := Config{
+		VaultURI: standIn.server.URL,
+		KeyName:  "missing-key",
+	}.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = provider.Provide(meta)
+	if err == nil {
+		t.Fatal("expected an error for a missing key")
+	}
+	if !strings.Contains(err.Error(), "failed to wrap key") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package azure_keyvault
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // RSA-OAEP in Key Vault uses SHA-1
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// keyVaultStandIn is a local HTTP stand-in for the wrapkey and unwrapkey operations of the Azure Key Vault API,
+// wrapping keys with real RSA keys.
+type keyVaultStandIn struct {
+	server *httptest.Server
+
+	mu sync.Mutex
+	// keys holds the versions of each key by name, the last one being the latest.
+	keys map[string][]standInKeyVersion
+}
+
+type standInKeyVersion struct {
+	version string
+	key     *rsa.PrivateKey
+}
+
+func newKeyVaultStandIn(t *testing.T) *keyVaultStandIn {
+	t.Helper()
+
+	s := &keyVaultStandIn{
+		keys: make(map[string][]standInKeyVersion),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// injectStandIn makes the key provider use the stand-in without authentication.
+func injectStandIn(t *testing.T) {
+	t.Helper()
+
+	original := newKeyVaultClient
+	newKeyVaultClient = func(_ context.Context, _ Config) (keyVaultClient, error) {
+		return newSDKClient(autorest.NullAuthorizer{}), nil
+	}
+	t.Cleanup(func() {
+		newKeyVaultClient = original
+	})
+}
+
+// addKeyVersion adds a new latest version to the named key, creating the key if needed.
+func (s *keyVaultStandIn) addKeyVersion(t *testing.T, name string) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	version := fmt.Sprintf("v%d", len(s.keys[name])+1)
+	s.keys[name] = append(s.keys[name], standInKeyVersion{version: version, key: key})
+	return version
+}
+
+func (s *keyVaultStandIn) handle(w http.ResponseWriter, r *http.Request) {
+	// /keys/{key-name}/{key-version}/{operation}
+	parts := strings.Split(r.URL.Path, "/")
+	if r.Method != http.MethodPost || len(parts) != 5 || parts[1] != "keys" {
+		s.fail(w, http.StatusNotFound, "NotFound", "unknown request: "+r.Method+" "+r.URL.Path)
+		return
+	}
+	name, version, operation := parts[2], parts[3], parts[4]
+
+	var req struct {
+		Algorithm string `json:"alg"`
+		Value     string `json:"value"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.fail(w, http.StatusBadRequest, "BadParameter", err.Error())
+		return
+	}
+	value, err := base64.RawURLEncoding.DecodeString(req.Value)
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, "BadParameter", err.Error())
+		return
+	}
+
+	var h hash.Hash
+	switch req.Algorithm {
+	case "RSA-OAEP":
+		h = sha1.New() //nolint:gosec // RSA-OAEP in Key Vault uses SHA-1
+	case "RSA-OAEP-256":
+		h = sha256.New()
+	default:
+		s.fail(w, http.StatusBadRequest, "BadParameter", "unsupported algorithm "+req.Algorithm)
+		return
+	}
+
+	s.mu.Lock()
+	versions := s.keys[name]
+	s.mu.Unlock()
+	var key *standInKeyVersion
+	for i := range versions {
+		if versions[i].version == version || (version == "" && i == len(versions)-1) {
+			key = &versions[i]
+		}
+	}
+	if key == nil {
+		s.fail(w, http.StatusNotFound, "KeyNotFound", fmt.Sprintf("key %s version %q not found", name, version))
+		return
+	}
+
+	var result []byte
+	switch operation {
+	case "wrapkey":
+		result, err = rsa.EncryptOAEP(h, rand.Reader, &key.key.PublicKey, value, nil)
+	case "unwrapkey":
+		result, err = rsa.DecryptOAEP(h, rand.Reader, key.key, value, nil)
+	default:
+		s.fail(w, http.StatusNotFound, "NotFound", "unknown operation "+operation)
+		return
+	}
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, "BadParameter", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"kid":   fmt.Sprintf("%s/keys/%s/%s", s.server.URL, name, key.version),
+		"value": base64.RawURLEncoding.EncodeToString(result),
+	})
+}
+
+func (s *keyVaultStandIn) fail(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]string{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
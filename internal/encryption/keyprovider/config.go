@@ -14,3 +14,14 @@ type Config interface {
 	// If a key provider does not need metadata, it may return nil.
 	Build() (KeyProvider, KeyMeta, error)
 }
+
+// FaultTolerantConfig is implemented by the configuration of key providers that can still work when some of the key
+// providers they reference fail, such as threshold schemes. When a referenced key provider fails at runtime with
+// ErrKeyProviderFailure, OpenTofu reports the failure as a warning and passes a null value in its place instead of
+// failing the whole configuration. Configuration errors of the referenced key providers are never tolerated.
+type FaultTolerantConfig interface {
+	Config
+
+	// ToleratesKeyProviderFailures returns true if the referenced key providers may fail.
+	ToleratesKeyProviderFailures() bool
+}
@@ -0,0 +1,186 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/compliancetest"
+)
+
+// getToken returns the configuration of a real PKCS#11 token for acceptance testing, such as a SoftHSM token with an
+// AES key. The PIN is read from the PKCS11_PIN environment variable.
+func getToken(t *testing.T) (library string, tokenLabel string, keyLabel string) {
+	if os.Getenv("TF_ACC") == "" {
+		return "", "", ""
+	}
+	return os.Getenv("TF_PKCS11_LIBRARY"), os.Getenv("TF_PKCS11_TOKEN_LABEL"), os.Getenv("TF_PKCS11_KEY_LABEL")
+}
+
+// The labels and PIN of the SoftHSM token created by setupSoftHSM, also used for the mock token.
+const (
+	softHSMTokenLabel = "opentofu"
+	softHSMKeyLabel   = "state-key"
+	softHSMUserPIN    = "1234"
+)
+
+func TestKeyProvider(t *testing.T) {
+	library, tokenLabel, keyLabel := getToken(t)
+
+	if library == "" {
+		// Use a SoftHSM token if SoftHSM is installed, and otherwise a mock of the token.
+		tokenLabel = softHSMTokenLabel
+		keyLabel = softHSMKeyLabel
+		library = setupSoftHSM(t)
+		if library == "" {
+			library = "/usr/lib/softhsm/libsofthsm2.so"
+			injectMock(t, tokenLabel, newMockToken(t))
+		}
+	}
+
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library     = "%s"
+							token_label = "%s"
+							key_label   = "%s"
+						}`, library, tokenLabel, keyLabel),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if keyProvider.keyLength != defaultKeyLength {
+							return fmt.Errorf("incorrect default key length: %d", keyProvider.keyLength)
+						}
+						return nil
+					},
+				},
+				"success-key-length": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library     = "%s"
+							token_label = "%s"
+							key_label   = "%s"
+							key_length  = 16
+						}`, library, tokenLabel, keyLabel),
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if keyProvider.keyLength != 16 {
+							return fmt.Errorf("incorrect key length: %d", keyProvider.keyLength)
+						}
+						return nil
+					},
+				},
+				"empty": {
+					HCL:        `key_provider "pkcs11" "foo" {}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+				"empty-library": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library     = ""
+							token_label = "%s"
+							key_label   = "%s"
+						}`, tokenLabel, keyLabel),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"empty-key-label": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library     = "%s"
+							token_label = "%s"
+							key_label   = ""
+						}`, library, tokenLabel),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-token": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library     = "%s"
+							token_label = "no-such-token"
+							key_label   = "%s"
+						}`, library, keyLabel),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-key-length": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library     = "%s"
+							token_label = "%s"
+							key_label   = "%s"
+							key_length  = -1
+						}`, library, tokenLabel, keyLabel),
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: fmt.Sprintf(`key_provider "pkcs11" "foo" {
+							library     = "%s"
+							token_label = "%s"
+							key_label   = "%s"
+							foo         = "bar"
+						}`, library, tokenLabel, keyLabel),
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config: &Config{
+						Library:    library,
+						TokenLabel: tokenLabel,
+						KeyLabel:   keyLabel,
+					},
+					ValidBuild: true,
+				},
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"empty": {
+					ValidConfig: &Config{
+						Library:    library,
+						TokenLabel: tokenLabel,
+						KeyLabel:   keyLabel,
+					},
+					Meta:      &keyMeta{},
+					IsPresent: false,
+					IsValid:   false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: &Config{
+					Library:    library,
+					TokenLabel: tokenLabel,
+					KeyLabel:   keyLabel,
+				},
+				ValidateKeys: func(dec []byte, enc []byte) error {
+					if len(dec) == 0 {
+						return fmt.Errorf("decryption key is empty")
+					}
+					if len(enc) == 0 {
+						return fmt.Errorf("encryption key is empty")
+					}
+					return nil
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if len(meta.Ciphertext) == 0 {
+						return fmt.Errorf("ciphertext is empty")
+					}
+					if len(meta.IV) == 0 {
+						return fmt.Errorf("IV is empty")
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
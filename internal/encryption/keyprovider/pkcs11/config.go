@@ -0,0 +1,62 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"os"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+type Config struct {
+	Library    string `hcl:"library"`
+	TokenLabel string `hcl:"token_label"`
+	PIN        string `hcl:"pin,optional"`
+	KeyLabel   string `hcl:"key_label"`
+	KeyLength  int    `hcl:"key_length,optional"`
+}
+
+const defaultKeyLength = 32
+
+type tokenInit func(c Config) (token, error)
+
+// Can be overridden for test mocking
+var newToken tokenInit = newPKCS11Token
+
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	// The PIN is a secret, so we allow passing it in the environment instead of the configuration.
+	if c.PIN == "" {
+		c.PIN = os.Getenv("PKCS11_PIN")
+	}
+
+	if c.Library == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "library must be provided"}
+	}
+	if c.TokenLabel == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "token_label must be provided"}
+	}
+	if c.KeyLabel == "" {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "key_label must be provided"}
+	}
+
+	if c.KeyLength == 0 {
+		c.KeyLength = defaultKeyLength
+	}
+	if c.KeyLength < 1 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "key_length must be at least 1"}
+	}
+	if c.KeyLength > 1024 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Message: "key_length must be at most 1024"}
+	}
+
+	tok, err := newToken(c)
+	if err != nil {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{Cause: err}
+	}
+
+	return &keyProvider{
+		token:     tok,
+		keyLength: c.KeyLength,
+	}, new(keyMeta), nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"testing"
+)
+
+// mockToken stands in for a PKCS#11 token holding a single AES key.
+type mockToken struct {
+	aead cipher.AEAD
+}
+
+func newMockToken(t *testing.T) *mockToken {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &mockToken{aead: aead}
+}
+
+func (m *mockToken) encrypt(plaintext []byte) ([]byte, []byte, error) {
+	iv := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return nil, nil, err
+	}
+	return iv, m.aead.Seal(nil, iv, plaintext, nil), nil
+}
+
+func (m *mockToken) decrypt(iv []byte, ciphertext []byte) ([]byte, error) {
+	if len(iv) != m.aead.NonceSize() {
+		return nil, fmt.Errorf("invalid IV length: %d", len(iv))
+	}
+	return m.aead.Open(nil, iv, ciphertext, nil)
+}
+
+// injectMock replaces the PKCS#11 token with the given mock for the duration of the test. Only the token with the
+// given label is available.
+func injectMock(t *testing.T, tokenLabel string, mock *mockToken) {
+	original := newToken
+	newToken = func(c Config) (token, error) {
+		if c.TokenLabel != tokenLabel {
+			return nil, fmt.Errorf("no PKCS#11 token found with the label %q", c.TokenLabel)
+		}
+		return mock, nil
+	}
+	t.Cleanup(func() {
+		newToken = original
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package pkcs11
+
+import (
+	"crypto/rand"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+type keyMeta struct {
+	IV         []byte `json:"iv"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return len(m.Ciphertext) != 0
+}
+
+// token is the part of a PKCS#11 token that the key provider uses. It encrypts and decrypts data with AES-GCM using
+// the configured secret key, which never leaves the token.
+type token interface {
+	// encrypt returns the ciphertext and the IV that the token used to encrypt the plaintext.
+	encrypt(plaintext []byte) (iv []byte, ciphertext []byte, err error)
+	decrypt(iv []byte, ciphertext []byte) ([]byte, error)
+}
+
+type keyProvider struct {
+	token     token
+	keyLength int
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: no metadata struct provided"}
+	}
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: invalid metadata struct type"}
+	}
+
+	outMeta := &keyMeta{}
+	out := keyprovider.Output{}
+
+	// Generate new key
+	out.EncryptionKey = make([]byte, p.keyLength)
+	_, err := rand.Read(out.EncryptionKey)
+	if err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to generate key",
+			Cause:   err,
+		}
+	}
+
+	// Encrypt new encryption key using the token
+	outMeta.IV, outMeta.Ciphertext, err = p.token.encrypt(out.EncryptionKey)
+	if err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to encrypt key (check if the configuration is valid and the token is accessible)",
+			Cause:   err,
+		}
+	}
+
+	// We do not set the DecryptionKey here as we should only be setting the decryption key if we are decrypting
+	// and that is handled below when we check if the inMeta has a Ciphertext
+
+	if inMeta.isPresent() {
+		// We have an existing decryption key to decrypt, so we should now populate the DecryptionKey
+		out.DecryptionKey, err = p.token.decrypt(inMeta.IV, inMeta.Ciphertext)
+		if err != nil {
+			return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+				Message: "failed to decrypt key (check if the configuration is valid and the token is accessible)",
+				Cause:   err,
+			}
+		}
+	}
+
+	return out, outMeta, nil
+}
@@ -0,0 +1,214 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build cgo
+
+package pkcs11
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+)
+
+// softHSMLibraries are the paths SoftHSM installs its PKCS#11 library to on common Linux distributions and macOS.
+var softHSMLibraries = []string{
+	"/usr/lib/softhsm/libsofthsm2.so",
+	"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+	"/usr/lib64/pkcs11/libsofthsm2.so",
+	"/usr/local/lib/softhsm/libsofthsm2.so",
+	"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+}
+
+// setupSoftHSM creates a SoftHSM token with an AES key in a temporary directory and returns the path to the SoftHSM
+// library, or an empty string if SoftHSM is not installed. The library path can be set with the TF_PKCS11_SOFTHSM
+// environment variable.
+func setupSoftHSM(t *testing.T) string {
+	library := os.Getenv("TF_PKCS11_SOFTHSM")
+	if library == "" {
+		for _, path := range softHSMLibraries {
+			if _, err := os.Stat(path); err == nil {
+				library = path
+				break
+			}
+		}
+	}
+	if library == "" {
+		return ""
+	}
+
+	// SoftHSM reads its configuration when the library is initialized, so the library must not be loaded yet.
+	dir := t.TempDir()
+	tokenDir := filepath.Join(dir, "tokens")
+	if err := os.Mkdir(tokenDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	conf := filepath.Join(dir, "softhsm2.conf")
+	if err := os.WriteFile(conf, []byte("directories.tokendir = "+tokenDir+"\nobjectstore.backend = file\nlog.level = ERROR\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SOFTHSM2_CONF", conf)
+	t.Setenv("PKCS11_PIN", softHSMUserPIN)
+
+	ctx, err := loadModule(library)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		modulesLock.Lock()
+		defer modulesLock.Unlock()
+		delete(modules, library)
+		_ = ctx.Finalize()
+		ctx.Destroy()
+	})
+
+	// SoftHSM always provides a slot with an uninitialized token, and moves the token to a new slot once it's
+	// initialized.
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slots) == 0 {
+		t.Fatal("SoftHSM provides no slots")
+	}
+	if err := ctx.InitToken(slots[0], "so-pin", softHSMTokenLabel); err != nil {
+		t.Fatal(err)
+	}
+	slot, err := findSoftHSMSlot(ctx, softHSMTokenLabel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = ctx.CloseSession(session)
+	}()
+	if err := ctx.Login(session, pkcs11.CKU_SO, "so-pin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.InitPIN(session, softHSMUserPIN); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Logout(session); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Login(session, pkcs11.CKU_USER, softHSMUserPIN); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ctx.GenerateKey(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)}, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, softHSMKeyLabel),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return library
+}
+
+func findSoftHSMSlot(ctx *pkcs11.Ctx, label string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, err
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, err
+		}
+		if info.Label == label {
+			return slot, nil
+		}
+	}
+	return 0, errors.New("initialized SoftHSM token not found")
+}
+
+func TestPKCS11Token(t *testing.T) {
+	library := setupSoftHSM(t)
+	if library == "" {
+		t.Skip("SoftHSM is not installed, set TF_PKCS11_SOFTHSM to the path of libsofthsm2.so to run this test")
+	}
+
+	config := Config{
+		Library:    library,
+		TokenLabel: softHSMTokenLabel,
+		KeyLabel:   softHSMKeyLabel,
+		PIN:        softHSMUserPIN,
+	}
+
+	t.Run("round-trip", func(t *testing.T) {
+		tok, err := newPKCS11Token(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iv, ciphertext, err := tok.encrypt([]byte("Hello world!"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(iv) != gcmIVLength {
+			t.Fatalf("incorrect IV length: %d", len(iv))
+		}
+		plaintext, err := tok.decrypt(iv, ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(plaintext) != "Hello world!" {
+			t.Fatalf("incorrect plaintext: %q", plaintext)
+		}
+
+		ciphertext[0] ^= 1
+		if _, err := tok.decrypt(iv, ciphertext); err == nil {
+			t.Fatal("decrypting a modified ciphertext did not fail")
+		}
+	})
+
+	errorTests := map[string]struct {
+		modify  func(c *Config)
+		wantErr string
+	}{
+		"unknown-token": {
+			modify:  func(c *Config) { c.TokenLabel = "no-such-token" },
+			wantErr: `no PKCS#11 token found with the label "no-such-token"`,
+		},
+		"unknown-key": {
+			modify:  func(c *Config) { c.KeyLabel = "no-such-key" },
+			wantErr: `no AES key found with the label "no-such-key"`,
+		},
+		"wrong-pin": {
+			modify:  func(c *Config) { c.PIN = "4321" },
+			wantErr: "failed to log in to the PKCS#11 token",
+		},
+		"no-pin": {
+			// The key is private, so it can't be found without logging in.
+			modify:  func(c *Config) { c.PIN = "" },
+			wantErr: `no AES key found with the label "state-key"`,
+		},
+	}
+	for name, test := range errorTests {
+		t.Run(name, func(t *testing.T) {
+			c := config
+			test.modify(&c)
+			_, err := newPKCS11Token(c)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
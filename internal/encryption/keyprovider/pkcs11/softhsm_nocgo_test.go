@@ -0,0 +1,15 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !cgo
+
+package pkcs11
+
+import (
+	"testing"
+)
+
+// setupSoftHSM returns an empty string, because the PKCS#11 library can't be loaded without cgo.
+func setupSoftHSM(_ *testing.T) string {
+	return ""
+}
@@ -0,0 +1,184 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build cgo
+
+package pkcs11
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+)
+
+const (
+	gcmIVLength  = 12
+	gcmTagLength = 128
+)
+
+var (
+	modulesLock sync.Mutex
+	// modules holds the initialized PKCS#11 modules by library path. A module can only be initialized once per
+	// process, so all key providers using the same library share it.
+	modules = map[string]*pkcs11.Ctx{}
+)
+
+// loadModule loads and initializes the PKCS#11 library at the given path, or returns the previously loaded one.
+func loadModule(library string) (*pkcs11.Ctx, error) {
+	modulesLock.Lock()
+	defer modulesLock.Unlock()
+
+	if ctx, ok := modules[library]; ok {
+		return ctx, nil
+	}
+	ctx := pkcs11.New(library)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load the PKCS#11 library %s", library)
+	}
+	if err := ctx.Initialize(); err != nil && !isError(err, pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		ctx.Destroy()
+		return nil, fmt.Errorf("failed to initialize the PKCS#11 library %s (%w)", library, err)
+	}
+	modules[library] = ctx
+	return ctx, nil
+}
+
+func isError(err error, code uint) bool {
+	var pkcs11Err pkcs11.Error
+	return errors.As(err, &pkcs11Err) && uint(pkcs11Err) == code
+}
+
+type pkcs11Token struct {
+	ctx      *pkcs11.Ctx
+	slot     uint
+	pin      string
+	keyLabel string
+}
+
+func newPKCS11Token(c Config) (token, error) {
+	ctx, err := loadModule(c.Library)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the PKCS#11 slots (%w)", err)
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the PKCS#11 token information in slot %d (%w)", slot, err)
+		}
+		if info.Label != c.TokenLabel {
+			continue
+		}
+		t := &pkcs11Token{
+			ctx:      ctx,
+			slot:     slot,
+			pin:      c.PIN,
+			keyLabel: c.KeyLabel,
+		}
+		// Check that we can log in and find the key, so configuration errors surface early.
+		err = t.withKey(func(pkcs11.SessionHandle, pkcs11.ObjectHandle) error {
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("no PKCS#11 token found with the label %q", c.TokenLabel)
+}
+
+// withKey opens a session on the token, logs in and finds the configured key to pass it to fn.
+func (t *pkcs11Token) withKey(fn func(pkcs11.SessionHandle, pkcs11.ObjectHandle) error) error {
+	session, err := t.ctx.OpenSession(t.slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("failed to open a PKCS#11 session (%w)", err)
+	}
+	// Closing the last session also logs out of the token.
+	defer func() {
+		_ = t.ctx.CloseSession(session)
+	}()
+
+	if t.pin != "" {
+		if err := t.ctx.Login(session, pkcs11.CKU_USER, t.pin); err != nil && !isError(err, pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+			return fmt.Errorf("failed to log in to the PKCS#11 token (%w)", err)
+		}
+	}
+
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, t.keyLabel),
+	}
+	if err := t.ctx.FindObjectsInit(session, template); err != nil {
+		return fmt.Errorf("failed to search for the PKCS#11 key (%w)", err)
+	}
+	objects, _, err := t.ctx.FindObjects(session, 2)
+	if finalErr := t.ctx.FindObjectsFinal(session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to search for the PKCS#11 key (%w)", err)
+	}
+	switch len(objects) {
+	case 0:
+		return fmt.Errorf("no AES key found with the label %q", t.keyLabel)
+	case 1:
+		return fn(session, objects[0])
+	default:
+		return fmt.Errorf("more than one AES key found with the label %q", t.keyLabel)
+	}
+}
+
+func (t *pkcs11Token) encrypt(plaintext []byte) ([]byte, []byte, error) {
+	iv := make([]byte, gcmIVLength)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate IV (%w)", err)
+	}
+
+	var ciphertext []byte
+	err := t.withKey(func(session pkcs11.SessionHandle, key pkcs11.ObjectHandle) error {
+		params := pkcs11.NewGCMParams(iv, nil, gcmTagLength)
+		defer params.Free()
+
+		if err := t.ctx.EncryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}, key); err != nil {
+			return err
+		}
+		var err error
+		ciphertext, err = t.ctx.Encrypt(session, plaintext)
+		if err != nil {
+			return err
+		}
+		// Some tokens generate the IV themselves, in which case we need to store theirs.
+		if tokenIV := params.IV(); len(tokenIV) != 0 {
+			iv = append([]byte(nil), tokenIV...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return iv, ciphertext, nil
+}
+
+func (t *pkcs11Token) decrypt(iv []byte, ciphertext []byte) ([]byte, error) {
+	var plaintext []byte
+	err := t.withKey(func(session pkcs11.SessionHandle, key pkcs11.ObjectHandle) error {
+		params := pkcs11.NewGCMParams(iv, nil, gcmTagLength)
+		defer params.Free()
+
+		if err := t.ctx.DecryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}, key); err != nil {
+			return err
+		}
+		var err error
+		plaintext, err = t.ctx.Decrypt(session, ciphertext)
+		return err
+	})
+	return plaintext, err
+}
@@ -0,0 +1,14 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !cgo
+
+package pkcs11
+
+import (
+	"errors"
+)
+
+func newPKCS11Token(_ Config) (token, error) {
+	return nil, errors.New("the pkcs11 key provider is not available in this build of OpenTofu, because loading PKCS#11 libraries requires cgo")
+}
@@ -0,0 +1,156 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package shamir
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/compliancetest"
+)
+
+func staticOutput(b byte) *keyprovider.Output {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = b
+	}
+	return &keyprovider.Output{EncryptionKey: key, DecryptionKey: key}
+}
+
+func TestCompliance(t *testing.T) {
+	compliancetest.ComplianceTest(
+		t,
+		compliancetest.TestConfiguration[*descriptor, *Config, *keyMeta, *keyProvider]{
+			Descriptor: New().(*descriptor),
+			HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*Config, *keyProvider]{
+				"success": {
+					HCL: `key_provider "shamir" "foo" {
+						key_providers = [
+							{ encryption_key = [1, 2, 3], decryption_key = null },
+							{ encryption_key = [4, 5, 6], decryption_key = null },
+							{ encryption_key = [7, 8, 9], decryption_key = null },
+						]
+						threshold = 2
+					}`,
+					ValidHCL:   true,
+					ValidBuild: true,
+					Validate: func(config *Config, keyProvider *keyProvider) error {
+						if keyProvider.threshold != 2 {
+							return fmt.Errorf("incorrect threshold: %d", keyProvider.threshold)
+						}
+						if keyProvider.keyLength != defaultKeyLength {
+							return fmt.Errorf("incorrect default key length: %d", keyProvider.keyLength)
+						}
+						return nil
+					},
+				},
+				"empty": {
+					HCL:        `key_provider "shamir" "foo" {}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+				"no-key-providers": {
+					HCL: `key_provider "shamir" "foo" {
+						key_providers = []
+						threshold     = 1
+					}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"threshold-too-large": {
+					HCL: `key_provider "shamir" "foo" {
+						key_providers = [
+							{ encryption_key = [1, 2, 3], decryption_key = null },
+							{ encryption_key = [4, 5, 6], decryption_key = null },
+						]
+						threshold = 3
+					}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"too-few-available": {
+					HCL: `key_provider "shamir" "foo" {
+						key_providers = [
+							{ encryption_key = [1, 2, 3], decryption_key = null },
+							null,
+							null,
+						]
+						threshold = 2
+					}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"invalid-key-length": {
+					HCL: `key_provider "shamir" "foo" {
+						key_providers = [
+							{ encryption_key = [1, 2, 3], decryption_key = null },
+						]
+						threshold  = 1
+						key_length = -1
+					}`,
+					ValidHCL:   true,
+					ValidBuild: false,
+				},
+				"unknown-property": {
+					HCL: `key_provider "shamir" "foo" {
+						key_providers = [
+							{ encryption_key = [1, 2, 3], decryption_key = null },
+						]
+						threshold = 1
+						foo       = "bar"
+					}`,
+					ValidHCL:   false,
+					ValidBuild: false,
+				},
+			},
+			ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *keyProvider]{
+				"success": {
+					Config: &Config{
+						KeyProviders: []*keyprovider.Output{staticOutput(1), staticOutput(2)},
+						Threshold:    2,
+					},
+					ValidBuild: true,
+				},
+				"one-unavailable": {
+					Config: &Config{
+						KeyProviders: []*keyprovider.Output{staticOutput(1), nil, staticOutput(3)},
+						Threshold:    2,
+					},
+					ValidBuild: true,
+				},
+				"empty": {
+					Config:     &Config{},
+					ValidBuild: false,
+				},
+			},
+			MetadataStructTestCases: map[string]compliancetest.MetadataStructTestCase[*Config, *keyMeta]{
+				"empty": {
+					ValidConfig: &Config{
+						KeyProviders: []*keyprovider.Output{staticOutput(1), staticOutput(2)},
+						Threshold:    2,
+					},
+					Meta:      &keyMeta{},
+					IsPresent: false,
+					IsValid:   false,
+				},
+			},
+			ProvideTestCase: compliancetest.ProvideTestCase[*Config, *keyMeta]{
+				ValidConfig: &Config{
+					KeyProviders: []*keyprovider.Output{staticOutput(1), staticOutput(2), staticOutput(3)},
+					Threshold:    2,
+				},
+				ValidateMetadata: func(meta *keyMeta) error {
+					if meta.Threshold != 2 {
+						return fmt.Errorf("incorrect threshold in metadata: %d", meta.Threshold)
+					}
+					if len(meta.Shares) != 3 {
+						return fmt.Errorf("incorrect number of shares in metadata: %d", len(meta.Shares))
+					}
+					return nil
+				},
+			},
+		},
+	)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package shamir
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// Config contains the configuration for this key provider supplied by the user.
+type Config struct {
+	// KeyProviders are the outputs of the key providers that each protect one share of the key. The entry is nil
+	// if the key provider failed.
+	KeyProviders []*keyprovider.Output `hcl:"key_providers"`
+	Threshold    int                   `hcl:"threshold"`
+	KeyLength    int                   `hcl:"key_length,optional"`
+}
+
+const defaultKeyLength = 32
+
+// ToleratesKeyProviderFailures allows OpenTofu to pass in null for failed key providers, so we can continue as long
+// as enough of them remain.
+func (c Config) ToleratesKeyProviderFailures() bool {
+	return true
+}
+
+// Build will create the usable key provider.
+func (c Config) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	if len(c.KeyProviders) < 1 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "at least one key provider must be provided",
+		}
+	}
+	if len(c.KeyProviders) > maxShares {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("at most %d key providers can be provided", maxShares),
+		}
+	}
+	if c.Threshold < 1 || c.Threshold > len(c.KeyProviders) {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("the threshold must be between 1 and the number of key providers (%d)", len(c.KeyProviders)),
+		}
+	}
+	if c.KeyLength == 0 {
+		c.KeyLength = defaultKeyLength
+	}
+	if c.KeyLength < 1 || c.KeyLength > 1024 {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: "key_length must be between 1 and 1024",
+		}
+	}
+
+	available := 0
+	for _, kp := range c.KeyProviders {
+		if kp != nil && len(kp.EncryptionKey) != 0 {
+			available++
+		}
+	}
+	if available < c.Threshold {
+		return nil, nil, &keyprovider.ErrInvalidConfiguration{
+			Message: fmt.Sprintf("only %d of the %d key providers are available, but at least %d are required", available, len(c.KeyProviders), c.Threshold),
+		}
+	}
+
+	return &keyProvider{
+		keyProviders: c.KeyProviders,
+		threshold:    c.Threshold,
+		keyLength:    c.KeyLength,
+	}, new(keyMeta), nil
+}
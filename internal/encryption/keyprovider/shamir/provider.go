@@ -0,0 +1,165 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+// Package shamir contains a key provider that splits a random key into shares with Shamir's secret sharing and
+// protects each share with a different key provider. Any threshold of those key providers can recover the key.
+package shamir
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// shareKeyInfo binds the keys derived for encrypting shares to this key provider.
+const shareKeyInfo = "opentofu shamir key share"
+
+type keyMeta struct {
+	Threshold int `json:"threshold"`
+	// Shares are the encrypted shares. They are not in any particular order, so that the key providers can be
+	// reordered in the configuration.
+	Shares []encryptedShare `json:"shares"`
+}
+
+type encryptedShare struct {
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (m keyMeta) isPresent() bool {
+	return len(m.Shares) != 0
+}
+
+type keyProvider struct {
+	keyProviders []*keyprovider.Output
+	threshold    int
+	keyLength    int
+}
+
+func (p keyProvider) Provide(rawMeta keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	if rawMeta == nil {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: no metadata struct provided"}
+	}
+	inMeta, ok := rawMeta.(*keyMeta)
+	if !ok {
+		return keyprovider.Output{}, nil, &keyprovider.ErrInvalidMetadata{Message: "bug: invalid metadata struct type"}
+	}
+
+	outMeta := &keyMeta{Threshold: p.threshold}
+	out := keyprovider.Output{
+		EncryptionKey: make([]byte, p.keyLength),
+	}
+	if _, err := rand.Read(out.EncryptionKey); err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to generate key",
+			Cause:   err,
+		}
+	}
+
+	shares, err := split(out.EncryptionKey, len(p.keyProviders), p.threshold)
+	if err != nil {
+		return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+			Message: "failed to split key",
+			Cause:   err,
+		}
+	}
+	for i, kp := range p.keyProviders {
+		if kp == nil || len(kp.EncryptionKey) == 0 {
+			// The key provider is unavailable, so the data we encrypt now can only be decrypted by the others.
+			continue
+		}
+		share, err := encryptShare(kp.EncryptionKey, shares[i])
+		if err != nil {
+			return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+				Message: "failed to encrypt key share",
+				Cause:   err,
+			}
+		}
+		outMeta.Shares = append(outMeta.Shares, share)
+	}
+
+	if inMeta.isPresent() {
+		out.DecryptionKey, err = p.recover(inMeta)
+		if err != nil {
+			return out, outMeta, &keyprovider.ErrKeyProviderFailure{
+				Message: "failed to recover decryption key",
+				Cause:   err,
+			}
+		}
+	}
+
+	return out, outMeta, nil
+}
+
+// recover decrypts the shares in the metadata with the available key providers and combines them into the key.
+func (p keyProvider) recover(meta *keyMeta) ([]byte, error) {
+	if meta.Threshold < 1 {
+		return nil, fmt.Errorf("invalid threshold in metadata: %d", meta.Threshold)
+	}
+	var shares [][]byte
+	for _, share := range meta.Shares {
+		// The key providers may have been reordered, so we try each of them. Authenticated encryption tells us which
+		// one is right.
+		for _, kp := range p.keyProviders {
+			if kp == nil || len(kp.DecryptionKey) == 0 {
+				continue
+			}
+			plaintext, err := decryptShare(kp.DecryptionKey, share)
+			if err == nil {
+				shares = append(shares, plaintext)
+				break
+			}
+		}
+		if len(shares) == meta.Threshold {
+			return combine(shares)
+		}
+	}
+	return nil, fmt.Errorf("only %d of the %d required key shares could be decrypted with the available key providers", len(shares), meta.Threshold)
+}
+
+// shareCipher derives an AES-256-GCM cipher for a key share from the key of a child key provider, which can have any
+// length.
+func shareCipher(key []byte) (cipher.AEAD, error) {
+	derived := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, nil, []byte(shareKeyInfo)), derived); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptShare(key []byte, share []byte) (encryptedShare, error) {
+	aead, err := shareCipher(key)
+	if err != nil {
+		return encryptedShare{}, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return encryptedShare{}, err
+	}
+	return encryptedShare{
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, share, nil),
+	}, nil
+}
+
+func decryptShare(key []byte, share encryptedShare) ([]byte, error) {
+	aead, err := shareCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(share.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length: %d", len(share.Nonce))
+	}
+	return aead.Open(nil, share.Nonce, share.Ciphertext, nil)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package shamir
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// This file implements Shamir's secret sharing over GF(2^8), applied to each byte of the secret separately. Each
+// share consists of its x coordinate followed by the y coordinates for each byte of the secret. The field arithmetic
+// and the y coordinates are the same as in github.com/hashicorp/vault/shamir, which puts the x coordinate last; the
+// tests check the implementation against shares produced by it.
+
+// maxShares is the number of distinct non-zero x coordinates available in GF(2^8).
+const maxShares = 255
+
+// gfMul multiplies two elements of GF(2^8) with the AES reduction polynomial without branching on their values.
+func gfMul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= a & -(b & 1)
+		a = (a << 1) ^ (0x1b & -(a >> 7))
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv returns the multiplicative inverse of a non-zero element of GF(2^8), computed as a^254.
+func gfInv(a byte) byte {
+	result := byte(1)
+	power := a
+	for i := 0; i < 7; i++ {
+		power = gfMul(power, power)
+		result = gfMul(result, power)
+	}
+	return result
+}
+
+// split splits the secret into the given number of shares, any threshold of which can reconstruct it.
+func split(secret []byte, shares int, threshold int) ([][]byte, error) {
+	if threshold < 1 || threshold > shares || shares > maxShares {
+		return nil, fmt.Errorf("invalid secret sharing parameters: %d of %d shares", threshold, shares)
+	}
+
+	// coefficients holds the random coefficients of the polynomial for each byte of the secret, with the secret byte
+	// itself as the constant term.
+	coefficients := make([]byte, threshold)
+	result := make([][]byte, shares)
+	for i := range result {
+		result[i] = make([]byte, len(secret)+1)
+		result[i][0] = byte(i + 1)
+	}
+	for pos, secretByte := range secret {
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, fmt.Errorf("failed to generate polynomial coefficients: %w", err)
+		}
+		coefficients[0] = secretByte
+		for _, share := range result {
+			// Horner's method
+			x := share[0]
+			var y byte
+			for c := threshold - 1; c >= 0; c-- {
+				y = gfMul(y, x) ^ coefficients[c]
+			}
+			share[pos+1] = y
+		}
+	}
+	return result, nil
+}
+
+// combine reconstructs the secret from the given shares. The caller must provide at least as many shares as the
+// threshold used when splitting the secret, otherwise the result is meaningless.
+func combine(shares [][]byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("no shares provided")
+	}
+	length := len(shares[0])
+	if length < 2 {
+		return nil, fmt.Errorf("invalid share length: %d", length)
+	}
+	seen := map[byte]bool{}
+	for _, share := range shares {
+		if len(share) != length {
+			return nil, fmt.Errorf("the shares have different lengths")
+		}
+		if share[0] == 0 || seen[share[0]] {
+			return nil, fmt.Errorf("invalid or duplicate share coordinate: %d", share[0])
+		}
+		seen[share[0]] = true
+	}
+
+	// Lagrange interpolation at x = 0. In GF(2^8), subtraction is the same as addition (XOR).
+	secret := make([]byte, length-1)
+	for i, share := range shares {
+		basis := byte(1)
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+			basis = gfMul(basis, gfMul(other[0], gfInv(other[0]^share[0])))
+		}
+		for pos := range secret {
+			secret[pos] ^= gfMul(share[pos+1], basis)
+		}
+	}
+	return secret, nil
+}
@@ -0,0 +1,163 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package shamir
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestGFMul(t *testing.T) {
+	// Known answers from FIPS-197, section 4.2, and the AES S-box construction.
+	testCases := []struct {
+		a, b, product byte
+	}{
+		{0x57, 0x83, 0xc1},
+		{0x57, 0x13, 0xfe},
+		{0x57, 0x02, 0xae},
+		{0x57, 0x04, 0x47},
+		{0x57, 0x08, 0x8e},
+		{0x57, 0x10, 0x07},
+		{0x53, 0xca, 0x01},
+		{0x00, 0xff, 0x00},
+	}
+	for _, tc := range testCases {
+		if product := gfMul(tc.a, tc.b); product != tc.product {
+			t.Errorf("%#02x * %#02x = %#02x, expected %#02x", tc.a, tc.b, product, tc.product)
+		}
+		if product := gfMul(tc.b, tc.a); product != tc.product {
+			t.Errorf("%#02x * %#02x = %#02x, expected %#02x", tc.b, tc.a, product, tc.product)
+		}
+	}
+	if inverse := gfInv(0x53); inverse != 0xca {
+		t.Errorf("inverse of 0x53 = %#02x, expected 0xca", inverse)
+	}
+}
+
+func TestGFInv(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		if product := gfMul(byte(a), gfInv(byte(a))); product != 1 {
+			t.Fatalf("%d * inverse = %d", a, product)
+		}
+	}
+}
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("this is a 32 byte long secret!!!")
+
+	shares, err := split(secret, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("incorrect number of shares: %d", len(shares))
+	}
+
+	// Every combination of three shares must recover the secret.
+	for i := 0; i < len(shares); i++ {
+		for j := i + 1; j < len(shares); j++ {
+			for k := j + 1; k < len(shares); k++ {
+				result, err := combine([][]byte{shares[k], shares[i], shares[j]})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(result, secret) {
+					t.Fatalf("shares %d, %d and %d recovered an incorrect secret: %x", i, j, k, result)
+				}
+			}
+		}
+	}
+
+	// More shares than the threshold also work.
+	result, err := combine(shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(result, secret) {
+		t.Fatalf("all shares recovered an incorrect secret: %x", result)
+	}
+
+	// Fewer shares than the threshold do not.
+	result, err = combine(shares[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(result, secret) {
+		t.Fatalf("two shares recovered the secret")
+	}
+}
+
+func TestCombineKnownAnswer(t *testing.T) {
+	// These shares of a 3 of 5 split were generated by the independent implementation in
+	// github.com/hashicorp/vault/shamir, which works in the same field, with the x coordinate moved to the front.
+	shares := [][]byte{
+		mustDecodeHex(t, "d3e8465c44ac57cab8cc26939c4bfeeddd8638f8b383"),
+		mustDecodeHex(t, "b2b3415253579cd7a3267c536b84959434f6729bdc8f"),
+		mustDecodeHex(t, "0e72d2d1fcd51782e213fe4e24a3cfc4bc73dd70869b"),
+		mustDecodeHex(t, "12e425d9b4f992009a883688eafd106ce731e0caff65"),
+		mustDecodeHex(t, "62e98dfd2b8724e78332b0bfc5032a1ad54898c0c985"),
+	}
+	secret := []byte("OpenTofu dual custody")
+
+	for i := 0; i < len(shares); i++ {
+		for j := i + 1; j < len(shares); j++ {
+			for k := j + 1; k < len(shares); k++ {
+				result, err := combine([][]byte{shares[i], shares[j], shares[k]})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(result, secret) {
+					t.Fatalf("shares %d, %d and %d recovered an incorrect secret: %q", i, j, k, result)
+				}
+			}
+		}
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	result, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestSplitInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		shares    int
+		threshold int
+	}{
+		"zero-threshold":      {shares: 3, threshold: 0},
+		"threshold-too-large": {shares: 3, threshold: 4},
+		"too-many-shares":     {shares: 256, threshold: 2},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := split([]byte("secret"), tc.shares, tc.threshold); err == nil {
+				t.Fatalf("expected error, got none")
+			}
+		})
+	}
+}
+
+func TestCombineInvalid(t *testing.T) {
+	testCases := map[string][][]byte{
+		"empty":                nil,
+		"zero-coordinate":      {{0, 1, 2}},
+		"zero-coordinate-last": {{1, 1, 2}, {2, 3, 4}, {0, 5, 6}},
+		"duplicate":            {{1, 1, 2}, {1, 3, 4}},
+		"duplicate-not-first":  {{1, 1, 2}, {2, 3, 4}, {2, 5, 6}},
+		"too-short":            {{1}},
+		"length-mismatch":      {{1, 1, 2}, {2, 3}},
+	}
+	for name, shares := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := combine(shares); err == nil {
+				t.Fatalf("expected error, got none")
+			}
+		})
+	}
+}
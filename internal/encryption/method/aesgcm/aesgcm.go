@@ -23,7 +23,7 @@ type aesgcm struct {
 
 // Encrypt encrypts the passed data with AES-GCM. If the data the encryption fails, it returns an error.
 func (a aesgcm) Encrypt(data []byte) ([]byte, error) {
-	result, err := handlePanic(
+	result, err := method.HandlePanic(
 		func() ([]byte, error) {
 			gcm, err := a.getGCM(a.encryptionKey)
 			if err != nil {
@@ -58,7 +58,7 @@ func (a aesgcm) Decrypt(data []byte) ([]byte, error) {
 	if len(a.decryptionKey) == 0 {
 		return nil, &method.ErrDecryptionKeyUnavailable{}
 	}
-	result, err := handlePanic(
+	result, err := method.HandlePanic(
 		func() ([]byte, error) {
 			if len(data) == 0 {
 				return nil, &method.ErrDecryptionFailed{
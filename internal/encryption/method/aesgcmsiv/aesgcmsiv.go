@@ -0,0 +1,119 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package aesgcmsiv
+
+import (
+	"errors"
+
+	"github.com/tink-crypto/tink-go/v2/aead/subtle"
+
+	"github.com/opentofu/opentofu/internal/encryption/method"
+)
+
+const (
+	// nonceSize is the size of the nonce in bytes.
+	nonceSize = subtle.AESGCMSIVNonceSize
+	// tagSize is the size of the authentication tag in bytes.
+	tagSize = 16
+)
+
+// aesgcmsiv contains the encryption/decryption methods according to AES-GCM-SIV (RFC 8452). The Go standard library
+// and the x/crypto module don't include an implementation, so we use the one from Tink.
+type aesgcmsiv struct {
+	encryptionKey []byte
+	decryptionKey []byte
+	aad           []byte
+}
+
+// Encrypt encrypts the passed data with AES-GCM-SIV. If the encryption fails, it returns an error.
+func (a aesgcmsiv) Encrypt(data []byte) ([]byte, error) {
+	result, err := method.HandlePanic(func() ([]byte, error) {
+		return a.encrypt(data)
+	})
+	if err != nil {
+		var encryptionFailed *method.ErrEncryptionFailed
+		if errors.As(err, &encryptionFailed) {
+			return nil, err
+		}
+		return nil, &method.ErrEncryptionFailed{Cause: &method.ErrCryptoFailure{Message: "unexpected error", Cause: err}}
+	}
+	return result, nil
+}
+
+func (a aesgcmsiv) encrypt(data []byte) ([]byte, error) {
+	aead, err := subtle.NewAESGCMSIV(a.encryptionKey)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: &method.ErrCryptoFailure{
+			Message: "failed to create AES-GCM-SIV cipher",
+			Cause:   err,
+		}}
+	}
+
+	// AES-GCM-SIV is resistant to nonce misuse, so a repeated random nonce only reveals whether the same data was
+	// encrypted twice. The result is the nonce, followed by the ciphertext and the tag.
+	encrypted, err := aead.Encrypt(data, a.aad)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: &method.ErrCryptoFailure{
+			Message: "failed to encrypt data with AES-GCM-SIV",
+			Cause:   err,
+		}}
+	}
+	return encrypted, nil
+}
+
+// Decrypt decrypts an AES-GCM-SIV-encrypted data set. If the data set fails decryption, it returns an error.
+func (a aesgcmsiv) Decrypt(data []byte) ([]byte, error) {
+	if len(a.decryptionKey) == 0 {
+		return nil, &method.ErrDecryptionKeyUnavailable{}
+	}
+	result, err := method.HandlePanic(func() ([]byte, error) {
+		return a.decrypt(data)
+	})
+	if err != nil {
+		var decryptionFailed *method.ErrDecryptionFailed
+		if errors.As(err, &decryptionFailed) {
+			return nil, err
+		}
+		return nil, &method.ErrDecryptionFailed{
+			Cause: &method.ErrCryptoFailure{Message: "unexpected error", Cause: err},
+		}
+	}
+	return result, nil
+}
+
+func (a aesgcmsiv) decrypt(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt empty data",
+			},
+		}
+	}
+	if len(data) < nonceSize+tagSize {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt data because it is too small (likely data corruption)",
+			},
+		}
+	}
+
+	aead, err := subtle.NewAESGCMSIV(a.decryptionKey)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: &method.ErrCryptoFailure{
+			Message: "failed to create AES-GCM-SIV cipher",
+			Cause:   err,
+		}}
+	}
+
+	decrypted, err := aead.Decrypt(data, a.aad)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+	return decrypted, nil
+}
+
+func Is(m method.Method) bool {
+	_, ok := m.(*aesgcmsiv)
+	return ok
+}
@@ -0,0 +1,146 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package aesgcmsiv_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcmsiv"
+)
+
+var config = &aesgcmsiv.Config{
+	Keys: keyprovider.Output{
+		EncryptionKey: []byte("aeshi1quahb2Rua0ooquaiwahbonedoh"),
+		DecryptionKey: []byte("aeshi1quahb2Rua0ooquaiwahbonedoh"),
+	},
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	encrypted, err := m.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	testCases := map[string][]byte{
+		"empty":   nil,
+		"short":   []byte("1"),
+		"invalid": []byte("abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyz"),
+		"corrupt": encrypted[:len(encrypted)-1],
+	}
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			decrypted, err := m.Decrypt(data)
+			if err == nil {
+				t.Fatalf("Expected error, got: %v", decrypted)
+			}
+			var e *method.ErrDecryptionFailed
+			if !errors.As(err, &e) {
+				t.Fatalf("Incorrect error type returned: %T (%v)", err, err)
+			}
+		})
+	}
+}
+
+func TestDecryptWrongAAD(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+	encrypted, err := m.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	withAAD := *config
+	withAAD.AAD = []byte("foo")
+	m, err = withAAD.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+	if _, err := m.Decrypt(encrypted); err == nil {
+		t.Fatalf("Expected error, none returned.")
+	}
+}
+
+// TestDecryptKnownAnswers checks the decryption against the test vectors in RFC 8452, appendix C, prefixed with the
+// nonce the way Encrypt stores it.
+func TestDecryptKnownAnswers(t *testing.T) {
+	testCases := map[string]struct {
+		key       string
+		nonce     string
+		plaintext string
+		result    string
+	}{
+		"aes-128-empty": {
+			key:    "01000000000000000000000000000000",
+			nonce:  "030000000000000000000000",
+			result: "dc20e2d83f25705bb49e439eca56de25",
+		},
+		"aes-128-8-bytes": {
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0100000000000000",
+			result:    "b5d839330ac7b786578782fff6013b815b287c22493a364c",
+		},
+		"aes-256-empty": {
+			key:    "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:  "030000000000000000000000",
+			result: "07f5f4169bbf55a8400cd47ea6fd400f",
+		},
+		"aes-256-8-bytes": {
+			key:       "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0100000000000000",
+			result:    "c2ef328e5c71c83b843122130f7364b761e0b97427e3df28",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			key := mustDecodeHex(t, tc.key)
+			cfg := &aesgcmsiv.Config{
+				Keys: keyprovider.Output{
+					EncryptionKey: key,
+					DecryptionKey: key,
+				},
+			}
+			m, err := cfg.Build()
+			if err != nil {
+				t.Fatalf("unexpected error (%v)", err)
+			}
+
+			data := append(mustDecodeHex(t, tc.nonce), mustDecodeHex(t, tc.result)...)
+			decrypted, err := m.Decrypt(data)
+			if err != nil {
+				t.Fatalf("unexpected error (%v)", err)
+			}
+			if want := mustDecodeHex(t, tc.plaintext); !bytes.Equal(decrypted, want) {
+				t.Fatalf("incorrect decrypted result: %x, expected %x", decrypted, want)
+			}
+
+			data[len(data)-1] ^= 1
+			if _, err := m.Decrypt(data); err == nil {
+				t.Fatal("expected an error when decrypting a modified ciphertext")
+			}
+		})
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
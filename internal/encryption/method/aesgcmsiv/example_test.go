@@ -0,0 +1,46 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package aesgcmsiv_test
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcmsiv"
+)
+
+func Example_config() {
+	// First, get the descriptor to make sure we always have the default values.
+	descriptor := aesgcmsiv.New()
+
+	// Obtain a modifiable, buildable config.
+	config := descriptor.TypedConfig()
+
+	// Set up a 16-byte encryption key:
+	config.Keys = keyprovider.Output{
+		EncryptionKey: []byte("AiphoogheuwohSha"),
+		DecryptionKey: []byte("AiphoogheuwohSha"),
+	}
+
+	// Now you can build a method:
+	method, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	// Encrypt something:
+	encrypted, err := method.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		panic(err)
+	}
+
+	// Decrypt it:
+	decrypted, err := method.Decrypt(encrypted)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%s", decrypted)
+	// Output: Hello world!
+}
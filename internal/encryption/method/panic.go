@@ -3,13 +3,14 @@
 // Copyright (c) 2023 HashiCorp, Inc.
 // SPDX-License-Identifier: MPL-2.0
 
-package aesgcm
+package method
 
 import "fmt"
 
-// handlePanic runs the specified function and returns its result value or returned error. If a panic occurs, it returns the
-// panic as an error.
-func handlePanic(f func() ([]byte, error)) (result []byte, err error) {
+// HandlePanic runs the specified function and returns its result value or returned error. If a panic occurs, it returns the
+// panic as an error. Methods use it to make sure that a panic in a cryptographic library results in an error instead of
+// crashing OpenTofu.
+func HandlePanic(f func() ([]byte, error)) (result []byte, err error) {
 	result, e := func() ([]byte, error) {
 		defer func() {
 			var ok bool
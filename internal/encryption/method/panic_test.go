@@ -3,14 +3,16 @@
 // Copyright (c) 2023 HashiCorp, Inc.
 // SPDX-License-Identifier: MPL-2.0
 
-package aesgcm
+package method_test
 
 import (
 	"fmt"
+
+	"github.com/opentofu/opentofu/internal/encryption/method"
 )
 
-func Example_handlePanic() {
-	_, err := handlePanic(func() ([]byte, error) {
+func ExampleHandlePanic() {
+	_, err := method.HandlePanic(func() ([]byte, error) {
 		panic("Hello world!")
 	})
 	fmt.Printf("%v", err)
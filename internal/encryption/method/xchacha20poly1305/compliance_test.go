@@ -0,0 +1,141 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package xchacha20poly1305
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method/compliancetest"
+)
+
+var testKey = []byte{
+	1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+	17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32,
+}
+
+func TestCompliance(t *testing.T) {
+	compliancetest.ComplianceTest(t, compliancetest.TestConfiguration[*descriptor, *Config, *xchacha20poly1305]{
+		Descriptor: New().(*descriptor),
+		HCLParseTestCases: map[string]compliancetest.HCLParseTestCase[*descriptor, *Config, *xchacha20poly1305]{
+			"empty": {
+				HCL:        `method "xchacha20poly1305" "foo" {}`,
+				ValidHCL:   false,
+				ValidBuild: false,
+				Validate:   nil,
+			},
+			"empty_keys": {
+				HCL: `method "xchacha20poly1305" "foo" {
+						keys = {
+							encryption_key = []
+							decryption_key = []
+						}
+					}`,
+				ValidHCL:   true,
+				ValidBuild: false,
+				Validate:   nil,
+			},
+			"16-byte-keys": {
+				HCL: `method "xchacha20poly1305" "foo" {
+						keys = {
+							encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16]
+							decryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16]
+						}
+					}`,
+				ValidHCL:   true,
+				ValidBuild: false,
+				Validate:   nil,
+			},
+			"short-decryption-key": {
+				HCL: `method "xchacha20poly1305" "foo" {
+						keys = {
+							encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+							decryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31]
+						}
+					}`,
+				ValidHCL:   true,
+				ValidBuild: false,
+				Validate:   nil,
+			},
+			"only-decryption-key": {
+				HCL: `method "xchacha20poly1305" "foo" {
+						keys = {
+							encryption_key = []
+							decryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+						}
+					}`,
+				ValidHCL:   true,
+				ValidBuild: false,
+			},
+			"only-encryption-key": {
+				HCL: `method "xchacha20poly1305" "foo" {
+						keys = {
+							encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+							decryption_key = []
+						}
+					}`,
+				ValidHCL:   true,
+				ValidBuild: true,
+				Validate: func(config *Config, method *xchacha20poly1305) error {
+					if len(config.Keys.DecryptionKey) > 0 {
+						return fmt.Errorf("decryption key found in config despite no decryption key being provided")
+					}
+					if len(method.decryptionKey) > 0 {
+						return fmt.Errorf("decryption key found in method despite no decryption key being provided")
+					}
+					if !bytes.Equal(method.encryptionKey, testKey) {
+						return fmt.Errorf("incorrect encryption key found after HCL parsing in config")
+					}
+					return nil
+				},
+			},
+			"aad": {
+				HCL: `method "xchacha20poly1305" "foo" {
+						keys = {
+							encryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+							decryption_key = [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]
+						}
+						aad = [1,2,3,4]
+					}`,
+				ValidHCL:   true,
+				ValidBuild: true,
+				Validate: func(config *Config, method *xchacha20poly1305) error {
+					if !bytes.Equal(method.decryptionKey, testKey) {
+						return fmt.Errorf("incorrect decryption key found after HCL parsing in config")
+					}
+					if !bytes.Equal(method.aad, []byte{1, 2, 3, 4}) {
+						return fmt.Errorf("invalid AAD in method after Build()")
+					}
+					return nil
+				},
+			},
+		},
+		ConfigStructTestCases: map[string]compliancetest.ConfigStructTestCase[*Config, *xchacha20poly1305]{
+			"empty": {
+				Config: &Config{
+					Keys: keyprovider.Output{},
+					AAD:  nil,
+				},
+				ValidBuild: false,
+				Validate:   nil,
+			},
+		},
+		EncryptDecryptTestCase: compliancetest.EncryptDecryptTestCase[*Config, *xchacha20poly1305]{
+			ValidEncryptOnlyConfig: &Config{
+				Keys: keyprovider.Output{
+					EncryptionKey: testKey,
+					DecryptionKey: nil,
+				},
+			},
+			ValidFullConfig: &Config{
+				Keys: keyprovider.Output{
+					EncryptionKey: []byte("eeth8eishieneeHei2ahr5aeteu9oob8"),
+					DecryptionKey: testKey,
+				},
+			},
+		},
+	})
+}
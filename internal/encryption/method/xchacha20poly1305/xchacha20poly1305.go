@@ -0,0 +1,126 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package xchacha20poly1305
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/opentofu/opentofu/internal/encryption/method"
+)
+
+// xchacha20poly1305 contains the encryption/decryption methods according to XChaCha20-Poly1305
+// (draft-irtf-cfrg-xchacha).
+type xchacha20poly1305 struct {
+	encryptionKey []byte
+	decryptionKey []byte
+	aad           []byte
+}
+
+// Encrypt encrypts the passed data with XChaCha20-Poly1305. If the encryption fails, it returns an error.
+func (x xchacha20poly1305) Encrypt(data []byte) ([]byte, error) {
+	result, err := method.HandlePanic(func() ([]byte, error) {
+		return x.encrypt(data)
+	})
+	if err != nil {
+		var encryptionFailed *method.ErrEncryptionFailed
+		if errors.As(err, &encryptionFailed) {
+			return nil, err
+		}
+		return nil, &method.ErrEncryptionFailed{Cause: &method.ErrCryptoFailure{Message: "unexpected error", Cause: err}}
+	}
+	return result, nil
+}
+
+func (x xchacha20poly1305) encrypt(data []byte) ([]byte, error) {
+	aead, err := x.getAEAD(x.encryptionKey)
+	if err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: err}
+	}
+
+	// The extended 24-byte nonce is large enough to be generated randomly for every encryption without a
+	// practical risk of reuse.
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, &method.ErrEncryptionFailed{Cause: &method.ErrCryptoFailure{
+			Message: "could not generate nonce",
+			Cause:   err,
+		}}
+	}
+
+	encrypted := aead.Seal(nil, nonce, data, x.aad)
+
+	return append(nonce, encrypted...), nil
+}
+
+// Decrypt decrypts an XChaCha20-Poly1305-encrypted data set. If the data set fails decryption, it returns an error.
+func (x xchacha20poly1305) Decrypt(data []byte) ([]byte, error) {
+	if len(x.decryptionKey) == 0 {
+		return nil, &method.ErrDecryptionKeyUnavailable{}
+	}
+	result, err := method.HandlePanic(func() ([]byte, error) {
+		return x.decrypt(data)
+	})
+	if err != nil {
+		var decryptionFailed *method.ErrDecryptionFailed
+		if errors.As(err, &decryptionFailed) {
+			return nil, err
+		}
+		return nil, &method.ErrDecryptionFailed{
+			Cause: &method.ErrCryptoFailure{Message: "unexpected error", Cause: err},
+		}
+	}
+	return result, nil
+}
+
+func (x xchacha20poly1305) decrypt(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt empty data",
+			},
+		}
+	}
+
+	aead, err := x.getAEAD(x.decryptionKey)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+
+	if len(data) < aead.NonceSize()+aead.Overhead() {
+		return nil, &method.ErrDecryptionFailed{
+			Cause: method.ErrCryptoFailure{
+				Message: "cannot decrypt data because it is too small (likely data corruption)",
+			},
+		}
+	}
+
+	nonce := data[:aead.NonceSize()]
+	data = data[aead.NonceSize():]
+
+	decrypted, err := aead.Open(nil, nonce, data, x.aad)
+	if err != nil {
+		return nil, &method.ErrDecryptionFailed{Cause: err}
+	}
+	return decrypted, nil
+}
+
+func (x xchacha20poly1305) getAEAD(key []byte) (cipher.AEAD, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, &method.ErrCryptoFailure{
+			Message: "failed to create XChaCha20-Poly1305 cipher",
+			Cause:   err,
+		}
+	}
+	return aead, nil
+}
+
+func Is(m method.Method) bool {
+	_, ok := m.(*xchacha20poly1305)
+	return ok
+}
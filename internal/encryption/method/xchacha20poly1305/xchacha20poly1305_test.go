@@ -0,0 +1,72 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package xchacha20poly1305_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/method"
+	"github.com/opentofu/opentofu/internal/encryption/method/xchacha20poly1305"
+)
+
+var config = &xchacha20poly1305.Config{
+	Keys: keyprovider.Output{
+		EncryptionKey: []byte("aeshi1quahb2Rua0ooquaiwahbonedoh"),
+		DecryptionKey: []byte("aeshi1quahb2Rua0ooquaiwahbonedoh"),
+	},
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	encrypted, err := m.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	testCases := map[string][]byte{
+		"empty":   nil,
+		"short":   []byte("1"),
+		"invalid": []byte("abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyz"),
+		"corrupt": encrypted[:len(encrypted)-1],
+	}
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			decrypted, err := m.Decrypt(data)
+			if err == nil {
+				t.Fatalf("Expected error, got: %v", decrypted)
+			}
+			var e *method.ErrDecryptionFailed
+			if !errors.As(err, &e) {
+				t.Fatalf("Incorrect error type returned: %T (%v)", err, err)
+			}
+		})
+	}
+}
+
+func TestDecryptWrongAAD(t *testing.T) {
+	m, err := config.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+	encrypted, err := m.Encrypt([]byte("Hello world!"))
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+
+	withAAD := *config
+	withAAD.AAD = []byte("foo")
+	m, err = withAAD.Build()
+	if err != nil {
+		t.Fatalf("unexpected error (%v)", err)
+	}
+	if _, err := m.Decrypt(encrypted); err == nil {
+		t.Fatalf("Expected error, none returned.")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+)
+
+// outputsEncryption encrypts the published root module outputs of a state file separately from the state itself, so
+// that terraform_remote_state data sources can read them without the key used for the rest of the state.
+type outputsEncryption struct {
+	base *baseEncryption
+	// names lists the published outputs, nil means all of them.
+	names []string
+}
+
+func newOutputsEncryption(enc *encryption, target *config.OutputsTargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (*outputsEncryption, hcl.Diagnostics) {
+	base, diags := newBaseEncryption(enc, target.AsTargetConfig(), enforced, false, name, staticEval)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	o := &outputsEncryption{base: base}
+	if target.Names != nil {
+		o.names = *target.Names
+	}
+	return o, diags
+}
+
+// outputsdata is merged into encrypted state files that publish their outputs.
+type outputsdata struct {
+	EncryptedOutputs json.RawMessage `json:"encrypted_outputs,omitempty"`
+}
+
+func (o *outputsEncryption) encrypt(plainState []byte) (json.RawMessage, error) {
+	published, err := o.outputsState(plainState)
+	if err != nil {
+		return nil, fmt.Errorf("unable to select the published outputs: %w", err)
+	}
+	return o.base.encrypt(published, func(base basedata) interface{} {
+		return base
+	})
+}
+
+// outputsState returns a state file that only contains the published root module outputs of the given state file.
+func (o *outputsEncryption) outputsState(plainState []byte) ([]byte, error) {
+	var state map[string]json.RawMessage
+	if err := json.Unmarshal(plainState, &state); err != nil {
+		return nil, err
+	}
+	var outputs map[string]json.RawMessage
+	if raw, ok := state["outputs"]; ok {
+		if err := json.Unmarshal(raw, &outputs); err != nil {
+			return nil, err
+		}
+	}
+
+	published := make(map[string]json.RawMessage)
+	if o.names == nil {
+		for name, output := range outputs {
+			published[name] = output
+		}
+	} else {
+		for _, name := range o.names {
+			if output, ok := outputs[name]; ok {
+				published[name] = output
+			}
+		}
+	}
+
+	result := map[string]interface{}{
+		"outputs":   published,
+		"resources": []interface{}{},
+	}
+	for _, key := range []string{"version", "terraform_version", "serial", "lineage"} {
+		if value, ok := state[key]; ok {
+			result[key] = value
+		}
+	}
+	return json.Marshal(result)
+}
+
+// decryptOutputs attempts to decrypt the published outputs of a state file that could not be decrypted as a whole. It
+// returns a state file that only contains those outputs.
+func (s *stateEncryption) decryptOutputs(encryptedState []byte, stateErr error) ([]byte, EncryptionStatus, error) {
+	var outputs outputsdata
+	if err := json.Unmarshal(encryptedState, &outputs); err != nil || len(outputs.EncryptedOutputs) == 0 {
+		return nil, StatusUnknown, stateErr
+	}
+	decrypted, status, err := s.base.decrypt(outputs.EncryptedOutputs, validateStateFile)
+	if err != nil {
+		return nil, status, errors.Join(stateErr, fmt.Errorf("unable to decrypt the published outputs: %w", err))
+	}
+	return decrypted, status, nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const outputsProducerConfig = `key_provider "pbkdf2" "state" {
+		passphrase = "Hello world! 123"
+	}
+	key_provider "pbkdf2" "outputs" {
+		passphrase = "OpenTofu has Encryption"
+	}
+	method "aes_gcm" "state" {
+		keys = key_provider.pbkdf2.state
+	}
+	method "aes_gcm" "outputs" {
+		keys = key_provider.pbkdf2.outputs
+	}
+	state {
+		method = method.aes_gcm.state
+		outputs {
+			method = method.aes_gcm.outputs
+			names  = ["vpc_id", "missing"]
+		}
+	}`
+
+const outputsConsumerConfig = `key_provider "pbkdf2" "outputs" {
+		passphrase = "OpenTofu has Encryption"
+	}
+	method "aes_gcm" "outputs" {
+		keys = key_provider.pbkdf2.outputs
+	}
+	state {
+		method = method.aes_gcm.outputs
+	}
+	remote_state_data_sources {
+		default {
+			method = method.aes_gcm.outputs
+		}
+	}`
+
+func TestOutputsEncryption(t *testing.T) {
+	producer := newEnvelopeTestEncryption(t, outputsProducerConfig)
+	consumer := newEnvelopeTestEncryption(t, outputsConsumerConfig)
+
+	testData := []byte(`{
+		"version": 4,
+		"terraform_version": "1.9.0",
+		"serial": 42,
+		"lineage": "magic",
+		"outputs": {
+			"vpc_id": {"value": "vpc-123", "type": "string"},
+			"db_password": {"value": "secret", "type": "string", "sensitive": true}
+		},
+		"resources": [{"mode": "managed", "type": "test", "name": "secret"}]
+	}`)
+	encryptedState, err := producer.State().EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// The producer decrypts the whole state as usual.
+	decryptedState, _, err := producer.State().DecryptState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if string(decryptedState) != string(testData) {
+		t.Fatalf("Incorrect decrypted state: %s", decryptedState)
+	}
+
+	// The published outputs are only available to remote state data sources.
+	if _, _, err := consumer.State().DecryptState(encryptedState); err == nil {
+		t.Fatalf("The consumer decrypted the state with the outputs key.")
+	}
+
+	decryptedOutputs, status, err := consumer.RemoteState("network").DecryptState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status != StatusSatisfied {
+		t.Fatalf("Incorrect encryption status: %v", status)
+	}
+
+	var got struct {
+		TerraformVersion string                     `json:"terraform_version"`
+		Serial           int                        `json:"serial"`
+		Lineage          string                     `json:"lineage"`
+		Outputs          map[string]json.RawMessage `json:"outputs"`
+		Resources        []json.RawMessage          `json:"resources"`
+	}
+	if err := json.Unmarshal(decryptedOutputs, &got); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if got.TerraformVersion != "1.9.0" || got.Serial != 42 || got.Lineage != "magic" {
+		t.Fatalf("The state metadata has not been preserved: %s", decryptedOutputs)
+	}
+	if len(got.Outputs) != 1 || got.Outputs["vpc_id"] == nil {
+		t.Fatalf("Incorrect published outputs: %s", decryptedOutputs)
+	}
+	if len(got.Resources) != 0 {
+		t.Fatalf("Resources have been published: %s", decryptedOutputs)
+	}
+}
+
+func TestOutputsEncryptionRewrap(t *testing.T) {
+	producer := newEnvelopeTestEncryption(t, `key_provider "pbkdf2" "state" {
+		passphrase = "Hello world! 123"
+	}
+	key_provider "pbkdf2" "outputs" {
+		passphrase = "OpenTofu has Encryption"
+	}
+	method "aes_gcm" "state" {
+		keys = key_provider.pbkdf2.state
+	}
+	method "aes_gcm" "outputs" {
+		keys = key_provider.pbkdf2.outputs
+	}
+	state {
+		envelope = true
+		method   = method.aes_gcm.state
+		outputs {
+			method = method.aes_gcm.outputs
+		}
+	}`)
+	consumer := newEnvelopeTestEncryption(t, outputsConsumerConfig)
+
+	testData := []byte(`{"terraform_version": "1.9.0", "serial": 42, "lineage": "magic", "outputs": {"vpc_id": {"value": "vpc-123", "type": "string"}}}`)
+	encryptedState, err := producer.State().EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	rewrapped, err := producer.State().RewrapState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// The published outputs survive rewrapping the data key.
+	if _, _, err := consumer.RemoteState("network").DecryptState(rewrapped); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
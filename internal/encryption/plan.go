@@ -44,14 +44,17 @@ type PlanEncryption interface {
 	// Pass a potentially encrypted plan file as an input, and you will receive the decrypted plan file or an error as
 	// a result.
 	DecryptPlan([]byte) ([]byte, error)
+
+	// DescribePlan describes how a plan file is encrypted, in the same way as StateEncryption.DescribeState.
+	DescribePlan([]byte) (Description, error)
 }
 
 type planEncryption struct {
 	base *baseEncryption
 }
 
-func newPlanEncryption(enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (PlanEncryption, hcl.Diagnostics) {
-	base, diags := newBaseEncryption(enc, target, enforced, name, staticEval)
+func newPlanEncryption(enc *encryption, target *config.TargetConfig, enforced bool, envelope bool, name string, staticEval *configs.StaticEvaluator) (PlanEncryption, hcl.Diagnostics) {
+	base, diags := newBaseEncryption(enc, target, enforced, envelope, name, staticEval)
 	return &planEncryption{base}, diags
 }
 
@@ -60,16 +63,23 @@ func (p planEncryption) EncryptPlan(data []byte) ([]byte, error) {
 }
 
 func (p planEncryption) DecryptPlan(data []byte) ([]byte, error) {
-	data, _, err := p.base.decrypt(data, func(data []byte) error {
-		// Check magic bytes
-		if len(data) < 2 || string(data[:2]) != "PK" {
-			return fmt.Errorf("Invalid plan file %v", string(data[:2]))
-		}
-		return nil
-	})
+	data, _, err := p.base.decrypt(data, validatePlanFile)
 	return data, err
 }
 
+func (p planEncryption) DescribePlan(data []byte) (Description, error) {
+	return p.base.describe(data, validatePlanFile)
+}
+
+// validatePlanFile checks if an unencrypted payload is a plan file.
+func validatePlanFile(data []byte) error {
+	// Check magic bytes
+	if len(data) < 2 || string(data[:2]) != "PK" {
+		return fmt.Errorf("Invalid plan file %v", string(data[:2]))
+	}
+	return nil
+}
+
 func PlanEncryptionDisabled() PlanEncryption {
 	return &planDisabled{}
 }
@@ -82,3 +92,11 @@ func (s *planDisabled) EncryptPlan(plainPlan []byte) ([]byte, error) {
 func (s *planDisabled) DecryptPlan(encryptedPlan []byte) ([]byte, error) {
 	return encryptedPlan, nil
 }
+func (s *planDisabled) DescribePlan(encryptedPlan []byte) (Description, error) {
+	desc, _, encrypted := describePayload(encryptedPlan)
+	if encrypted {
+		return desc, fmt.Errorf("the plan is encrypted, but plan encryption is not configured")
+	}
+	desc.Status = StatusSatisfied
+	return desc, nil
+}
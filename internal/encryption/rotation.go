@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+)
+
+// timeNow is replaced in tests.
+var timeNow = time.Now
+
+// keyProviderExpiry maps the metadata keys of key providers whose keys exceeded their maximum age to the age of the
+// keys.
+type keyProviderExpiry map[keyprovider.MetaStorageKey]time.Duration
+
+// rotationMeta is stored alongside the metadata of key providers with a rotation policy. Key providers ignore it when
+// decoding their own metadata.
+type rotationMeta struct {
+	Rotation *keyRotationMeta `json:"rotation,omitempty"`
+}
+
+type keyRotationMeta struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// checkKeyAge records the key provider in expired if the key its metadata describes is older than the maximum age
+// of its rotation policy. Keys without a recorded creation time are treated as expired, as their age is unknown.
+func checkKeyAge(cfg config.KeyProviderConfig, metaKey keyprovider.MetaStorageKey, rawMeta []byte, expired keyProviderExpiry) error {
+	maxAge, err := cfg.Rotation.MaxAgeDuration()
+	if err != nil {
+		return err
+	}
+	var meta rotationMeta
+	if err := json.Unmarshal(rawMeta, &meta); err != nil {
+		return err
+	}
+	if meta.Rotation == nil {
+		expired[metaKey] = 0
+		return nil
+	}
+	if age := timeNow().Sub(meta.Rotation.CreatedAt); age > maxAge {
+		expired[metaKey] = age
+	}
+	return nil
+}
+
+// addKeyCreationTime records the current time as the creation time of the key described by the given metadata.
+func addKeyCreationTime(rawMeta []byte) ([]byte, error) {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(rawMeta, &fields); err != nil {
+		return nil, fmt.Errorf("the metadata is not a JSON object: %w", err)
+	}
+	if _, ok := fields["rotation"]; ok {
+		return nil, fmt.Errorf("the metadata already contains a rotation field")
+	}
+	rotation, err := json.Marshal(keyRotationMeta{CreatedAt: timeNow().UTC()})
+	if err != nil {
+		return nil, err
+	}
+	fields["rotation"] = rotation
+	return json.Marshal(fields)
+}
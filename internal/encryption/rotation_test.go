@@ -0,0 +1,124 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/encryption/config"
+)
+
+const rotationConfig = `key_provider "pbkdf2" "rotated" {
+		passphrase = "Hello world! 123"
+		rotation {
+			max_age = "720h"
+		}
+	}
+	method "aes_gcm" "rotated" {
+		keys = key_provider.pbkdf2.rotated
+	}
+	state {
+		method = method.aes_gcm.rotated
+	}`
+
+func TestKeyRotation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	enc := newEnvelopeTestEncryption(t, rotationConfig)
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encryptedState, err := enc.State().EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var payload basedata
+	if err := json.Unmarshal(encryptedState, &payload); err != nil {
+		t.Fatalf("%v", err)
+	}
+	var meta rotationMeta
+	if err := json.Unmarshal(payload.Meta["key_provider.pbkdf2.rotated"], &meta); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if meta.Rotation == nil || !meta.Rotation.CreatedAt.Equal(start) {
+		t.Fatalf("Incorrect key creation time in metadata: %s", payload.Meta["key_provider.pbkdf2.rotated"])
+	}
+
+	// The key is still young enough.
+	now = start.Add(719 * time.Hour)
+	_, status, err := enc.State().DecryptState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status != StatusSatisfied {
+		t.Fatalf("Incorrect encryption status: %v", status)
+	}
+
+	// The key exceeded its maximum age, so the state needs to be encrypted again.
+	now = start.Add(721 * time.Hour)
+	decryptedState, status, err := enc.State().DecryptState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status != StatusRotation {
+		t.Fatalf("Incorrect encryption status: %v", status)
+	}
+	if string(decryptedState) != string(testData) {
+		t.Fatalf("Incorrect decrypted state: %s", decryptedState)
+	}
+	desc, err := enc.State().DescribeState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if desc.Status != StatusRotation {
+		t.Fatalf("Incorrect described encryption status: %v", desc.Status)
+	}
+
+	// A new encryption instance generates a new key.
+	reencrypted, err := newEnvelopeTestEncryption(t, rotationConfig).State().EncryptState(decryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	_, status, err = enc.State().DecryptState(reencrypted)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status != StatusSatisfied {
+		t.Fatalf("Incorrect encryption status after rotation: %v", status)
+	}
+}
+
+func TestKeyRotation_unknownAge(t *testing.T) {
+	// States encrypted before the rotation policy was configured have no recorded key creation time.
+	withoutRotation := newEnvelopeTestEncryption(t, strings.Replace(rotationConfig, `rotation {
+			max_age = "720h"
+		}`, "", 1))
+	encryptedState, err := withoutRotation.State().EncryptState([]byte(`{"serial": 42, "lineage": "magic"}`))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	_, status, err := newEnvelopeTestEncryption(t, rotationConfig).State().DecryptState(encryptedState)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	if status != StatusRotation {
+		t.Fatalf("Incorrect encryption status: %v", status)
+	}
+}
+
+func TestKeyRotation_invalidMaxAge(t *testing.T) {
+	_, diags := config.LoadConfigFromString("test", strings.Replace(rotationConfig, "720h", "30 days", 1))
+	if !diags.HasErrors() {
+		t.Fatalf("Expected an error for an invalid max_age.")
+	}
+	if got, want := diags.Error(), "Invalid key rotation max_age"; !strings.Contains(got, want) {
+		t.Fatalf("Incorrect error: %s", got)
+	}
+}
@@ -52,15 +52,49 @@ type StateEncryption interface {
 	// output to any additional functions that require a valid state file as it may not contain the fields typically
 	// present in a state file.
 	EncryptState([]byte) ([]byte, error)
+
+	// RewrapState re-encrypts the data key of an envelope encrypted state file and returns the updated form.
+	//
+	// When implementing this function:
+	//
+	// Decrypt the data key stored in the encrypted state file using the configured methods, including any fallbacks,
+	// and encrypt it again using the primary method. The encrypted state itself must be passed through unchanged. If
+	// the input is not an envelope encrypted state file, return an error.
+	//
+	// When using this function:
+	//
+	// Pass in the state file exactly as read from its source and store the output in its place. This allows changing
+	// the key provider of a large state file without decrypting and re-encrypting the state itself.
+	RewrapState([]byte) ([]byte, error)
+
+	// DescribeState describes how a state file is encrypted.
+	//
+	// When implementing this function:
+	//
+	// Report the encryption details stored in the state file, then determine which of the configured methods, if
+	// any, can decrypt it without returning the decrypted state. If none of them can, return the description along with
+	// an error.
+	//
+	// When using this function:
+	//
+	// Pass in the state file exactly as read from its source. The description tells whether the state needs to be
+	// encrypted again with the primary method.
+	DescribeState([]byte) (Description, error)
 }
 
 type stateEncryption struct {
 	base *baseEncryption
+
+	// outputs, if set, publishes the root module outputs encrypted separately when encrypting.
+	outputs *outputsEncryption
+	// readOutputs allows falling back to the published outputs when the state cannot be decrypted. This is only
+	// enabled for remote state data sources, which do not need the rest of the state.
+	readOutputs bool
 }
 
-func newStateEncryption(enc *encryption, target *config.TargetConfig, enforced bool, name string, staticEval *configs.StaticEvaluator) (StateEncryption, hcl.Diagnostics) {
-	base, diags := newBaseEncryption(enc, target, enforced, name, staticEval)
-	return &stateEncryption{base}, diags
+func newStateEncryption(enc *encryption, target *config.TargetConfig, enforced bool, envelope bool, name string, staticEval *configs.StaticEvaluator) (*stateEncryption, hcl.Diagnostics) {
+	base, diags := newBaseEncryption(enc, target, enforced, envelope, name, staticEval)
+	return &stateEncryption{base: base}, diags
 }
 
 type statedata struct {
@@ -75,34 +109,60 @@ func (s *stateEncryption) EncryptState(plainState []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	var published outputsdata
+	if s.outputs != nil {
+		published.EncryptedOutputs, err = s.outputs.encrypt(plainState)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return s.base.encrypt(plainState, func(base basedata) interface{} {
 		// Merge together the base encryption data and the passthrough fields
 		return struct {
 			statedata
+			outputsdata
 			basedata
 		}{
-			statedata: passthrough,
-			basedata:  base,
+			statedata:   passthrough,
+			outputsdata: published,
+			basedata:    base,
 		}
 	})
 }
 
-func (s *stateEncryption) DecryptState(encryptedState []byte) ([]byte, EncryptionStatus, error) {
-	decryptedState, status, err := s.base.decrypt(encryptedState, func(data []byte) error {
-		tmp := struct {
-			FormatVersion string `json:"terraform_version"`
-		}{}
-		err := json.Unmarshal(data, &tmp)
-		if err != nil {
-			return err
-		}
-		if len(tmp.FormatVersion) == 0 {
-			// Not a state file
-			return fmt.Errorf("Given payload is not a state file")
+func (s *stateEncryption) RewrapState(encryptedState []byte) ([]byte, error) {
+	var passthrough statedata
+	err := json.Unmarshal(encryptedState, &passthrough)
+	if err != nil {
+		return nil, err
+	}
+	// The published outputs are encrypted with their own method, so they are passed through as well
+	var published outputsdata
+	err = json.Unmarshal(encryptedState, &published)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.base.rewrap(encryptedState, func(base basedata) interface{} {
+		// Merge together the base encryption data and the passthrough fields
+		return struct {
+			statedata
+			outputsdata
+			basedata
+		}{
+			statedata:   passthrough,
+			outputsdata: published,
+			basedata:    base,
 		}
-		// Probably a state file
-		return nil
 	})
+}
+
+func (s *stateEncryption) DecryptState(encryptedState []byte) ([]byte, EncryptionStatus, error) {
+	decryptedState, status, err := s.base.decrypt(encryptedState, validateStateFile)
+	if err != nil && s.readOutputs {
+		decryptedState, status, err = s.decryptOutputs(encryptedState, err)
+	}
 
 	if err != nil {
 		return nil, status, err
@@ -133,6 +193,27 @@ func (s *stateEncryption) DecryptState(encryptedState []byte) ([]byte, Encryptio
 	return decryptedState, status, nil
 }
 
+func (s *stateEncryption) DescribeState(encryptedState []byte) (Description, error) {
+	return s.base.describe(encryptedState, validateStateFile)
+}
+
+// validateStateFile checks if an unencrypted payload is a state file.
+func validateStateFile(data []byte) error {
+	tmp := struct {
+		FormatVersion string `json:"terraform_version"`
+	}{}
+	err := json.Unmarshal(data, &tmp)
+	if err != nil {
+		return err
+	}
+	if len(tmp.FormatVersion) == 0 {
+		// Not a state file
+		return fmt.Errorf("Given payload is not a state file")
+	}
+	// Probably a state file
+	return nil
+}
+
 func StateEncryptionDisabled() StateEncryption {
 	return &stateDisabled{}
 }
@@ -142,6 +223,17 @@ type stateDisabled struct{}
 func (s *stateDisabled) EncryptState(plainState []byte) ([]byte, error) {
 	return plainState, nil
 }
+func (s *stateDisabled) RewrapState(encryptedState []byte) ([]byte, error) {
+	return nil, fmt.Errorf("state encryption is not configured")
+}
 func (s *stateDisabled) DecryptState(encryptedState []byte) ([]byte, EncryptionStatus, error) {
 	return encryptedState, StatusSatisfied, nil
 }
+func (s *stateDisabled) DescribeState(encryptedState []byte) (Description, error) {
+	desc, _, encrypted := describePayload(encryptedState)
+	if encrypted {
+		return desc, fmt.Errorf("the state is encrypted, but state encryption is not configured")
+	}
+	desc.Status = StatusSatisfied
+	return desc, nil
+}
@@ -0,0 +1,222 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package encryption
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/encryption/config"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/pbkdf2"
+	"github.com/opentofu/opentofu/internal/encryption/keyprovider/shamir"
+	"github.com/opentofu/opentofu/internal/encryption/method/aesgcm"
+	"github.com/opentofu/opentofu/internal/encryption/method/unencrypted"
+	"github.com/opentofu/opentofu/internal/encryption/registry"
+	"github.com/opentofu/opentofu/internal/encryption/registry/lockingencryptionregistry"
+)
+
+// thresholdConfig returns a configuration that requires two of three passphrases. Passing unavailable instead of a
+// passphrase replaces the corresponding key provider with one that fails at runtime.
+func thresholdConfig(passphraseA, passphraseB, passphraseC string) string {
+	keyProvider := func(name string, passphrase string) (string, string) {
+		if passphrase == unavailable {
+			return fmt.Sprintf(`key_provider "unavailable" %q {
+			}`, name), "key_provider.unavailable." + name
+		}
+		return fmt.Sprintf(`key_provider "pbkdf2" %q {
+			passphrase = %q
+		}`, name, passphrase), "key_provider.pbkdf2." + name
+	}
+	blockA, refA := keyProvider("a", passphraseA)
+	blockB, refB := keyProvider("b", passphraseB)
+	blockC, refC := keyProvider("c", passphraseC)
+	return fmt.Sprintf(`%s
+		%s
+		%s
+		key_provider "shamir" "two_of_three" {
+			key_providers = [%s, %s, %s]
+			threshold     = 2
+		}
+		method "aes_gcm" "example" {
+			keys = key_provider.shamir.two_of_three
+		}
+		state {
+			method = method.aes_gcm.example
+		}`, blockA, blockB, blockC, refA, refB, refC)
+}
+
+// unavailable is a placeholder passphrase for thresholdConfig to use unavailableKeyProvider.
+const unavailable = "unavailable"
+
+// unavailableKeyProvider is a key provider that is configured correctly, but always fails at runtime, like a key
+// management service that cannot be reached.
+type unavailableKeyProvider struct{}
+
+func (unavailableKeyProvider) ID() keyprovider.ID {
+	return "unavailable"
+}
+
+func (unavailableKeyProvider) ConfigStruct() keyprovider.Config {
+	return &unavailableKeyProvider{}
+}
+
+func (p unavailableKeyProvider) Build() (keyprovider.KeyProvider, keyprovider.KeyMeta, error) {
+	return p, nil, nil
+}
+
+func (unavailableKeyProvider) Provide(keyprovider.KeyMeta) (keyprovider.Output, keyprovider.KeyMeta, error) {
+	return keyprovider.Output{}, nil, &keyprovider.ErrKeyProviderFailure{
+		Message: "the key management service is unavailable",
+	}
+}
+
+func newThresholdTestRegistry() registry.Registry {
+	reg := lockingencryptionregistry.New()
+	if err := reg.RegisterKeyProvider(shamir.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterKeyProvider(pbkdf2.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterKeyProvider(unavailableKeyProvider{}); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(aesgcm.New()); err != nil {
+		panic(err)
+	}
+	if err := reg.RegisterMethod(unencrypted.New()); err != nil {
+		panic(err)
+	}
+	return reg
+}
+
+func newThresholdTestEncryption(t *testing.T, cfg string) (Encryption, hcl.Diagnostics) {
+	t.Helper()
+
+	parsedConfig, diags := config.LoadConfigFromString("test", cfg)
+	if diags.HasErrors() {
+		t.Fatalf("%v", diags.Error())
+	}
+
+	staticEval := configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting())
+
+	return New(newThresholdTestRegistry(), parsedConfig, staticEval)
+}
+
+func TestThresholdEncryption(t *testing.T) {
+	const (
+		passphraseA = "This is passphrase A"
+		passphraseB = "This is passphrase B"
+		passphraseC = "This is passphrase C"
+	)
+
+	enc, diags := newThresholdTestEncryption(t, thresholdConfig(passphraseA, passphraseB, passphraseC))
+	if len(diags) != 0 {
+		t.Fatalf("%v", diags.Error())
+	}
+
+	testData := []byte(`{"serial": 42, "lineage": "magic"}`)
+	encryptedState, err := enc.State().EncryptState(testData)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	t.Run("one-unavailable", func(t *testing.T) {
+		for _, cfg := range []string{
+			thresholdConfig(unavailable, passphraseB, passphraseC),
+			thresholdConfig(passphraseA, unavailable, passphraseC),
+			thresholdConfig(passphraseA, passphraseB, unavailable),
+		} {
+			enc, diags := newThresholdTestEncryption(t, cfg)
+			if diags.HasErrors() {
+				t.Fatalf("%v", diags.Error())
+			}
+			if len(diags) == 0 {
+				t.Fatalf("Expected a warning about the unavailable key provider.")
+			}
+			decryptedState, _, err := enc.State().DecryptState(encryptedState)
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			if string(decryptedState) != string(testData) {
+				t.Fatalf("Incorrect decrypted state: %s", decryptedState)
+			}
+		}
+	})
+
+	t.Run("one-wrong", func(t *testing.T) {
+		enc, diags := newThresholdTestEncryption(t, thresholdConfig(passphraseA, "This is not passphrase B", passphraseC))
+		if len(diags) != 0 {
+			t.Fatalf("%v", diags.Error())
+		}
+		decryptedState, _, err := enc.State().DecryptState(encryptedState)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if string(decryptedState) != string(testData) {
+			t.Fatalf("Incorrect decrypted state: %s", decryptedState)
+		}
+	})
+
+	t.Run("two-unavailable", func(t *testing.T) {
+		_, diags := newThresholdTestEncryption(t, thresholdConfig(passphraseA, unavailable, unavailable))
+		if !diags.HasErrors() {
+			t.Fatalf("Expected an error with only one of three key providers available.")
+		}
+	})
+
+	t.Run("one-misconfigured", func(t *testing.T) {
+		// A configuration error is not tolerated, even if the remaining key providers would meet the threshold.
+		_, diags := newThresholdTestEncryption(t, thresholdConfig(passphraseA, passphraseB, "too short"))
+		if !diags.HasErrors() {
+			t.Fatalf("Expected an error for the misconfigured key provider.")
+		}
+	})
+
+	t.Run("shared-unavailable", func(t *testing.T) {
+		// A key provider that is not fault-tolerant must still see the failure of a key provider that a threshold key
+		// provider tolerated, no matter which of them is set up first.
+		cfg, diags := config.LoadConfigFromString("test", thresholdConfig(passphraseA, passphraseB, unavailable))
+		if diags.HasErrors() {
+			t.Fatalf("%v", diags.Error())
+		}
+		var thresholdKeyProvider, unavailableKeyProvider config.KeyProviderConfig
+		for _, kp := range cfg.KeyProviderConfigs {
+			switch kp.Type {
+			case "shamir":
+				thresholdKeyProvider = kp
+			case "unavailable":
+				unavailableKeyProvider = kp
+			}
+		}
+		staticEval := configs.NewStaticEvaluator(nil, configs.RootModuleCallForTesting())
+
+		for name, kpConfigs := range map[string][]config.KeyProviderConfig{
+			"threshold-first":   {thresholdKeyProvider, unavailableKeyProvider},
+			"unavailable-first": {unavailableKeyProvider, thresholdKeyProvider},
+		} {
+			meta := keyProviderMetadata{
+				input:  make(keyProviderMetamap),
+				output: make(keyProviderMetamap),
+			}
+			_, diags := setupKeyProviders(cfg, kpConfigs, meta, newThresholdTestRegistry(), staticEval)
+			if !diags.HasErrors() {
+				t.Fatalf("%s: expected an error for the unavailable key provider.", name)
+			}
+		}
+	})
+
+	t.Run("two-wrong", func(t *testing.T) {
+		enc, diags := newThresholdTestEncryption(t, thresholdConfig(passphraseA, "This is not passphrase B", "This is not passphrase C"))
+		if len(diags) != 0 {
+			t.Fatalf("%v", diags.Error())
+		}
+		if _, _, err := enc.State().DecryptState(encryptedState); err == nil {
+			t.Fatalf("Expected an error with only one of three correct passphrases.")
+		}
+	})
+}
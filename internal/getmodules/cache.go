@@ -0,0 +1,260 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/replacefile"
+)
+
+// PackageCache is a directory shared between working directories that
+// retains the module packages fetched from their origin, so that they can
+// be installed again without fetching them.
+//
+// The packages are stored by content, using their hash as returned by
+// [PackageHash], under the "packages" subdirectory. The "addresses"
+// subdirectory maps the hash of each package address to the hash of the
+// package that was most recently fetched from it, which is only used for
+// addresses that refer to content that cannot change.
+//
+// Packages are installed from the cache by hard-linking their files into the
+// installation directory where possible, or by copying them otherwise.
+// Modifying the files of an installed module may therefore modify the cached
+// package too, but the cache verifies the hash of a package before using it.
+type PackageCache struct {
+	dir string
+}
+
+// NewPackageCache returns a [PackageCache] that stores packages in the given
+// directory, which must already exist.
+func NewPackageCache(dir string) *PackageCache {
+	return &PackageCache{dir: dir}
+}
+
+// install installs a cached copy of the package at the given address into
+// the given directory, and returns true if it did so.
+//
+// If wantHash is set then only a package with that hash is used, regardless
+// of the address it was fetched from. Otherwise, the package most recently
+// fetched from the address is used only if the address refers to content
+// that cannot change, either because immutable is set by the caller or
+// because the address itself selects a specific commit or digest, and
+// refresh is not set.
+func (c *PackageCache) install(instDir, packageAddr, wantHash string, immutable, refresh bool) (bool, error) {
+	if !cacheablePackageAddr(packageAddr) {
+		return false, nil
+	}
+
+	hash := wantHash
+	if hash == "" {
+		if refresh || !(immutable || immutablePackageAddr(packageAddr)) {
+			return false, nil
+		}
+		raw, err := os.ReadFile(c.addressPath(packageAddr))
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		hash = strings.TrimSpace(string(raw))
+	}
+
+	pkgDir := c.packagePath(hash)
+	if _, err := os.Stat(pkgDir); errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	// The files of a cached package may be hard-linked into working
+	// directories, where they could have been modified, so we verify the
+	// package before using it and discard it if it's been changed.
+	gotHash, err := PackageHash(pkgDir)
+	if err != nil {
+		return false, err
+	}
+	if gotHash != hash {
+		log.Printf("[WARN] getmodules: discarding cached package %s because its content has changed", pkgDir)
+		return false, os.RemoveAll(pkgDir)
+	}
+
+	log.Printf("[TRACE] getmodules: installing cached package %s for %q into %s", pkgDir, packageAddr, instDir)
+	if err := linkDir(instDir, pkgDir); err != nil {
+		return false, fmt.Errorf("failed to install cached package from %s: %w", pkgDir, err)
+	}
+	return true, nil
+}
+
+// store saves a copy of the package that was fetched from the given address
+// into the given directory.
+func (c *PackageCache) store(instDir, packageAddr string) error {
+	if !cacheablePackageAddr(packageAddr) {
+		return nil
+	}
+
+	hash, err := PackageHash(instDir)
+	if err != nil {
+		return err
+	}
+
+	pkgDir := c.packagePath(hash)
+	if _, err := os.Stat(pkgDir); errors.Is(err, fs.ErrNotExist) {
+		// We populate a temporary directory first and then rename it into
+		// place, so that other OpenTofu processes sharing the cache never
+		// see a partial package.
+		if err := os.MkdirAll(filepath.Dir(pkgDir), 0755); err != nil {
+			return err
+		}
+		tmpDir, err := os.MkdirTemp(filepath.Dir(pkgDir), ".tmp-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+		if err := linkDir(tmpDir, instDir); err != nil {
+			return err
+		}
+		if err := os.Rename(tmpDir, pkgDir); err != nil {
+			if _, statErr := os.Stat(pkgDir); statErr != nil {
+				return err
+			}
+			// Another process stored the same package in the meantime.
+		}
+	} else if err != nil {
+		return err
+	}
+
+	addrPath := c.addressPath(packageAddr)
+	if err := os.MkdirAll(filepath.Dir(addrPath), 0755); err != nil {
+		return err
+	}
+	return replacefile.AtomicWriteFile(addrPath, []byte(hash+"\n"), 0644)
+}
+
+func (c *PackageCache) packagePath(hash string) string {
+	// Package hashes use the standard base64 alphabet, which includes "/",
+	// so we re-encode them to be safe to use as filenames.
+	scheme, value, _ := strings.Cut(hash, ":")
+	if raw, err := base64.StdEncoding.DecodeString(value); err == nil {
+		value = hex.EncodeToString(raw)
+	} else {
+		sum := sha256.Sum256([]byte(value))
+		value = hex.EncodeToString(sum[:])
+	}
+	return filepath.Join(c.dir, "packages", scheme, value)
+}
+
+func (c *PackageCache) addressPath(packageAddr string) string {
+	sum := sha256.Sum256([]byte(packageAddr))
+	return filepath.Join(c.dir, "addresses", hex.EncodeToString(sum[:]))
+}
+
+// cacheablePackageAddr returns false for the package addresses that refer to
+// local directories, whose content can change at any time.
+func cacheablePackageAddr(packageAddr string) bool {
+	return !strings.HasPrefix(packageAddr, "file:")
+}
+
+// immutablePackageAddr returns true for the package addresses whose content
+// cannot change over time: git repositories at a full commit hash, and OCI
+// repositories at a specific manifest digest. Other addresses, such as git
+// branches or tags, and HTTP URLs, may refer to different content each time
+// they are fetched.
+func immutablePackageAddr(packageAddr string) bool {
+	getterName, rawURL, _ := strings.Cut(packageAddr, "::")
+	if rawURL == "" {
+		getterName, rawURL = "", packageAddr
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	query := u.Query()
+	switch {
+	case u.Scheme == "oci":
+		return query.Get("digest") != ""
+	case getterName == "git" || strings.HasPrefix(u.Scheme, "git"):
+		return fullCommitHash(query.Get("ref"))
+	default:
+		return false
+	}
+}
+
+// fullCommitHash returns true if the given string is a complete SHA-1 or
+// SHA-256 git commit hash, rather than a branch, tag, or abbreviated hash.
+func fullCommitHash(ref string) bool {
+	if len(ref) != 40 && len(ref) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(ref)
+	return err == nil
+}
+
+// linkDir recreates the directory tree of src inside dst, hard-linking the
+// files if possible and copying them otherwise. Unlike [copy.CopyDir], it
+// includes the "dot files", such as the ".git" directory of a package.
+func linkDir(dst, src string) error {
+	src, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(dst, rel)
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(dstPath, 0755)
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, dstPath)
+		}
+
+		if err := os.Link(path, dstPath); err == nil {
+			return nil
+		}
+		// Hard links are not possible across filesystems, so we fall back
+		// to copying the file.
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return copyFile(dstPath, path, info.Mode())
+	})
+}
+
+func copyFile(dst, src string, mode fs.FileMode) error {
+	srcF, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcF.Close()
+
+	dstF, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dstF, srcF); err != nil {
+		dstF.Close()
+		return err
+	}
+	return dstF.Close()
+}
@@ -0,0 +1,222 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPackageCache(t *testing.T) {
+	const addr = "git::https://example.com/foo.git?ref=0123456789abcdef0123456789abcdef01234567"
+	const branchAddr = "git::https://example.com/foo.git?ref=main"
+
+	cache := NewPackageCache(t.TempDir())
+	srcDir := t.TempDir()
+	writeTestFile(t, filepath.Join(srcDir, "main.tf"), `variable "foo" {}`)
+	writeTestFile(t, filepath.Join(srcDir, ".git", "HEAD"), "ref: refs/heads/main\n")
+	hash, err := PackageHash(srcDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	install := func(packageAddr, wantHash string, immutable, refresh bool) (string, bool) {
+		t.Helper()
+		instDir := filepath.Join(t.TempDir(), "inst")
+		installed, err := cache.install(instDir, packageAddr, wantHash, immutable, refresh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return instDir, installed
+	}
+
+	if _, installed := install(addr, "", false, false); installed {
+		t.Fatal("installed a package from an empty cache")
+	}
+
+	if err := cache.store(srcDir, addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.store(srcDir, branchAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("by address", func(t *testing.T) {
+		instDir, installed := install(addr, "", false, false)
+		if !installed {
+			t.Fatal("package was not installed from the cache")
+		}
+		if got, err := PackageHash(instDir); err != nil || got != hash {
+			t.Errorf("wrong hash %q for the installed package; want %q (err: %v)", got, hash, err)
+		}
+		// The git metadata must be retained, so that the commit of the
+		// package can still be determined.
+		if _, err := os.Stat(filepath.Join(instDir, ".git", "HEAD")); err != nil {
+			t.Errorf("git metadata was not installed: %s", err)
+		}
+	})
+	t.Run("by address with refresh", func(t *testing.T) {
+		if _, installed := install(addr, "", false, true); installed {
+			t.Error("package was installed from the cache despite refresh")
+		}
+	})
+	t.Run("by mutable address", func(t *testing.T) {
+		// A branch may refer to a different commit each time it's fetched,
+		// so the package must be fetched again.
+		if _, installed := install(branchAddr, "", false, false); installed {
+			t.Error("package was installed from the cache by a mutable address")
+		}
+	})
+	t.Run("by mutable address marked immutable", func(t *testing.T) {
+		// The caller knows that the address refers to content that cannot
+		// change, such as the package of a module registry version.
+		if _, installed := install(branchAddr, "", true, false); !installed {
+			t.Error("package was not installed from the cache")
+		}
+	})
+	t.Run("by hash", func(t *testing.T) {
+		// The hash identifies the package regardless of its address, and
+		// regardless of refresh.
+		if _, installed := install("git::https://example.com/bar.git", hash, false, true); !installed {
+			t.Error("package was not installed from the cache")
+		}
+	})
+	t.Run("by unknown hash", func(t *testing.T) {
+		if _, installed := install(addr, "h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=", false, false); installed {
+			t.Error("installed a package with the wrong hash")
+		}
+	})
+	t.Run("local directory", func(t *testing.T) {
+		if err := cache.store(srcDir, "file://"+srcDir); err != nil {
+			t.Fatal(err)
+		}
+		if _, installed := install("file://"+srcDir, "", true, false); installed {
+			t.Error("installed a local directory from the cache")
+		}
+	})
+	t.Run("modified package", func(t *testing.T) {
+		// The files of an installed package may be hard links to the cached
+		// files, so modifying them in place also modifies the cached package.
+		cachedFile := filepath.Join(cache.packagePath(hash), "main.tf")
+		if err := os.WriteFile(cachedFile, []byte(`variable "bar" {}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, installed := install(addr, "", false, false); installed {
+			t.Error("installed a package whose content has changed")
+		}
+		if _, err := os.Stat(cache.packagePath(hash)); !os.IsNotExist(err) {
+			t.Errorf("modified package was not removed from the cache")
+		}
+	})
+}
+
+func TestPackageFetcher_moduleCacheDir(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("main.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(`variable "foo" {}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			requests.Add(1)
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	env := testPackageFetcherEnvironment{moduleCacheDir: t.TempDir()}
+	packageAddr := server.URL + "/module.zip"
+
+	// Each fetcher has its own in-memory record of previous installs, so we
+	// use a new one for each fetch to show that only the cache is reused.
+	fetch := func(immutable, refresh bool) {
+		t.Helper()
+		fetcher := NewPackageFetcher(t.Context(), env)
+		instDir := filepath.Join(t.TempDir(), "inst")
+		if err := fetcher.FetchPackage(t.Context(), instDir, packageAddr, "", immutable, refresh); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(instDir, "main.tf")); err != nil {
+			t.Fatalf("package was not installed: %s", err)
+		}
+	}
+
+	// The content of an HTTP URL can change over time, so by default it is
+	// fetched again each time.
+	fetch(false, false)
+	fetch(false, false)
+	if got, want := requests.Load(), int32(2); got != want {
+		t.Errorf("wrong number of requests %d after fetching twice; want %d", got, want)
+	}
+	fetch(true, false)
+	if got, want := requests.Load(), int32(2); got != want {
+		t.Errorf("wrong number of requests %d after fetching an immutable package; want %d", got, want)
+	}
+	fetch(true, true)
+	if got, want := requests.Load(), int32(3); got != want {
+		t.Errorf("wrong number of requests %d after refreshing; want %d", got, want)
+	}
+}
+
+type testPackageFetcherEnvironment struct {
+	noopPackageFetcherEnvironment
+	moduleCacheDir string
+}
+
+func (e testPackageFetcherEnvironment) ModuleCacheDir() string {
+	return e.moduleCacheDir
+}
+
+var _ PackageFetcherEnvironment = testPackageFetcherEnvironment{}
+
+func TestImmutablePackageAddr(t *testing.T) {
+	tests := map[string]bool{
+		"git::https://example.com/foo.git":                                                         false,
+		"git::https://example.com/foo.git?ref=main":                                                false,
+		"git::https://example.com/foo.git?ref=v1.2.0":                                              false,
+		"git::https://example.com/foo.git?ref=0123456":                                             false,
+		"git::https://example.com/foo.git?ref=0123456789abcdef0123456789abcdef01234567":            true,
+		"git::ssh://git@example.com/foo.git?ref=0123456789abcdef0123456789abcdef01234567":          true,
+		"https://example.com/foo.zip?ref=0123456789abcdef0123456789abcdef01234567":                 false,
+		"oci://example.com/foo?tag=latest":                                                         false,
+		"oci://example.com/foo?digest=sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123": true,
+		"hg::https://example.com/foo?ref=0123456789abcdef0123456789abcdef01234567":                 false,
+	}
+	for addr, want := range tests {
+		if got := immutablePackageAddr(addr); got != want {
+			t.Errorf("wrong result for %q: got %t, want %t", addr, got, want)
+		}
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// We remove the file first so that we don't write through a hard link.
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// PackageHash computes a hash of the contents of the module package that
+// was installed into the given directory, using the same "h1:" hash scheme
+// that OpenTofu uses for provider packages.
+//
+// The metadata directories of version control systems, such as the ".git"
+// directory of a package installed from a git repository, are excluded
+// because their content depends on how the package was fetched rather than
+// on the content of the package itself.
+//
+// So that the same package has the same hash on every platform, the content
+// is normalized in the same way as git represents it in a repository:
+//   - Line endings in text files are normalized to LF before hashing, because
+//     git may check out text files with CRLF line endings depending on the
+//     core.autocrlf setting. A file is considered to be text if it contains no
+//     NUL bytes in its first 8000 bytes, which is the heuristic git uses.
+//   - A symlink, whether to a file or a directory, is hashed as a file whose
+//     content is the target of the symlink, which is also how git checks out
+//     symlinks on systems that don't support them.
+func PackageHash(dir string) (string, error) {
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var files []string
+	symlinks := make(map[string]string)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == ".hg" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			symlinks[rel] = filepath.ToSlash(target)
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return dirhash.Hash1(files, func(name string) (io.ReadCloser, error) {
+		if target, ok := symlinks[name]; ok {
+			return io.NopCloser(strings.NewReader(target)), nil
+		}
+		src, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(normalizeLineEndings(src))), nil
+	})
+}
+
+// normalizeLineEndings replaces CRLF line endings with LF if the given file
+// content seems to be text, using the same heuristic as git.
+func normalizeLineEndings(src []byte) []byte {
+	if bytes.IndexByte(src[:min(len(src), 8000)], 0) != -1 {
+		return src
+	}
+	return bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+}
+
+// PackageGitCommit returns the commit that is checked out in the module
+// package that was installed into the given directory, or an empty string
+// if the package was not installed from a git repository.
+func PackageGitCommit(ctx context.Context, dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine the checked out commit of %s: %w", dir, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
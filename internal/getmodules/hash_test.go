@@ -0,0 +1,138 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackageHash(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash := func() string {
+		t.Helper()
+		got, err := PackageHash(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	writeFile("main.tf", `variable "foo" {}`)
+	writeFile("modules/child/main.tf", `output "bar" { value = "bar" }`)
+	initial := hash()
+	if !strings.HasPrefix(initial, "h1:") {
+		t.Fatalf("wrong hash scheme: %s", initial)
+	}
+
+	// The git metadata of the package is not part of its content.
+	writeFile(".git/HEAD", "ref: refs/heads/main\n")
+	if got := hash(); got != initial {
+		t.Errorf("hash changed after adding git metadata\ngot:  %s\nwant: %s", got, initial)
+	}
+
+	writeFile("modules/child/main.tf", `output "bar" { value = "baz" }`)
+	if got := hash(); got == initial {
+		t.Errorf("hash didn't change after modifying the package")
+	}
+}
+
+func TestPackageHash_lineEndings(t *testing.T) {
+	hash := func(files map[string]string) string {
+		t.Helper()
+		dir := t.TempDir()
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := PackageHash(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	// Text files checked out with CRLF line endings, such as by git with
+	// core.autocrlf enabled, have the same hash as with LF line endings.
+	lf := hash(map[string]string{"main.tf": "variable \"foo\" {}\nvariable \"bar\" {}\n"})
+	crlf := hash(map[string]string{"main.tf": "variable \"foo\" {}\r\nvariable \"bar\" {}\r\n"})
+	if lf != crlf {
+		t.Errorf("line endings changed the hash of a text file\nLF:   %s\nCRLF: %s", lf, crlf)
+	}
+
+	// Binary files are hashed as they are.
+	lf = hash(map[string]string{"data.bin": "\x00\n"})
+	crlf = hash(map[string]string{"data.bin": "\x00\r\n"})
+	if lf == crlf {
+		t.Errorf("line endings didn't change the hash of a binary file")
+	}
+}
+
+func TestPackageHash_symlinks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "modules", "a"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "modules", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("modules", "a"), filepath.Join(dir, "child")); err != nil {
+		t.Skipf("can't create symlinks: %s", err)
+	}
+	hash := func() string {
+		t.Helper()
+		got, err := PackageHash(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	initial := hash()
+
+	// Changing the target of a symlink to a directory changes the hash.
+	if err := os.Remove(filepath.Join(dir, "child")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("modules", "b"), filepath.Join(dir, "child")); err != nil {
+		t.Fatal(err)
+	}
+	if got := hash(); got == initial {
+		t.Errorf("hash didn't change after changing the target of a symlink")
+	}
+
+	// A symlink has the same hash as the file that git checks out in its
+	// place on systems without symlinks.
+	if err := os.Remove(filepath.Join(dir, "child")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "child"), []byte("modules/a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := hash(); got != initial {
+		t.Errorf("symlink and its placeholder file have different hashes\ngot:  %s\nwant: %s", got, initial)
+	}
+}
+
+func TestPackageGitCommit(t *testing.T) {
+	got, err := PackageGitCommit(t.Context(), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "" {
+		t.Errorf("unexpected commit %q for a package that isn't a git repository", got)
+	}
+}
@@ -8,7 +8,9 @@ package getmodules
 import (
 	"context"
 	"fmt"
+	"log"
 	"maps"
+	"os"
 
 	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
 	"go.opentelemetry.io/otel/trace"
@@ -31,8 +33,13 @@ import (
 // rather than fetching the package from its origin repeatedly. There is
 // no way to reset this cache, so a particular PackageFetcher instance should
 // live only for the duration of a single initialization process.
+//
+// If the environment specifies a module cache directory then a PackageFetcher
+// also retains the packages it fetches in a [PackageCache] shared with other
+// working directories, and installs packages from there when possible.
 type PackageFetcher struct {
 	getter *reusingGetter
+	cache  *PackageCache
 }
 
 // NewPackageFetcher constructs a new [PackageFetcher] that interacts with
@@ -71,8 +78,14 @@ func NewPackageFetcher(ctx context.Context, env PackageFetcherEnvironment) *Pack
 	getters["http"] = httpGetter
 	getters["https"] = httpGetter
 
+	var cache *PackageCache
+	if dir := env.ModuleCacheDir(); dir != "" {
+		cache = NewPackageCache(dir)
+	}
+
 	return &PackageFetcher{
 		getter: newReusingGetter(getters),
+		cache:  cache,
 	}
 }
 
@@ -88,16 +101,48 @@ func NewPackageFetcher(ctx context.Context, env PackageFetcherEnvironment) *Pack
 // a module source address which includes a subdirectory portion then the
 // caller must resolve that itself, possibly with the help of the
 // getmodules.SplitPackageSubdir and getmodules.ExpandSubdirGlobs functions.
-func (f *PackageFetcher) FetchPackage(ctx context.Context, instDir string, packageAddr string) error {
+//
+// If the fetcher has a module cache then wantHash, if set, is the hash of
+// the package recorded in the dependency lock file, which allows installing
+// the package from the cache even if it was fetched from a different address.
+// Otherwise the package most recently fetched from the same address is
+// installed from the cache only if the address refers to content that cannot
+// change: either the caller sets immutable, such as for the package of a
+// specific module registry version, or the address selects a specific git
+// commit or OCI manifest digest. Setting refresh forces fetching the package
+// from its origin again, such as when upgrading modules.
+func (f *PackageFetcher) FetchPackage(ctx context.Context, instDir string, packageAddr string, wantHash string, immutable, refresh bool) error {
 	ctx, span := tracing.Tracer().Start(ctx, "Fetch Package",
 		trace.WithAttributes(semconv.URLFull(packageAddr)),
 	)
 	defer span.End()
+
+	if f.cache != nil {
+		installed, err := f.cache.install(instDir, packageAddr, wantHash, immutable, refresh)
+		if err != nil {
+			// The cache is only an optimization, so we'll fall back to
+			// fetching the package from its origin.
+			log.Printf("[WARN] getmodules: failed to install %q from the module cache: %s", packageAddr, err)
+			if err := os.RemoveAll(instDir); err != nil {
+				span.RecordError(err)
+				return err
+			}
+		} else if installed {
+			return nil
+		}
+	}
+
 	err := f.getter.getWithGoGetter(ctx, instDir, packageAddr)
 	if err != nil {
 		span.RecordError(err)
 		return err
 	}
+
+	if f.cache != nil {
+		if err := f.cache.store(instDir, packageAddr); err != nil {
+			log.Printf("[WARN] getmodules: failed to store %q in the module cache: %s", packageAddr, err)
+		}
+	}
 	return nil
 }
 
@@ -113,6 +158,10 @@ func (f *PackageFetcher) FetchPackage(ctx context.Context, instDir string, packa
 // concerns is still the best design for that different context.
 type PackageFetcherEnvironment interface {
 	OCIRepositoryStore(ctx context.Context, registryDomainName, repositoryPath string) (OCIRepositoryStore, error)
+
+	// ModuleCacheDir returns the directory to use as a [PackageCache], or
+	// an empty string if module packages should not be cached.
+	ModuleCacheDir() string
 }
 
 // preparePackageFetcherEnvironment takes a [PackageFetcherEnvironment]
@@ -137,3 +186,8 @@ type noopPackageFetcherEnvironment struct{}
 func (n noopPackageFetcherEnvironment) OCIRepositoryStore(ctx context.Context, registryDomainName string, repositoryPath string) (OCIRepositoryStore, error) {
 	return nil, fmt.Errorf("module installation from OCI repositories is not available in this context")
 }
+
+// ModuleCacheDir implements PackageFetcherEnvironment.
+func (n noopPackageFetcherEnvironment) ModuleCacheDir() string {
+	return ""
+}
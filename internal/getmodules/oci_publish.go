@@ -0,0 +1,102 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	ociDigest "github.com/opencontainers/go-digest"
+	ociSpecs "github.com/opencontainers/image-spec/specs-go"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	otelAttr "go.opentelemetry.io/otel/attribute"
+	otelTrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/opentofu/opentofu/internal/ocipush"
+	"github.com/opentofu/opentofu/internal/tracing"
+)
+
+// PushOCIModulePackage pushes the module package archive at the given path,
+// as written by [WriteModulePackageArchive], to an OCI repository using the
+// artifact layout expected by the "oci" module source type, and then
+// associates the given tag name with it.
+//
+// The returned descriptor is for the image manifest, whose digest can be
+// used to select exactly this package in a module source address.
+func PushOCIModulePackage(ctx context.Context, store ocipush.RepositoryStore, archivePath string, tagName string) (ociv1.Descriptor, error) {
+	ctx, span := tracing.Tracer().Start(
+		ctx, "Push module package",
+		otelTrace.WithAttributes(
+			otelAttr.String("opentofu.oci.reference.tag", tagName),
+		),
+	)
+	defer span.End()
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, err
+	}
+	defer f.Close()
+	digester := ociDigest.Canonical.Digester()
+	size, err := io.Copy(digester.Hash(), f)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, err
+	}
+	blobDesc := ociv1.Descriptor{
+		MediaType: ociBlobMediaTypePreference[0],
+		Digest:    digester.Digest(),
+		Size:      size,
+	}
+	if err := ocipush.PushContent(ctx, store, blobDesc, f); err != nil {
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, fmt.Errorf("pushing package blob: %w", err)
+	}
+
+	// The manifest has no configuration, so we use the empty configuration
+	// blob as recommended by the OCI image specification for artifacts.
+	if err := ocipush.PushContent(ctx, store, ociv1.DescriptorEmptyJSON, bytes.NewReader(ociv1.DescriptorEmptyJSON.Data)); err != nil {
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, fmt.Errorf("pushing empty configuration blob: %w", err)
+	}
+
+	manifest := &ociv1.Manifest{
+		Versioned:    ociSpecs.Versioned{SchemaVersion: 2},
+		MediaType:    ociv1.MediaTypeImageManifest,
+		ArtifactType: ociIndexManifestArtifactType,
+		Config:       ociv1.DescriptorEmptyJSON,
+		Layers:       []ociv1.Descriptor{blobDesc},
+	}
+	manifestSrc, err := json.Marshal(manifest)
+	if err != nil {
+		// Should not happen because the manifest is always JSON-serializable.
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, err
+	}
+	manifestDesc := ociv1.Descriptor{
+		MediaType:    manifest.MediaType,
+		ArtifactType: manifest.ArtifactType,
+		Digest:       ociDigest.FromBytes(manifestSrc),
+		Size:         int64(len(manifestSrc)),
+	}
+	if err := ocipush.PushContent(ctx, store, manifestDesc, bytes.NewReader(manifestSrc)); err != nil {
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, fmt.Errorf("pushing manifest: %w", err)
+	}
+
+	if err := store.Tag(ctx, manifestDesc, tagName); err != nil {
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, fmt.Errorf("creating tag %q: %w", tagName, err)
+	}
+	return manifestDesc, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-getter"
+	orasMemoryStore "oras.land/oras-go/v2/content/memory"
+)
+
+func TestPushOCIModulePackage(t *testing.T) {
+	srcDir := t.TempDir()
+	writeTestFile(t, filepath.Join(srcDir, "main.tf"), `variable "foo" {}`)
+	writeTestFile(t, filepath.Join(srcDir, "notes.txt"), "private notes")
+	writeTestFile(t, filepath.Join(srcDir, ".tofuignore"), "*.txt\n")
+
+	archivePath := filepath.Join(t.TempDir(), "package.zip")
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteModulePackageArchive(srcDir, f); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store := digestResolvingInMemoryOCIStore{orasMemoryStore.New()}
+	// Pushing the same package twice must succeed, reusing the blobs that
+	// are already present.
+	manifestDesc, err := PushOCIModulePackage(t.Context(), store, archivePath, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := PushOCIModulePackage(t.Context(), store, archivePath, "latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Digest != manifestDesc.Digest {
+		t.Errorf("pushing the same package produced a different manifest digest %s; want %s", again.Digest, manifestDesc.Digest)
+	}
+
+	// The pushed content must then be installable by the "oci" module
+	// source type, by tag and by digest.
+	ociGetter := &ociDistributionGetter{
+		getOCIRepositoryStore: func(ctx context.Context, registryDomain, repositoryName string) (OCIRepositoryStore, error) {
+			if registryDomain != "example.com" || repositoryName != "modules/foo" {
+				return nil, fmt.Errorf("no such repository")
+			}
+			return store, nil
+		},
+	}
+	for _, source := range []string{
+		"oci://example.com/modules/foo",
+		"oci://example.com/modules/foo?tag=v1.0.0",
+		"oci://example.com/modules/foo?digest=" + manifestDesc.Digest.String(),
+	} {
+		t.Run(source, func(t *testing.T) {
+			instPath := t.TempDir()
+			client := getter.Client{
+				Src: source,
+				Dst: instPath,
+				Pwd: instPath,
+
+				Mode: getter.ClientModeDir,
+
+				Detectors: goGetterNoDetectors,
+				Getters: map[string]getter.Getter{
+					"oci": ociGetter,
+				},
+				Ctx: t.Context(),
+			}
+			if err := client.Get(); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := os.ReadFile(filepath.Join(instPath, "main.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := strings.TrimSpace(string(got)), `variable "foo" {}`; got != want {
+				t.Errorf("wrong file content\ngot:  %s\nwant: %s", got, want)
+			}
+			if _, err := os.Stat(filepath.Join(instPath, "notes.txt")); !os.IsNotExist(err) {
+				t.Errorf("ignored file was installed")
+			}
+		})
+	}
+}
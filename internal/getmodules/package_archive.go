@@ -0,0 +1,239 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"archive/zip"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// PackageIgnoreFilenames are the names of the files, in order of preference,
+// that [WriteModulePackageArchive] reads from the root of a module package
+// directory to decide which files to exclude from the package.
+//
+// ".terraformignore" is supported for compatibility with the files used to
+// exclude content from uploads to remote backends.
+var PackageIgnoreFilenames = []string{".tofuignore", ".terraformignore"}
+
+// defaultPackageIgnoreRules are the rules that apply before any rules from
+// an ignore file, which can override them if needed.
+var defaultPackageIgnoreRules = []string{
+	".git/",
+	".terraform/",
+}
+
+// WriteModulePackageArchive writes a .zip archive of the module package in
+// the given directory to the given writer, using the archive format expected
+// for module packages in OCI registries.
+//
+// Files are excluded from the archive if they match the rules in the first
+// file named in [PackageIgnoreFilenames] that is present in the root of the
+// directory. Each non-empty line of that file that doesn't start with "#" is
+// a pattern, using the same syntax as ".gitignore": a pattern that contains
+// no slash other than a trailing one matches a file or directory at any
+// depth, a pattern with a trailing slash matches only directories, "**"
+// matches any number of directories, and a pattern starting with "!"
+// re-includes a previously-excluded file. The last rule that matches a path
+// decides whether it's excluded.
+//
+// Symlinks are included as the files they refer to, but a symlink to a
+// directory is rejected because its content could be outside of the package,
+// and so is a symlink to a file outside of the package.
+func WriteModulePackageArchive(srcDir string, w io.Writer) error {
+	rules, err := loadPackageIgnoreRules(srcDir)
+	if err != nil {
+		return err
+	}
+	realSrcDir, err := filepath.Abs(srcDir)
+	if err != nil {
+		return err
+	}
+	realSrcDir, err = filepath.EvalSymlinks(realSrcDir)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	err = filepath.WalkDir(srcDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		info, err := os.Stat(p) // follows symlinks
+		if err != nil {
+			return err
+		}
+		if rules.excludes(rel, info.IsDir()) {
+			if info.IsDir() && d.IsDir() {
+				// Files inside an excluded directory can't be re-included,
+				// in the same way as for ".gitignore".
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if !d.IsDir() {
+				return fmt.Errorf("%s is a symlink to a directory, which is not supported in a module package", rel)
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", rel)
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, err := filepath.EvalSymlinks(p)
+			if err != nil {
+				return err
+			}
+			if !withinDir(realSrcDir, target) {
+				return fmt.Errorf("%s is a symlink to %s, which is outside of the module package", rel, target)
+			}
+		}
+
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		hdr.Method = zip.Deflate
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(fw, f)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to package %s: %w", srcDir, err)
+	}
+	return zw.Close()
+}
+
+// withinDir returns true if the given absolute path is inside the given
+// absolute directory path.
+func withinDir(dir, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+type packageIgnoreRule struct {
+	pattern  string
+	negated  bool
+	dirOnly  bool
+	anchored bool
+}
+
+type packageIgnoreRules []packageIgnoreRule
+
+func loadPackageIgnoreRules(srcDir string) (packageIgnoreRules, error) {
+	rules := parsePackageIgnoreRules(defaultPackageIgnoreRules)
+	for _, name := range PackageIgnoreFilenames {
+		f, err := os.Open(filepath.Join(srcDir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		var lines []string
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+		if err := sc.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		for _, rule := range parsePackageIgnoreRules(lines) {
+			if !doublestar.ValidatePattern(rule.pattern) {
+				return nil, fmt.Errorf("invalid pattern %q in %s", rule.pattern, name)
+			}
+			rules = append(rules, rule)
+		}
+		break
+	}
+	return rules, nil
+}
+
+func parsePackageIgnoreRules(lines []string) packageIgnoreRules {
+	var rules packageIgnoreRules
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var rule packageIgnoreRule
+		if after, ok := strings.CutPrefix(line, "!"); ok {
+			rule.negated = true
+			line = after
+		}
+		if after, ok := strings.CutSuffix(line, "/"); ok {
+			rule.dirOnly = true
+			line = after
+		}
+		if after, ok := strings.CutPrefix(line, "/"); ok {
+			rule.anchored = true
+			line = after
+		} else if strings.Contains(line, "/") {
+			rule.anchored = true
+		}
+		if line == "" {
+			continue
+		}
+		rule.pattern = line
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// excludes returns true if the given slash-separated path, relative to the
+// root of the package, is excluded by the rules.
+func (rules packageIgnoreRules) excludes(rel string, isDir bool) bool {
+	excluded := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.matches(rel) {
+			excluded = !rule.negated
+		}
+	}
+	return excluded
+}
+
+func (rule packageIgnoreRule) matches(rel string) bool {
+	if !rule.anchored {
+		rel = path.Base(rel)
+	}
+	// The patterns were already validated while loading the rules, so
+	// there can be no error here.
+	matched, _ := doublestar.Match(rule.pattern, rel)
+	return matched
+}
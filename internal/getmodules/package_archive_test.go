@@ -0,0 +1,162 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getmodules
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteModulePackageArchive(t *testing.T) {
+	srcDir := t.TempDir()
+	writeTestFile(t, filepath.Join(srcDir, "main.tf"), `variable "foo" {}`)
+	writeTestFile(t, filepath.Join(srcDir, "README.md"), "# Example")
+	writeTestFile(t, filepath.Join(srcDir, "modules", "child", "main.tf"), `variable "bar" {}`)
+	writeTestFile(t, filepath.Join(srcDir, "modules", "child", "notes.txt"), "private notes")
+	writeTestFile(t, filepath.Join(srcDir, "examples", "basic", "main.tf"), `module "foo" {}`)
+	writeTestFile(t, filepath.Join(srcDir, "examples", "basic", "terraform.tfstate"), "{}")
+	writeTestFile(t, filepath.Join(srcDir, "build", "output.txt"), "build output")
+	writeTestFile(t, filepath.Join(srcDir, "docs", "build", "index.md"), "docs")
+	writeTestFile(t, filepath.Join(srcDir, ".git", "HEAD"), "ref: refs/heads/main\n")
+	writeTestFile(t, filepath.Join(srcDir, ".terraform", "modules", "modules.json"), "{}")
+	writeTestFile(t, filepath.Join(srcDir, ".tofuignore"), `
+# Comments and blank lines are ignored
+
+*.txt
+!modules/child/notes.txt
+**/*.tfstate
+/build/
+`)
+	// The .terraformignore file is not used when .tofuignore is present.
+	writeTestFile(t, filepath.Join(srcDir, ".terraformignore"), "*.md\n")
+
+	got := readTestPackageArchive(t, srcDir)
+	want := map[string]string{
+		".terraformignore":        "*.md\n",
+		".tofuignore":             "\n# Comments and blank lines are ignored\n\n*.txt\n!modules/child/notes.txt\n**/*.tfstate\n/build/\n",
+		"README.md":               "# Example",
+		"docs/build/index.md":     "docs",
+		"examples/basic/main.tf":  `module "foo" {}`,
+		"main.tf":                 `variable "foo" {}`,
+		"modules/child/main.tf":   `variable "bar" {}`,
+		"modules/child/notes.txt": "private notes",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong archive content\n%s", diff)
+	}
+}
+
+func TestWriteModulePackageArchive_terraformignore(t *testing.T) {
+	srcDir := t.TempDir()
+	writeTestFile(t, filepath.Join(srcDir, "main.tf"), `variable "foo" {}`)
+	writeTestFile(t, filepath.Join(srcDir, "README.md"), "# Example")
+	writeTestFile(t, filepath.Join(srcDir, ".terraformignore"), "*.md\n.terraformignore\n")
+
+	got := readTestPackageArchive(t, srcDir)
+	want := map[string]string{
+		"main.tf": `variable "foo" {}`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong archive content\n%s", diff)
+	}
+}
+
+func TestWriteModulePackageArchive_symlinks(t *testing.T) {
+	srcDir := t.TempDir()
+	writeTestFile(t, filepath.Join(srcDir, "main.tf"), `variable "foo" {}`)
+	writeTestFile(t, filepath.Join(srcDir, "modules", "shared.tf"), `variable "shared" {}`)
+	if err := os.Symlink(filepath.Join("modules", "shared.tf"), filepath.Join(srcDir, "shared.tf")); err != nil {
+		t.Skipf("can't create symlinks: %s", err)
+	}
+
+	// A symlink to a file inside the package is included as that file.
+	got := readTestPackageArchive(t, srcDir)
+	want := map[string]string{
+		"main.tf":           `variable "foo" {}`,
+		"modules/shared.tf": `variable "shared" {}`,
+		"shared.tf":         `variable "shared" {}`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong archive content\n%s", diff)
+	}
+
+	t.Run("file outside of the package", func(t *testing.T) {
+		outsideDir := t.TempDir()
+		writeTestFile(t, filepath.Join(outsideDir, "credentials"), "secret")
+		srcDir := t.TempDir()
+		writeTestFile(t, filepath.Join(srcDir, "main.tf"), `variable "foo" {}`)
+		if err := os.Symlink(filepath.Join(outsideDir, "credentials"), filepath.Join(srcDir, "credentials")); err != nil {
+			t.Fatal(err)
+		}
+
+		err := WriteModulePackageArchive(srcDir, &bytes.Buffer{})
+		if err == nil || !strings.Contains(err.Error(), "credentials is a symlink to") || !strings.Contains(err.Error(), "outside of the module package") {
+			t.Fatalf("wrong error: %v", err)
+		}
+	})
+	t.Run("directory", func(t *testing.T) {
+		srcDir := t.TempDir()
+		writeTestFile(t, filepath.Join(srcDir, "modules", "shared.tf"), `variable "shared" {}`)
+		if err := os.Symlink("modules", filepath.Join(srcDir, "shared")); err != nil {
+			t.Fatal(err)
+		}
+
+		err := WriteModulePackageArchive(srcDir, &bytes.Buffer{})
+		if err == nil {
+			t.Fatal("unexpected success with a symlink to a directory")
+		}
+	})
+}
+
+func TestWriteModulePackageArchive_invalidPattern(t *testing.T) {
+	srcDir := t.TempDir()
+	writeTestFile(t, filepath.Join(srcDir, "main.tf"), `variable "foo" {}`)
+	writeTestFile(t, filepath.Join(srcDir, ".tofuignore"), "[abc\n")
+
+	err := WriteModulePackageArchive(srcDir, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("unexpected success")
+	}
+	if got, want := err.Error(), `invalid pattern "[abc" in .tofuignore`; got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+// readTestPackageArchive writes a package archive of the given directory
+// and returns the content of each file in it, by path.
+func readTestPackageArchive(t *testing.T, srcDir string) map[string]string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := WriteModulePackageArchive(srcDir, &buf); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := make(map[string]string, len(zr.File))
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var content bytes.Buffer
+		if _, err := content.ReadFrom(r); err != nil {
+			t.Fatal(err)
+		}
+		r.Close()
+		if _, exists := ret[f.Name]; exists {
+			t.Fatalf("archive has duplicate entries for %s", f.Name)
+		}
+		ret[f.Name] = content.String()
+	}
+	return ret
+}
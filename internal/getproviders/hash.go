@@ -526,6 +526,12 @@ type HashDisposition struct {
 	// unless the provider developer's signing key also appears in
 	// SignedByGPGKeyIDs.
 	VerifiedLocally bool
+
+	// SignedBySigstoreIdentities is a set of descriptions of the Sigstore
+	// identities or public keys which provided signatures covering the
+	// associated hash, as verified against the Sigstore policy configured
+	// for the source that the package was installed from.
+	SignedBySigstoreIdentities collections.Set[string]
 }
 
 // SignedByAnyGPGKeys returns true if the reciever has at least one GPG key
@@ -539,6 +545,12 @@ func (d HashDisposition) SignedByAnyGPGKeys() bool {
 	return len(d.SignedByGPGKeyIDs) != 0
 }
 
+// SignedByAnySigstoreIdentities returns true if the receiver has at least one
+// Sigstore identity or public key that signed the associated package.
+func (d HashDisposition) SignedByAnySigstoreIdentities() bool {
+	return len(d.SignedBySigstoreIdentities) != 0
+}
+
 // GPGSigningKeysString returns a string representation of any GPG signing
 // key IDs that signed an assertion that the associated hash is valid for the
 // associated provider version.
@@ -573,6 +585,16 @@ func MergeHashDisposition(a, b *HashDisposition) *HashDisposition {
 			ret.SignedByGPGKeyIDs[key] = struct{}{}
 		}
 	}
+	identityCount := len(a.SignedBySigstoreIdentities) + len(b.SignedBySigstoreIdentities)
+	if identityCount > 0 {
+		ret.SignedBySigstoreIdentities = make(collections.Set[string], identityCount)
+		for identity := range a.SignedBySigstoreIdentities {
+			ret.SignedBySigstoreIdentities[identity] = struct{}{}
+		}
+		for identity := range b.SignedBySigstoreIdentities {
+			ret.SignedBySigstoreIdentities[identity] = struct{}{}
+		}
+	}
 	ret.ReportedByRegistry = a.ReportedByRegistry || b.ReportedByRegistry
 	ret.VerifiedLocally = a.VerifiedLocally || b.VerifiedLocally
 	return ret
@@ -611,6 +633,34 @@ func (ds HashDispositions) AllGPGSigningKeysString() string {
 	return strings.Join(keyIDs, ", ")
 }
 
+// AllSigstoreIdentitiesString returns a string representation of all
+// Sigstore identities or public keys that signed one of the packages.
+//
+// If there are no such identities then the result is an empty string.
+//
+// As with [HashDispositions.AllGPGSigningKeysString], the result is intended
+// for display to a human in the UI.
+func (ds HashDispositions) AllSigstoreIdentitiesString() string {
+	all := make(collections.Set[string])
+	for _, disp := range ds {
+		for identity := range disp.SignedBySigstoreIdentities {
+			all[identity] = struct{}{}
+		}
+	}
+	identities := slices.Collect(maps.Keys(all))
+	sort.Strings(identities)
+	return strings.Join(identities, ", ")
+}
+
+func (ds HashDispositions) HasAnySignedBySigstoreIdentities() bool {
+	for _, disp := range ds {
+		if disp.SignedByAnySigstoreIdentities() {
+			return true
+		}
+	}
+	return false
+}
+
 func (ds HashDispositions) HasAnyReportedByRegistry() bool {
 	for _, disp := range ds {
 		if disp.ReportedByRegistry {
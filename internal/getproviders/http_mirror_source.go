@@ -34,6 +34,10 @@ type HTTPMirrorSource struct {
 	baseURL    *url.URL
 	creds      svcauth.CredentialsSource
 	httpClient *retryablehttp.Client
+
+	// sigstorePolicy, if non-nil, requires each package to have a Sigstore
+	// bundle alongside it that's acceptable to the policy.
+	sigstorePolicy *SigstorePolicy
 }
 
 var _ Source = (*HTTPMirrorSource)(nil)
@@ -46,7 +50,11 @@ var _ Source = (*HTTPMirrorSource)(nil)
 // (When the URL comes from user input, such as in the CLI config, it's the
 // UI/config layer's responsibility to validate this and return a suitable
 // error message for the end-user audience.)
-func NewHTTPMirrorSource(baseURL *url.URL, creds svcauth.CredentialsSource, requestTimeout time.Duration) *HTTPMirrorSource {
+//
+// If sigstorePolicy is non-nil then each package must be signed in a way
+// that's acceptable to the policy, using a Sigstore bundle whose URL is the
+// package URL with ".sigstore.json" appended.
+func NewHTTPMirrorSource(baseURL *url.URL, creds svcauth.CredentialsSource, requestTimeout time.Duration, sigstorePolicy *SigstorePolicy) *HTTPMirrorSource {
 	httpClient := httpclient.NewForRegistryRequests(context.TODO(), 0, requestTimeout)
 	httpClient.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		// If we get redirected more than five times we'll assume we're
@@ -56,7 +64,9 @@ func NewHTTPMirrorSource(baseURL *url.URL, creds svcauth.CredentialsSource, requ
 		}
 		return nil
 	}
-	return newHTTPMirrorSourceWithHTTPClient(baseURL, creds, httpClient)
+	ret := newHTTPMirrorSourceWithHTTPClient(baseURL, creds, httpClient)
+	ret.sigstorePolicy = sigstorePolicy
+	return ret
 }
 
 func newHTTPMirrorSourceWithHTTPClient(baseURL *url.URL, creds svcauth.CredentialsSource, httpClient *retryablehttp.Client) *HTTPMirrorSource {
@@ -213,7 +223,9 @@ func (s *HTTPMirrorSource) PackageMeta(ctx context.Context, provider addrs.Provi
 		Filename: path.Base(absURL.Path),
 	}
 	// A network mirror might not provide any hashes at all, in which case
-	// the package has no source-defined authentication whatsoever.
+	// the package has no source-defined authentication whatsoever unless
+	// there's a Sigstore policy.
+	var auths []PackageAuthentication
 	if len(archiveMeta.Hashes) > 0 {
 		hashes := make([]Hash, 0, len(archiveMeta.Hashes))
 		for _, hashStr := range archiveMeta.Hashes {
@@ -226,12 +238,52 @@ func (s *HTTPMirrorSource) PackageMeta(ctx context.Context, provider addrs.Provi
 			}
 			hashes = append(hashes, hash)
 		}
-		ret.Authentication = NewPackageHashAuthentication(target, hashes)
+		auths = append(auths, NewPackageHashAuthentication(target, hashes))
+	}
+	if s.sigstorePolicy != nil {
+		bundle, err := s.getSigstoreBundle(ctx, absURL)
+		if err != nil {
+			return PackageMeta{}, s.errQueryFailed(provider, err)
+		}
+		auths = append(auths, NewSigstoreBundleAuthentication(bundle, s.sigstorePolicy))
+	}
+	switch len(auths) {
+	case 0:
+		// No authentication at all
+	case 1:
+		ret.Authentication = auths[0]
+	default:
+		ret.Authentication = PackageAuthenticationAll(auths...)
 	}
 
 	return ret, nil
 }
 
+// getSigstoreBundle retrieves the Sigstore bundle for the package at the
+// given URL.
+func (s *HTTPMirrorSource) getSigstoreBundle(ctx context.Context, packageURL *url.URL) ([]byte, error) {
+	bundleURL := sigstoreBundleURL(packageURL)
+	statusCode, body, finalURL, err := s.get(ctx, bundleURL.String())
+	defer func() {
+		if body != nil {
+			body.Close()
+		}
+	}()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve Sigstore bundle: %w", err)
+	}
+	switch statusCode {
+	case http.StatusOK:
+		return readSigstoreBundle(body)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("provider mirror has no Sigstore bundle at %s, which is required by the configured Sigstore policy", finalURL)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, s.errUnauthorized(finalURL)
+	default:
+		return nil, fmt.Errorf("failed to retrieve Sigstore bundle: server returned unsuccessful status %d", statusCode)
+	}
+}
+
 // ForDisplay returns a string description of the source for user-facing output.
 func (s *HTTPMirrorSource) ForDisplay(provider addrs.Provider) string {
 	return "provider mirror at " + s.baseURL.String()
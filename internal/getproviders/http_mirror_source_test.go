@@ -7,10 +7,13 @@ package getproviders
 
 import (
 	"context"
+	"crypto"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -216,6 +219,66 @@ func TestHTTPMirrorSource(t *testing.T) {
 	})
 }
 
+func TestHTTPMirrorSource_sigstore(t *testing.T) {
+	content := []byte("provider package content")
+	archive := writeTestSigstorePackage(t, content)
+	signingKey := generateTestSigstoreKey(t)
+	bundle := testSigstoreKeyBundle(t, signingKey, content)
+
+	httpServer := httptest.NewTLSServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/terraform.io/test/exists/1.0.0.json", "/terraform.io/test/exists/1.0.1.json":
+			resp.Header().Add("Content-Type", "application/json")
+			resp.WriteHeader(200)
+			fmt.Fprintf(resp, `{"archives": {"tos_m68k": {"url": "terraform-provider-test_%s_tos_m68k.zip"}}}`, path.Base(strings.TrimSuffix(req.URL.Path, ".json")))
+		case "/terraform.io/test/exists/terraform-provider-test_1.0.0_tos_m68k.zip.sigstore.json":
+			resp.Header().Add("Content-Type", "application/json")
+			resp.WriteHeader(200)
+			resp.Write(bundle)
+		default:
+			resp.WriteHeader(404)
+			fmt.Fprintln(resp, "not found")
+		}
+	}))
+	defer httpServer.Close()
+	baseURL, err := url.Parse(httpServer.URL)
+	if err != nil {
+		t.Fatalf("httptest.NewTLSServer returned a server with an invalid URL")
+	}
+	retryHTTPClient := retryablehttp.NewClient()
+	retryHTTPClient.HTTPClient = httpServer.Client()
+	source := newHTTPMirrorSourceWithHTTPClient(baseURL, nil, retryHTTPClient)
+	source.sigstorePolicy = &SigstorePolicy{
+		PublicKeys: []crypto.PublicKey{signingKey.Public()},
+	}
+
+	provider := addrs.MustParseProviderSourceString("terraform.io/test/exists")
+	tosPlatform := Platform{OS: "tos", Arch: "m68k"}
+
+	t.Run("PackageMeta for a version with a Sigstore bundle", func(t *testing.T) {
+		meta, err := source.PackageMeta(context.Background(), provider, MustParseVersion("1.0.0"), tosPlatform)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		result, err := meta.Authentication.AuthenticatePackage(archive)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !result.Signed() {
+			t.Errorf("result is not signed")
+		}
+	})
+	t.Run("PackageMeta for a version without a Sigstore bundle", func(t *testing.T) {
+		_, err := source.PackageMeta(context.Background(), provider, MustParseVersion("1.0.1"), tosPlatform)
+		if err == nil {
+			t.Fatalf("unexpected success")
+		}
+		if got, want := err.Error(), "has no Sigstore bundle"; !strings.Contains(got, want) {
+			t.Errorf("wrong error\ngot:  %s\nwant: error containing %q", got, want)
+		}
+	})
+}
+
 func testHTTPMirrorSourceHandler(resp http.ResponseWriter, req *http.Request) {
 	if auth := req.Header.Get("authorization"); auth != "Bearer placeholder-token" {
 		resp.WriteHeader(401)
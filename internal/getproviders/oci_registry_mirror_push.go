@@ -0,0 +1,309 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getproviders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	ociDigest "github.com/opencontainers/go-digest"
+	ociSpecs "github.com/opencontainers/image-spec/specs-go"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	otelAttr "go.opentelemetry.io/otel/attribute"
+	otelTrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/ocipush"
+	"github.com/opentofu/opentofu/internal/tracing"
+	"github.com/opentofu/opentofu/internal/tracing/traceattrs"
+)
+
+// ociProviderSourceAnnotation is the annotation that
+// [PushOCIProviderMirrorVersion] sets on the index manifests it creates to
+// the source address of the provider, so that an operator can tell which
+// provider a repository contains.
+const ociProviderSourceAnnotation = "org.opentofu.provider.source"
+
+// ociSHA256SumsMediaType and ociSHA256SumsSignatureMediaType are the media
+// types of the layers that [PushOCIProviderMirrorVersion] uses for the
+// checksums document published by a provider's origin registry and for the
+// detached GPG signature of that document.
+const (
+	ociSHA256SumsMediaType          = "application/vnd.opentofu.provider.sha256sums"
+	ociSHA256SumsSignatureMediaType = "application/pgp-signature"
+)
+
+// OCIMirrorPackage is a provider package to push with
+// [PushOCIProviderMirrorVersion], along with the signing metadata that its
+// origin published for it.
+//
+// The caller is responsible for authenticating the package before pushing
+// it. The signing metadata is pushed as additional layers of the package's
+// image manifest so that the package can still be verified after it has
+// been copied to the mirror.
+type OCIMirrorPackage struct {
+	TargetPlatform Platform
+	Archive        PackageLocalArchive
+
+	// SHA256Sums is the checksums document that the origin registry
+	// published for the package, and SHA256SumsSignature is the detached
+	// GPG signature of that document by the provider's developer. Either
+	// both or neither must be set.
+	SHA256Sums          []byte
+	SHA256SumsSignature []byte
+
+	// SigstoreBundle is a Sigstore bundle containing a signature of the
+	// package, as written by "cosign sign-blob --bundle".
+	// [OCIRegistryMirrorSource] requires it when it's configured with a
+	// Sigstore policy.
+	SigstoreBundle []byte
+}
+
+// NewOCIMirrorPackage returns an [OCIMirrorPackage] for the given archive,
+// which must have been obtained from the location in the given metadata,
+// including any signing metadata that the metadata's authentication relies
+// on.
+func NewOCIMirrorPackage(meta PackageMeta, archive PackageLocalArchive) OCIMirrorPackage {
+	pkg := OCIMirrorPackage{
+		TargetPlatform: meta.TargetPlatform,
+		Archive:        archive,
+	}
+	var visit func(auth PackageAuthentication)
+	visit = func(auth PackageAuthentication) {
+		switch auth := auth.(type) {
+		case packageAuthenticationAll:
+			for _, check := range auth {
+				visit(check)
+			}
+		case signatureAuthentication:
+			pkg.SHA256Sums = auth.Document
+			pkg.SHA256SumsSignature = auth.Signature
+		case sigstoreBundleAuthentication:
+			pkg.SigstoreBundle = auth.Bundle
+		}
+	}
+	visit(meta.Authentication)
+	return pkg
+}
+
+// PushOCIProviderMirrorVersion pushes the given packages for a particular
+// version of a provider to an OCI repository, using the artifact layout
+// expected by [OCIRegistryMirrorSource].
+//
+// The packages are pushed as blobs that are each referred to by a
+// per-platform image manifest, and those manifests are then referred to by a
+// single index manifest that is tagged with the version number, with any "+"
+// replaced by "_" as required by the OCI tag syntax. If the repository
+// already has a tag for the version then it is replaced, and so the packages
+// given here must include all of the platforms to be made available for the
+// version.
+//
+// Each per-platform image manifest also has a layer for each item of signing
+// metadata included in the corresponding package.
+//
+// Blobs that are already present in the repository are not uploaded again.
+func PushOCIProviderMirrorVersion(ctx context.Context, store ocipush.RepositoryStore, provider addrs.Provider, version Version, packages []OCIMirrorPackage) (ociv1.Descriptor, error) {
+	ctx, span := tracing.Tracer().Start(
+		ctx, "Push to oci_mirror",
+		otelTrace.WithAttributes(
+			otelAttr.String(traceattrs.ProviderAddress, provider.String()),
+			otelAttr.String(traceattrs.ProviderVersion, version.String()),
+		),
+	)
+	defer span.End()
+
+	if len(packages) == 0 {
+		err := fmt.Errorf("no packages to push for %s v%s", provider, version)
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, err
+	}
+
+	// The image manifests all share the same empty configuration blob, as
+	// recommended by the OCI image specification for artifacts that have
+	// no configuration.
+	if err := ocipush.PushContent(ctx, store, ociv1.DescriptorEmptyJSON, bytes.NewReader(ociv1.DescriptorEmptyJSON.Data)); err != nil {
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, fmt.Errorf("pushing empty configuration blob: %w", err)
+	}
+
+	manifestDescs := make([]ociv1.Descriptor, 0, len(packages))
+	for _, pkg := range packages {
+		desc, err := pushOCIProviderPackage(ctx, store, provider, version, pkg)
+		if err != nil {
+			tracing.SetSpanError(span, err)
+			return ociv1.Descriptor{}, fmt.Errorf("pushing package for %s: %w", pkg.TargetPlatform, err)
+		}
+		manifestDescs = append(manifestDescs, desc)
+	}
+
+	index := &ociv1.Index{
+		Versioned:    ociSpecs.Versioned{SchemaVersion: 2},
+		MediaType:    ociv1.MediaTypeImageIndex,
+		ArtifactType: ociIndexManifestArtifactType,
+		Manifests:    manifestDescs,
+		Annotations: map[string]string{
+			ociProviderSourceAnnotation: provider.String(),
+			ociv1.AnnotationVersion:     version.String(),
+		},
+	}
+	indexDesc, err := pushOCIManifest(ctx, store, index.MediaType, index.ArtifactType, index)
+	if err != nil {
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, fmt.Errorf("pushing index manifest: %w", err)
+	}
+
+	tagName := strings.ReplaceAll(version.String(), "+", "_")
+	if err := store.Tag(ctx, indexDesc, tagName); err != nil {
+		tracing.SetSpanError(span, err)
+		return ociv1.Descriptor{}, fmt.Errorf("creating tag %q: %w", tagName, err)
+	}
+	return indexDesc, nil
+}
+
+// pushOCIProviderPackage pushes a single package and its image manifest,
+// returning a descriptor for the manifest suitable for inclusion in the
+// index manifest.
+func pushOCIProviderPackage(ctx context.Context, store ocipush.RepositoryStore, provider addrs.Provider, version Version, pkg OCIMirrorPackage) (ociv1.Descriptor, error) {
+	// Our digest is the same SHA256 checksum as used by the "zh:" hash scheme,
+	// so we can compute it in the same way.
+	hash, err := PackageHashLegacyZipSHA(pkg.Archive)
+	if err != nil {
+		return ociv1.Descriptor{}, err
+	}
+	info, err := os.Stat(string(pkg.Archive))
+	if err != nil {
+		return ociv1.Descriptor{}, err
+	}
+	blobDesc := ociv1.Descriptor{
+		MediaType: ociPackageMediaType,
+		Digest:    ociDigest.NewDigestFromEncoded(ociDigest.SHA256, hash.Value()),
+		Size:      info.Size(),
+		Annotations: map[string]string{
+			ociv1.AnnotationTitle: fmt.Sprintf("terraform-provider-%s_%s_%s_%s.zip", provider.Type, version, pkg.TargetPlatform.OS, pkg.TargetPlatform.Arch),
+		},
+	}
+	exists, err := store.Exists(ctx, blobDesc)
+	if err != nil {
+		return ociv1.Descriptor{}, err
+	}
+	if !exists {
+		f, err := os.Open(string(pkg.Archive))
+		if err != nil {
+			return ociv1.Descriptor{}, err
+		}
+		defer f.Close()
+		if err := store.Push(ctx, blobDesc, f); err != nil {
+			return ociv1.Descriptor{}, err
+		}
+	}
+
+	layers := []ociv1.Descriptor{blobDesc}
+	signingLayers, err := pushOCIProviderSigningMetadata(ctx, store, provider, version, pkg)
+	if err != nil {
+		return ociv1.Descriptor{}, err
+	}
+	layers = append(layers, signingLayers...)
+
+	manifest := &ociv1.Manifest{
+		Versioned:    ociSpecs.Versioned{SchemaVersion: 2},
+		MediaType:    ociv1.MediaTypeImageManifest,
+		ArtifactType: ociPackageManifestArtifactType,
+		Config:       ociv1.DescriptorEmptyJSON,
+		Layers:       layers,
+	}
+	desc, err := pushOCIManifest(ctx, store, manifest.MediaType, manifest.ArtifactType, manifest)
+	if err != nil {
+		return ociv1.Descriptor{}, err
+	}
+	desc.Platform = &ociv1.Platform{
+		OS:           pkg.TargetPlatform.OS,
+		Architecture: pkg.TargetPlatform.Arch,
+	}
+	return desc, nil
+}
+
+// pushOCIProviderSigningMetadata pushes the signing metadata included in the
+// given package, returning descriptors for the layers to add to its image
+// manifest.
+func pushOCIProviderSigningMetadata(ctx context.Context, store ocipush.RepositoryStore, provider addrs.Provider, version Version, pkg OCIMirrorPackage) ([]ociv1.Descriptor, error) {
+	var layers []ociv1.Descriptor
+
+	if (pkg.SHA256Sums == nil) != (pkg.SHA256SumsSignature == nil) {
+		// Should not happen because NewOCIMirrorPackage sets both together.
+		return nil, fmt.Errorf("checksums document and its signature must be pushed together")
+	}
+	if pkg.SHA256Sums != nil {
+		// These are named in the same way as in the origin registry's
+		// releases, so that they can be verified using the usual tools.
+		sumsFilename := fmt.Sprintf("terraform-provider-%s_%s_SHA256SUMS", provider.Type, version)
+		for _, item := range []struct {
+			mediaType, filename string
+			content             []byte
+		}{
+			{ociSHA256SumsMediaType, sumsFilename, pkg.SHA256Sums},
+			{ociSHA256SumsSignatureMediaType, sumsFilename + ".sig", pkg.SHA256SumsSignature},
+		} {
+			desc := ociv1.Descriptor{
+				MediaType: item.mediaType,
+				Digest:    ociDigest.FromBytes(item.content),
+				Size:      int64(len(item.content)),
+				Annotations: map[string]string{
+					ociv1.AnnotationTitle: item.filename,
+				},
+			}
+			if err := ocipush.PushContent(ctx, store, desc, bytes.NewReader(item.content)); err != nil {
+				return nil, fmt.Errorf("pushing %s: %w", item.filename, err)
+			}
+			layers = append(layers, desc)
+		}
+	}
+
+	if pkg.SigstoreBundle != nil {
+		// The media type of a Sigstore bundle layer includes the version of
+		// the bundle format, which the bundle itself declares.
+		var bundle struct {
+			MediaType string `json:"mediaType"`
+		}
+		if err := json.Unmarshal(pkg.SigstoreBundle, &bundle); err != nil {
+			return nil, fmt.Errorf("invalid Sigstore bundle: %w", err)
+		}
+		if !strings.HasPrefix(bundle.MediaType, sigstoreBundleMediaTypePrefix) {
+			return nil, fmt.Errorf("invalid Sigstore bundle: unsupported media type %q", bundle.MediaType)
+		}
+		desc := ociv1.Descriptor{
+			MediaType: bundle.MediaType,
+			Digest:    ociDigest.FromBytes(pkg.SigstoreBundle),
+			Size:      int64(len(pkg.SigstoreBundle)),
+		}
+		if err := ocipush.PushContent(ctx, store, desc, bytes.NewReader(pkg.SigstoreBundle)); err != nil {
+			return nil, fmt.Errorf("pushing Sigstore bundle: %w", err)
+		}
+		layers = append(layers, desc)
+	}
+
+	return layers, nil
+}
+
+func pushOCIManifest(ctx context.Context, store ocipush.RepositoryStore, mediaType, artifactType string, manifest any) (ociv1.Descriptor, error) {
+	src, err := json.Marshal(manifest)
+	if err != nil {
+		// Should not happen because the manifest types are all
+		// JSON-serializable.
+		return ociv1.Descriptor{}, err
+	}
+	if len(src) > ociImageManifestSizeLimitMiB*1024*1024 {
+		return ociv1.Descriptor{}, fmt.Errorf("manifest size exceeds OpenTofu's size limit of %d MiB", ociImageManifestSizeLimitMiB)
+	}
+	desc := ociv1.Descriptor{
+		MediaType:    mediaType,
+		ArtifactType: artifactType,
+		Digest:       ociDigest.FromBytes(src),
+		Size:         int64(len(src)),
+	}
+	return desc, ocipush.PushContent(ctx, store, desc, bytes.NewReader(src))
+}
@@ -0,0 +1,204 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getproviders
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	orasOCI "oras.land/oras-go/v2/content/oci"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+func TestPushOCIProviderMirrorVersion(t *testing.T) {
+	// As in TestOCIRegistryMirrorSource, we use a local-filesystem-based
+	// repository as a stand-in for a remote registry.
+	store, err := orasOCI.NewWithContext(t.Context(), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := addrs.MustParseProviderSourceString("example.com/foo/bar")
+	version := MustParseVersion("1.0.0+foo.1")
+	platforms := []Platform{
+		{OS: "amigaos", Arch: "m68k"},
+		{OS: "tos", Arch: "m68k"},
+	}
+	signingKey := generateTestSigstoreKey(t)
+	sha256Sums := []byte("placeholder checksums document")
+	sha256SumsSignature := []byte("placeholder signature")
+	packages := make([]OCIMirrorPackage, 0, len(platforms))
+	wantHashes := make(map[Platform]Hash, len(platforms))
+	for _, platform := range platforms {
+		archivePath := filepath.Join(t.TempDir(), "package.zip")
+		content := makePlaceholderProviderPackageZip(t, fmt.Sprintf("placeholder executable for %s", platform))
+		if err := os.WriteFile(archivePath, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := PackageHashLegacyZipSHA(PackageLocalArchive(archivePath))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantHashes[platform] = hash
+		packages = append(packages, OCIMirrorPackage{
+			TargetPlatform:      platform,
+			Archive:             PackageLocalArchive(archivePath),
+			SHA256Sums:          sha256Sums,
+			SHA256SumsSignature: sha256SumsSignature,
+			SigstoreBundle:      testSigstoreKeyBundle(t, signingKey, content),
+		})
+	}
+
+	// Pushing the same packages twice must succeed, reusing the blobs
+	// that are already present.
+	for range 2 {
+		if _, err := PushOCIProviderMirrorVersion(t.Context(), store, provider, version, packages); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The pushed content must then be usable by the OCI mirror source,
+	// including when it requires the packages to be signed.
+	source := NewOCIRegistryMirrorSource(
+		func(addr addrs.Provider) (string, string, error) {
+			return "example.net", "foo/bar", nil
+		},
+		func(ctx context.Context, registryDomain, repositoryName string) (OCIRepositoryStore, error) {
+			return store, nil
+		},
+		&SigstorePolicy{
+			PublicKeys: []crypto.PublicKey{signingKey.Public()},
+		},
+	)
+	gotVersions, _, err := source.AvailableVersions(t.Context(), provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(VersionList{version}, gotVersions); diff != "" {
+		t.Errorf("wrong available versions\n%s", diff)
+	}
+	for _, platform := range platforms {
+		meta, err := source.PackageMeta(t.Context(), provider, version, platform)
+		if err != nil {
+			t.Fatalf("failed to get metadata for %s: %s", platform, err)
+		}
+		result, err := meta.Location.InstallProviderPackage(t.Context(), meta, t.TempDir(), nil)
+		if err != nil {
+			t.Fatalf("failed to install package for %s: %s", platform, err)
+		}
+		if !result.Signed() {
+			t.Errorf("package for %s is not signed", platform)
+		}
+		found := false
+		for hash := range result.HashesWithDisposition(func(*HashDisposition) bool { return true }) {
+			found = found || hash == wantHashes[platform]
+		}
+		if !found {
+			t.Errorf("package for %s doesn't have the expected hash %s", platform, wantHashes[platform])
+		}
+	}
+
+	// The index manifest records which provider it belongs to.
+	indexDesc, err := store.Resolve(t.Context(), "1.0.0_foo.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index ociv1.Index
+	readOCIJSON(t, store, indexDesc, &index)
+	if got, want := index.Annotations[ociProviderSourceAnnotation], provider.String(); got != want {
+		t.Errorf("wrong provider source annotation %q; want %q", got, want)
+	}
+	if got, want := len(index.Manifests), len(platforms); got != want {
+		t.Fatalf("wrong number of manifests %d; want %d", got, want)
+	}
+	var manifest ociv1.Manifest
+	readOCIJSON(t, store, index.Manifests[0], &manifest)
+	var gotLayers []string
+	for _, layer := range manifest.Layers {
+		gotLayers = append(gotLayers, layer.MediaType+" "+layer.Annotations[ociv1.AnnotationTitle])
+	}
+	wantLayers := []string{
+		"archive/zip terraform-provider-bar_1.0.0+foo.1_amigaos_m68k.zip",
+		"application/vnd.opentofu.provider.sha256sums terraform-provider-bar_1.0.0+foo.1_SHA256SUMS",
+		"application/pgp-signature terraform-provider-bar_1.0.0+foo.1_SHA256SUMS.sig",
+		"application/vnd.dev.sigstore.bundle.v0.3+json ",
+	}
+	if diff := cmp.Diff(wantLayers, gotLayers); diff != "" {
+		t.Errorf("wrong layers\n%s", diff)
+	}
+	sigLayer := manifest.Layers[2]
+	r, err := store.Fetch(t.Context(), sigLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	gotSignature, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(gotSignature, sha256SumsSignature) {
+		t.Errorf("wrong signature content %q; want %q", gotSignature, sha256SumsSignature)
+	}
+}
+
+func TestNewOCIMirrorPackage(t *testing.T) {
+	platform := Platform{OS: "tos", Arch: "m68k"}
+	archive := PackageLocalArchive("package.zip")
+	meta := PackageMeta{
+		TargetPlatform: platform,
+		Authentication: PackageAuthenticationAll(
+			NewArchiveChecksumAuthentication(platform, [32]byte{}),
+			NewSignatureAuthentication(PackageMeta{}, []byte("sums"), []byte("sig"), nil, addrs.Provider{}),
+			NewSigstoreBundleAuthentication([]byte("bundle"), nil),
+		),
+	}
+
+	got := NewOCIMirrorPackage(meta, archive)
+	want := OCIMirrorPackage{
+		TargetPlatform:      platform,
+		Archive:             archive,
+		SHA256Sums:          []byte("sums"),
+		SHA256SumsSignature: []byte("sig"),
+		SigstoreBundle:      []byte("bundle"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	// Packages without any signing metadata are still acceptable.
+	got = NewOCIMirrorPackage(PackageMeta{TargetPlatform: platform}, archive)
+	want = OCIMirrorPackage{
+		TargetPlatform: platform,
+		Archive:        archive,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result without signing metadata\n%s", diff)
+	}
+}
+
+func readOCIJSON(t *testing.T, store *orasOCI.Store, desc ociv1.Descriptor, into any) {
+	t.Helper()
+	r, err := store.Fetch(t.Context(), desc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	src, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(src, into); err != nil {
+		t.Fatal(err)
+	}
+}
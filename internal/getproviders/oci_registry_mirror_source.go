@@ -134,6 +134,11 @@ type OCIRegistryMirrorSource struct {
 	// OCI registry".
 	getOCIRepositoryStore func(ctx context.Context, registryDomain, repositoryName string) (OCIRepositoryStore, error)
 
+	// sigstorePolicy, if non-nil, requires each package's image manifest to
+	// also include a layer containing a Sigstore bundle that's acceptable
+	// to the policy.
+	sigstorePolicy *SigstorePolicy
+
 	// We keep an internal cache of the most-recently-instantiated
 	// repository store object because in the common case there will
 	// be call to AvailableVersions immediately followed by
@@ -148,13 +153,22 @@ type OCIRegistryMirrorSource struct {
 
 var _ Source = (*OCIRegistryMirrorSource)(nil)
 
+// NewOCIRegistryMirrorSource constructs and returns a new OCI mirror source
+// using the given functions to map provider source addresses to OCI
+// repositories and to obtain a store for each repository.
+//
+// If sigstorePolicy is non-nil then each package must be signed in a way
+// that's acceptable to the policy, using a Sigstore bundle included as an
+// additional layer in the package's image manifest.
 func NewOCIRegistryMirrorSource(
 	resolveRepositoryAddr func(addr addrs.Provider) (registryDomain, repositoryName string, err error),
 	getRepositoryStore func(ctx context.Context, registryDomain, repositoryName string) (OCIRepositoryStore, error),
+	sigstorePolicy *SigstorePolicy,
 ) *OCIRegistryMirrorSource {
 	return &OCIRegistryMirrorSource{
 		resolveOCIRepositoryAddr: resolveRepositoryAddr,
 		getOCIRepositoryStore:    getRepositoryStore,
+		sigstorePolicy:           sigstorePolicy,
 	}
 }
 
@@ -345,6 +359,16 @@ func (o *OCIRegistryMirrorSource) PackageMeta(ctx context.Context, provider addr
 		return PackageMeta{}, err
 	}
 	authentication := NewPackageHashAuthentication(target, []Hash{expectedHash})
+	if o.sigstorePolicy != nil {
+		bundle, err := fetchOCISigstoreBundle(ctx, imageManifest.Layers, store)
+		if err != nil {
+			return PackageMeta{}, err
+		}
+		authentication = PackageAuthenticationAll(
+			authentication,
+			NewSigstoreBundleAuthentication(bundle, o.sigstorePolicy),
+		)
+	}
 
 	// If we got through all of the above then we seem to have found a suitable
 	// package to install, but our job is only to describe its metadata.
@@ -747,6 +771,39 @@ func selectOCILayerBlob(descs []ociv1.Descriptor) (ociv1.Descriptor, error) {
 	return selected, nil
 }
 
+// fetchOCISigstoreBundle fetches the Sigstore bundle from the layer of an
+// image manifest that has a Sigstore bundle media type, such as
+// "application/vnd.dev.sigstore.bundle.v0.3+json".
+func fetchOCISigstoreBundle(ctx context.Context, layers []ociv1.Descriptor, store OCIRepositoryStore) ([]byte, error) {
+	var selected *ociv1.Descriptor
+	for i, desc := range layers {
+		if !strings.HasPrefix(desc.MediaType, sigstoreBundleMediaTypePrefix) {
+			continue
+		}
+		if selected != nil {
+			return nil, fmt.Errorf("ambiguous manifest declares multiple Sigstore bundles")
+		}
+		selected = &layers[i]
+	}
+	if selected == nil {
+		return nil, fmt.Errorf("image manifest contains no Sigstore bundle, which is required by the configured Sigstore policy")
+	}
+
+	readCloser, err := store.Fetch(ctx, *selected)
+	if err != nil {
+		return nil, fmt.Errorf("fetching Sigstore bundle: %w", err)
+	}
+	defer readCloser.Close()
+	src, err := readSigstoreBundle(io.LimitReader(readCloser, selected.Size))
+	if err != nil {
+		return nil, fmt.Errorf("reading Sigstore bundle: %w", err)
+	}
+	if gotDigest := selected.Digest.Algorithm().FromBytes(src); gotDigest != selected.Digest {
+		return nil, fmt.Errorf("content of Sigstore bundle does not match digest %s", selected.Digest)
+	}
+	return src, nil
+}
+
 func fetchOCIManifestBlob(ctx context.Context, desc ociv1.Descriptor, store OCIRepositoryStore) ([]byte, error) {
 	// We impose a size limit on the manifest just to avoid an abusive remote registry
 	// occupuing unbounded memory when we read the manifest content into memory below.
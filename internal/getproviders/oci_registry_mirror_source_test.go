@@ -8,6 +8,7 @@ package getproviders
 import (
 	"bytes"
 	"context"
+	"crypto"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -515,6 +516,84 @@ func makeFakeOCIRepositoryWithNonProviderContent(t *testing.T) (OCIRepositorySto
 	return store, lookups
 }
 
+func TestOCIRegistryMirrorSource_sigstore(t *testing.T) {
+	store, err := orasOCI.NewWithContext(t.Context(), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingKey := generateTestSigstoreKey(t)
+	platform := Platform{OS: "tos", Arch: "m86k"}
+	packageBytes := makePlaceholderProviderPackageZip(t, "placeholder executable")
+	packageDesc := pushOCIBlob(t, "archive/zip", "", packageBytes, store)
+	bundleDesc := pushOCIBlob(t, "application/vnd.dev.sigstore.bundle.v0.3+json", "", testSigstoreKeyBundle(t, signingKey, packageBytes), store)
+
+	// Version 1.0.0 includes a Sigstore bundle for the package while
+	// version 1.0.1 does not.
+	for tagName, layers := range map[string][]ociv1.Descriptor{
+		"1.0.0": {packageDesc, bundleDesc},
+		"1.0.1": {packageDesc},
+	} {
+		manifestDesc := pushOCIImageManifest(t, &ociv1.Manifest{
+			Versioned:    ociSpecs.Versioned{SchemaVersion: 2},
+			MediaType:    ociv1.MediaTypeImageManifest,
+			ArtifactType: "application/vnd.opentofu.provider-target",
+			Config:       ociv1.DescriptorEmptyJSON,
+			Layers:       layers,
+		}, store)
+		manifestDesc.Platform = &ociv1.Platform{
+			Architecture: platform.Arch,
+			OS:           platform.OS,
+		}
+		indexDesc := pushOCIIndexManifest(t, &ociv1.Index{
+			Versioned:    ociSpecs.Versioned{SchemaVersion: 2},
+			MediaType:    ociv1.MediaTypeImageIndex,
+			ArtifactType: "application/vnd.opentofu.provider",
+			Manifests:    []ociv1.Descriptor{manifestDesc},
+		}, store)
+		createOCITag(t, tagName, indexDesc, store)
+	}
+
+	source := NewOCIRegistryMirrorSource(
+		func(addr addrs.Provider) (registryDomain string, repositoryName string, err error) {
+			return "example.com", fmt.Sprintf("%s_%s", addr.Namespace, addr.Type), nil
+		},
+		func(ctx context.Context, registryDomain, repositoryName string) (OCIRepositoryStore, error) {
+			return store, nil
+		},
+		&SigstorePolicy{
+			PublicKeys: []crypto.PublicKey{signingKey.Public()},
+		},
+	)
+	provider := addrs.MustParseProviderSourceString("example.com/foo/bar")
+
+	t.Run("PackageMeta for a version with a Sigstore bundle", func(t *testing.T) {
+		meta, err := source.PackageMeta(t.Context(), provider, MustParseVersion("1.0.0"), platform)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		archivePath := filepath.Join(t.TempDir(), "package.zip")
+		if err := os.WriteFile(archivePath, packageBytes, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		result, err := meta.Authentication.AuthenticatePackage(PackageLocalArchive(archivePath))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !result.Signed() {
+			t.Errorf("result is not signed")
+		}
+	})
+	t.Run("PackageMeta for a version without a Sigstore bundle", func(t *testing.T) {
+		_, err := source.PackageMeta(t.Context(), provider, MustParseVersion("1.0.1"), platform)
+		if err == nil {
+			t.Fatalf("unexpected success")
+		}
+		if got, want := err.Error(), "image manifest contains no Sigstore bundle"; !strings.Contains(got, want) {
+			t.Errorf("wrong error\ngot:  %s\nwant: error containing %q", got, want)
+		}
+	})
+}
+
 func TestErrRepresentsOCIProviderNotFound(t *testing.T) {
 	// These are tests just for the internal errRepresentsOCIProviderNotFound
 	// helper, with some of the test inputs based directly on how ORAS-Go
@@ -67,7 +67,7 @@ func (t *PackageAuthenticationResult) summaryResult() packageAuthenticationResul
 	registryReportCount := 0
 	locallyVerifiedCount := 0
 	for _, disp := range t.hashes {
-		if disp.SignedByAnyGPGKeys() {
+		if disp.SignedByAnyGPGKeys() || disp.SignedByAnySigstoreIdentities() {
 			signedCount++
 		}
 		if disp.ReportedByRegistry {
@@ -133,12 +133,21 @@ func (t *PackageAuthenticationResult) GPGKeyIDsString() string {
 	return t.hashes.AllGPGSigningKeysString()
 }
 
+// SigstoreIdentitiesString returns a UI-oriented string representation of
+// all of the Sigstore identities or public keys that signed the package.
+func (t *PackageAuthenticationResult) SigstoreIdentitiesString() string {
+	if t == nil {
+		return ""
+	}
+	return t.hashes.AllSigstoreIdentitiesString()
+}
+
 // Signed returns whether the package was authenticated as signed by anyone.
 func (t *PackageAuthenticationResult) Signed() bool {
 	if t == nil {
 		return false
 	}
-	return t.hashes.HasAnySignedByGPGKeys()
+	return t.hashes.HasAnySignedByGPGKeys() || t.hashes.HasAnySignedBySigstoreIdentities()
 }
 
 // SigningSkipped returns whether the package was authenticated but the key
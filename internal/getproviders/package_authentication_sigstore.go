@@ -0,0 +1,357 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getproviders
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	"github.com/sigstore/sigstore/pkg/signature"
+
+	"github.com/opentofu/opentofu/internal/collections"
+)
+
+// sigstoreBundleMediaTypePrefix is the prefix shared by the media types of
+// all versions of the Sigstore bundle format, such as
+// "application/vnd.dev.sigstore.bundle.v0.3+json".
+const sigstoreBundleMediaTypePrefix = "application/vnd.dev.sigstore.bundle"
+
+// sigstoreBundleFilenameSuffix is the suffix added to the URL of a package
+// in a network mirror to find its Sigstore bundle, following the naming
+// convention for bundles created by "cosign sign-blob --bundle".
+const sigstoreBundleFilenameSuffix = ".sigstore.json"
+
+// sigstoreBundleURL returns the URL at which the Sigstore bundle for the
+// package at the given URL is expected.
+func sigstoreBundleURL(packageURL *url.URL) *url.URL {
+	bundleURL := *packageURL
+	bundleURL.Path += sigstoreBundleFilenameSuffix
+	bundleURL.RawPath = ""
+	return &bundleURL
+}
+
+// FetchSigstoreBundle retrieves the Sigstore bundle that the provider's
+// developer published alongside the package at the given URL, following the
+// naming convention for bundles created by "cosign sign-blob --bundle".
+//
+// Most providers don't publish Sigstore bundles, so this returns nil
+// without an error if there is no bundle.
+func FetchSigstoreBundle(ctx context.Context, client *http.Client, packageURL *url.URL) ([]byte, error) {
+	bundleURL := sigstoreBundleURL(packageURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bundleURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve Sigstore bundle: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return readSigstoreBundle(resp.Body)
+	case http.StatusNotFound, http.StatusForbidden:
+		// Some release hosts respond with 403 Forbidden for objects that
+		// don't exist.
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to retrieve Sigstore bundle: server returned unsuccessful status %d", resp.StatusCode)
+	}
+}
+
+// SigstorePolicy describes which Sigstore signatures are acceptable for
+// the provider packages from a particular source.
+//
+// A package is accepted if its bundle was signed either by one of the
+// PublicKeys, or by a short-lived certificate issued by a certificate
+// authority in TrustedRoot for one of the Identities.
+type SigstorePolicy struct {
+	// PublicKeys are the keys that may sign packages directly, as with
+	// "cosign sign-blob --key".
+	PublicKeys []crypto.PublicKey
+
+	// Identities are the certificate identities that may sign packages
+	// using keyless signing.
+	Identities []SigstoreIdentity
+
+	// TrustedRoot describes the certificate authorities, transparency logs
+	// and timestamp authorities that are trusted. It is required for keyless
+	// signing. If it's set then a package signed with one of the PublicKeys
+	// must also have been recorded in one of its transparency logs.
+	TrustedRoot *SigstoreTrustedRoot
+}
+
+// SigstoreIdentity is an identity that may sign packages using Sigstore's
+// keyless signing, in which case it's recorded in the signing certificate.
+type SigstoreIdentity struct {
+	// Issuer is the exact URL of the OIDC issuer that authenticated the
+	// identity, such as "https://token.actions.githubusercontent.com".
+	Issuer string
+
+	// Subject is the exact subject alternative name of the certificate,
+	// which is an email address or a URI depending on the issuer.
+	Subject string
+
+	// SubjectRegexp is used instead of Subject if it's set, and matches the
+	// subject alternative name of the certificate.
+	SubjectRegexp *regexp.Regexp
+}
+
+// SigstoreTrustedRoot is the set of certificate authorities, certificate
+// transparency logs, transparency logs and timestamp authorities used to
+// verify Sigstore bundles, as described in a Sigstore "trusted root" file.
+type SigstoreTrustedRoot struct {
+	material root.TrustedMaterial
+}
+
+// ParseSigstoreTrustedRoot parses the JSON representation of a Sigstore
+// trusted root, as used by the "--trusted-root" option of cosign.
+func ParseSigstoreTrustedRoot(src []byte) (*SigstoreTrustedRoot, error) {
+	trustedRoot, err := root.NewTrustedRootFromJSON(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted root: %w", err)
+	}
+	return &SigstoreTrustedRoot{material: trustedRoot}, nil
+}
+
+// ParseSigstorePublicKey parses a PEM-encoded public key, as written by
+// "cosign generate-key-pair".
+func ParseSigstorePublicKey(src []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(src)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM-encoded public key found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+type sigstoreBundleAuthentication struct {
+	Bundle []byte
+	Policy *SigstorePolicy
+}
+
+// NewSigstoreBundleAuthentication returns a PackageAuthentication
+// implementation that verifies that a package archive was signed in a way
+// that's acceptable to the given policy, using the given Sigstore bundle
+// as written by "cosign sign-blob --bundle".
+//
+// Only bundles containing a signature over the package archive itself are
+// supported, and not bundles containing DSSE attestations. For keyless
+// signing the signing certificate must include a signed certificate
+// timestamp from a trusted certificate transparency log, and the bundle must
+// include a transparency log entry with an inclusion proof, along with an
+// inclusion promise or a signed timestamp that provides a trusted time at
+// which the short-lived signing certificate must have been valid.
+func NewSigstoreBundleAuthentication(bundle []byte, policy *SigstorePolicy) PackageAuthentication {
+	return sigstoreBundleAuthentication{
+		Bundle: bundle,
+		Policy: policy,
+	}
+}
+
+func (a sigstoreBundleAuthentication) AuthenticatePackage(localLocation PackageLocation) (*PackageAuthenticationResult, error) {
+	archiveLocation, ok := localLocation.(PackageLocalArchive)
+	if !ok {
+		// A source should only request Sigstore authentication for a
+		// package archive.
+		return nil, fmt.Errorf("cannot verify a Sigstore signature for an unpacked provider package")
+	}
+	content, err := os.ReadFile(string(archiveLocation))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider package: %w", err)
+	}
+
+	signer, err := verifySigstoreBundle(a.Bundle, content, a.Policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify the provider package's Sigstore signature: %w", err)
+	}
+	log.Printf("[DEBUG] Provider package %s signed by %s", localLocation, signer)
+
+	hash := HashLegacyZipSHAFromSHA(sha256.Sum256(content))
+	return &PackageAuthenticationResult{
+		hashes: HashDispositions{
+			hash: &HashDisposition{
+				VerifiedLocally:            true,
+				SignedBySigstoreIdentities: collections.NewSet(signer),
+			},
+		},
+	}, nil
+}
+
+// verifySigstoreBundle verifies that the given bundle is a signature of the
+// given content that's acceptable to the given policy, returning a
+// description of the identity or key that signed it.
+func verifySigstoreBundle(src []byte, content []byte, policy *SigstorePolicy) (string, error) {
+	var b bundle.ProtobufBundle
+	if err := b.UnmarshalJSON(src); err != nil {
+		return "", fmt.Errorf("invalid Sigstore bundle: %w", err)
+	}
+	if b.GetMessageSignature() == nil {
+		// sigstore-go can verify DSSE envelopes, but then the package would
+		// only be one of the subjects of an attestation, which isn't what
+		// "cosign sign-blob" creates.
+		return "", fmt.Errorf("bundles containing DSSE envelopes are not supported")
+	}
+	verificationContent, err := b.VerificationContent()
+	if err != nil {
+		return "", fmt.Errorf("invalid Sigstore bundle: %w", err)
+	}
+
+	if _, ok := verificationContent.HasCertificate(); !ok {
+		// The bundle was signed with a key that is not included in the bundle,
+		// so we try each of the trusted keys in turn.
+		return verifySigstoreKeyBundle(&b, content, policy)
+	}
+
+	// Otherwise the bundle was signed using a short-lived certificate, which
+	// must have been logged in a certificate transparency log, and must have
+	// been valid at the time recorded in a transparency log entry.
+	if len(policy.Identities) == 0 || policy.TrustedRoot == nil {
+		return "", fmt.Errorf("bundle is signed with a certificate, but no certificate identities are trusted")
+	}
+	verifier, err := verify.NewSignedEntityVerifier(
+		policy.TrustedRoot.material,
+		verify.WithSignedCertificateTimestamps(1),
+		verify.WithTransparencyLog(1),
+		verify.WithObserverTimestamps(1),
+	)
+	if err != nil {
+		return "", err
+	}
+	opts := []verify.PolicyOption{}
+	for _, identity := range policy.Identities {
+		subject, subjectRegexp := identity.Subject, ""
+		if identity.SubjectRegexp != nil {
+			subject, subjectRegexp = "", identity.SubjectRegexp.String()
+		}
+		certID, err := verify.NewShortCertificateIdentity(identity.Issuer, subject, "", subjectRegexp)
+		if err != nil {
+			return "", fmt.Errorf("invalid certificate identity: %w", err)
+		}
+		opts = append(opts, verify.WithCertificateIdentity(certID))
+	}
+	result, err := verifier.Verify(&b, verify.NewPolicy(verify.WithArtifact(bytes.NewReader(content)), opts...))
+	if err != nil {
+		return "", err
+	}
+	cert := result.Signature.Certificate
+	return fmt.Sprintf("%s (%s)", cert.SubjectAlternativeName.Value, cert.Issuer), nil
+}
+
+// verifySigstoreKeyBundle verifies a bundle that was signed with a key
+// rather than a certificate, returning a description of the key.
+func verifySigstoreKeyBundle(b *bundle.ProtobufBundle, content []byte, policy *SigstorePolicy) (string, error) {
+	for _, key := range policy.PublicKeys {
+		material, err := sigstoreKeyMaterial(key)
+		if err != nil {
+			return "", err
+		}
+		// A key has no validity period, so the signature is checked as of
+		// the current time rather than requiring an observer timestamp.
+		verifier, err := verify.NewSignedEntityVerifier(material, verify.WithoutAnyObserverTimestampsInsecure())
+		if err != nil {
+			return "", err
+		}
+		if _, err := verifier.Verify(b, verify.NewPolicy(verify.WithArtifact(bytes.NewReader(content)), verify.WithoutIdentitiesUnsafe())); err != nil {
+			log.Printf("[TRACE] Sigstore bundle not signed by %s: %s", sigstoreKeyDescription(key), err)
+			continue
+		}
+
+		if policy.TrustedRoot != nil {
+			// The signature must also have been recorded in one of the
+			// trusted transparency logs.
+			verifier, err := verify.NewSignedEntityVerifier(
+				root.TrustedMaterialCollection{policy.TrustedRoot.material, material},
+				verify.WithTransparencyLog(1),
+				verify.WithObserverTimestamps(1),
+			)
+			if err != nil {
+				return "", err
+			}
+			if _, err := verifier.Verify(b, verify.NewPolicy(verify.WithArtifact(bytes.NewReader(content)), verify.WithoutIdentitiesUnsafe())); err != nil {
+				return "", err
+			}
+		}
+		return sigstoreKeyDescription(key), nil
+	}
+	return "", fmt.Errorf("bundle is not signed by any of the trusted public keys")
+}
+
+// sigstoreKeyMaterial returns the trusted material for verifying a bundle
+// that was signed with the given key, which applies regardless of the key
+// hint in the bundle.
+func sigstoreKeyMaterial(key crypto.PublicKey) (root.TrustedMaterial, error) {
+	// Like cosign, ECDSA signatures use the hash matching the curve size.
+	hashFunc := crypto.SHA256
+	if key, ok := key.(*ecdsa.PublicKey); ok {
+		switch key.Curve.Params().BitSize {
+		case 384:
+			hashFunc = crypto.SHA384
+		case 521:
+			hashFunc = crypto.SHA512
+		}
+	}
+	sigVerifier, err := signature.LoadVerifier(key, hashFunc)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported public key: %w", err)
+	}
+	expiringKey := root.NewExpiringKey(sigVerifier, time.Time{}, time.Time{})
+	return root.NewTrustedPublicKeyMaterial(func(string) (root.TimeConstrainedVerifier, error) {
+		return expiringKey, nil
+	}), nil
+}
+
+// sigstoreKeyDescription returns a UI-oriented description of a public key,
+// using the first bytes of the SHA256 checksum of its PKIX encoding.
+func sigstoreKeyDescription(key crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		// Should not happen, because we only support key types that can be
+		// marshalled.
+		return "unknown public key"
+	}
+	sum := sha256.Sum256(der)
+	return "public key " + hex.EncodeToString(sum[:8])
+}
+
+// readSigstoreBundle reads a Sigstore bundle from the given reader, limiting
+// its size to avoid unbounded memory use.
+func readSigstoreBundle(r io.Reader) ([]byte, error) {
+	const limit = 4 * 1024 * 1024
+	src, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(src) > limit {
+		return nil, fmt.Errorf("bundle is larger than the limit of %d bytes", limit)
+	}
+	return src, nil
+}
@@ -0,0 +1,823 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package getproviders
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/tls"
+	ctx509 "github.com/google/certificate-transparency-go/x509"
+	ctx509util "github.com/google/certificate-transparency-go/x509util"
+	rekorutil "github.com/sigstore/rekor/pkg/util"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/transparency-dev/merkle/rfc6962"
+)
+
+func TestSigstoreBundleAuthentication_publicKey(t *testing.T) {
+	content := []byte("provider package content")
+	archive := writeTestSigstorePackage(t, content)
+	signingKey := generateTestSigstoreKey(t)
+	bundle := testSigstoreKeyBundle(t, signingKey, content)
+
+	t.Run("trusted key", func(t *testing.T) {
+		otherKey := generateTestSigstoreKey(t)
+		policy := &SigstorePolicy{
+			PublicKeys: []crypto.PublicKey{otherKey.Public(), signingKey.Public()},
+		}
+		result, err := NewSigstoreBundleAuthentication(bundle, policy).AuthenticatePackage(archive)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !result.Signed() {
+			t.Errorf("result is not signed")
+		}
+		if got, want := result.SigstoreIdentitiesString(), sigstoreKeyDescription(signingKey.Public()); got != want {
+			t.Errorf("wrong signer\ngot:  %s\nwant: %s", got, want)
+		}
+		sum := sha256.Sum256(content)
+		wantHash := HashLegacyZipSHAFromSHA(sum)
+		var gotHashes []Hash
+		for hash := range result.HashesWithDisposition(func(hd *HashDisposition) bool { return hd.VerifiedLocally }) {
+			gotHashes = append(gotHashes, hash)
+		}
+		if len(gotHashes) != 1 || gotHashes[0] != wantHash {
+			t.Errorf("wrong hashes\ngot:  %s\nwant: [%s]", gotHashes, wantHash)
+		}
+	})
+	t.Run("untrusted key", func(t *testing.T) {
+		policy := &SigstorePolicy{
+			PublicKeys: []crypto.PublicKey{generateTestSigstoreKey(t).Public()},
+		}
+		_, err := NewSigstoreBundleAuthentication(bundle, policy).AuthenticatePackage(archive)
+		assertSigstoreError(t, err, "bundle is not signed by any of the trusted public keys")
+	})
+	t.Run("modified package", func(t *testing.T) {
+		policy := &SigstorePolicy{
+			PublicKeys: []crypto.PublicKey{signingKey.Public()},
+		}
+		modified := writeTestSigstorePackage(t, []byte("malicious package content"))
+		_, err := NewSigstoreBundleAuthentication(bundle, policy).AuthenticatePackage(modified)
+		assertSigstoreError(t, err, "bundle is not signed by any of the trusted public keys")
+	})
+	t.Run("transparency log entry", func(t *testing.T) {
+		infra := newTestSigstoreInfrastructure(t)
+		policy := &SigstorePolicy{
+			PublicKeys:  []crypto.PublicKey{signingKey.Public()},
+			TrustedRoot: infra.trustedRoot(t),
+		}
+		result, err := NewSigstoreBundleAuthentication(infra.keyBundle(t, signingKey, content), policy).AuthenticatePackage(archive)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got, want := result.SigstoreIdentitiesString(), sigstoreKeyDescription(signingKey.Public()); got != want {
+			t.Errorf("wrong signer\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+	t.Run("missing transparency log entry", func(t *testing.T) {
+		policy := &SigstorePolicy{
+			PublicKeys:  []crypto.PublicKey{signingKey.Public()},
+			TrustedRoot: newTestSigstoreInfrastructure(t).trustedRoot(t),
+		}
+		_, err := NewSigstoreBundleAuthentication(bundle, policy).AuthenticatePackage(archive)
+		assertSigstoreError(t, err, "not enough verified log entries from transparency log")
+	})
+	t.Run("unpacked package", func(t *testing.T) {
+		policy := &SigstorePolicy{
+			PublicKeys: []crypto.PublicKey{signingKey.Public()},
+		}
+		_, err := NewSigstoreBundleAuthentication(bundle, policy).AuthenticatePackage(PackageLocalDir(t.TempDir()))
+		assertSigstoreError(t, err, "cannot verify a Sigstore signature for an unpacked provider package")
+	})
+}
+
+func TestSigstoreBundleAuthentication_keyless(t *testing.T) {
+	content := []byte("provider package content")
+	archive := writeTestSigstorePackage(t, content)
+	infra := newTestSigstoreInfrastructure(t)
+	trustedRoot := infra.trustedRoot(t)
+	const (
+		issuer  = "https://token.actions.example.com"
+		subject = "https://git.example.com/org/provider/.github/workflows/release.yml@refs/tags/v1.0.0"
+	)
+	bundle := infra.keylessBundle(t, content, issuer, subject, nil)
+
+	tests := map[string]struct {
+		bundle     []byte
+		policy     *SigstorePolicy
+		wantSigner string
+		wantErr    string
+	}{
+		"exact subject": {
+			bundle: bundle,
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: issuer, Subject: "someone@example.com"},
+					{Issuer: issuer, Subject: subject},
+				},
+				TrustedRoot: trustedRoot,
+			},
+			wantSigner: subject + " (" + issuer + ")",
+		},
+		"subject regexp": {
+			bundle: bundle,
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: issuer, SubjectRegexp: regexp.MustCompile(`^https://git\.example\.com/org/provider/`)},
+				},
+				TrustedRoot: trustedRoot,
+			},
+			wantSigner: subject + " (" + issuer + ")",
+		},
+		"untrusted subject": {
+			bundle: bundle,
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: issuer, Subject: "someone@example.com"},
+				},
+				TrustedRoot: trustedRoot,
+			},
+			wantErr: "no matching certificate identity found",
+		},
+		"untrusted issuer": {
+			bundle: bundle,
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: "https://accounts.example.com", Subject: subject},
+				},
+				TrustedRoot: trustedRoot,
+			},
+			wantErr: "no matching certificate identity found",
+		},
+		"untrusted transparency log": {
+			bundle: bundle,
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: issuer, Subject: subject},
+				},
+				TrustedRoot: newTestSigstoreInfrastructure(t).trustedRoot(t),
+			},
+			wantErr: "not enough verified log entries from transparency log",
+		},
+		"no identities": {
+			bundle: bundle,
+			policy: &SigstorePolicy{
+				PublicKeys:  []crypto.PublicKey{generateTestSigstoreKey(t).Public()},
+				TrustedRoot: trustedRoot,
+			},
+			wantErr: "bundle is signed with a certificate, but no certificate identities are trusted",
+		},
+		"expired certificate": {
+			// The certificate is valid for only ten minutes after it was
+			// issued, so it must not be accepted for a log entry integrated
+			// after that.
+			bundle: infra.keylessBundle(t, content, issuer, subject, func(s *testSigstoreSigning) {
+				s.integratedAt = s.signedAt.Add(time.Hour)
+			}),
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: issuer, Subject: subject},
+				},
+				TrustedRoot: trustedRoot,
+			},
+			wantErr: "integrated time outside certificate validity",
+		},
+		"missing transparency log entry": {
+			bundle: removeTestSigstoreTlogEntries(t, bundle),
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: issuer, Subject: subject},
+				},
+				TrustedRoot: trustedRoot,
+			},
+			wantErr: "not enough verified log entries from transparency log",
+		},
+		"forged inclusion promise": {
+			bundle: infra.keylessBundle(t, content, issuer, subject, func(s *testSigstoreSigning) {
+				s.promiseKey = generateTestSigstoreKey(t)
+			}),
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: issuer, Subject: subject},
+				},
+				TrustedRoot: trustedRoot,
+			},
+			wantErr: "not enough verified log entries from transparency log",
+		},
+		"missing inclusion proof": {
+			bundle: infra.keylessBundle(t, content, issuer, subject, func(s *testSigstoreSigning) {
+				s.checkpointKey = nil
+			}),
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: issuer, Subject: subject},
+				},
+				TrustedRoot: trustedRoot,
+			},
+			wantErr: "inclusion proof missing in bundle",
+		},
+		"forged checkpoint": {
+			bundle: infra.keylessBundle(t, content, issuer, subject, func(s *testSigstoreSigning) {
+				s.checkpointKey = generateTestSigstoreKey(t)
+			}),
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: issuer, Subject: subject},
+				},
+				TrustedRoot: trustedRoot,
+			},
+			wantErr: "signature on checkpoint did not verify",
+		},
+		"missing signed certificate timestamp": {
+			bundle: infra.keylessBundle(t, content, issuer, subject, func(s *testSigstoreSigning) {
+				s.sctKey = nil
+			}),
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: issuer, Subject: subject},
+				},
+				TrustedRoot: trustedRoot,
+			},
+			wantErr: "unable to meet threshold of 1",
+		},
+		"untrusted certificate transparency log": {
+			bundle: infra.keylessBundle(t, content, issuer, subject, func(s *testSigstoreSigning) {
+				s.sctKey = generateTestSigstoreKey(t)
+			}),
+			policy: &SigstorePolicy{
+				Identities: []SigstoreIdentity{
+					{Issuer: issuer, Subject: subject},
+				},
+				TrustedRoot: trustedRoot,
+			},
+			wantErr: "unable to meet threshold of 1",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := NewSigstoreBundleAuthentication(test.bundle, test.policy).AuthenticatePackage(archive)
+			if test.wantErr != "" {
+				assertSigstoreError(t, err, test.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := result.SigstoreIdentitiesString(); got != test.wantSigner {
+				t.Errorf("wrong signer\ngot:  %s\nwant: %s", got, test.wantSigner)
+			}
+		})
+	}
+
+	t.Run("modified package", func(t *testing.T) {
+		policy := &SigstorePolicy{
+			Identities: []SigstoreIdentity{
+				{Issuer: issuer, Subject: subject},
+			},
+			TrustedRoot: trustedRoot,
+		}
+		modified := writeTestSigstorePackage(t, []byte("malicious package content"))
+		_, err := NewSigstoreBundleAuthentication(bundle, policy).AuthenticatePackage(modified)
+		assertSigstoreError(t, err, "could not verify message")
+	})
+}
+
+func TestFetchSigstoreBundle(t *testing.T) {
+	bundle := []byte(`{"mediaType": "application/vnd.dev.sigstore.bundle.v0.3+json"}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/signed.zip.sigstore.json":
+			w.Write(bundle)
+		case "/broken.zip.sigstore.json":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fetch := func(filename string) ([]byte, error) {
+		packageURL, err := url.Parse(server.URL + "/" + filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return FetchSigstoreBundle(t.Context(), server.Client(), packageURL)
+	}
+
+	got, err := fetch("signed.zip")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(got) != string(bundle) {
+		t.Errorf("wrong bundle\ngot:  %s\nwant: %s", got, bundle)
+	}
+
+	got, err = fetch("unsigned.zip")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != nil {
+		t.Errorf("unexpected bundle %s", got)
+	}
+
+	_, err = fetch("broken.zip")
+	assertSigstoreError(t, err, "server returned unsuccessful status 500")
+}
+
+func TestParseSigstorePublicKey(t *testing.T) {
+	key := generateTestSigstoreKey(t)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	got, err := ParseSigstorePublicKey(src)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !key.PublicKey.Equal(got) {
+		t.Errorf("wrong public key")
+	}
+
+	if _, err := ParseSigstorePublicKey([]byte("not a key")); err == nil {
+		t.Errorf("unexpected success with invalid key")
+	}
+}
+
+func assertSigstoreError(t *testing.T, err error, want string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("unexpected success; want error containing %q", want)
+	}
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: error containing %q", err, want)
+	}
+}
+
+func writeTestSigstorePackage(t *testing.T, content []byte) PackageLocalArchive {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "terraform-provider-test_1.0.0_tos_m68k.zip")
+	if err := os.WriteFile(filename, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return PackageLocalArchive(filename)
+}
+
+func generateTestSigstoreKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func signTestSigstoreContent(t *testing.T, key *ecdsa.PrivateKey, content []byte) (digest, sig []byte) {
+	t.Helper()
+	sum := sha256.Sum256(content)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, sum[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sum[:], sig
+}
+
+// testSigstoreKeyBundle returns a bundle like those created by
+// "cosign sign-blob --key --tlog-upload=false".
+func testSigstoreKeyBundle(t *testing.T, key *ecdsa.PrivateKey, content []byte) []byte {
+	t.Helper()
+	digest, sig := signTestSigstoreContent(t, key, content)
+	return marshalTestSigstoreJSON(t, map[string]any{
+		"mediaType": "application/vnd.dev.sigstore.bundle.v0.3+json",
+		"verificationMaterial": map[string]any{
+			"publicKey": map[string]any{
+				"hint": "test",
+			},
+		},
+		"messageSignature": map[string]any{
+			"messageDigest": map[string]any{
+				"algorithm": "SHA2_256",
+				"digest":    digest,
+			},
+			"signature": sig,
+		},
+	})
+}
+
+func removeTestSigstoreTlogEntries(t *testing.T, bundle []byte) []byte {
+	t.Helper()
+	var raw map[string]any
+	if err := json.Unmarshal(bundle, &raw); err != nil {
+		t.Fatal(err)
+	}
+	delete(raw["verificationMaterial"].(map[string]any), "tlogEntries")
+	return marshalTestSigstoreJSON(t, raw)
+}
+
+func marshalTestSigstoreJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	src, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+// sigstoreOIDCIssuerV2OID is the object identifier of the certificate
+// extension that Fulcio uses to record the OIDC issuer of the identity that
+// a certificate was issued for, as a DER-encoded UTF8String.
+var sigstoreOIDCIssuerV2OID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+
+// testSigstoreInfrastructure is a fake certificate authority, certificate
+// transparency log and transparency log, standing in for Fulcio, the
+// Sigstore CT log and Rekor.
+type testSigstoreInfrastructure struct {
+	caKey   *ecdsa.PrivateKey
+	caCert  *x509.Certificate
+	ctKey   *ecdsa.PrivateKey
+	tlogKey *ecdsa.PrivateKey
+}
+
+func newTestSigstoreInfrastructure(t *testing.T) *testSigstoreInfrastructure {
+	t.Helper()
+	caKey := generateTestSigstoreKey(t)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-fulcio"},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testSigstoreInfrastructure{
+		caKey:   caKey,
+		caCert:  caCert,
+		ctKey:   generateTestSigstoreKey(t),
+		tlogKey: generateTestSigstoreKey(t),
+	}
+}
+
+// testSigstoreLogID returns the ID of a log with the given key, which is
+// the SHA256 checksum of the key's PKIX encoding.
+func testSigstoreLogID(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(der)
+	return sum[:]
+}
+
+func (i *testSigstoreInfrastructure) trustedRoot(t *testing.T) *SigstoreTrustedRoot {
+	t.Helper()
+	logKey := func(key *ecdsa.PrivateKey) map[string]any {
+		der, err := x509.MarshalPKIXPublicKey(key.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return map[string]any{
+			"rawBytes":   der,
+			"keyDetails": "PKIX_ECDSA_P256_SHA_256",
+			"validFor": map[string]any{
+				"start": time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+			},
+		}
+	}
+	src := marshalTestSigstoreJSON(t, map[string]any{
+		"mediaType": "application/vnd.dev.sigstore.trustedroot+json;version=0.1",
+		"tlogs": []any{
+			map[string]any{
+				"baseUrl":       "https://rekor.example.com",
+				"hashAlgorithm": "SHA2_256",
+				"publicKey":     logKey(i.tlogKey),
+				"logId": map[string]any{
+					"keyId": testSigstoreLogID(t, i.tlogKey),
+				},
+			},
+		},
+		"ctlogs": []any{
+			map[string]any{
+				"baseUrl":       "https://ctfe.example.com",
+				"hashAlgorithm": "SHA2_256",
+				"publicKey":     logKey(i.ctKey),
+				"logId": map[string]any{
+					"keyId": testSigstoreLogID(t, i.ctKey),
+				},
+			},
+		},
+		"certificateAuthorities": []any{
+			map[string]any{
+				"uri": "https://fulcio.example.com",
+				"certChain": map[string]any{
+					"certificates": []any{
+						map[string]any{"rawBytes": i.caCert.Raw},
+					},
+				},
+				"validFor": map[string]any{
+					"start": time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+				},
+			},
+		},
+	})
+	ret, err := ParseSigstoreTrustedRoot(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+// testSigstoreSigning describes how a test bundle is signed and logged, so
+// that each test can change one aspect of an otherwise valid bundle.
+type testSigstoreSigning struct {
+	// signedAt is the time at which the signing certificate was issued.
+	signedAt time.Time
+	// integratedAt is the time at which the transparency log integrated
+	// the entry.
+	integratedAt time.Time
+
+	// sctKey signs the certificate's signed certificate timestamp, which is
+	// omitted if it's nil.
+	sctKey *ecdsa.PrivateKey
+	// promiseKey signs the entry's inclusion promise.
+	promiseKey *ecdsa.PrivateKey
+	// checkpointKey signs the checkpoint of the entry's inclusion proof,
+	// which is omitted if it's nil.
+	checkpointKey *ecdsa.PrivateKey
+}
+
+// signing returns the options for a bundle that's valid for this
+// infrastructure, signed at the given time.
+func (i *testSigstoreInfrastructure) signing(signedAt time.Time) testSigstoreSigning {
+	return testSigstoreSigning{
+		signedAt:      signedAt,
+		integratedAt:  signedAt,
+		sctKey:        i.ctKey,
+		promiseKey:    i.tlogKey,
+		checkpointKey: i.tlogKey,
+	}
+}
+
+// keylessBundle returns a bundle like those created by
+// "cosign sign-blob --bundle" using keyless signing, after applying the
+// given function to the default signing options.
+func (i *testSigstoreInfrastructure) keylessBundle(t *testing.T, content []byte, issuer, subject string, modify func(*testSigstoreSigning)) []byte {
+	t.Helper()
+	signing := i.signing(time.Now())
+	if modify != nil {
+		modify(&signing)
+	}
+
+	// The signing key is ephemeral, and certified by the certificate
+	// authority for only a short time.
+	signingKey := generateTestSigstoreKey(t)
+	subjectURI, err := url.Parse(subject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuerExt, err := asn1.MarshalWithParams(issuer, "utf8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(signing.signedAt.UnixNano()),
+		NotBefore:    signing.signedAt.Add(-time.Minute),
+		NotAfter:     signing.signedAt.Add(10 * time.Minute),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:         []*url.URL{subjectURI},
+		ExtraExtensions: []pkix.Extension{
+			{Id: sigstoreOIDCIssuerV2OID, Value: issuerExt},
+		},
+	}
+	if signing.sctKey != nil {
+		i.addTestSigstoreSCT(t, template, signingKey, signing.sctKey, signing.signedAt)
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, i.caCert, signingKey.Public(), i.caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, sig := signTestSigstoreContent(t, signingKey, content)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+
+	return marshalTestSigstoreJSON(t, map[string]any{
+		"mediaType": "application/vnd.dev.sigstore.bundle.v0.3+json",
+		"verificationMaterial": map[string]any{
+			"certificate": map[string]any{
+				"rawBytes": certDER,
+			},
+			"tlogEntries": []any{
+				i.tlogEntry(t, digest, sig, certPEM, signing),
+			},
+		},
+		"messageSignature": map[string]any{
+			"messageDigest": map[string]any{
+				"algorithm": "SHA2_256",
+				"digest":    digest,
+			},
+			"signature": sig,
+		},
+	})
+}
+
+// keyBundle returns a bundle like those created by
+// "cosign sign-blob --key --bundle", including a transparency log entry.
+func (i *testSigstoreInfrastructure) keyBundle(t *testing.T, key *ecdsa.PrivateKey, content []byte) []byte {
+	t.Helper()
+	digest, sig := signTestSigstoreContent(t, key, content)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	return marshalTestSigstoreJSON(t, map[string]any{
+		"mediaType": "application/vnd.dev.sigstore.bundle.v0.3+json",
+		"verificationMaterial": map[string]any{
+			"publicKey": map[string]any{
+				"hint": "test",
+			},
+			"tlogEntries": []any{
+				i.tlogEntry(t, digest, sig, keyPEM, i.signing(time.Now())),
+			},
+		},
+		"messageSignature": map[string]any{
+			"messageDigest": map[string]any{
+				"algorithm": "SHA2_256",
+				"digest":    digest,
+			},
+			"signature": sig,
+		},
+	})
+}
+
+// addTestSigstoreSCT adds a signed certificate timestamp to the given
+// template, as a certificate transparency log would for the precertificate
+// that Fulcio submits to it before issuing the certificate.
+func (i *testSigstoreInfrastructure) addTestSigstoreSCT(t *testing.T, template *x509.Certificate, signingKey, sctKey *ecdsa.PrivateKey, signedAt time.Time) {
+	t.Helper()
+
+	// The timestamp covers the certificate without the SCT extension.
+	precertDER, err := x509.CreateCertificate(rand.Reader, template, i.caCert, signingKey.Public(), i.caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	precert, err := x509.ParseCertificate(precertDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sct := ct.SignedCertificateTimestamp{
+		SCTVersion: ct.V1,
+		LogID:      ct.LogID{KeyID: [32]byte(testSigstoreLogID(t, sctKey))},
+		Timestamp:  uint64(signedAt.UnixMilli()),
+	}
+	input, err := ct.SerializeSCTSignatureInput(sct, ct.LogEntry{
+		Leaf: ct.MerkleTreeLeaf{
+			Version:  ct.V1,
+			LeafType: ct.TimestampedEntryLeafType,
+			TimestampedEntry: &ct.TimestampedEntry{
+				Timestamp: sct.Timestamp,
+				EntryType: ct.PrecertLogEntryType,
+				PrecertEntry: &ct.PreCert{
+					IssuerKeyHash:  sha256.Sum256(i.caCert.RawSubjectPublicKeyInfo),
+					TBSCertificate: precert.RawTBSCertificate,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, sctSig := signTestSigstoreContent(t, sctKey, input)
+	sct.Signature = ct.DigitallySigned{
+		Algorithm: tls.SignatureAndHashAlgorithm{
+			Hash:      tls.SHA256,
+			Signature: tls.ECDSA,
+		},
+		Signature: sctSig,
+	}
+
+	list, err := ctx509util.MarshalSCTsIntoSCTList([]*ct.SignedCertificateTimestamp{&sct})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listBytes, err := tls.Marshal(*list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extValue, err := asn1.Marshal(listBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.ExtraExtensions = append(template.ExtraExtensions, pkix.Extension{
+		Id:    asn1.ObjectIdentifier(ctx509.OIDExtensionCTSCT),
+		Value: extValue,
+	})
+}
+
+// tlogEntry returns a transparency log entry for the given signature, with
+// an inclusion promise and an inclusion proof.
+func (i *testSigstoreInfrastructure) tlogEntry(t *testing.T, digest, sig, publicKeyPEM []byte, signing testSigstoreSigning) map[string]any {
+	t.Helper()
+	body := marshalTestSigstoreJSON(t, map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]any{
+			"data": map[string]any{
+				"hash": map[string]any{
+					"algorithm": "sha256",
+					"value":     hex.EncodeToString(digest),
+				},
+			},
+			"signature": map[string]any{
+				"content": sig,
+				"publicKey": map[string]any{
+					"content": publicKeyPEM,
+				},
+			},
+		},
+	})
+	logID := testSigstoreLogID(t, i.tlogKey)
+	const logIndex = 12345
+	promisePayload := marshalTestSigstoreJSON(t, map[string]any{
+		"body":           base64.StdEncoding.EncodeToString(body),
+		"integratedTime": signing.integratedAt.Unix(),
+		"logID":          hex.EncodeToString(logID),
+		"logIndex":       logIndex,
+	})
+	_, promise := signTestSigstoreContent(t, signing.promiseKey, promisePayload)
+
+	entry := map[string]any{
+		"logIndex": strconv.Itoa(logIndex),
+		"logId": map[string]any{
+			"keyId": logID,
+		},
+		"kindVersion": map[string]any{
+			"kind":    "hashedrekord",
+			"version": "0.0.1",
+		},
+		"integratedTime": strconv.FormatInt(signing.integratedAt.Unix(), 10),
+		"inclusionPromise": map[string]any{
+			"signedEntryTimestamp": promise,
+		},
+		"canonicalizedBody": body,
+	}
+	if signing.checkpointKey != nil {
+		// The entry is the second leaf of a tree of size two, so the proof
+		// is the hash of the first leaf.
+		otherLeaf := rfc6962.DefaultHasher.HashLeaf([]byte("other entry"))
+		rootHash := rfc6962.DefaultHasher.HashChildren(otherLeaf, rfc6962.DefaultHasher.HashLeaf(body))
+		signer, err := signature.LoadECDSASignerVerifier(signing.checkpointKey, crypto.SHA256)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkpoint, err := rekorutil.CreateAndSignCheckpoint(context.Background(), "rekor.example.com", 1, 2, rootHash, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry["inclusionProof"] = map[string]any{
+			"logIndex": "1",
+			"rootHash": rootHash,
+			"treeSize": "2",
+			"hashes":   [][]byte{otherLeaf},
+			"checkpoint": map[string]any{
+				"envelope": string(checkpoint),
+			},
+		}
+	}
+	return entry
+}
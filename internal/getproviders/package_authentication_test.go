@@ -119,6 +119,18 @@ func TestPackageAuthenticationResult(t *testing.T) {
 			"signed",
 			"abc123", true, false,
 		},
+		"SignedBySigstoreIdentities+VerifiedLocally": {
+			&PackageAuthenticationResult{
+				hashes: HashDispositions{
+					Hash("test:placeholder"): {
+						SignedBySigstoreIdentities: collections.NewSet("ci@example.com (https://issuer.example.com)"),
+						VerifiedLocally:            true,
+					},
+				},
+			},
+			"signed",
+			"", true, false,
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -171,7 +171,7 @@ func DirFromModule(ctx context.Context, loader *configload.Loader, rootDir, modu
 		Dir: rootDir,
 	}
 
-	walker := inst.moduleInstallWalker(ctx, instManifest, true, wrapHooks, remoteFetcher)
+	walker := inst.moduleInstallWalker(ctx, instManifest, nil, true, wrapHooks, remoteFetcher)
 	_, cDiags := inst.installDescendentModules(ctx, fakeRootModule, instManifest, walker, true)
 	if cDiags.HasErrors() {
 		return diags.Append(cDiags)
@@ -26,6 +26,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/modsdir"
 	"github.com/opentofu/opentofu/internal/registry"
@@ -120,6 +121,25 @@ func NewModuleInstaller(modsDir string, loader *configload.Loader, registryClien
 // first return value is the early configuration tree that was constructed by
 // the installation process.
 func (i *ModuleInstaller) InstallModules(ctx context.Context, rootDir, testsDir string, upgrade, installErrsOnly bool, hooks ModuleInstallHooks, call configs.StaticModuleCall) (*configs.Config, tfdiags.Diagnostics) {
+	return i.installModules(ctx, rootDir, testsDir, nil, upgrade, installErrsOnly, hooks, call)
+}
+
+// InstallModulesWithLocks is like InstallModules, but also uses the module
+// entries of the given dependency locks to select and verify the packages of
+// the remote modules.
+//
+// Unless the upgrade flag is set, a registry module is installed at the
+// version recorded in the locks and the package of any remote module must
+// match the recorded commit and hash, or installation fails. The returned
+// locks are a copy of the given locks whose module entries describe the
+// packages that are now installed, which the caller can save if they differ.
+func (i *ModuleInstaller) InstallModulesWithLocks(ctx context.Context, rootDir, testsDir string, locks *depsfile.Locks, upgrade, installErrsOnly bool, hooks ModuleInstallHooks, call configs.StaticModuleCall) (*configs.Config, *depsfile.Locks, tfdiags.Diagnostics) {
+	modLocks := newModuleLocks(locks, upgrade)
+	cfg, diags := i.installModules(ctx, rootDir, testsDir, modLocks, upgrade, installErrsOnly, hooks, call)
+	return cfg, modLocks.new, diags
+}
+
+func (i *ModuleInstaller) installModules(ctx context.Context, rootDir, testsDir string, locks *moduleLocks, upgrade, installErrsOnly bool, hooks ModuleInstallHooks, call configs.StaticModuleCall) (*configs.Config, tfdiags.Diagnostics) {
 	log.Printf("[TRACE] ModuleInstaller: installing child modules for %s into %s", rootDir, i.modsDir)
 	var diags tfdiags.Diagnostics
 
@@ -161,7 +181,7 @@ func (i *ModuleInstaller) InstallModules(ctx context.Context, rootDir, testsDir
 		Key: "",
 		Dir: rootDir,
 	}
-	walker := i.moduleInstallWalker(ctx, manifest, upgrade, hooks, fetcher)
+	walker := i.moduleInstallWalker(ctx, manifest, locks, upgrade, hooks, fetcher)
 
 	cfg, instDiags := i.installDescendentModules(ctx, rootMod, manifest, walker, installErrsOnly)
 	diags = append(diags, instDiags...)
@@ -169,7 +189,7 @@ func (i *ModuleInstaller) InstallModules(ctx context.Context, rootDir, testsDir
 	return cfg, diags
 }
 
-func (i *ModuleInstaller) moduleInstallWalker(_ context.Context, manifest modsdir.Manifest, upgrade bool, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) configs.ModuleWalker {
+func (i *ModuleInstaller) moduleInstallWalker(_ context.Context, manifest modsdir.Manifest, locks *moduleLocks, upgrade bool, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) configs.ModuleWalker {
 	return configs.ModuleWalkerFunc(
 		func(ctx context.Context, req *configs.ModuleRequest) (*configs.Module, *version.Version, hcl.Diagnostics) {
 			var diags hcl.Diagnostics
@@ -228,6 +248,10 @@ func (i *ModuleInstaller) moduleInstallWalker(_ context.Context, manifest modsdi
 					log.Printf("[TRACE] ModuleInstaller: %s version %s no longer compatible with constraints %s", key, record.Version, req.VersionConstraint.Required)
 					span.AddEvent("Module version constraint changed")
 					replace = true
+				case !isLocalSource(req.SourceAddr) && !locks.keepInstalled(ctx, key, req, record, instPath):
+					log.Printf("[TRACE] ModuleInstaller: %s doesn't match the dependency lock file", key)
+					span.AddEvent("Module package doesn't match the dependency lock file")
+					replace = true
 				}
 			}
 
@@ -312,13 +336,13 @@ func (i *ModuleInstaller) moduleInstallWalker(_ context.Context, manifest modsdi
 			case addrs.ModuleSourceRegistry:
 				log.Printf("[TRACE] ModuleInstaller: %s is a registry module at %s", key, addr.String())
 				span.SetAttributes(otelAttr.String("opentofu.module.source_type", "registry"))
-				mod, v, mDiags := i.installRegistryModule(ctx, req, key, instPath, addr, manifest, hooks, fetcher)
+				mod, v, mDiags := i.installRegistryModule(ctx, req, key, instPath, addr, manifest, locks, upgrade, hooks, fetcher)
 				diags = append(diags, mDiags...)
 				return mod, v, diags
 
 			case addrs.ModuleSourceRemote:
 				log.Printf("[TRACE] ModuleInstaller: %s address %q will be handled by go-getter", key, addr.String())
-				mod, mDiags := i.installGoGetterModule(ctx, req, key, instPath, manifest, hooks, fetcher)
+				mod, mDiags := i.installGoGetterModule(ctx, req, key, instPath, manifest, locks, upgrade, hooks, fetcher)
 				diags = append(diags, mDiags...)
 				return mod, nil, diags
 
@@ -465,7 +489,7 @@ func (i *ModuleInstaller) installLocalModule(ctx context.Context, req *configs.M
 // public hashicorp/go-version API.
 var versionRegexp = regexp.MustCompile(version.VersionRegexpRaw)
 
-func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *configs.ModuleRequest, key string, instPath string, addr addrs.ModuleSourceRegistry, manifest modsdir.Manifest, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*configs.Module, *version.Version, hcl.Diagnostics) {
+func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *configs.ModuleRequest, key string, instPath string, addr addrs.ModuleSourceRegistry, manifest modsdir.Manifest, locks *moduleLocks, upgrade bool, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*configs.Module, *version.Version, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
 	ctx, span := tracing.Tracer().Start(ctx, "Install Registry Module",
@@ -702,6 +726,39 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 		return nil, nil, diags
 	}
 
+	if lock := locks.lock(key, req); lock != nil && lock.Version() != nil {
+		// Unless upgrading, we select the version recorded in the dependency
+		// lock file rather than the newest version matching the constraints.
+		lockedVersion := lock.Version()
+		if !req.VersionConstraint.Required.Check(lockedVersion) {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Locked module version doesn't match the version constraint",
+				Detail:   fmt.Sprintf("The dependency lock file selects version %s of module %q (%s:%d), which doesn't match the configured version constraint. Run \"tofu init -upgrade\" to allow selecting a new version.", lockedVersion, addr, req.CallRange.Filename, req.CallRange.Start.Line),
+				Subject:  req.CallRange.Ptr(),
+			})
+			tracing.SetSpanError(span, diags)
+			return nil, nil, diags
+		}
+		latestMatch = nil
+		for _, mv := range modMeta.Versions {
+			if v, err := version.NewVersion(mv.Version); err == nil && v.String() == lockedVersion.String() {
+				latestMatch = v
+				break
+			}
+		}
+		if latestMatch == nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Locked module version not available",
+				Detail:   fmt.Sprintf("Version %s of module %q (%s:%d), which is selected by the dependency lock file, is not available on %s. Run \"tofu init -upgrade\" to allow selecting a new version.", lockedVersion, addr, req.CallRange.Filename, req.CallRange.Start.Line, hostname),
+				Subject:  req.CallRange.Ptr(),
+			})
+			tracing.SetSpanError(span, diags)
+			return nil, nil, diags
+		}
+	}
+
 	// Report up to the caller that we're about to start downloading.
 	hooks.Download(key, packageAddr.String(), latestMatch)
 
@@ -758,7 +815,9 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 
 	log.Printf("[TRACE] ModuleInstaller: %s %s %s is available at %q", key, packageAddr, latestMatch, dlAddr.Package)
 
-	err := fetcher.FetchPackage(ctx, instPath, dlAddr.Package.String())
+	// The package of a specific registry module version is not expected to
+	// change, so it can be installed from the module cache by its address.
+	err := fetcher.FetchPackage(ctx, instPath, dlAddr.Package.String(), locks.lockedHash(key, req), true, upgrade)
 	if errors.Is(err, context.Canceled) {
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -784,6 +843,12 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 
 	log.Printf("[TRACE] ModuleInstaller: %s %q was downloaded to %s", key, dlAddr.Package, instPath)
 
+	if lockDiags := locks.lockPackage(ctx, key, req, instPath, latestMatch); lockDiags.HasErrors() {
+		diags = diags.Extend(lockDiags)
+		tracing.SetSpanError(span, diags)
+		return nil, nil, diags
+	}
+
 	// Incorporate any subdir information from the original path into the
 	// address returned by the registry in order to find the final directory
 	// of the target module.
@@ -828,7 +893,7 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 	return mod, latestMatch, diags
 }
 
-func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *configs.ModuleRequest, key string, instPath string, manifest modsdir.Manifest, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*configs.Module, hcl.Diagnostics) {
+func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *configs.ModuleRequest, key string, instPath string, manifest modsdir.Manifest, locks *moduleLocks, upgrade bool, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*configs.Module, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
 	if fetcher == nil {
@@ -858,7 +923,7 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *config
 		return nil, diags
 	}
 
-	err := fetcher.FetchPackage(ctx, instPath, packageAddr.String())
+	err := fetcher.FetchPackage(ctx, instPath, packageAddr.String(), locks.lockedHash(key, req), false, upgrade)
 	if err != nil {
 		// go-getter generates a poor error for an invalid relative path, so
 		// we'll detect that case and generate a better one.
@@ -906,6 +971,11 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *config
 
 	log.Printf("[TRACE] ModuleInstaller: %s %q was downloaded to %s", key, addr, modDir)
 
+	if lockDiags := locks.lockPackage(ctx, key, req, instPath, nil); lockDiags.HasErrors() {
+		diags = diags.Extend(lockDiags)
+		return nil, diags
+	}
+
 	// Finally we are ready to try actually loading the module.
 	mod, mDiags := i.loader.Parser().LoadConfigDir(modDir, req.Call)
 	if mod == nil {
@@ -940,6 +1010,13 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *config
 	return mod, diags
 }
 
+// isLocalSource returns true if the given module source address refers to a
+// directory within the package of the calling module.
+func isLocalSource(addr addrs.ModuleSource) bool {
+	_, ok := addr.(addrs.ModuleSourceLocal)
+	return ok
+}
+
 func (i *ModuleInstaller) packageInstallPath(modulePath addrs.Module) string {
 	return filepath.Join(i.modsDir, strings.Join(modulePath, "."))
 }
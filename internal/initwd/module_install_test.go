@@ -24,6 +24,7 @@ import (
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/copy"
+	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/registry"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -231,6 +232,99 @@ func TestModuleInstaller_explicitPackageBoundary(t *testing.T) {
 	}
 }
 
+func TestModuleInstaller_locks(t *testing.T) {
+	fixtureDir := filepath.Clean("testdata/load-module-package-prefix")
+	dir := tempChdir(t, fixtureDir)
+
+	// As in TestModuleInstaller_explicitPackageBoundary, we use an absolute
+	// path to the temporary directory so the module is a remote package.
+	{
+		rootFilename := filepath.Join(dir, "package-prefix.tf")
+		template, err := os.ReadFile(rootFilename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		final := bytes.ReplaceAll(template, []byte("%%BASE%%"), []byte(filepath.ToSlash(dir)))
+		err = os.WriteFile(rootFilename, final, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	modulesDir := filepath.Join(dir, ".terraform/modules")
+	install := func(t *testing.T, locks *depsfile.Locks, upgrade bool) (*depsfile.Locks, tfdiags.Diagnostics) {
+		t.Helper()
+		loader := configload.NewLoaderForTests(t)
+		inst := NewModuleInstaller(modulesDir, loader, nil, getmodules.NewPackageFetcher(t.Context(), nil))
+		_, newLocks, diags := inst.InstallModulesWithLocks(context.Background(), ".", "tests", locks, upgrade, false, &testInstallHooks{}, configs.RootModuleCallForTesting())
+		return newLocks, diags
+	}
+	reinstall := func(t *testing.T, locks *depsfile.Locks, upgrade bool) (*depsfile.Locks, tfdiags.Diagnostics) {
+		t.Helper()
+		if err := os.RemoveAll(modulesDir); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(modulesDir, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		return install(t, locks, upgrade)
+	}
+
+	locks, diags := install(t, depsfile.NewLocks(), false)
+	assertNoDiagnostics(t, diags)
+
+	// Only the remote package is locked, and not the local module inside it.
+	if got, want := len(locks.AllModules()), 1; got != want {
+		t.Fatalf("wrong number of module locks %d; want %d", got, want)
+	}
+	lock := locks.Module("child")
+	if lock == nil {
+		t.Fatal("no lock for module child")
+	}
+	if got, want := lock.Source(), "file://"+filepath.ToSlash(dir)+"/package//child"; got != want {
+		t.Errorf("wrong source\ngot:  %s\nwant: %s", got, want)
+	}
+	if !strings.HasPrefix(lock.Hash(), "h1:") {
+		t.Errorf("wrong hash %q", lock.Hash())
+	}
+
+	// Installing again with the same package leaves the locks unchanged,
+	// whether or not the module is already installed.
+	newLocks, diags := install(t, locks, false)
+	assertNoDiagnostics(t, diags)
+	if !newLocks.Equal(locks) {
+		t.Errorf("locks changed when the module was already installed")
+	}
+	newLocks, diags = reinstall(t, locks, false)
+	assertNoDiagnostics(t, diags)
+	if !newLocks.Equal(locks) {
+		t.Errorf("locks changed when the module was installed again")
+	}
+
+	// Changing the package at its source must be detected when the module
+	// is installed again.
+	err := os.WriteFile(filepath.Join(dir, "package", "grandchild", "changed.tf"), []byte("# changed\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, diags = reinstall(t, locks, false)
+	assertDiagnosticSummary(t, diags, "Module package doesn't match the dependency lock file")
+
+	// ...unless upgrading, which selects the new package.
+	newLocks, diags = reinstall(t, locks, true)
+	assertNoDiagnostics(t, diags)
+	if got := newLocks.Module("child").Hash(); got == lock.Hash() {
+		t.Errorf("hash didn't change after upgrading")
+	}
+
+	// An installed module that doesn't match its lock entry is not trusted,
+	// and so is installed again and verified.
+	tampered := depsfile.NewLocks()
+	tampered.SetModule("child", lock.Source(), nil, "", lock.Hash())
+	_, diags = install(t, tampered, false)
+	assertDiagnosticSummary(t, diags, "Module package doesn't match the dependency lock file")
+}
+
 func TestModuleInstaller_Prerelease(t *testing.T) {
 	if os.Getenv("TF_ACC") == "" {
 		t.Skip("this test accesses registry.opentofu.org and github.com; set TF_ACC=1 to run it")
@@ -0,0 +1,181 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package initwd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/getmodules"
+	"github.com/opentofu/opentofu/internal/modsdir"
+)
+
+// moduleLocks tracks the dependency lock entries for the remote module
+// packages during a single call to [ModuleInstaller.InstallModulesWithLocks].
+//
+// A nil *moduleLocks disables dependency locking for modules, and all of its
+// methods are safe to call on a nil receiver.
+type moduleLocks struct {
+	// previous are the locks that were recorded before this installation,
+	// which are disregarded when upgrade is set.
+	previous *depsfile.Locks
+	upgrade  bool
+
+	// new are the locks to record after this installation. The module
+	// entries are populated only for the modules visited by the installer,
+	// so the entries of modules no longer in the configuration are dropped.
+	new *depsfile.Locks
+}
+
+func newModuleLocks(previous *depsfile.Locks, upgrade bool) *moduleLocks {
+	new := previous.DeepCopy()
+	for key := range new.AllModules() {
+		new.RemoveModule(key)
+	}
+	return &moduleLocks{
+		previous: previous,
+		upgrade:  upgrade,
+		new:      new,
+	}
+}
+
+// lock returns the previously-recorded lock entry that applies to the given
+// module request, or nil if there is none or if it must be disregarded.
+func (l *moduleLocks) lock(key string, req *configs.ModuleRequest) *depsfile.ModuleLock {
+	if l == nil || l.upgrade {
+		return nil
+	}
+	lock := l.previous.Module(key)
+	if lock == nil || lock.Source() != req.SourceAddr.String() {
+		// If the source address has changed then the module must be
+		// resolved again, as if it were a new module.
+		return nil
+	}
+	return lock
+}
+
+// lockedHash returns the package hash recorded in the lock entry that applies
+// to the given module request, or an empty string if there is none.
+func (l *moduleLocks) lockedHash(key string, req *configs.ModuleRequest) string {
+	if lock := l.lock(key, req); lock != nil {
+		return lock.Hash()
+	}
+	return ""
+}
+
+// keepInstalled returns true if the package already installed for the given
+// module can be kept, in which case its lock entry is recorded in the new
+// locks.
+//
+// A module that has no lock entry yet is locked to the package that is
+// installed, in the same way as a newly-installed package is trusted on first
+// use. Otherwise, the installed package must still match its lock entry.
+func (l *moduleLocks) keepInstalled(ctx context.Context, key string, req *configs.ModuleRequest, record modsdir.Record, instPath string) bool {
+	if l == nil {
+		return true
+	}
+	hash, err := getmodules.PackageHash(instPath)
+	if err != nil {
+		log.Printf("[TRACE] ModuleInstaller: failed to hash the package of %s: %s", key, err)
+		return false
+	}
+
+	lock := l.lock(key, req)
+	if lock == nil {
+		commit, err := getmodules.PackageGitCommit(ctx, instPath)
+		if err != nil {
+			log.Printf("[TRACE] ModuleInstaller: failed to read the commit of %s: %s", key, err)
+			return false
+		}
+		log.Printf("[TRACE] ModuleInstaller: recording the installed package of %s in the dependency lock file", key)
+		l.new.SetModule(key, req.SourceAddr.String(), record.Version, commit, hash)
+		return true
+	}
+	if versionString(record.Version) != versionString(lock.Version()) {
+		log.Printf("[TRACE] ModuleInstaller: %s version %s doesn't match the locked version %s", key, record.Version, lock.Version())
+		return false
+	}
+	if hash != lock.Hash() {
+		log.Printf("[TRACE] ModuleInstaller: %s package hash %s doesn't match the locked hash %s", key, hash, lock.Hash())
+		return false
+	}
+
+	l.new.SetModule(key, lock.Source(), lock.Version(), lock.Commit(), lock.Hash())
+	return true
+}
+
+// lockPackage verifies the package that was just installed for the given
+// module against its previous lock entry, if any, and then records it in the
+// new locks.
+func (l *moduleLocks) lockPackage(ctx context.Context, key string, req *configs.ModuleRequest, instPath string, version *version.Version) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	if l == nil {
+		return diags
+	}
+
+	hash, err := getmodules.PackageHash(instPath)
+	if err != nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to hash module package",
+			Detail:   fmt.Sprintf("Could not calculate the hash of the package for module %q (%s:%d): %s.", req.Name, req.CallRange.Filename, req.CallRange.Start.Line, err),
+			Subject:  req.CallRange.Ptr(),
+		})
+		return diags
+	}
+	commit, err := getmodules.PackageGitCommit(ctx, instPath)
+	if err != nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to read module package commit",
+			Detail:   fmt.Sprintf("Could not determine the commit of the package for module %q (%s:%d): %s.", req.Name, req.CallRange.Filename, req.CallRange.Start.Line, err),
+			Subject:  req.CallRange.Ptr(),
+		})
+		return diags
+	}
+
+	lock := l.lock(key, req)
+	if lock == nil {
+		l.new.SetModule(key, req.SourceAddr.String(), version, commit, hash)
+		return diags
+	}
+
+	// A package that was copied from another module installed from the same
+	// address has no git metadata, in which case its hash alone identifies
+	// the package.
+	if lock.Commit() != "" && commit != "" && commit != lock.Commit() {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Module commit doesn't match the dependency lock file",
+			Detail:   fmt.Sprintf("The package for module %q (%s:%d) was installed from commit %s, but the dependency lock file requires commit %s. If the source is expected to have changed, run \"tofu init -upgrade\" to select the new commit.", req.Name, req.CallRange.Filename, req.CallRange.Start.Line, commit, lock.Commit()),
+			Subject:  req.CallRange.Ptr(),
+		})
+		return diags
+	}
+	if hash != lock.Hash() {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Module package doesn't match the dependency lock file",
+			Detail:   fmt.Sprintf("The package installed for module %q (%s:%d) has hash %s, but the dependency lock file requires hash %s. If the source is expected to have changed, run \"tofu init -upgrade\" to select the new package.", req.Name, req.CallRange.Filename, req.CallRange.Start.Line, hash, lock.Hash()),
+			Subject:  req.CallRange.Ptr(),
+		})
+		return diags
+	}
+
+	l.new.SetModule(key, lock.Source(), lock.Version(), lock.Commit(), lock.Hash())
+	return diags
+}
+
+func versionString(v *version.Version) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
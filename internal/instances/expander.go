@@ -64,6 +64,14 @@ func (e *Expander) SetModuleCount(parentAddr addrs.ModuleInstance, callAddr addr
 	e.setModuleExpansion(parentAddr, callAddr, expansionCount(count))
 }
 
+// SetModuleEnabled records that the given module call inside the given parent
+// module instance uses the "enabled" lifecycle argument, with the given value.
+// An enabled module call has a single instance with no key, while a disabled
+// one has no instances at all.
+func (e *Expander) SetModuleEnabled(parentAddr addrs.ModuleInstance, callAddr addrs.ModuleCall, enabled bool) {
+	e.setModuleExpansion(parentAddr, callAddr, expansionEnabled(enabled))
+}
+
 // SetModuleForEach records that the given module call inside the given parent
 // module instance uses the "for_each" repetition argument, with the given
 // map value.
@@ -87,6 +95,14 @@ func (e *Expander) SetResourceCount(moduleAddr addrs.ModuleInstance, resourceAdd
 	e.setResourceExpansion(moduleAddr, resourceAddr, expansionCount(count))
 }
 
+// SetResourceEnabled records that the given resource inside the given module
+// uses the "enabled" lifecycle argument, with the given value. An enabled
+// resource has a single instance with no key, while a disabled one has no
+// instances at all.
+func (e *Expander) SetResourceEnabled(moduleAddr addrs.ModuleInstance, resourceAddr addrs.Resource, enabled bool) {
+	e.setResourceExpansion(moduleAddr, resourceAddr, expansionEnabled(enabled))
+}
+
 // SetResourceForEach records that the given resource inside the given module
 // uses the "for_each" repetition argument, with the given map value.
 //
@@ -0,0 +1,105 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package evalchecks
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// EvaluateEnabledExpression is our standard mechanism for interpreting an
+// expression given for the "enabled" lifecycle argument on a resource or a
+// module call. This should be called during expansion in order to determine
+// whether the object has a single instance or no instances at all.
+//
+// EvaluateEnabledExpression differs from EvaluateEnabledExpressionValue by
+// returning an error if the value is not known, and converting the
+// cty.Value to a bool.
+//
+// If excludableAddr is non-nil then the unknown value error will include
+// an additional idea to exclude that address using the -exclude
+// planning option to converge over multiple plan/apply rounds.
+func EvaluateEnabledExpression(expr hcl.Expression, ctx EvaluateFunc, excludableAddr addrs.Targetable) (bool, tfdiags.Diagnostics) {
+	enabledVal, diags := EvaluateEnabledExpressionValue(expr, ctx)
+	if !enabledVal.IsKnown() {
+		suggestion := enabledCommandLineExcludeSuggestion(excludableAddr, runtime.GOOS)
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid enabled argument",
+			Detail:   "The \"enabled\" value depends on resource attributes that cannot be determined until apply, so OpenTofu cannot predict whether an instance will be created.\n\n" + suggestion,
+			Subject:  expr.Range().Ptr(),
+			Extra:    DiagnosticCausedByUnknown(true),
+		})
+	}
+
+	if enabledVal.IsNull() || !enabledVal.IsKnown() {
+		return false, diags
+	}
+	return enabledVal.True(), diags
+}
+
+// EvaluateEnabledExpressionValue is like EvaluateEnabledExpression
+// except that it returns a cty.Value which must be a cty.Bool and can be
+// unknown.
+func EvaluateEnabledExpressionValue(expr hcl.Expression, ctx EvaluateFunc) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	nullEnabled := cty.NullVal(cty.Bool)
+	if expr == nil {
+		return nullEnabled, nil
+	}
+
+	enabledVal, enabledDiags := ctx(expr)
+	diags = diags.Append(enabledDiags)
+	if diags.HasErrors() {
+		return nullEnabled, diags
+	}
+
+	// As with count, sensitive values are allowed here because whether or not
+	// an object exists is not considered to disclose the value.
+	enabledVal, _ = enabledVal.Unmark()
+
+	enabledVal, err := convert.Convert(enabledVal, cty.Bool)
+	if err != nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid enabled argument",
+			Detail:   fmt.Sprintf(`The given "enabled" argument value is unsuitable: %s.`, tfdiags.FormatError(err)),
+			Subject:  expr.Range().Ptr(),
+		})
+		return nullEnabled, diags
+	}
+
+	if enabledVal.IsNull() {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid enabled argument",
+			Detail:   `The given "enabled" argument value is null. A boolean value is required.`,
+			Subject:  expr.Range().Ptr(),
+		})
+		return nullEnabled, diags
+	}
+
+	return enabledVal, diags
+}
+
+// enabledCommandLineExcludeSuggestion returns some English-language text
+// describing a workaround using the -exclude planning option to converge over
+// two plan/apply rounds when enabled has an unknown value.
+func enabledCommandLineExcludeSuggestion(excludableAddr addrs.Targetable, goos string) string {
+	if excludableAddr == nil {
+		return `To work around this, use the -target option to first apply only the resources that the enabled argument depends on, and then apply normally to converge.`
+	}
+
+	return fmt.Sprintf(
+		"To work around this, use the planning option -exclude=%s to first apply without this object, and then apply normally to converge.",
+		commandLineArgumentsSuggestion([]string{excludableAddr.String()}, goos),
+	)
+}
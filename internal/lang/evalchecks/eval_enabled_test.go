@@ -0,0 +1,116 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package evalchecks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hcltest"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestEvaluateEnabledExpression_valid(t *testing.T) {
+	tests := map[string]struct {
+		val  cty.Value
+		want bool
+	}{
+		"true": {
+			cty.True,
+			true,
+		},
+		"false": {
+			cty.False,
+			false,
+		},
+		"string": {
+			cty.StringVal("true"),
+			true,
+		},
+		"sensitive": {
+			cty.True.Mark(marks.Sensitive),
+			true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, diags := EvaluateEnabledExpression(hcltest.MockExprLiteral(test.val), mockEvaluateFunc(test.val), nil)
+			if len(diags) != 0 {
+				t.Fatalf("unexpected diagnostics: %s", diags.Err())
+			}
+			if got != test.want {
+				t.Errorf("wrong result %t; want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateEnabledExpression_errors(t *testing.T) {
+	tests := map[string]struct {
+		val                      cty.Value
+		excludableAddr           addrs.Targetable
+		Summary, DetailSubstring string
+		CausedByUnknown          bool
+	}{
+		"null": {
+			cty.NullVal(cty.Bool),
+			nil,
+			"Invalid enabled argument",
+			`The given "enabled" argument value is null. A boolean value is required.`,
+			false,
+		},
+		"number": {
+			cty.NumberIntVal(1),
+			nil,
+			"Invalid enabled argument",
+			`The given "enabled" argument value is unsuitable: bool required, but have number.`,
+			false,
+		},
+		"unknown": {
+			cty.UnknownVal(cty.Bool),
+			nil,
+			"Invalid enabled argument",
+			"The \"enabled\" value depends on resource attributes that cannot be determined until apply, so OpenTofu cannot predict whether an instance will be created.\n\nTo work around this, use the -target option to first apply only the resources that the enabled argument depends on, and then apply normally to converge.",
+			true,
+		},
+		"unknown with excludable address": {
+			cty.UnknownVal(cty.Bool),
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Name: "foo",
+				Type: "bar",
+			}.Absolute(addrs.RootModuleInstance.Child("a", addrs.NoKey)),
+			"Invalid enabled argument",
+			"To work around this, use the planning option -exclude=module.a.bar.foo to first apply without this object, and then apply normally to converge.",
+			true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, diags := EvaluateEnabledExpression(hcltest.MockExprLiteral(test.val), mockEvaluateFunc(test.val), test.excludableAddr)
+
+			if len(diags) != 1 {
+				t.Fatalf("got %d diagnostics; want 1", len(diags))
+			}
+			if got, want := diags[0].Severity(), tfdiags.Error; got != want {
+				t.Errorf("wrong diagnostic severity %#v; want %#v", got, want)
+			}
+			if got, want := diags[0].Description().Summary, test.Summary; got != want {
+				t.Errorf("wrong diagnostic summary\ngot:  %s\nwant: %s", got, want)
+			}
+			if got, want := diags[0].Description().Detail, test.DetailSubstring; !strings.Contains(got, want) {
+				t.Errorf("wrong diagnostic detail\ngot: %s\nwant substring: %s", got, want)
+			}
+			if got, want := tfdiags.DiagnosticCausedByUnknown(diags[0]), test.CausedByUnknown; got != want {
+				t.Errorf("wrong result from tfdiags.DiagnosticCausedByUnknown\ngot:  %#v\nwant: %#v", got, want)
+			}
+		})
+	}
+}
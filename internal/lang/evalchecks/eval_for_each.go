@@ -184,6 +184,20 @@ func performValueChecks(expr hcl.Expression, hclCtx *hcl.EvalContext, allowUnkno
 		resultVal = cty.NullVal(ty)
 	}
 
+	// Instance keys are saved in the state, so they must not be derived from
+	// ephemeral resources.
+	if forEachVal.HasMark(marks.Ephemeral) {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     "Invalid for_each argument",
+			Detail:      "Values derived from ephemeral resources cannot be used as for_each arguments. If used, the ephemeral value would be saved in the state as a resource instance key.",
+			Subject:     expr.Range().Ptr(),
+			Expression:  expr,
+			EvalContext: hclCtx,
+		})
+		resultVal = cty.NullVal(ty)
+	}
+
 	return resultVal, diags
 }
 
@@ -225,7 +239,10 @@ func performSetValueChecks(expr hcl.Expression, hclCtx *hcl.EvalContext, forEach
 
 	// A set of strings may contain null, which makes it impossible to
 	// convert to a map, so we must return an error
-	it := forEachVal.ElementIterator()
+	// The set itself may be marked, such as if it's derived from sensitive or
+	// ephemeral values, which the caller reports separately.
+	unmarkedVal, _ := forEachVal.Unmark()
+	it := unmarkedVal.ElementIterator()
 	for it.Next() {
 		item, _ := it.Element()
 		if item.IsNull() {
@@ -94,6 +94,20 @@ func TestEvaluateForEachExpression_multi_errors(t *testing.T) {
 				},
 			},
 		},
+		"ephemeral set": {
+			hcltest.MockExprLiteral(cty.SetVal([]cty.Value{cty.StringVal("a")}).Mark(marks.Ephemeral)),
+			[]struct {
+				Summary           string
+				DetailSubstring   string
+				CausedBySensitive bool
+			}{
+				{
+					"Invalid for_each argument",
+					"Values derived from ephemeral resources cannot be used as for_each arguments.",
+					false,
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -48,6 +48,10 @@ func Contains(val cty.Value, mark valueMark) bool {
 // OpenTofu.
 const Sensitive = valueMark("Sensitive")
 
+// Ephemeral indicates that this value was produced by an ephemeral resource
+// and so must never be persisted in a plan or state snapshot.
+const Ephemeral = valueMark("Ephemeral")
+
 // TypeType is used to indicate that the value contains a representation of
 // another value's type. This is part of the implementation of the console-only
 // `type` function.
@@ -6,6 +6,8 @@
 package moduletest
 
 import (
+	"time"
+
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -18,5 +20,9 @@ type File struct {
 
 	Runs []*Run
 
+	// Duration is the wall-clock time spent executing the run blocks within
+	// this file, excluding the cleanup of any created infrastructure.
+	Duration time.Duration
+
 	Diagnostics tfdiags.Diagnostics
 }
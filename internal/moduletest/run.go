@@ -7,6 +7,7 @@ package moduletest
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 
@@ -28,6 +29,10 @@ type Run struct {
 	Index  int
 	Status Status
 
+	// Duration is the wall-clock time spent executing this run block. It is
+	// zero for run blocks that were never executed.
+	Duration time.Duration
+
 	Diagnostics tfdiags.Diagnostics
 }
 
@@ -0,0 +1,47 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+// Package ocipush contains the parts of pushing content to an OCI repository
+// that are shared between publishing module packages and mirroring provider
+// packages.
+package ocipush
+
+import (
+	"context"
+	"io"
+
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// RepositoryStore is the interface used to add content to a specific OCI
+// repository.
+//
+// As with the interfaces used for installing modules and providers from OCI
+// repositories, this is intentionally a subset of the interfaces defined in
+// the ORAS-Go library.
+type RepositoryStore interface {
+	// Exists returns true if the blob or manifest described by the given
+	// descriptor is already present in the repository.
+	Exists(ctx context.Context, target ociv1.Descriptor) (bool, error)
+
+	// Push uploads the given content to the repository as the blob or
+	// manifest described by the given descriptor.
+	Push(ctx context.Context, expected ociv1.Descriptor, content io.Reader) error
+
+	// Tag associates the given tag name with the manifest described by the
+	// given descriptor, replacing any existing association for that tag.
+	Tag(ctx context.Context, desc ociv1.Descriptor, reference string) error
+}
+
+// PushContent uploads the given content as the blob or manifest described by
+// the given descriptor, unless the repository already has it.
+func PushContent(ctx context.Context, store RepositoryStore, desc ociv1.Descriptor, content io.Reader) error {
+	exists, err := store.Exists(ctx, desc)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	return store.Push(ctx, desc, content)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package ocipush
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	ociDigest "github.com/opencontainers/go-digest"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestPushContent(t *testing.T) {
+	content := []byte("hello")
+	desc := ociv1.Descriptor{
+		MediaType: "application/octet-stream",
+		Digest:    ociDigest.FromBytes(content),
+		Size:      int64(len(content)),
+	}
+	store := &testStore{blobs: make(map[ociDigest.Digest][]byte)}
+
+	if err := PushContent(context.Background(), store, desc, bytes.NewReader(content)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := store.pushes, 1; got != want {
+		t.Fatalf("wrong number of pushes %d; want %d", got, want)
+	}
+	if got := store.blobs[desc.Digest]; !bytes.Equal(got, content) {
+		t.Fatalf("wrong content %q; want %q", got, content)
+	}
+
+	// Content that is already present must not be uploaded again.
+	if err := PushContent(context.Background(), store, desc, bytes.NewReader(content)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := store.pushes, 1; got != want {
+		t.Fatalf("wrong number of pushes %d after pushing existing content; want %d", got, want)
+	}
+}
+
+type testStore struct {
+	blobs  map[ociDigest.Digest][]byte
+	pushes int
+}
+
+func (s *testStore) Exists(_ context.Context, target ociv1.Descriptor) (bool, error) {
+	_, ok := s.blobs[target.Digest]
+	return ok, nil
+}
+
+func (s *testStore) Push(_ context.Context, expected ociv1.Descriptor, content io.Reader) error {
+	src, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	s.blobs[expected.Digest] = src
+	s.pushes++
+	return nil
+}
+
+func (s *testStore) Tag(context.Context, ociv1.Descriptor, string) error {
+	return nil
+}
@@ -317,6 +317,50 @@ func TestAssertPlanValid(t *testing.T) {
 			}),
 			nil,
 		},
+		"write-only, null in plan": {
+			&configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"a": {
+						Type:      cty.String,
+						Optional:  true,
+						WriteOnly: true,
+					},
+				},
+			},
+			cty.NullVal(cty.Object(map[string]cty.Type{
+				"a": cty.String,
+			})),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("secret"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.NullVal(cty.String),
+			}),
+			nil,
+		},
+		"write-only, value in plan": {
+			&configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"a": {
+						Type:      cty.String,
+						Optional:  true,
+						WriteOnly: true,
+					},
+				},
+			},
+			cty.NullVal(cty.Object(map[string]cty.Type{
+				"a": cty.String,
+			})),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("secret"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("secret"),
+			}),
+			[]string{
+				`.a: planned value for write-only attribute is not null`,
+			},
+		},
 		"nested map, normal update": {
 			&configschema.Block{
 				BlockTypes: map[string]*configschema.NestedBlock{
@@ -20,6 +20,7 @@ import (
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	tfversion "github.com/opentofu/opentofu/version"
@@ -95,6 +96,17 @@ func TestRoundtrip(t *testing.T) {
 		},
 	)
 
+	policiesIn := []*policy.File{
+		{
+			Filename: filepath.Join("policies", "main.tfpolicy.hcl"),
+			Source: []byte(`policy "tags" {
+  condition     = true
+  error_message = "Resources must be tagged."
+}
+`),
+		},
+	}
+
 	planFn := filepath.Join(t.TempDir(), "tfplan")
 
 	err = Create(planFn, CreateArgs{
@@ -103,6 +115,7 @@ func TestRoundtrip(t *testing.T) {
 		StateFile:            stateFileIn,
 		Plan:                 planIn,
 		DependencyLocks:      locksIn,
+		Policies:             policiesIn,
 	}, encryption.PlanEncryptionDisabled())
 	if err != nil {
 		t.Fatalf("failed to create plan file: %s", err)
@@ -181,6 +194,19 @@ func TestRoundtrip(t *testing.T) {
 			t.Errorf("provider locks did not survive round-trip\n%s", diff)
 		}
 	})
+
+	t.Run("ReadPolicies", func(t *testing.T) {
+		policiesOut, diags := pr.ReadPolicies()
+		if diags.HasErrors() {
+			t.Fatalf("when reading policies: %s", diags.Err())
+		}
+		if len(policiesOut) != 1 || policiesOut[0].Name != "tags" {
+			t.Fatalf("wrong policies: %#v", policiesOut)
+		}
+		if diff := cmp.Diff(policiesIn, policy.Files(policiesOut)); diff != "" {
+			t.Errorf("policies did not survive round-trip\n%s", diff)
+		}
+	})
 }
 
 func TestWrappedError(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -18,6 +19,7 @@ import (
 	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -25,6 +27,7 @@ import (
 const tfstateFilename = "tfstate"
 const tfstatePreviousFilename = "tfstate-prev"
 const dependencyLocksFilename = ".terraform.lock.hcl" // matches the conventional name in an input configuration
+const policiesFilename = "tfpolicy.json"
 
 // ErrUnusableLocalPlan is an error wrapper to indicate that we *think* the
 // input represents plan file data, but can't use it for some reason (as
@@ -278,3 +281,52 @@ func (r *Reader) ReadDependencyLocks() (*depsfile.Locks, tfdiags.Diagnostics) {
 	))
 	return nil, diags
 }
+
+// ReadPolicies reads the policies that were evaluated against the plan when
+// it was created.
+//
+// Plan files created without any policies contain no policy information, in
+// which case this returns no policies and no diagnostics.
+func (r *Reader) ReadPolicies() ([]*policy.Policy, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	for _, file := range r.zip.File {
+		if file.Name != policiesFilename {
+			continue
+		}
+
+		r, err := file.Open()
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read policies from plan file",
+				fmt.Sprintf("Couldn't read the policies embedded in the plan file: %s.", err),
+			))
+			return nil, diags
+		}
+		defer r.Close()
+
+		var raw []policyFileJSON
+		if err := json.NewDecoder(r).Decode(&raw); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read policies from plan file",
+				fmt.Sprintf("Couldn't read the policies embedded in the plan file: %s.", err),
+			))
+			return nil, diags
+		}
+
+		files := make([]*policy.File, len(raw))
+		for i, f := range raw {
+			files[i] = &policy.File{
+				Filename: f.Filename,
+				Source:   []byte(f.Source),
+			}
+		}
+		policies, moreDiags := policy.LoadFiles(files)
+		diags = diags.Append(moreDiags)
+		return policies, diags
+	}
+
+	return nil, diags
+}
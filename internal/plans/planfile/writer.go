@@ -8,7 +8,9 @@ package planfile
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 )
 
@@ -46,6 +49,11 @@ type CreateArgs struct {
 	// checked prior to creating the plan, so we can make sure that all of the
 	// same dependencies are still available when applying the plan.
 	DependencyLocks *depsfile.Locks
+
+	// Policies records the source of the policy files that were evaluated
+	// against the plan, so that the same policies are evaluated again when
+	// the plan is applied.
+	Policies []*policy.File
 }
 
 // Create creates a new plan file with the given filename, overwriting any
@@ -136,6 +144,22 @@ func Create(filename string, args CreateArgs, enc encryption.PlanEncryption) err
 		}
 	}
 
+	// tfpolicy.json file, containing the policy files evaluated against the plan
+	if len(args.Policies) != 0 {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     policiesFilename,
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create embedded policies file: %w", err)
+		}
+		err = writePolicies(args.Policies, w)
+		if err != nil {
+			return fmt.Errorf("failed to write embedded policies file: %w", err)
+		}
+	}
+
 	// Finish zip file
 	zw.Close()
 	// Encrypt payload
@@ -145,3 +169,19 @@ func Create(filename string, args CreateArgs, enc encryption.PlanEncryption) err
 	}
 	return os.WriteFile(filename, encrypted, 0644)
 }
+
+type policyFileJSON struct {
+	Filename string `json:"filename"`
+	Source   string `json:"source"`
+}
+
+func writePolicies(files []*policy.File, w io.Writer) error {
+	raw := make([]policyFileJSON, len(files))
+	for i, f := range files {
+		raw[i] = policyFileJSON{
+			Filename: f.Filename,
+			Source:   string(f.Source),
+		}
+	}
+	return json.NewEncoder(w).Encode(raw)
+}
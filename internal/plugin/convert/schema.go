@@ -35,6 +35,7 @@ func ConfigSchemaToProto(b *configschema.Block) *proto.Schema_Block {
 			Computed:        a.Computed,
 			Required:        a.Required,
 			Sensitive:       a.Sensitive,
+			WriteOnly:       a.WriteOnly,
 			Deprecated:      a.Deprecated,
 		}
 
@@ -118,6 +119,7 @@ func ProtoToConfigSchema(b *proto.Schema_Block) *configschema.Block {
 			Optional:        a.Optional,
 			Computed:        a.Computed,
 			Sensitive:       a.Sensitive,
+			WriteOnly:       a.WriteOnly,
 			Deprecated:      a.Deprecated,
 		}
 
@@ -51,6 +51,12 @@ func TestConvertSchemaBlocks(t *testing.T) {
 						Type:     []byte(`"number"`),
 						Required: true,
 					},
+					{
+						Name:      "write_only",
+						Type:      []byte(`"string"`),
+						Optional:  true,
+						WriteOnly: true,
+					},
 				},
 			},
 			&configschema.Block{
@@ -72,6 +78,11 @@ func TestConvertSchemaBlocks(t *testing.T) {
 						Type:     cty.Number,
 						Required: true,
 					},
+					"write_only": {
+						Type:      cty.String,
+						Optional:  true,
+						WriteOnly: true,
+					},
 				},
 			},
 		},
@@ -223,6 +234,12 @@ func TestConvertProtoSchemaBlocks(t *testing.T) {
 						Type:     []byte(`"number"`),
 						Required: true,
 					},
+					{
+						Name:      "write_only",
+						Type:      []byte(`"string"`),
+						Optional:  true,
+						WriteOnly: true,
+					},
 				},
 			},
 			&configschema.Block{
@@ -244,6 +261,11 @@ func TestConvertProtoSchemaBlocks(t *testing.T) {
 						Type:     cty.Number,
 						Required: true,
 					},
+					"write_only": {
+						Type:      cty.String,
+						Optional:  true,
+						WriteOnly: true,
+					},
 				},
 			},
 		},
@@ -36,6 +36,7 @@ func ConfigSchemaToProto(b *configschema.Block) *proto.Schema_Block {
 			Computed:        a.Computed,
 			Required:        a.Required,
 			Sensitive:       a.Sensitive,
+			WriteOnly:       a.WriteOnly,
 			Deprecated:      a.Deprecated,
 		}
 
@@ -124,6 +125,7 @@ func ProtoToConfigSchema(b *proto.Schema_Block) *configschema.Block {
 			Optional:        a.Optional,
 			Computed:        a.Computed,
 			Sensitive:       a.Sensitive,
+			WriteOnly:       a.WriteOnly,
 			Deprecated:      a.Deprecated,
 		}
 
@@ -214,6 +216,7 @@ func protoObjectToConfigSchema(b *proto.Schema_Object) *configschema.Object {
 			Optional:        a.Optional,
 			Computed:        a.Computed,
 			Sensitive:       a.Sensitive,
+			WriteOnly:       a.WriteOnly,
 			Deprecated:      a.Deprecated,
 		}
 
@@ -277,6 +280,7 @@ func configschemaObjectToProto(b *configschema.Object) *proto.Schema_Object {
 			Computed:        a.Computed,
 			Required:        a.Required,
 			Sensitive:       a.Sensitive,
+			WriteOnly:       a.WriteOnly,
 			Deprecated:      a.Deprecated,
 		}
 
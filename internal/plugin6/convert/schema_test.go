@@ -51,6 +51,12 @@ func TestConvertSchemaBlocks(t *testing.T) {
 						Type:     []byte(`"number"`),
 						Required: true,
 					},
+					{
+						Name:      "write_only",
+						Type:      []byte(`"string"`),
+						Optional:  true,
+						WriteOnly: true,
+					},
 					{
 						Name: "nested_type",
 						NestedType: &proto.Schema_Object{
@@ -183,6 +189,11 @@ func TestConvertSchemaBlocks(t *testing.T) {
 						Type:     cty.Number,
 						Required: true,
 					},
+					"write_only": {
+						Type:      cty.String,
+						Optional:  true,
+						WriteOnly: true,
+					},
 					"nested_type": {
 						NestedType: &configschema.Object{
 							Attributes: map[string]*configschema.Attribute{
@@ -428,6 +439,12 @@ func TestConvertProtoSchemaBlocks(t *testing.T) {
 						Type:     []byte(`"number"`),
 						Required: true,
 					},
+					{
+						Name:      "write_only",
+						Type:      []byte(`"string"`),
+						Optional:  true,
+						WriteOnly: true,
+					},
 				},
 			},
 			&configschema.Block{
@@ -449,6 +466,11 @@ func TestConvertProtoSchemaBlocks(t *testing.T) {
 						Type:     cty.Number,
 						Required: true,
 					},
+					"write_only": {
+						Type:      cty.String,
+						Optional:  true,
+						WriteOnly: true,
+					},
 				},
 			},
 		},
@@ -0,0 +1,371 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Status is the outcome of evaluating a single policy.
+type Status string
+
+const (
+	Pass  Status = "pass"
+	Fail  Status = "fail"
+	Error Status = "error"
+)
+
+// Result describes the outcome of evaluating a single policy.
+type Result struct {
+	Policy *Policy
+	Status Status
+
+	// Message is the evaluated error message of a failed policy.
+	Message string
+
+	// diags describes any problems encountered while evaluating the policy,
+	// which are the reason for the Error status.
+	diags tfdiags.Diagnostics
+}
+
+// Evaluate evaluates each of the given policies against the given plan,
+// which is usually the result of PlanValue.
+//
+// The plan is available to the policy expressions as the "plan" variable.
+func Evaluate(policies []*Policy, plan cty.Value) []*Result {
+	results := make([]*Result, 0, len(policies))
+	for _, p := range policies {
+		results = append(results, evaluatePolicy(p, plan))
+	}
+	return results
+}
+
+func evaluatePolicy(p *Policy, plan cty.Value) *Result {
+	result := &Result{
+		Policy: p,
+		Status: Pass,
+	}
+
+	scope := &lang.Scope{
+		BaseDir: p.BaseDir,
+	}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"plan": plan,
+		},
+		Functions: scope.Functions(),
+	}
+
+	val, hclDiags := p.Condition.Value(ctx)
+	if !hclDiags.HasErrors() {
+		var err error
+		val, err = convert.Convert(val, cty.Bool)
+		if err != nil {
+			hclDiags = hclDiags.Append(&hcl.Diagnostic{
+				Severity:    hcl.DiagError,
+				Summary:     "Invalid policy condition",
+				Detail:      fmt.Sprintf("Invalid condition result value: %s.", tfdiags.FormatError(err)),
+				Subject:     p.Condition.Range().Ptr(),
+				Expression:  p.Condition,
+				EvalContext: ctx,
+			})
+		} else if val.IsNull() || !val.IsKnown() {
+			hclDiags = hclDiags.Append(&hcl.Diagnostic{
+				Severity:    hcl.DiagError,
+				Summary:     "Invalid policy condition",
+				Detail:      "The condition value must be either true or false.",
+				Subject:     p.Condition.Range().Ptr(),
+				Expression:  p.Condition,
+				EvalContext: ctx,
+			})
+		}
+	}
+	result.diags = result.diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		result.Status = Error
+		return result
+	}
+
+	// A condition that refers to sensitive values still decides the outcome,
+	// since the result itself doesn't reveal them.
+	val, _ = val.Unmark()
+	if val.True() {
+		return result
+	}
+
+	result.Status = Fail
+	result.Message = "The plan does not satisfy this policy."
+
+	msgVal, hclDiags := p.ErrorMessage.Value(ctx)
+	if !hclDiags.HasErrors() {
+		var err error
+		msgVal, err = convert.Convert(msgVal, cty.String)
+		if err != nil || msgVal.IsNull() || !msgVal.IsKnown() {
+			hclDiags = hclDiags.Append(&hcl.Diagnostic{
+				Severity:    hcl.DiagError,
+				Summary:     "Invalid policy error message",
+				Detail:      "The error message must be a string.",
+				Subject:     p.ErrorMessage.Range().Ptr(),
+				Expression:  p.ErrorMessage,
+				EvalContext: ctx,
+			})
+		}
+	}
+	if !hclDiags.HasErrors() && marks.Has(msgVal, marks.Sensitive) {
+		hclDiags = hclDiags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Error message refers to sensitive values",
+			Detail: `The error expression used to explain this policy failure refers to sensitive values, so OpenTofu will not display the resulting message.
+
+You can correct this by removing references to sensitive values, or by carefully using the nonsensitive() function if the expression will not reveal the sensitive data.`,
+			Subject:     p.ErrorMessage.Range().Ptr(),
+			Expression:  p.ErrorMessage,
+			EvalContext: ctx,
+		})
+	}
+	if hclDiags.HasErrors() {
+		// The policy still failed, so we report the problem with its message
+		// alongside the generic message above.
+		result.diags = result.diags.Append(hclDiags)
+		return result
+	}
+	msgVal, _ = msgVal.Unmark()
+	result.Message = strings.TrimSpace(msgVal.AsString())
+	return result
+}
+
+// Diagnostics returns the diagnostics that describe the outcome of the
+// policy, if it didn't pass.
+//
+// Failures of mandatory policies are errors, while failures of advisory
+// policies are only warnings.
+func (r *Result) Diagnostics() tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	severity := hcl.DiagError
+	if r.Policy.EnforcementLevel == Advisory {
+		severity = hcl.DiagWarning
+	}
+
+	for _, diag := range r.diags {
+		// Problems evaluating an advisory policy are never fatal.
+		if diag.Severity() == tfdiags.Error && severity == hcl.DiagWarning {
+			desc := diag.Description()
+			warning := &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  desc.Summary,
+				Detail:   desc.Detail,
+			}
+			if subject := diag.Source().Subject; subject != nil {
+				warning.Subject = subject.ToHCL().Ptr()
+			}
+			diags = diags.Append(warning)
+			continue
+		}
+		diags = diags.Append(diag)
+	}
+
+	if r.Status == Fail {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: severity,
+			Summary:  fmt.Sprintf("Policy %q failed", r.Policy.Name),
+			Detail:   fmt.Sprintf("%s\n\nThe enforcement level of this policy is %s.", r.Message, r.Policy.EnforcementLevel),
+			Subject:  r.Policy.Condition.Range().Ptr(),
+		})
+	}
+
+	return diags
+}
+
+// Diagnostics returns the diagnostics of all of the given results.
+func Diagnostics(results []*Result) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for _, r := range results {
+		diags = diags.Append(r.Diagnostics())
+	}
+	return diags
+}
+
+// PlanValue converts the JSON representation of a plan, as produced by
+// jsonplan.Marshal, into the value that policies are evaluated against.
+//
+// The JSON representation omits the properties that would be empty, so we
+// add them back here so that policies don't need to handle their absence.
+//
+// The JSON representation also includes the values of sensitive attributes,
+// outputs and variables, describing which ones are sensitive alongside them.
+// We mark those values as sensitive so that policies can still make decisions
+// based on them but can't reveal them in their error messages.
+func PlanValue(planJSON []byte) (cty.Value, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(planJSON, &raw); err != nil {
+		return cty.NilVal, err
+	}
+
+	defaults := map[string]interface{}{
+		"resource_drift":   []interface{}{},
+		"resource_changes": []interface{}{},
+		"output_changes":   map[string]interface{}{},
+		"prior_state":      nil,
+	}
+	for k, v := range defaults {
+		if _, ok := raw[k]; !ok {
+			raw[k] = v
+		}
+	}
+
+	src, err := json.Marshal(raw)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	ty, err := ctyjson.ImpliedType(src)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	val, err := ctyjson.Unmarshal(src, ty)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	var pvm []cty.PathValueMarks
+	for _, path := range sensitivePlanPaths(raw) {
+		pvm = append(pvm, cty.PathValueMarks{
+			Path:  path,
+			Marks: cty.NewValueMarks(marks.Sensitive),
+		})
+	}
+	return val.MarkWithPaths(pvm), nil
+}
+
+// sensitivePlanPaths returns the paths of all of the values in the given
+// JSON plan that the plan itself describes as sensitive.
+func sensitivePlanPaths(raw map[string]interface{}) []cty.Path {
+	var paths []cty.Path
+
+	for _, key := range []string{"resource_changes", "resource_drift"} {
+		changes, _ := raw[key].([]interface{})
+		for i, rc := range changes {
+			rc, _ := rc.(map[string]interface{})
+			change, _ := rc["change"].(map[string]interface{})
+			path := cty.GetAttrPath(key).IndexInt(i).GetAttr("change")
+			paths = append(paths, sensitiveChangePaths(path, change)...)
+		}
+	}
+
+	outputChanges, _ := raw["output_changes"].(map[string]interface{})
+	for name, change := range outputChanges {
+		change, _ := change.(map[string]interface{})
+		path := cty.GetAttrPath("output_changes").GetAttr(name)
+		paths = append(paths, sensitiveChangePaths(path, change)...)
+	}
+
+	if values, ok := raw["planned_values"].(map[string]interface{}); ok {
+		paths = append(paths, sensitiveStateValuesPaths(cty.GetAttrPath("planned_values"), values)...)
+	}
+	if state, ok := raw["prior_state"].(map[string]interface{}); ok {
+		if values, ok := state["values"].(map[string]interface{}); ok {
+			path := cty.GetAttrPath("prior_state").GetAttr("values")
+			paths = append(paths, sensitiveStateValuesPaths(path, values)...)
+		}
+	}
+
+	// Variable values don't describe their own sensitivity, so we take it
+	// from the root module configuration instead.
+	config, _ := raw["configuration"].(map[string]interface{})
+	rootModule, _ := config["root_module"].(map[string]interface{})
+	decls, _ := rootModule["variables"].(map[string]interface{})
+	vars, _ := raw["variables"].(map[string]interface{})
+	for name := range vars {
+		decl, _ := decls[name].(map[string]interface{})
+		if sensitive, _ := decl["sensitive"].(bool); sensitive {
+			paths = append(paths, cty.GetAttrPath("variables").GetAttr(name).GetAttr("value"))
+		}
+	}
+
+	return paths
+}
+
+// sensitiveChangePaths returns the paths of the sensitive values of the
+// given change, which are described by its before_sensitive and
+// after_sensitive properties.
+func sensitiveChangePaths(path cty.Path, change map[string]interface{}) []cty.Path {
+	var paths []cty.Path
+	for _, key := range []string{"before", "after"} {
+		paths = append(paths, sensitiveValuePaths(path.GetAttr(key), change[key+"_sensitive"])...)
+	}
+	return paths
+}
+
+// sensitiveStateValuesPaths returns the paths of the sensitive values of the
+// given state values, which are either the planned values or the values of
+// the prior state.
+func sensitiveStateValuesPaths(path cty.Path, values map[string]interface{}) []cty.Path {
+	var paths []cty.Path
+
+	outputs, _ := values["outputs"].(map[string]interface{})
+	for name, output := range outputs {
+		output, _ := output.(map[string]interface{})
+		if sensitive, _ := output["sensitive"].(bool); sensitive {
+			paths = append(paths, path.GetAttr("outputs").GetAttr(name).GetAttr("value"))
+		}
+	}
+
+	if module, ok := values["root_module"].(map[string]interface{}); ok {
+		paths = append(paths, sensitiveModulePaths(path.GetAttr("root_module"), module)...)
+	}
+	return paths
+}
+
+func sensitiveModulePaths(path cty.Path, module map[string]interface{}) []cty.Path {
+	var paths []cty.Path
+
+	resources, _ := module["resources"].([]interface{})
+	for i, r := range resources {
+		r, _ := r.(map[string]interface{})
+		paths = append(paths, sensitiveValuePaths(path.GetAttr("resources").IndexInt(i).GetAttr("values"), r["sensitive_values"])...)
+	}
+
+	children, _ := module["child_modules"].([]interface{})
+	for i, child := range children {
+		child, _ := child.(map[string]interface{})
+		paths = append(paths, sensitiveModulePaths(path.GetAttr("child_modules").IndexInt(i), child)...)
+	}
+	return paths
+}
+
+// sensitiveValuePaths returns the paths of the sensitive values described by
+// the given JSON value, which has the same structure as the value at the
+// given path but with each sensitive value replaced by true.
+func sensitiveValuePaths(path cty.Path, sensitive interface{}) []cty.Path {
+	switch sensitive := sensitive.(type) {
+	case bool:
+		if sensitive {
+			return []cty.Path{path}
+		}
+	case map[string]interface{}:
+		var paths []cty.Path
+		for k, v := range sensitive {
+			paths = append(paths, sensitiveValuePaths(path.GetAttr(k), v)...)
+		}
+		return paths
+	case []interface{}:
+		var paths []cty.Path
+		for i, v := range sensitive {
+			paths = append(paths, sensitiveValuePaths(path.IndexInt(i), v)...)
+		}
+		return paths
+	}
+	return nil
+}
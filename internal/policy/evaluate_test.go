@@ -0,0 +1,229 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+const testPlanJSON = `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {
+      "address": "test_instance.a",
+      "change": {"actions": ["create"], "after": {"tags": {"team": "a"}}}
+    },
+    {
+      "address": "test_instance.b",
+      "change": {"actions": ["delete"], "after": null}
+    }
+  ]
+}`
+
+func TestEvaluate(t *testing.T) {
+	plan, err := PlanValue([]byte(testPlanJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		level     EnforcementLevel
+		condition string
+		message   string
+
+		wantStatus   Status
+		wantMessage  string
+		wantSeverity tfdiags.Severity
+		wantDiag     string
+	}{
+		"pass": {
+			level:      Mandatory,
+			condition:  `length(plan.resource_changes) == 2`,
+			message:    `"unused"`,
+			wantStatus: Pass,
+		},
+		"mandatory failure": {
+			level:        Mandatory,
+			condition:    `alltrue([for rc in plan.resource_changes : !contains(rc.change.actions, "delete")])`,
+			message:      `"${plan.resource_changes[1].address} is deleted."`,
+			wantStatus:   Fail,
+			wantMessage:  "test_instance.b is deleted.",
+			wantSeverity: tfdiags.Error,
+			wantDiag:     `Policy "test" failed`,
+		},
+		"advisory failure": {
+			level:        Advisory,
+			condition:    `false`,
+			message:      `"Advice."`,
+			wantStatus:   Fail,
+			wantMessage:  "Advice.",
+			wantSeverity: tfdiags.Warning,
+			wantDiag:     `Policy "test" failed`,
+		},
+		"invalid message": {
+			level:        Mandatory,
+			condition:    `false`,
+			message:      `plan.nope`,
+			wantStatus:   Fail,
+			wantMessage:  "The plan does not satisfy this policy.",
+			wantSeverity: tfdiags.Error,
+			wantDiag:     "Unsupported attribute",
+		},
+		"mandatory error": {
+			level:        Mandatory,
+			condition:    `"maybe"`,
+			message:      `"unused"`,
+			wantStatus:   Error,
+			wantSeverity: tfdiags.Error,
+			wantDiag:     "Invalid policy condition",
+		},
+		"advisory error": {
+			level:        Advisory,
+			condition:    `plan.nope`,
+			message:      `"unused"`,
+			wantStatus:   Error,
+			wantSeverity: tfdiags.Warning,
+			wantDiag:     "Unsupported attribute",
+		},
+		"empty prior state": {
+			level:      Mandatory,
+			condition:  `plan.prior_state == null && length(plan.output_changes) == 0`,
+			message:    `"unused"`,
+			wantStatus: Pass,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &Policy{
+				Name:             "test",
+				EnforcementLevel: test.level,
+				Condition:        testExpr(t, test.condition),
+				ErrorMessage:     testExpr(t, test.message),
+			}
+
+			results := Evaluate([]*Policy{p}, plan)
+			if len(results) != 1 {
+				t.Fatalf("expected 1 result, got %d", len(results))
+			}
+			result := results[0]
+			if result.Status != test.wantStatus {
+				t.Errorf("wrong status %q; want %q", result.Status, test.wantStatus)
+			}
+			if result.Message != test.wantMessage {
+				t.Errorf("wrong message %q; want %q", result.Message, test.wantMessage)
+			}
+
+			diags := Diagnostics(results)
+			if test.wantDiag == "" {
+				if len(diags) != 0 {
+					t.Fatalf("unexpected diagnostics: %s", diags.ErrWithWarnings())
+				}
+				return
+			}
+			found := false
+			for _, diag := range diags {
+				if diag.Severity() != test.wantSeverity {
+					t.Errorf("wrong severity for %q", diag.Description().Summary)
+				}
+				if strings.Contains(diag.Description().Summary, test.wantDiag) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("missing diagnostic %q in:\n%s", test.wantDiag, diags.ErrWithWarnings())
+			}
+		})
+	}
+}
+
+func testExpr(t *testing.T, src string) hcl.Expression {
+	t.Helper()
+	expr, diags := hclsyntax.ParseExpression([]byte(src), "test.tfpolicy.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	return expr
+}
+
+func TestEvaluate_sensitive(t *testing.T) {
+	plan, err := PlanValue([]byte(`{
+  "format_version": "1.2",
+  "variables": {"token": {"value": "hunter3"}},
+  "resource_changes": [
+    {
+      "address": "test_instance.a",
+      "change": {
+        "actions": ["create"],
+        "after": {"id": "a", "password": "hunter2"},
+        "after_sensitive": {"password": true}
+      }
+    }
+  ],
+  "configuration": {
+    "root_module": {"variables": {"token": {"sensitive": true}}}
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		condition string
+		message   string
+		secret    string
+	}{
+		"attribute": {
+			condition: `length(plan.resource_changes[0].change.after.password) > 10`,
+			message:   `"bad password ${plan.resource_changes[0].change.after.password}"`,
+			secret:    "hunter2",
+		},
+		"variable": {
+			condition: `plan.variables.token.value == "nope"`,
+			message:   `"bad token ${plan.variables.token.value}"`,
+			secret:    "hunter3",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &Policy{
+				Name:             "test",
+				EnforcementLevel: Mandatory,
+				Condition:        testExpr(t, test.condition),
+				ErrorMessage:     testExpr(t, test.message),
+			}
+
+			results := Evaluate([]*Policy{p}, plan)
+			result := results[0]
+			if result.Status != Fail {
+				t.Fatalf("wrong status %q; want %q\n%s", result.Status, Fail, Diagnostics(results).ErrWithWarnings())
+			}
+			if strings.Contains(result.Message, test.secret) {
+				t.Errorf("message reveals sensitive value: %s", result.Message)
+			}
+
+			diags := Diagnostics(results)
+			found := false
+			for _, diag := range diags {
+				desc := diag.Description()
+				if strings.Contains(desc.Summary, test.secret) || strings.Contains(desc.Detail, test.secret) {
+					t.Errorf("diagnostic reveals sensitive value: %s: %s", desc.Summary, desc.Detail)
+				}
+				if desc.Summary == "Error message refers to sensitive values" {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("missing sensitive error message diagnostic in:\n%s", diags.ErrWithWarnings())
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// FileExtension is the suffix of the files that LoadPaths loads policies from
+// when it is given a directory.
+const FileExtension = ".tfpolicy.hcl"
+
+// EnforcementLevel decides what happens when a policy fails.
+type EnforcementLevel string
+
+const (
+	// Advisory policies produce a warning when they fail, but allow the
+	// operation to continue.
+	Advisory EnforcementLevel = "advisory"
+
+	// Mandatory policies produce an error when they fail, which prevents the
+	// plan from being applied.
+	Mandatory EnforcementLevel = "mandatory"
+)
+
+// Policy is a single rule that a plan must satisfy, declared by a "policy"
+// block in a policy file.
+type Policy struct {
+	Name             string
+	EnforcementLevel EnforcementLevel
+
+	// Condition must evaluate to true for the plan to satisfy the policy.
+	// ErrorMessage is evaluated only when it doesn't, to describe why.
+	Condition    hcl.Expression
+	ErrorMessage hcl.Expression
+
+	// BaseDir is the directory containing the file that declared the policy,
+	// which is the base directory for any functions that accept filesystem
+	// paths as arguments.
+	BaseDir string
+
+	// File is the policy file that declared the policy, which is recorded in
+	// saved plan files so that the policy is evaluated again when the plan is
+	// applied.
+	File *File
+
+	DeclRange hcl.Range
+}
+
+// File is the source code of a policy file.
+type File struct {
+	Filename string
+	Source   []byte
+}
+
+// Files returns the distinct files that declared the given policies, in the
+// order in which the policies were declared.
+func Files(policies []*Policy) []*File {
+	var files []*File
+	seen := make(map[*File]bool)
+	for _, p := range policies {
+		if p.File == nil || seen[p.File] {
+			continue
+		}
+		seen[p.File] = true
+		files = append(files, p.File)
+	}
+	return files
+}
+
+var policyFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "policy",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+var policyBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "condition", Required: true},
+		{Name: "error_message", Required: true},
+		{Name: "enforcement_level"},
+	},
+}
+
+// LoadPaths loads all of the policies declared in the given paths.
+//
+// Each path may be either a policy file or a directory, in which case all of
+// the files directly within it whose names end with FileExtension are loaded
+// in lexical order.
+func LoadPaths(paths []string) ([]*Policy, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var filenames []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read policy path",
+				fmt.Sprintf("Cannot read policies from %s: %s.", path, err),
+			))
+			continue
+		}
+		if !info.IsDir() {
+			filenames = append(filenames, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read policy directory",
+				fmt.Sprintf("Cannot read policies from %s: %s.", path, err),
+			))
+			continue
+		}
+		var dirFilenames []string
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), FileExtension) {
+				continue
+			}
+			dirFilenames = append(dirFilenames, filepath.Join(path, entry.Name()))
+		}
+		sort.Strings(dirFilenames)
+		filenames = append(filenames, dirFilenames...)
+	}
+
+	var files []*File
+	for _, filename := range filenames {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read policy file",
+				fmt.Sprintf("Cannot read policies from %s: %s.", filename, err),
+			))
+			continue
+		}
+		files = append(files, &File{Filename: filename, Source: src})
+	}
+
+	policies, moreDiags := LoadFiles(files)
+	return policies, diags.Append(moreDiags)
+}
+
+// Merge returns the policies in a followed by the policies in b, except for
+// the policies in b that were declared by a file with the same filename and
+// source as a file that declared policies in a.
+func Merge(a, b []*Policy) []*Policy {
+	type fileKey struct {
+		filename, source string
+	}
+	seen := make(map[fileKey]bool)
+	for _, p := range a {
+		if p.File != nil {
+			seen[fileKey{p.File.Filename, string(p.File.Source)}] = true
+		}
+	}
+
+	ret := append([]*Policy(nil), a...)
+	for _, p := range b {
+		if p.File != nil && seen[fileKey{p.File.Filename, string(p.File.Source)}] {
+			continue
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+// LoadFiles loads all of the policies declared in the given policy files, such
+// as the files recorded in a saved plan file.
+func LoadFiles(files []*File) ([]*Policy, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	parser := hclparse.NewParser()
+	var policies []*Policy
+	seen := make(map[string]*Policy)
+	for _, f := range files {
+		file, hclDiags := parser.ParseHCL(f.Source, f.Filename)
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() {
+			continue
+		}
+
+		filePolicies, moreDiags := decodePolicyFile(file, f)
+		diags = diags.Append(moreDiags)
+		for _, p := range filePolicies {
+			if existing, ok := seen[p.Name]; ok {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate policy",
+					Detail:   fmt.Sprintf("A policy named %q was already declared at %s. Policy names must be unique.", p.Name, existing.DeclRange),
+					Subject:  p.DeclRange.Ptr(),
+				})
+				continue
+			}
+			seen[p.Name] = p
+			policies = append(policies, p)
+		}
+	}
+
+	return policies, diags
+}
+
+func decodePolicyFile(file *hcl.File, f *File) ([]*Policy, hcl.Diagnostics) {
+	content, diags := file.Body.Content(policyFileSchema)
+
+	var policies []*Policy
+	for _, block := range content.Blocks {
+		p, moreDiags := decodePolicyBlock(block, f)
+		diags = append(diags, moreDiags...)
+		if p != nil {
+			policies = append(policies, p)
+		}
+	}
+	return policies, diags
+}
+
+func decodePolicyBlock(block *hcl.Block, f *File) (*Policy, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	p := &Policy{
+		Name:             block.Labels[0],
+		EnforcementLevel: Mandatory,
+		BaseDir:          filepath.Dir(f.Filename),
+		File:             f,
+		DeclRange:        block.DefRange,
+	}
+
+	if !hclsyntax.ValidIdentifier(p.Name) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid policy name",
+			Detail:   "A name must start with a letter or underscore and may contain only letters, digits, underscores, and dashes.",
+			Subject:  &block.LabelRanges[0],
+		})
+	}
+
+	content, moreDiags := block.Body.Content(policyBlockSchema)
+	diags = append(diags, moreDiags...)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+
+	p.Condition = content.Attributes["condition"].Expr
+	p.ErrorMessage = content.Attributes["error_message"].Expr
+
+	if attr, ok := content.Attributes["enforcement_level"]; ok {
+		val, moreDiags := attr.Expr.Value(nil)
+		diags = append(diags, moreDiags...)
+		if !moreDiags.HasErrors() {
+			switch {
+			case val.Type() == cty.String && val.IsKnown() && !val.IsNull() && val.AsString() == string(Advisory):
+				p.EnforcementLevel = Advisory
+			case val.Type() == cty.String && val.IsKnown() && !val.IsNull() && val.AsString() == string(Mandatory):
+				p.EnforcementLevel = Mandatory
+			default:
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid enforcement level",
+					Detail:   fmt.Sprintf("The enforcement level must be either %q or %q.", Advisory, Mandatory),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			}
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return p, diags
+}
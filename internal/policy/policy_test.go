@@ -0,0 +1,100 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package policy
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPaths(t *testing.T) {
+	policies, diags := LoadPaths([]string{"testdata/valid"})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	var got []string
+	for _, p := range policies {
+		got = append(got, p.Name+":"+string(p.EnforcementLevel))
+		if p.BaseDir != filepath.Join("testdata", "valid") {
+			t.Errorf("wrong base directory %q for %s", p.BaseDir, p.Name)
+		}
+	}
+	// Files are loaded in lexical order.
+	want := "no_deletes:advisory,require_tags:mandatory"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("wrong policies\ngot:  %s\nwant: %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestLoadPaths_file(t *testing.T) {
+	policies, diags := LoadPaths([]string{"testdata/valid/tags.tfpolicy.hcl"})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if len(policies) != 1 || policies[0].Name != "require_tags" {
+		t.Fatalf("wrong policies: %#v", policies)
+	}
+}
+
+func TestLoadPaths_errors(t *testing.T) {
+	tests := map[string][]string{
+		"testdata/invalid": {
+			"Invalid policy name",
+			"Invalid enforcement level",
+			`The argument "condition" is required`,
+		},
+		"testdata/duplicate": {
+			"Duplicate policy",
+		},
+		"testdata/nonexistent": {
+			"Failed to read policy path",
+		},
+	}
+
+	for path, wantErrs := range tests {
+		t.Run(path, func(t *testing.T) {
+			_, diags := LoadPaths([]string{path})
+			if !diags.HasErrors() {
+				t.Fatal("expected errors")
+			}
+			got := diags.Err().Error()
+			for _, want := range wantErrs {
+				if !strings.Contains(got, want) {
+					t.Errorf("missing error %q in:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	planned, diags := LoadPaths([]string{"testdata/valid"})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	// The same files given again, such as to the apply command for a saved
+	// plan that already records them, are evaluated only once.
+	again, diags := LoadPaths([]string{"testdata/valid/tags.tfpolicy.hcl"})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if got := Merge(planned, again); len(got) != len(planned) {
+		t.Fatalf("wrong number of policies %d; want %d", len(got), len(planned))
+	}
+
+	// A file whose source has changed is evaluated alongside the original.
+	changed, diags := LoadFiles([]*File{{
+		Filename: again[0].File.Filename,
+		Source:   append([]byte("# changed\n"), again[0].File.Source...),
+	}})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if got := Merge(planned, changed); len(got) != len(planned)+1 {
+		t.Fatalf("wrong number of policies %d; want %d", len(got), len(planned)+1)
+	}
+}
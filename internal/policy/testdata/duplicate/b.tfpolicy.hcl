@@ -0,0 +1,4 @@
+policy "same" {
+  condition     = true
+  error_message = "Never fails."
+}
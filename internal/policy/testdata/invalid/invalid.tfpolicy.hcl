@@ -0,0 +1,9 @@
+policy "bad level" {
+  enforcement_level = "sometimes"
+  condition         = true
+  error_message     = "Never fails."
+}
+
+policy "missing_condition" {
+  error_message = "Never fails."
+}
@@ -0,0 +1,5 @@
+policy "no_deletes" {
+  enforcement_level = "advisory"
+  condition         = length([for rc in plan.resource_changes : rc if contains(rc.change.actions, "delete")]) == 0
+  error_message     = "This plan deletes resources."
+}
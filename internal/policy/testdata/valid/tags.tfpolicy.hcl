@@ -0,0 +1,7 @@
+policy "require_tags" {
+  condition = alltrue([
+    for rc in plan.resource_changes : contains(keys(rc.change.after), "tags")
+    if contains(rc.change.actions, "create")
+  ])
+  error_message = "All new resources must have tags."
+}
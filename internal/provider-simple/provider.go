@@ -9,6 +9,7 @@ package simple
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/opentofu/opentofu/internal/configs/configschema"
@@ -151,6 +152,26 @@ func (s simple) ReadDataSource(_ context.Context, req providers.ReadDataSourceRe
 	return resp
 }
 
+func (s simple) ValidateEphemeralResourceConfig(_ context.Context, req providers.ValidateEphemeralResourceConfigRequest) (resp providers.ValidateEphemeralResourceConfigResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource type %q", req.TypeName))
+	return resp
+}
+
+func (s simple) OpenEphemeralResource(_ context.Context, req providers.OpenEphemeralResourceRequest) (resp providers.OpenEphemeralResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource type %q", req.TypeName))
+	return resp
+}
+
+func (s simple) RenewEphemeralResource(_ context.Context, req providers.RenewEphemeralResourceRequest) (resp providers.RenewEphemeralResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource type %q", req.TypeName))
+	return resp
+}
+
+func (s simple) CloseEphemeralResource(_ context.Context, req providers.CloseEphemeralResourceRequest) (resp providers.CloseEphemeralResourceResponse) {
+	resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unsupported ephemeral resource type %q", req.TypeName))
+	return resp
+}
+
 func (s simple) GetFunctions(_ context.Context) providers.GetFunctionsResponse {
 	panic("Not Implemented")
 }
@@ -7,6 +7,7 @@ package providers
 
 import (
 	"context"
+	"time"
 
 	"github.com/zclconf/go-cty/cty"
 
@@ -40,6 +41,10 @@ type Unconfigured interface {
 	// configuration values.
 	ValidateDataResourceConfig(context.Context, ValidateDataResourceConfigRequest) ValidateDataResourceConfigResponse
 
+	// ValidateEphemeralResourceConfig allows the provider to validate the
+	// ephemeral resource configuration values.
+	ValidateEphemeralResourceConfig(context.Context, ValidateEphemeralResourceConfigRequest) ValidateEphemeralResourceConfigResponse
+
 	// MoveResourceState requests that the given resource data be moved from one
 	// type to another, potentially between providers as well.
 	MoveResourceState(context.Context, MoveResourceStateRequest) MoveResourceStateResponse
@@ -114,6 +119,18 @@ type Configured interface {
 	// ReadDataSource returns the data source's current state.
 	ReadDataSource(context.Context, ReadDataSourceRequest) ReadDataSourceResponse
 
+	// OpenEphemeralResource opens an ephemeral resource and returns its
+	// result value, which must never be persisted in the state or a plan.
+	OpenEphemeralResource(context.Context, OpenEphemeralResourceRequest) OpenEphemeralResourceResponse
+
+	// RenewEphemeralResource extends the validity of an ephemeral resource
+	// that was previously opened and which requested renewal.
+	RenewEphemeralResource(context.Context, RenewEphemeralResourceRequest) RenewEphemeralResourceResponse
+
+	// CloseEphemeralResource releases any remote resources associated with
+	// a previously-opened ephemeral resource.
+	CloseEphemeralResource(context.Context, CloseEphemeralResourceRequest) CloseEphemeralResourceResponse
+
 	// GetFunctions returns a full list of functions defined in this provider. It should be a super
 	// set of the functions returned in GetProviderSchema()
 	GetFunctions(context.Context) GetFunctionsResponse
@@ -144,6 +161,10 @@ type GetProviderSchemaResponse struct {
 	// DataSources maps the data source name to that data source's schema.
 	DataSources map[string]Schema
 
+	// EphemeralResources maps the ephemeral resource type name to that
+	// type's schema.
+	EphemeralResources map[string]Schema
+
 	// Diagnostics contains any warnings or errors from the method call.
 	Diagnostics tfdiags.Diagnostics
 
@@ -264,6 +285,20 @@ type ValidateDataResourceConfigResponse struct {
 	Diagnostics tfdiags.Diagnostics
 }
 
+type ValidateEphemeralResourceConfigRequest struct {
+	// TypeName is the name of the ephemeral resource type to validate.
+	TypeName string
+
+	// Config is the configuration value to validate, which may contain unknown
+	// values.
+	Config cty.Value
+}
+
+type ValidateEphemeralResourceConfigResponse struct {
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
 type UpgradeResourceStateRequest struct {
 	// TypeName is the name of the resource type being upgraded
 	TypeName string
@@ -547,6 +582,67 @@ type ReadDataSourceResponse struct {
 	Diagnostics tfdiags.Diagnostics
 }
 
+type OpenEphemeralResourceRequest struct {
+	// TypeName is the name of the ephemeral resource type to open.
+	TypeName string
+
+	// Config is the complete configuration for the requested ephemeral
+	// resource.
+	Config cty.Value
+}
+
+type OpenEphemeralResourceResponse struct {
+	// Result is the value of the ephemeral resource. It must never be
+	// persisted in the state or in a saved plan.
+	Result cty.Value
+
+	// Private is an opaque blob that must be passed back to the provider
+	// in any later RenewEphemeralResource or CloseEphemeralResource calls.
+	Private []byte
+
+	// RenewAt, if not the zero time, is the time at which the provider
+	// wants RenewEphemeralResource to be called for this resource.
+	RenewAt time.Time
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
+type RenewEphemeralResourceRequest struct {
+	// TypeName is the name of the ephemeral resource type to renew.
+	TypeName string
+
+	// Private is the most recent private data returned by the provider
+	// for this ephemeral resource.
+	Private []byte
+}
+
+type RenewEphemeralResourceResponse struct {
+	// Private is the updated private data for the ephemeral resource.
+	Private []byte
+
+	// RenewAt, if not the zero time, is the time at which the provider
+	// wants RenewEphemeralResource to be called again for this resource.
+	RenewAt time.Time
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
+type CloseEphemeralResourceRequest struct {
+	// TypeName is the name of the ephemeral resource type to close.
+	TypeName string
+
+	// Private is the most recent private data returned by the provider
+	// for this ephemeral resource.
+	Private []byte
+}
+
+type CloseEphemeralResourceResponse struct {
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
 type GetFunctionsResponse struct {
 	Functions map[string]FunctionSpec
 
@@ -7,6 +7,7 @@ package refactoring
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
@@ -83,6 +84,13 @@ func findMoveStatements(cfg *configs.Config, into []MoveStatement) []MoveStateme
 // preferred, but our goal here is to match exactly the same cases that the
 // old heuristic would've matched, to retain compatibility for existing modules.
 //
+// The one rule that goes beyond the old heuristic is for module calls using
+// the "enabled" lifecycle argument: an existing zeroth instance of such a
+// module call is moved to its NoKey instance, so that switching a module
+// call from "count = var.x ? 1 : 0" to "enabled = var.x" preserves its
+// objects, as it does for resources. This is again only for module calls
+// that aren't mentioned in an explicit move statement.
+//
 // We should think very hard before adding any _new_ implication rules for
 // moved statements.
 func ImpliedMoveStatements(rootCfg *configs.Config, prevRunState *states.State, explicitStmts []MoveStatement) []MoveStatement {
@@ -130,6 +138,10 @@ func impliedMoveStatements(cfg *configs.Config, prevRunState *states.State, expl
 					toKey = addrs.IntKey(0)
 				}
 			case rCfg.Count == nil && rCfg.ForEach == nil: // no repetition at all
+				// This also covers resources using the "enabled" lifecycle
+				// argument, whose only possible instance is the no-key one,
+				// so that switching from count = 0/1 to enabled preserves
+				// the existing object.
 				if riState := rState.Instances[addrs.IntKey(0)]; riState != nil {
 					fromKey = addrs.IntKey(0)
 					toKey = addrs.NoKey
@@ -153,6 +165,34 @@ func impliedMoveStatements(cfg *configs.Config, prevRunState *states.State, expl
 		}
 	}
 
+	// A module call using the "enabled" argument has only a NoKey
+	// instance, so we move the zeroth instance from the equivalent
+	// count form to it. An instance of this module might have no resources
+	// of its own, and so no module state, so we consider every instance
+	// that has anything recorded for it or for its descendants.
+	for _, modInst := range moduleInstancesInState(modAddr, prevRunState) {
+		for _, callCfg := range cfg.Module.ModuleCalls {
+			if callCfg.Enabled == nil {
+				continue
+			}
+			fromAddr := modInst.Child(callCfg.Name, addrs.IntKey(0))
+			if !haveModuleInstanceInState(fromAddr, prevRunState) {
+				continue
+			}
+			toAddr := modInst.Child(callCfg.Name, addrs.NoKey)
+			if haveMoveStatementForModule(fromAddr, explicitStmts) || haveMoveStatementForModule(toAddr, explicitStmts) {
+				continue
+			}
+			approxSrcRange := tfdiags.SourceRangeFromHCL(callCfg.Enabled.Range())
+			into = append(into, MoveStatement{
+				From:      addrs.ImpliedModuleMoveStatementEndpoint(fromAddr, approxSrcRange),
+				To:        addrs.ImpliedModuleMoveStatementEndpoint(toAddr, approxSrcRange),
+				DeclRange: approxSrcRange,
+				Implied:   true,
+			})
+		}
+	}
+
 	for _, childCfg := range cfg.Children {
 		into = impliedMoveStatements(childCfg, prevRunState, explicitStmts, into)
 	}
@@ -172,6 +212,51 @@ func (s *MoveStatement) Name() string {
 	return fmt.Sprintf("%s->%s", s.From, s.To)
 }
 
+// moduleInstancesInState returns the instances of the given module that have
+// anything recorded in the given state, either directly or in any of their
+// descendant modules.
+func moduleInstancesInState(addr addrs.Module, state *states.State) []addrs.ModuleInstance {
+	var ret []addrs.ModuleInstance
+	for _, ms := range state.Modules {
+		if len(ms.Addr) < len(addr) {
+			continue
+		}
+		inst := ms.Addr[:len(addr)]
+		if !inst.Module().Equal(addr) {
+			continue
+		}
+		if !slices.ContainsFunc(ret, inst.Equal) {
+			ret = append(ret, inst)
+		}
+	}
+	return ret
+}
+
+// haveModuleInstanceInState returns true if the given state has anything
+// recorded for the given module instance or any of its descendants.
+func haveModuleInstanceInState(addr addrs.ModuleInstance, state *states.State) bool {
+	for _, ms := range state.Modules {
+		if addr.Equal(ms.Addr) || addr.IsAncestor(ms.Addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func haveMoveStatementForModule(addr addrs.ModuleInstance, stmts []MoveStatement) bool {
+	// As with haveMoveStatementForResource, we expect the number of explicit
+	// statements to be small enough that a linear search is fine.
+	for _, stmt := range stmts {
+		if stmt.ObjectKind() != addrs.MoveEndpointModule {
+			continue
+		}
+		if stmt.From.SelectsModule(addr) || stmt.To.SelectsModule(addr) {
+			return true
+		}
+	}
+	return false
+}
+
 func haveMoveStatementForResource(addr addrs.AbsResource, stmts []MoveStatement) bool {
 	// This is not a particularly optimal way to answer this question,
 	// particularly since our caller calls this function in a loop already,
@@ -257,3 +257,103 @@ func TestImpliedMoveStatements(t *testing.T) {
 		t.Errorf("wrong result\n%s", diff)
 	}
 }
+
+func TestImpliedMoveStatements_enabledModule(t *testing.T) {
+	resourceAddrIn := func(mod addrs.ModuleInstance) addrs.AbsResourceInstance {
+		return addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "foo",
+			Name: "a",
+		}.Absolute(mod).Instance(addrs.NoKey)
+	}
+	providerAddr := addrs.AbsProviderConfig{
+		Module:   addrs.RootModule,
+		Provider: addrs.MustParseProviderSourceString("hashicorp/foo"),
+	}
+
+	rootCfg, _ := loadRefactoringFixture(t, "testdata/move-statement-implied-enabled-module")
+	prevRunState := states.BuildState(func(s *states.SyncState) {
+		for _, mod := range []addrs.ModuleInstance{
+			addrs.RootModuleInstance.Child("formerly_count", addrs.IntKey(0)),
+			// This module instance has no resources of its own, only those
+			// of its child module.
+			addrs.RootModuleInstance.Child("formerly_count_nested", addrs.IntKey(0)).Child("child", addrs.NoKey),
+			addrs.RootModuleInstance.Child("formerly_count_explicit", addrs.IntKey(0)),
+			addrs.RootModuleInstance.Child("formerly_count_explicit", addrs.IntKey(1)),
+			addrs.RootModuleInstance.Child("still_count", addrs.IntKey(0)),
+			// Module calls that don't use "enabled" keep the same behavior
+			// as before, with no implied move.
+			addrs.RootModuleInstance.Child("never_count", addrs.IntKey(0)),
+			// The module containing this module call has no resources of
+			// its own, and so isn't recorded in the state itself.
+			addrs.RootModuleInstance.Child("wrapper", addrs.NoKey).Child("formerly_count", addrs.IntKey(0)),
+		} {
+			s.SetResourceInstanceCurrent(
+				resourceAddrIn(mod),
+				&states.ResourceInstanceObjectSrc{},
+				providerAddr,
+				addrs.NoKey,
+			)
+		}
+	})
+
+	explicitStmts := FindMoveStatements(rootCfg)
+	got := ImpliedMoveStatements(rootCfg, prevRunState, explicitStmts)
+	filename := filepath.Join("testdata", "move-statement-implied-enabled-module", "main.tf")
+	want := []MoveStatement{
+		{
+			From:    addrs.ImpliedModuleMoveStatementEndpoint(addrs.RootModuleInstance.Child("formerly_count", addrs.IntKey(0)), tfdiags.SourceRange{}),
+			To:      addrs.ImpliedModuleMoveStatementEndpoint(addrs.RootModuleInstance.Child("formerly_count", addrs.NoKey), tfdiags.SourceRange{}),
+			Implied: true,
+			DeclRange: tfdiags.SourceRange{
+				Filename: filename,
+				Start:    tfdiags.SourcePos{Line: 9, Column: 15, Byte: 268},
+				End:      tfdiags.SourcePos{Line: 9, Column: 19, Byte: 272},
+			},
+		},
+		{
+			From:    addrs.ImpliedModuleMoveStatementEndpoint(addrs.RootModuleInstance.Child("formerly_count_nested", addrs.IntKey(0)), tfdiags.SourceRange{}),
+			To:      addrs.ImpliedModuleMoveStatementEndpoint(addrs.RootModuleInstance.Child("formerly_count_nested", addrs.NoKey), tfdiags.SourceRange{}),
+			Implied: true,
+			DeclRange: tfdiags.SourceRange{
+				Filename: filename,
+				Start:    tfdiags.SourcePos{Line: 17, Column: 15, Byte: 369},
+				End:      tfdiags.SourcePos{Line: 17, Column: 19, Byte: 373},
+			},
+		},
+		{
+			From:    addrs.ImpliedModuleMoveStatementEndpoint(addrs.RootModuleInstance.Child("wrapper", addrs.NoKey).Child("formerly_count", addrs.IntKey(0)), tfdiags.SourceRange{}),
+			To:      addrs.ImpliedModuleMoveStatementEndpoint(addrs.RootModuleInstance.Child("wrapper", addrs.NoKey).Child("formerly_count", addrs.NoKey), tfdiags.SourceRange{}),
+			Implied: true,
+			DeclRange: tfdiags.SourceRange{
+				Filename: filepath.Join("testdata", "move-statement-implied-enabled-module", "wrapper", "main.tf"),
+				Start:    tfdiags.SourcePos{Line: 5, Column: 15, Byte: 77},
+				End:      tfdiags.SourcePos{Line: 5, Column: 19, Byte: 81},
+			},
+		},
+	}
+
+	sort.Slice(got, func(i, j int) bool {
+		if got[i].DeclRange.Filename != got[j].DeclRange.Filename {
+			return got[i].DeclRange.Filename < got[j].DeclRange.Filename
+		}
+		return got[i].DeclRange.Start.Line < got[j].DeclRange.Start.Line
+	})
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	// The implied statements must also actually move the objects.
+	newState := prevRunState.DeepCopy()
+	ApplyMoves(append(explicitStmts, got...), newState)
+	for _, mod := range []addrs.ModuleInstance{
+		addrs.RootModuleInstance.Child("formerly_count", addrs.NoKey),
+		addrs.RootModuleInstance.Child("formerly_count_nested", addrs.NoKey).Child("child", addrs.NoKey),
+		addrs.RootModuleInstance.Child("wrapper", addrs.NoKey).Child("formerly_count", addrs.NoKey),
+	} {
+		if newState.ResourceInstance(resourceAddrIn(mod)) == nil {
+			t.Errorf("no object at %s after applying moves", resourceAddrIn(mod))
+		}
+	}
+}
@@ -269,6 +269,8 @@ func movableObjectDeclRange(addr addrs.AbsMoveable, cfg *configs.Config) (tfdiag
 			return tfdiags.SourceRangeFromHCL(call.ForEach.Range()), true
 		case call.Count != nil:
 			return tfdiags.SourceRangeFromHCL(call.Count.Range()), true
+		case call.Enabled != nil:
+			return tfdiags.SourceRangeFromHCL(call.Enabled.Range()), true
 		default:
 			return tfdiags.SourceRangeFromHCL(call.DeclRange), true
 		}
@@ -300,6 +302,8 @@ func movableObjectDeclRange(addr addrs.AbsMoveable, cfg *configs.Config) (tfdiag
 			return tfdiags.SourceRangeFromHCL(rc.ForEach.Range()), true
 		case rc.Count != nil:
 			return tfdiags.SourceRangeFromHCL(rc.Count.Range()), true
+		case rc.Enabled != nil:
+			return tfdiags.SourceRangeFromHCL(rc.Enabled.Range()), true
 		default:
 			return tfdiags.SourceRangeFromHCL(rc.DeclRange), true
 		}
@@ -0,0 +1,2 @@
+resource "foo" "a" {
+}
@@ -0,0 +1,45 @@
+# This fixture is useful only in conjunction with a previous run state that
+# conforms to the statements encoded in the module names. It's for
+# TestImpliedMoveStatements_enabledModule only.
+
+module "formerly_count" {
+  source = "./child"
+
+  lifecycle {
+    enabled = true
+  }
+}
+
+module "formerly_count_nested" {
+  source = "./grandparent"
+
+  lifecycle {
+    enabled = true
+  }
+}
+
+module "formerly_count_explicit" {
+  source = "./child"
+
+  lifecycle {
+    enabled = true
+  }
+}
+
+moved {
+  from = module.formerly_count_explicit[1]
+  to   = module.formerly_count_explicit
+}
+
+module "still_count" {
+  source = "./child"
+  count  = 1
+}
+
+module "never_count" {
+  source = "./child"
+}
+
+module "wrapper" {
+  source = "./wrapper"
+}
@@ -0,0 +1,7 @@
+module "formerly_count" {
+  source = "../child"
+
+  lifecycle {
+    enabled = true
+  }
+}
@@ -6,6 +6,8 @@
 package remote
 
 import (
+	"time"
+
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 )
 
@@ -41,6 +43,35 @@ type OptionalClientLocker interface {
 	IsLockingEnabled() bool
 }
 
+// ClientVersioner is an optional interface that allows a remote state
+// backend to expose the previous versions of the state that are retained by
+// its underlying storage, such as a bucket with object versioning enabled.
+type ClientVersioner interface {
+	Client
+
+	// Versions returns all of the retained versions of the state, ordered
+	// from the most recent to the oldest.
+	Versions() ([]*Version, error)
+
+	// GetVersion returns the state stored in the version with the given ID,
+	// or nil if that version exists but is empty.
+	GetVersion(id string) (*Payload, error)
+}
+
+// Version describes a single retained version of the remote state.
+type Version struct {
+	// ID is the storage-specific identifier of the version, which can be
+	// passed to ClientVersioner.GetVersion.
+	ID string
+
+	// LastModified is the time at which this version was written.
+	LastModified time.Time
+
+	// IsLatest is true for the version that is currently returned by
+	// Client.Get.
+	IsLatest bool
+}
+
 // Payload is the return value from the remote state storage.
 type Payload struct {
 	MD5  []byte
@@ -55,6 +55,9 @@ type State struct {
 
 var _ statemgr.Full = (*State)(nil)
 var _ statemgr.Migrator = (*State)(nil)
+var _ statemgr.Rewrapper = (*State)(nil)
+var _ statemgr.EncryptionDescriber = (*State)(nil)
+var _ statemgr.EncryptionStatusReporter = (*State)(nil)
 var _ local.IntermediateStateConditionalPersister = (*State)(nil)
 
 func NewState(client Client, enc encryption.StateEncryption) *State {
@@ -194,7 +197,8 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 		lineageUnchanged := s.readLineage != "" && s.lineage == s.readLineage
 		serialUnchanged := s.readSerial != 0 && s.serial == s.readSerial
 		stateUnchanged := statefile.StatesMarshalEqual(s.state, s.readState)
-		if stateUnchanged && lineageUnchanged && serialUnchanged && s.readEncryption != encryption.StatusMigration {
+		encryptionUnchanged := s.readEncryption != encryption.StatusMigration && s.readEncryption != encryption.StatusRotation
+		if stateUnchanged && lineageUnchanged && serialUnchanged && encryptionUnchanged {
 			// If the state, lineage or serial haven't changed at all then we have nothing to do.
 			return nil
 		}
@@ -245,6 +249,58 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 	return nil
 }
 
+// statemgr.Rewrapper impl.
+func (s *State) RewrapState() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payload, err := s.Client.Get()
+	if err != nil {
+		return err
+	}
+	if payload == nil {
+		return fmt.Errorf("there is no remote state to rewrap")
+	}
+
+	data, err := s.encryption.RewrapState(payload.Data)
+	if err != nil {
+		return err
+	}
+	if err := s.Client.Put(data); err != nil {
+		return err
+	}
+
+	// The stored data key is now wrapped by the primary method, so there is
+	// no longer a pending encryption migration.
+	s.readEncryption = encryption.StatusSatisfied
+	return nil
+}
+
+// statemgr.EncryptionDescriber impl.
+func (s *State) DescribeStateEncryption() (*encryption.Description, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payload, err := s.Client.Get()
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil || len(payload.Data) == 0 {
+		return nil, nil
+	}
+
+	desc, err := s.encryption.DescribeState(payload.Data)
+	return &desc, err
+}
+
+// statemgr.EncryptionStatusReporter impl.
+func (s *State) StateEncryptionStatus() encryption.EncryptionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.readEncryption
+}
+
 // ShouldPersistIntermediateState implements local.IntermediateStateConditionalPersister
 func (s *State) ShouldPersistIntermediateState(info *local.IntermediateStatePersistInfo) bool {
 	if s.disableIntermediateSnapshots {
@@ -108,3 +108,53 @@ func TestRemoteLocks(t *testing.T, a, b Client) {
 
 	// TODO: Should we enforce that Unlock requires the correct ID?
 }
+
+// TestClientVersions is a generic function to test any client that supports
+// retrieving previous versions of the state.
+func TestClientVersions(t *testing.T, c ClientVersioner) {
+	var datas [][]byte
+	for serial := uint64(1); serial <= 3; serial++ {
+		var buf bytes.Buffer
+		sf := statefile.New(statemgr.TestFullInitialState(), "stub-lineage", serial)
+		if err := statefile.Write(sf, &buf, encryption.StateEncryptionDisabled()); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		data := buf.Bytes()
+		if err := c.Put(data); err != nil {
+			t.Fatalf("put: %s", err)
+		}
+		datas = append(datas, data)
+	}
+
+	versions, err := c.Versions()
+	if err != nil {
+		t.Fatalf("versions: %s", err)
+	}
+	if len(versions) < len(datas) {
+		t.Fatalf("expected at least %d versions, got %d", len(datas), len(versions))
+	}
+	if !versions[0].IsLatest {
+		t.Fatalf("expected the first version to be the latest")
+	}
+	for i, v := range versions[1:] {
+		if v.IsLatest {
+			t.Fatalf("version %d (%s) is unexpectedly marked as the latest", i+1, v.ID)
+		}
+		if v.LastModified.After(versions[i].LastModified) {
+			t.Fatalf("versions are not ordered from the most recent to the oldest")
+		}
+	}
+
+	// The versions are returned most recent first, so the versions we wrote
+	// are the first ones in reverse order.
+	for i := range datas {
+		want := datas[len(datas)-1-i]
+		p, err := c.GetVersion(versions[i].ID)
+		if err != nil {
+			t.Fatalf("get version %s: %s", versions[i].ID, err)
+		}
+		if p == nil || !bytes.Equal(p.Data, want) {
+			t.Fatalf("wrong data for version %s\nwant: %q", versions[i].ID, string(want))
+		}
+	}
+}
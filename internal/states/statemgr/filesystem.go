@@ -67,9 +67,12 @@ type Filesystem struct {
 }
 
 var (
-	_ Full           = (*Filesystem)(nil)
-	_ PersistentMeta = (*Filesystem)(nil)
-	_ Migrator       = (*Filesystem)(nil)
+	_ Full                     = (*Filesystem)(nil)
+	_ PersistentMeta           = (*Filesystem)(nil)
+	_ Migrator                 = (*Filesystem)(nil)
+	_ Rewrapper                = (*Filesystem)(nil)
+	_ EncryptionDescriber      = (*Filesystem)(nil)
+	_ EncryptionStatusReporter = (*Filesystem)(nil)
 )
 
 // NewFilesystem creates a filesystem-based state manager that reads and writes
@@ -416,6 +419,96 @@ func (s *Filesystem) Unlock(id string) error {
 	return unlockErr
 }
 
+// RewrapState is an implementation of Rewrapper.
+func (s *Filesystem) RewrapState() error {
+	defer s.mutex()()
+
+	src, err := s.readRaw()
+	if err != nil {
+		return err
+	}
+	if len(src) == 0 {
+		return fmt.Errorf("there is no state at %s to rewrap", s.readPath)
+	}
+
+	rewrapped, err := s.encryption.RewrapState(src)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[TRACE] statemgr.Filesystem: writing rewrapped snapshot at %s", s.path)
+	if s.stateFileOut == nil {
+		// We're not holding the output file open, and so not holding a lock
+		// on it either, so we can just replace it.
+		if err := os.WriteFile(s.path, rewrapped, 0666); err != nil {
+			return err
+		}
+	} else {
+		if _, err := s.stateFileOut.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if err := s.stateFileOut.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := s.stateFileOut.Write(rewrapped); err != nil {
+			return err
+		}
+		if err := s.stateFileOut.Sync(); err != nil {
+			return err
+		}
+	}
+
+	// Any future reads must come from the file we've now updated
+	s.readPath = s.path
+	return nil
+}
+
+// DescribeStateEncryption is an implementation of EncryptionDescriber.
+func (s *Filesystem) DescribeStateEncryption() (*encryption.Description, error) {
+	defer s.mutex()()
+
+	src, err := s.readRaw()
+	if err != nil {
+		return nil, err
+	}
+	if len(src) == 0 {
+		return nil, nil
+	}
+
+	desc, err := s.encryption.DescribeState(src)
+	return &desc, err
+}
+
+// StateEncryptionStatus is an implementation of EncryptionStatusReporter.
+func (s *Filesystem) StateEncryptionStatus() encryption.EncryptionStatus {
+	defer s.mutex()()
+
+	if s.readFile == nil {
+		return encryption.StatusUnknown
+	}
+	return s.readFile.EncryptionStatus
+}
+
+// readRaw reads the latest snapshot as stored, without decrypting it. The
+// result is empty if there is no snapshot.
+func (s *Filesystem) readRaw() ([]byte, error) {
+	// As in refreshState, we must read through the output file if we're
+	// already holding it open, because Windows doesn't allow opening it again.
+	var src []byte
+	var err error
+	if s.stateFileOut == nil || s.readPath != s.path {
+		src, err = os.ReadFile(s.readPath)
+	} else {
+		if _, err = s.stateFileOut.Seek(0, io.SeekStart); err == nil {
+			src, err = io.ReadAll(s.stateFileOut)
+		}
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return src, nil
+}
+
 // StateSnapshotMeta returns the metadata from the most recently persisted
 // or refreshed persistent state snapshot.
 //
@@ -19,6 +19,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/encryption/enctest"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	tfversion "github.com/opentofu/opentofu/version"
@@ -446,6 +447,112 @@ func TestFilesystem_GetRootOutputValues(t *testing.T) {
 	}
 }
 
+func TestFilesystem_rewrapWhileLocked(t *testing.T) {
+	defer testOverrideVersion(t, "1.2.3")()
+
+	oldEnc := enctest.EncryptionDirect(`
+		key_provider "static" "old" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		method "aes_gcm" "old" {
+			keys = key_provider.static.old
+		}
+		state {
+			envelope = true
+			method   = method.aes_gcm.old
+		}
+	`).State()
+	newEnc := enctest.EncryptionDirect(`
+		key_provider "static" "old" {
+			key = "6f6f706830656f67686f6834616872756f3751756165686565796f6f72653169"
+		}
+		key_provider "static" "new" {
+			key = "7468652d6e65772d6b65792d666f722d7468652d7265777261702d7465737473"
+		}
+		method "aes_gcm" "old" {
+			keys = key_provider.static.old
+		}
+		method "aes_gcm" "new" {
+			keys = key_provider.static.new
+		}
+		state {
+			envelope = true
+			method   = method.aes_gcm.new
+			fallback {
+				method = method.aes_gcm.old
+			}
+		}
+	`).State()
+	newOnlyEnc := enctest.EncryptionDirect(`
+		key_provider "static" "new" {
+			key = "7468652d6e65772d6b65792d666f722d7468652d7265777261702d7465737473"
+		}
+		method "aes_gcm" "new" {
+			keys = key_provider.static.new
+		}
+		state {
+			envelope = true
+			method   = method.aes_gcm.new
+		}
+	`).State()
+
+	path := filepath.Join(t.TempDir(), "terraform.tfstate")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	err = statefile.Write(&statefile.File{
+		Lineage:          "test-lineage",
+		Serial:           3,
+		TerraformVersion: version.Must(version.NewVersion("1.2.3")),
+		State:            TestFullInitialState(),
+	}, f, oldEnc)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	f.Close()
+
+	s := NewFilesystem(path, newEnc)
+	info := NewLockInfo()
+	info.Operation = "test"
+	lockID, err := s.Lock(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RewrapState(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Unlock(lockID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rewrapped state can now be read without the old key provider, and
+	// is otherwise unchanged.
+	s = NewFilesystem(path, newOnlyEnc)
+	if err := s.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.StateSnapshotMeta().Serial, uint64(3); got != want {
+		t.Fatalf("wrong serial %d; want %d", got, want)
+	}
+	if !s.State().Equal(TestFullInitialState()) {
+		t.Fatalf("wrong state\n%s", s.State())
+	}
+
+	s = NewFilesystem(path, oldEnc)
+	if err := s.RefreshState(); err == nil {
+		t.Fatal("expected the old key provider to no longer decrypt the state")
+	}
+}
+
+func TestFilesystem_rewrapNonExist(t *testing.T) {
+	s := NewFilesystem(filepath.Join(t.TempDir(), "terraform.tfstate"), enctest.EncryptionRequired().State())
+	err := s.RewrapState()
+	if err == nil || !strings.Contains(err.Error(), "there is no state") {
+		t.Fatalf("wrong error: %v", err)
+	}
+}
+
 func testOverrideVersion(t *testing.T, v string) func() {
 	oldVersionStr := tfversion.Version
 	oldPrereleaseStr := tfversion.Prerelease
@@ -8,6 +8,7 @@ package statemgr
 import (
 	version "github.com/hashicorp/go-version"
 
+	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tofu"
 )
@@ -86,6 +87,41 @@ type Persister interface {
 	PersistState(*tofu.Schemas) error
 }
 
+// Rewrapper is an optional extension to Persistent for managers that can
+// re-encrypt the data key of an envelope encrypted snapshot in persistent
+// storage, without decrypting and re-encrypting the snapshot itself.
+type Rewrapper interface {
+	// RewrapState reads the latest persistent snapshot as stored, re-encrypts
+	// its data key using the manager's state encryption, and writes the
+	// result back in its place.
+	//
+	// The state of the snapshot, including its serial, is unchanged and so
+	// the manager's latest transient snapshot remains valid.
+	RewrapState() error
+}
+
+// EncryptionDescriber is an optional extension to Persistent for managers
+// that can describe how the latest persistent snapshot is encrypted in
+// storage.
+type EncryptionDescriber interface {
+	// DescribeStateEncryption reads the latest persistent snapshot as stored
+	// and describes its encryption using the manager's state encryption.
+	//
+	// The result is nil if there is no persistent snapshot yet. If the
+	// snapshot is encrypted but none of the configured methods can decrypt
+	// it, the description is returned along with the error.
+	DescribeStateEncryption() (*encryption.Description, error)
+}
+
+// EncryptionStatusReporter is an optional extension to Refresher for managers
+// that can report the encryption status of the most recently read snapshot.
+type EncryptionStatusReporter interface {
+	// StateEncryptionStatus returns the status the state encryption reported
+	// when decrypting the most recently read snapshot, or StatusUnknown if no
+	// snapshot was read.
+	StateEncryptionStatus() encryption.EncryptionStatus
+}
+
 // PersistentMeta is an optional extension to Persistent that allows inspecting
 // the metadata associated with the snapshot that was most recently either
 // read by RefreshState or written by PersistState.
@@ -291,6 +291,10 @@ func (c *Context) watchStop(walker *ContextGraphWalker) (chan struct{}, <-chan s
 		// If we're here, we're stopped, trigger the call.
 		log.Printf("[TRACE] Context: requesting providers and provisioners to gracefully stop")
 
+		// Renewing ephemeral resources would only extend their lifetime
+		// beyond the interrupted operation.
+		walker.EphemeralResources.StopRenewals()
+
 		{
 			// Copy the providers so that a misbehaved blocking Stop doesn't
 			// completely hang OpenTofu.
@@ -246,3 +246,31 @@ func (c *Context) ApplyGraphForUI(plan *plans.Plan, config *configs.Config) (*Gr
 	diags = diags.Append(moreDiags)
 	return graph, diags
 }
+
+// PlannedChangesToApply returns the addresses of the resource instances whose
+// planned changes Apply would make for the given plan, after taking into
+// account the plan's target and exclude addresses.
+//
+// This uses the same apply graph as Apply, so callers can use it to find out
+// which other changes must be skipped as a consequence of excluding a resource
+// instance, because they depend on it.
+func (c *Context) PlannedChangesToApply(ctx context.Context, plan *plans.Plan, config *configs.Config) (addrs.Set[addrs.AbsResourceInstance], tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	graph, _, moreDiags := c.applyGraph(ctx, plan, config, make(ProviderFunctionMapping))
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+
+	ret := addrs.MakeSet[addrs.AbsResourceInstance]()
+	for _, v := range graph.Vertices() {
+		// A resource instance can be represented by more than one node, such
+		// as when it's being replaced, but we only need to know whether any
+		// of them remain in the graph.
+		if n, ok := v.(GraphNodeResourceInstance); ok {
+			ret.Add(n.ResourceInstanceAddr())
+		}
+	}
+	return ret, diags
+}
@@ -5603,3 +5603,178 @@ check "http_check" {
 		t.Fatal(diags.Err())
 	}
 }
+
+func TestContext2Apply_ephemeralResource(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+ephemeral "test_secret" "token" {
+  count = 2
+  name  = "example-${count.index}"
+}
+
+resource "test_object" "a" {
+  test_string = "a"
+
+  depends_on = [ephemeral.test_secret.token]
+}
+`,
+	})
+
+	p := ephemeralTestProvider()
+	var openCount int
+	var mu sync.Mutex
+	open := p.OpenEphemeralResourceFn
+	p.OpenEphemeralResourceFn = func(req providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse {
+		mu.Lock()
+		openCount++
+		mu.Unlock()
+		return open(req)
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	// Each of the two instances must be opened once during plan and once
+	// again during apply, since their results are never saved.
+	if got, want := openCount, 4; got != want {
+		t.Fatalf("wrong number of OpenEphemeralResource calls %d; want %d", got, want)
+	}
+	if !p.CloseEphemeralResourceCalled {
+		t.Fatal("CloseEphemeralResource not called")
+	}
+	for _, rs := range state.RootModule().Resources {
+		if rs.Addr.Resource.Mode == addrs.EphemeralResourceMode {
+			t.Fatalf("ephemeral resource %s was saved in the state", rs.Addr)
+		}
+	}
+}
+
+func TestContext2Apply_writeOnlyAttribute(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+ephemeral "test_secret" "token" {
+  name = "example"
+}
+
+resource "test_object" "a" {
+  test_string = "a"
+  password    = ephemeral.test_secret.token.value
+}
+`,
+	})
+
+	p := ephemeralTestProvider()
+	p.GetProviderSchemaResponse.ResourceTypes["test_object"] = providers.Schema{
+		Block: &configschema.Block{
+			Attributes: map[string]*configschema.Attribute{
+				"test_string": {Type: cty.String, Optional: true},
+				"password":    {Type: cty.String, Optional: true, WriteOnly: true},
+			},
+		},
+	}
+	var appliedPassword cty.Value
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		appliedPassword = req.Config.GetAttr("password")
+		return providers.ApplyResourceChangeResponse{NewState: req.PlannedState}
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	if got, want := p.PlanResourceChangeRequest.Config.GetAttr("password"), cty.StringVal("secret-example"); !got.RawEquals(want) {
+		t.Fatalf("wrong password in plan request config\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	addr := mustResourceInstanceAddr("test_object.a")
+	rcs := plan.Changes.ResourceInstance(addr)
+	if rcs == nil {
+		t.Fatalf("no planned change for %s", addr)
+	}
+	schema := p.GetProviderSchemaResponse.ResourceTypes["test_object"].Block
+	rc, err := rcs.Decode(schema.ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rc.After.GetAttr("password"); !got.IsNull() {
+		t.Fatalf("write-only value was saved in the plan: %#v", got)
+	}
+
+	state, diags := ctx.Apply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+
+	if got, want := appliedPassword, cty.StringVal("secret-example"); !got.RawEquals(want) {
+		t.Fatalf("wrong password in apply request config\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	obj := state.ResourceInstance(addr).Current
+	if obj == nil {
+		t.Fatalf("no state for %s", addr)
+	}
+	if bytes.Contains(obj.AttrsJSON, []byte("secret-example")) {
+		t.Fatalf("write-only value was saved in the state: %s", obj.AttrsJSON)
+	}
+}
+
+func TestContext2Apply_plannedChangesToApply(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "aws_instance" "a" {
+  num = 1
+}
+
+resource "aws_instance" "b" {
+  num = aws_instance.a.num
+}
+
+resource "aws_instance" "c" {
+  num = 3
+}
+`,
+	})
+	p := testProvider("aws")
+	p.PlanResourceChangeFn = testDiffFn
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	instance := func(name string) addrs.AbsResourceInstance {
+		return mustResourceInstanceAddr("aws_instance." + name)
+	}
+
+	got, diags := ctx.PlannedChangesToApply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+	for _, name := range []string{"a", "b", "c"} {
+		if !got.Has(instance(name)) {
+			t.Errorf("missing change for aws_instance.%s", name)
+		}
+	}
+
+	// Excluding aws_instance.a must also skip aws_instance.b, which depends
+	// on it.
+	plan.ExcludeAddrs = []addrs.Targetable{instance("a")}
+	got, diags = ctx.PlannedChangesToApply(context.Background(), plan, m)
+	assertNoErrors(t, diags)
+	if len(got) != 1 || !got.Has(instance("c")) {
+		t.Fatalf("wrong changes to apply with exclusion: %v", got)
+	}
+}
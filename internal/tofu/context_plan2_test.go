@@ -8526,3 +8526,481 @@ func featuresBlockTestSchema() *configschema.Block {
 		},
 	}
 }
+
+func TestContext2Plan_ephemeralResourceProviderConfig(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+ephemeral "test_secret" "token" {
+  name = "example"
+}
+
+provider "other" {
+  token = ephemeral.test_secret.token.value
+}
+
+resource "other_object" "a" {
+}
+`,
+	})
+
+	p := ephemeralTestProvider()
+	other := &MockProvider{
+		GetProviderSchemaResponse: getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+			Provider: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"token": {Type: cty.String, Optional: true},
+				},
+			},
+			ResourceTypes: map[string]*configschema.Block{
+				"other_object": {},
+			},
+		}),
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"):  testProviderFuncFixed(p),
+			addrs.NewDefaultProvider("other"): testProviderFuncFixed(other),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	if !p.OpenEphemeralResourceCalled {
+		t.Fatal("OpenEphemeralResource not called")
+	}
+	if !p.CloseEphemeralResourceCalled {
+		t.Fatal("CloseEphemeralResource not called")
+	}
+	if got, want := other.ConfigureProviderRequest.Config.GetAttr("token"), cty.StringVal("secret-example"); !got.RawEquals(want) {
+		t.Fatalf("wrong provider token\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	addr := mustResourceInstanceAddr("ephemeral.test_secret.token")
+	if rs := plan.PriorState.ResourceInstance(addr); rs != nil {
+		t.Fatalf("ephemeral resource %s was saved in the prior state", addr)
+	}
+	if rc := plan.Changes.ResourceInstance(addr); rc != nil {
+		t.Fatalf("ephemeral resource %s has a planned change", addr)
+	}
+}
+
+func TestContext2Plan_ephemeralResourceInManagedResource(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+ephemeral "test_secret" "token" {
+  name = "example"
+}
+
+resource "test_object" "a" {
+  test_string = ephemeral.test_secret.token.value
+}
+`,
+	})
+
+	p := ephemeralTestProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Err().Error(), "Invalid use of ephemeral value"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+	if !p.CloseEphemeralResourceCalled {
+		t.Fatal("CloseEphemeralResource not called")
+	}
+}
+
+func TestContext2Plan_ephemeralResourceRootOutput(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+ephemeral "test_secret" "token" {
+  name = "example"
+}
+
+output "token" {
+  value = ephemeral.test_secret.token.value
+}
+`,
+	})
+
+	p := ephemeralTestProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := diags.Err().Error(), "Output refers to ephemeral values"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+}
+
+func TestContext2Plan_ephemeralResourceInstanceKeys(t *testing.T) {
+	tests := map[string]struct {
+		config  string
+		wantErr string
+	}{
+		"resource for_each": {
+			config: `
+resource "test_object" "a" {
+  for_each    = toset([ephemeral.test_secret.token.value])
+  test_string = "a"
+}
+`,
+			wantErr: "Values derived from ephemeral resources cannot be used as for_each arguments",
+		},
+		"provider instance key": {
+			config: `
+provider "test" {
+  alias    = "by_name"
+  for_each = toset(["secret-example"])
+}
+
+resource "test_object" "a" {
+  provider    = test.by_name[ephemeral.test_secret.token.value]
+  test_string = "a"
+}
+`,
+			wantErr: "A provider instance key must not be derived from an ephemeral resource",
+		},
+		"import id": {
+			config: `
+import {
+  to = test_object.a
+  id = ephemeral.test_secret.token.value
+}
+
+resource "test_object" "a" {
+  test_string = "a"
+}
+`,
+			wantErr: "The import ID cannot be derived from an ephemeral resource",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := testModuleInline(t, map[string]string{
+				"main.tf": `
+ephemeral "test_secret" "token" {
+  name = "example"
+}
+` + test.config,
+			})
+
+			p := ephemeralTestProvider()
+			ctx := testContext2(t, &ContextOpts{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+				},
+			})
+
+			_, diags := ctx.Plan(context.Background(), m, states.NewState(), DefaultPlanOpts)
+			if !diags.HasErrors() {
+				t.Fatal("succeeded; want error")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, test.wantErr) {
+				t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", got, test.wantErr)
+			}
+		})
+	}
+}
+
+// ephemeralTestProvider returns a mock provider with an ephemeral resource
+// type "test_secret", whose "value" attribute is derived from its "name"
+// argument, along with a managed resource type "test_object".
+func ephemeralTestProvider() *MockProvider {
+	p := simpleMockProvider()
+	p.GetProviderSchemaResponse.EphemeralResources = map[string]providers.Schema{
+		"test_secret": {
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"name":  {Type: cty.String, Required: true},
+					"value": {Type: cty.String, Computed: true},
+				},
+			},
+		},
+	}
+	p.OpenEphemeralResourceFn = func(req providers.OpenEphemeralResourceRequest) (resp providers.OpenEphemeralResourceResponse) {
+		resp.Result = cty.ObjectVal(map[string]cty.Value{
+			"name":  req.Config.GetAttr("name"),
+			"value": cty.StringVal("secret-" + req.Config.GetAttr("name").AsString()),
+		})
+		return resp
+	}
+	return p
+}
+
+func TestContext2Plan_enabled(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			variable "on" {
+				type = bool
+			}
+
+			resource "test_object" "a" {
+				test_string = "a"
+
+				lifecycle {
+					enabled = var.on
+				}
+			}
+
+			module "child" {
+				source = "./child"
+
+				lifecycle {
+					enabled = !var.on
+				}
+			}
+
+			output "a" {
+				value = test_object.a
+			}
+
+			output "child" {
+				value = module.child
+			}
+		`,
+		"child/main.tf": `
+			resource "test_object" "b" {
+				test_string = "b"
+			}
+
+			output "b" {
+				value = test_object.b.test_string
+			}
+		`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		plan, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+			Mode: plans.NormalMode,
+			SetVariables: InputValues{
+				"on": &InputValue{
+					Value:      cty.True,
+					SourceType: ValueFromCaller,
+				},
+			},
+		})
+		assertNoErrors(t, diags)
+
+		addr := mustResourceInstanceAddr("test_object.a")
+		instPlan := plan.Changes.ResourceInstance(addr)
+		if instPlan == nil {
+			t.Fatalf("no plan for %s at all", addr)
+		}
+		if got, want := instPlan.Action, plans.Create; got != want {
+			t.Errorf("wrong planned action for %s\ngot:  %s\nwant: %s", addr, got, want)
+		}
+		if got := plan.Changes.ResourceInstance(mustResourceInstanceAddr("module.child.test_object.b")); got != nil {
+			t.Errorf("unexpected plan for module.child.test_object.b: %s", got.Action)
+		}
+
+		outChange := plan.Changes.OutputValue(addrs.OutputValue{Name: "child"}.Absolute(addrs.RootModuleInstance))
+		if outChange == nil {
+			t.Fatal("no planned change for output value \"child\"")
+		}
+		outVal, err := outChange.After.Decode(cty.DynamicPseudoType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !outVal.IsNull() {
+			t.Errorf("wrong value for output \"child\"\ngot:  %#v\nwant: null", outVal)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		plan, diags := ctx.Plan(context.Background(), m, states.NewState(), &PlanOpts{
+			Mode: plans.NormalMode,
+			SetVariables: InputValues{
+				"on": &InputValue{
+					Value:      cty.False,
+					SourceType: ValueFromCaller,
+				},
+			},
+		})
+		assertNoErrors(t, diags)
+
+		if got := plan.Changes.ResourceInstance(mustResourceInstanceAddr("test_object.a")); got != nil {
+			t.Errorf("unexpected plan for test_object.a: %s", got.Action)
+		}
+		addr := mustResourceInstanceAddr("module.child.test_object.b")
+		instPlan := plan.Changes.ResourceInstance(addr)
+		if instPlan == nil {
+			t.Fatalf("no plan for %s at all", addr)
+		}
+		if got, want := instPlan.Action, plans.Create; got != want {
+			t.Errorf("wrong planned action for %s\ngot:  %s\nwant: %s", addr, got, want)
+		}
+
+		outChange := plan.Changes.OutputValue(addrs.OutputValue{Name: "a"}.Absolute(addrs.RootModuleInstance))
+		if outChange == nil {
+			t.Fatal("no planned change for output value \"a\"")
+		}
+		outVal, err := outChange.After.Decode(cty.DynamicPseudoType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !outVal.IsNull() {
+			t.Errorf("wrong value for output \"a\"\ngot:  %#v\nwant: null", outVal)
+		}
+	})
+}
+
+func TestContext2Plan_enabledFromCount(t *testing.T) {
+	// Switching from count = 1 to the "enabled" lifecycle argument must
+	// retain the existing object without requiring a "moved" block.
+	addrFrom := mustResourceInstanceAddr("test_object.a[0]")
+	addrTo := mustResourceInstanceAddr("test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+				lifecycle {
+					enabled = true
+				}
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addrFrom, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	if instPlan := plan.Changes.ResourceInstance(addrFrom); instPlan != nil {
+		t.Fatalf("unexpected plan for %s; should've moved to %s", addrFrom, addrTo)
+	}
+	instPlan := plan.Changes.ResourceInstance(addrTo)
+	if instPlan == nil {
+		t.Fatalf("no plan for %s at all", addrTo)
+	}
+	if got, want := instPlan.PrevRunAddr, addrFrom; !got.Equal(want) {
+		t.Errorf("wrong previous run address\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := instPlan.Action, plans.NoOp; got != want {
+		t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestContext2Plan_enabledModuleFromCount(t *testing.T) {
+	// Switching a module call from count = 1 to the "enabled" lifecycle
+	// argument must retain the objects in the module without requiring a
+	// "moved" block.
+	addrFrom := mustResourceInstanceAddr("module.child[0].test_object.a")
+	addrTo := mustResourceInstanceAddr("module.child.test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			module "child" {
+				source = "./child"
+				lifecycle {
+					enabled = true
+				}
+			}
+		`,
+		"child/main.tf": `
+			resource "test_object" "a" {
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addrFrom, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	if instPlan := plan.Changes.ResourceInstance(addrFrom); instPlan != nil {
+		t.Fatalf("unexpected plan for %s; should've moved to %s", addrFrom, addrTo)
+	}
+	instPlan := plan.Changes.ResourceInstance(addrTo)
+	if instPlan == nil {
+		t.Fatalf("no plan for %s at all", addrTo)
+	}
+	if got, want := instPlan.PrevRunAddr, addrFrom; !got.Equal(want) {
+		t.Errorf("wrong previous run address\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := instPlan.Action, plans.NoOp; got != want {
+		t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestContext2Plan_enabledFalseDestroys(t *testing.T) {
+	addr := mustResourceInstanceAddr("test_object.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+				lifecycle {
+					enabled = false
+				}
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`), addrs.NoKey)
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(context.Background(), m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	instPlan := plan.Changes.ResourceInstance(addr)
+	if instPlan == nil {
+		t.Fatalf("no plan for %s at all", addr)
+	}
+	if got, want := instPlan.Action, plans.Delete; got != want {
+		t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+	}
+}
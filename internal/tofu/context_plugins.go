@@ -115,6 +115,9 @@ func (cp *contextPlugins) ProviderSchema(ctx context.Context, addr addrs.Provide
 		if err := d.Block.InternalValidate(); err != nil {
 			return resp, fmt.Errorf("provider %s has invalid schema for data resource type %q, which is a bug in the provider: %w", addr, t, err)
 		}
+		if d.Block.ContainsWriteOnly() {
+			return resp, fmt.Errorf("provider %s has invalid schema for data resource type %q, which is a bug in the provider: write-only attributes are only valid for managed resource types", addr, t)
+		}
 		if d.Version < 0 {
 			// We're not using the version numbers here yet, but we'll check
 			// for validity anyway in case we start using them in future.
@@ -60,6 +60,10 @@ func (c *Context) walk(ctx context.Context, graph *Graph, operation walkOperatio
 	// Walk the real graph, this will block until it completes
 	diags := graph.Walk(ctx, walker)
 
+	// Any ephemeral resource instances that are still open at this point
+	// had their close node skipped, so we'll close them now.
+	diags = diags.Append(walker.EphemeralResources.CloseAll(ctx))
+
 	// Close the channel so the watcher stops, and wait for it to return.
 	close(watchStop)
 	<-watchWait
@@ -153,6 +157,7 @@ func (c *Context) graphWalker(operation walkOperation, opts *graphWalkOpts) *Con
 		InstanceExpander:        instances.NewExpander(),
 		MoveResults:             opts.MoveResults,
 		ImportResolver:          NewImportResolver(),
+		EphemeralResources:      NewEphemeralResources(),
 		Operation:               operation,
 		StopContext:             c.runContext,
 		PlanTimestamp:           opts.PlanTimeTimestamp,
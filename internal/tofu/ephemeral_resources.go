@@ -0,0 +1,261 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// EphemeralResources tracks the instances of ephemeral resources that have
+// been opened during a single graph walk.
+//
+// Ephemeral resource results are never written to the state or to a plan,
+// so this is the only place their values live while the walk is running.
+// Each open instance must eventually be closed, either by the graph node
+// that closes the resource once nothing else needs it or, as a last resort,
+// by CloseAll at the end of the walk.
+type EphemeralResources struct {
+	mu        sync.Mutex
+	resources map[string]*ephemeralResource
+}
+
+// ephemeralResource is the set of instances belonging to one expanded
+// ephemeral resource in one module instance.
+type ephemeralResource struct {
+	addr      addrs.AbsResource
+	instances map[addrs.InstanceKey]*ephemeralResourceInstance
+}
+
+// ephemeralResourceInstance is a single open ephemeral resource instance.
+//
+// If provider is nil then the instance was never actually opened, because
+// its configuration wasn't known yet, and so there is nothing to renew or
+// close.
+type ephemeralResourceInstance struct {
+	addr     addrs.AbsResourceInstance
+	value    cty.Value
+	provider providers.Interface
+	typeName string
+
+	mu      sync.Mutex
+	private []byte
+	closed  bool
+	diags   tfdiags.Diagnostics
+
+	// cancelRenew stops the background renewal started by setInstance, if
+	// any, and renewDone is closed once it has stopped.
+	cancelRenew context.CancelFunc
+	renewDone   chan struct{}
+}
+
+func NewEphemeralResources() *EphemeralResources {
+	return &EphemeralResources{
+		resources: make(map[string]*ephemeralResource),
+	}
+}
+
+// registerResource records that the given resource has been expanded, so
+// that references to it can be resolved even if it has no instances.
+func (e *EphemeralResources) registerResource(addr addrs.AbsResource) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := addr.String()
+	if _, exists := e.resources[key]; exists {
+		return
+	}
+	e.resources[key] = &ephemeralResource{
+		addr:      addr,
+		instances: make(map[addrs.InstanceKey]*ephemeralResourceInstance),
+	}
+}
+
+// setInstance records the result of opening an ephemeral resource instance
+// and, if the provider requested it, starts renewing it in the background
+// until the instance is closed or the given context is cancelled.
+func (e *EphemeralResources) setInstance(ctx context.Context, inst *ephemeralResourceInstance, renewAt time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	resAddr := inst.addr.ContainingResource()
+	key := resAddr.String()
+	res, exists := e.resources[key]
+	if !exists {
+		res = &ephemeralResource{
+			addr:      resAddr,
+			instances: make(map[addrs.InstanceKey]*ephemeralResourceInstance),
+		}
+		e.resources[key] = res
+	}
+	res.instances[inst.addr.Resource.Key] = inst
+
+	if inst.provider != nil && !renewAt.IsZero() {
+		renewCtx, cancel := context.WithCancel(ctx)
+		inst.cancelRenew = cancel
+		inst.renewDone = make(chan struct{})
+		go inst.renewLoop(renewCtx, renewAt)
+	}
+}
+
+// instanceValues returns the values of all of the known instances of the
+// given resource. The second return value is false if the resource has not
+// been expanded yet.
+func (e *EphemeralResources) instanceValues(addr addrs.AbsResource) (map[addrs.InstanceKey]cty.Value, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	res, exists := e.resources[addr.String()]
+	if !exists {
+		return nil, false
+	}
+	ret := make(map[addrs.InstanceKey]cty.Value, len(res.instances))
+	for key, inst := range res.instances {
+		ret[key] = inst.value
+	}
+	return ret, true
+}
+
+// closeResource closes all of the open instances of the given resource
+// across all of the instances of its containing module.
+func (e *EphemeralResources) closeResource(ctx context.Context, addr addrs.ConfigResource) tfdiags.Diagnostics {
+	e.mu.Lock()
+	var insts []*ephemeralResourceInstance
+	for _, res := range e.resources {
+		if !res.addr.Config().Equal(addr) {
+			continue
+		}
+		for _, inst := range res.instances {
+			insts = append(insts, inst)
+		}
+	}
+	e.mu.Unlock()
+
+	var diags tfdiags.Diagnostics
+	for _, inst := range insts {
+		diags = diags.Append(inst.close(ctx))
+	}
+	return diags
+}
+
+// StopRenewals stops the background renewal of all of the open instances,
+// without closing them.
+//
+// This is called when the operation is interrupted, so that OpenTofu stops
+// making renewal requests while it waits for the graph walk to finish.
+func (e *EphemeralResources) StopRenewals() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, res := range e.resources {
+		for _, inst := range res.instances {
+			if inst.cancelRenew != nil {
+				inst.cancelRenew()
+			}
+		}
+	}
+}
+
+// CloseAll closes any ephemeral resource instances that are still open.
+//
+// This is called at the end of each graph walk so that instances whose
+// close node was skipped, for example due to an error upstream, are still
+// closed before the provider plugins are shut down.
+func (e *EphemeralResources) CloseAll(ctx context.Context) tfdiags.Diagnostics {
+	e.mu.Lock()
+	var insts []*ephemeralResourceInstance
+	for _, res := range e.resources {
+		for _, inst := range res.instances {
+			insts = append(insts, inst)
+		}
+	}
+	e.mu.Unlock()
+
+	var diags tfdiags.Diagnostics
+	for _, inst := range insts {
+		diags = diags.Append(inst.close(ctx))
+	}
+	return diags
+}
+
+func (i *ephemeralResourceInstance) renewLoop(ctx context.Context, renewAt time.Time) {
+	defer close(i.renewDone)
+
+	for {
+		timer := time.NewTimer(time.Until(renewAt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		i.mu.Lock()
+		log.Printf("[TRACE] EphemeralResources: renewing %s", i.addr)
+		resp := i.provider.RenewEphemeralResource(ctx, providers.RenewEphemeralResourceRequest{
+			TypeName: i.typeName,
+			Private:  i.private,
+		})
+		i.diags = i.diags.Append(resp.Diagnostics)
+		if resp.Diagnostics.HasErrors() {
+			i.mu.Unlock()
+			return
+		}
+		i.private = resp.Private
+		i.mu.Unlock()
+
+		if resp.RenewAt.IsZero() {
+			return
+		}
+		renewAt = resp.RenewAt
+	}
+}
+
+// close stops any background renewal and then asks the provider to close
+// the instance. It's safe to call close more than once; only the first call
+// has any effect.
+func (i *ephemeralResourceInstance) close(ctx context.Context) tfdiags.Diagnostics {
+	if i.cancelRenew != nil {
+		i.cancelRenew()
+		<-i.renewDone
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.closed || i.provider == nil {
+		i.closed = true
+		return nil
+	}
+	i.closed = true
+
+	diags := i.diags
+	log.Printf("[TRACE] EphemeralResources: closing %s", i.addr)
+	resp := i.provider.CloseEphemeralResource(ctx, providers.CloseEphemeralResourceRequest{
+		TypeName: i.typeName,
+		Private:  i.private,
+	})
+	for _, diag := range resp.Diagnostics {
+		if diag.Severity() == tfdiags.Error {
+			desc := diag.Description()
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to close ephemeral resource",
+				fmt.Sprintf("The provider failed to close %s: %s", i.addr, desc.Summary),
+			))
+			continue
+		}
+		diags = diags.Append(diag)
+	}
+	return diags
+}
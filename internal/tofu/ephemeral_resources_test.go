@@ -0,0 +1,111 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/providers"
+)
+
+func TestEphemeralResources_renewAndClose(t *testing.T) {
+	var mu sync.Mutex
+	renewed := make(chan struct{})
+	p := &MockProvider{
+		RenewEphemeralResourceFn: func(req providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse {
+			mu.Lock()
+			defer mu.Unlock()
+			defer close(renewed)
+			if got, want := string(req.Private), "initial"; got != want {
+				t.Errorf("wrong private data in renew request %q; want %q", got, want)
+			}
+			// Returning no RenewAt stops any further renewals.
+			return providers.RenewEphemeralResourceResponse{
+				Private: []byte("renewed"),
+			}
+		},
+	}
+
+	addr := mustResourceInstanceAddr("ephemeral.test_secret.token")
+	resources := NewEphemeralResources()
+	resources.setInstance(context.Background(), &ephemeralResourceInstance{
+		addr:     addr,
+		value:    cty.StringVal("secret"),
+		provider: p,
+		typeName: "test_secret",
+		private:  []byte("initial"),
+	}, time.Now().Add(10*time.Millisecond))
+
+	vals, ok := resources.instanceValues(addr.ContainingResource())
+	if !ok {
+		t.Fatalf("no values recorded for %s", addr.ContainingResource())
+	}
+	if got, want := vals[addr.Resource.Key], cty.StringVal("secret"); !got.RawEquals(want) {
+		t.Fatalf("wrong value\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	select {
+	case <-renewed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("instance was not renewed")
+	}
+
+	diags := resources.closeResource(context.Background(), addr.ConfigResource())
+	assertNoDiagnostics(t, diags)
+	if !p.CloseEphemeralResourceCalled {
+		t.Fatal("CloseEphemeralResource not called")
+	}
+	if got, want := string(p.CloseEphemeralResourceRequest.Private), "renewed"; got != want {
+		t.Fatalf("wrong private data in close request %q; want %q", got, want)
+	}
+
+	// Closing everything afterwards must not close the instance again.
+	p.CloseEphemeralResourceCalled = false
+	diags = resources.CloseAll(context.Background())
+	assertNoDiagnostics(t, diags)
+	if p.CloseEphemeralResourceCalled {
+		t.Fatal("CloseEphemeralResource called for an instance that was already closed")
+	}
+}
+
+func TestEphemeralResources_renewStopsWhenCanceled(t *testing.T) {
+	p := &MockProvider{
+		RenewEphemeralResourceFn: func(req providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse {
+			return providers.RenewEphemeralResourceResponse{
+				Private: req.Private,
+				RenewAt: time.Now().Add(time.Millisecond),
+			}
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	addr := mustResourceInstanceAddr("ephemeral.test_secret.token")
+	inst := &ephemeralResourceInstance{
+		addr:     addr,
+		value:    cty.StringVal("secret"),
+		provider: p,
+		typeName: "test_secret",
+	}
+	resources := NewEphemeralResources()
+	resources.setInstance(ctx, inst, time.Now().Add(time.Millisecond))
+
+	cancel()
+	select {
+	case <-inst.renewDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("renewal did not stop when the context was canceled")
+	}
+
+	// The instance is still open, so it must still be closed.
+	diags := resources.CloseAll(context.Background())
+	assertNoDiagnostics(t, diags)
+	if !p.CloseEphemeralResourceCalled {
+		t.Fatal("CloseEphemeralResource not called")
+	}
+}
@@ -215,6 +215,11 @@ type EvalContext interface {
 	// and have a configuration
 	ImportResolver() *ImportResolver
 
+	// EphemeralResources returns the object that tracks the ephemeral
+	// resource instances opened during the current graph walk, along with
+	// their results.
+	EphemeralResources() *EphemeralResources
+
 	// WithPath returns a copy of the context with the internal path set to the
 	// path argument.
 	WithPath(path addrs.ModuleInstance) EvalContext
@@ -81,6 +81,7 @@ type BuiltinEvalContext struct {
 	InstanceExpanderValue   *instances.Expander
 	MoveResultsValue        refactoring.MoveResults
 	ImportResolverValue     *ImportResolver
+	EphemeralResourcesValue *EphemeralResources
 	Encryption              encryption.Encryption
 	ProviderFunctionTracker ProviderFunctionMapping
 }
@@ -585,6 +586,10 @@ func (c *BuiltinEvalContext) ImportResolver() *ImportResolver {
 	return c.ImportResolverValue
 }
 
+func (c *BuiltinEvalContext) EphemeralResources() *EphemeralResources {
+	return c.EphemeralResourcesValue
+}
+
 func (c *BuiltinEvalContext) GetEncryption() encryption.Encryption {
 	return c.Encryption
 }
@@ -158,6 +158,9 @@ type MockEvalContext struct {
 	ImportResolverCalled  bool
 	ImportResolverResults *ImportResolver
 
+	EphemeralResourcesCalled  bool
+	EphemeralResourcesResults *EphemeralResources
+
 	InstanceExpanderCalled   bool
 	InstanceExpanderExpander *instances.Expander
 }
@@ -411,6 +414,11 @@ func (c *MockEvalContext) ImportResolver() *ImportResolver {
 	return c.ImportResolverResults
 }
 
+func (c *MockEvalContext) EphemeralResources() *EphemeralResources {
+	c.EphemeralResourcesCalled = true
+	return c.EphemeralResourcesResults
+}
+
 func (c *MockEvalContext) InstanceExpander() *instances.Expander {
 	c.InstanceExpanderCalled = true
 	return c.InstanceExpanderExpander
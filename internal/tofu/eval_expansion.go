@@ -47,3 +47,17 @@ func evaluateCountExpression(expr hcl.Expression, ctx EvalContext, excludeableAd
 func evaluateCountExpressionValue(expr hcl.Expression, ctx EvalContext) (cty.Value, tfdiags.Diagnostics) {
 	return evalchecks.EvaluateCountExpressionValue(expr, evalContextEvaluate(ctx))
 }
+
+func evalContextEvaluateBool(ctx EvalContext) evalchecks.EvaluateFunc {
+	return func(expr hcl.Expression) (cty.Value, tfdiags.Diagnostics) {
+		return ctx.EvaluateExpr(expr, cty.Bool, nil)
+	}
+}
+
+func evaluateEnabledExpression(expr hcl.Expression, ctx EvalContext, excludeableAddr addrs.Targetable) (bool, tfdiags.Diagnostics) {
+	return evalchecks.EvaluateEnabledExpression(expr, evalContextEvaluateBool(ctx), excludeableAddr)
+}
+
+func evaluateEnabledExpressionValue(expr hcl.Expression, ctx EvalContext) (cty.Value, tfdiags.Diagnostics) {
+	return evalchecks.EvaluateEnabledExpressionValue(expr, evalContextEvaluateBool(ctx))
+}
@@ -64,6 +64,15 @@ func evaluateImportIdExpression(expr hcl.Expression, ctx EvalContext, keyData in
 		})
 	}
 
+	if importIdVal.HasMark(marks.Ephemeral) {
+		return "", diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid import id argument",
+			Detail:   "The import ID cannot be derived from an ephemeral resource, because it is saved in the plan.",
+			Subject:  expr.Range().Ptr(),
+		})
+	}
+
 	var importId string
 	err := gocty.FromCtyValue(importIdVal, &importId)
 	if err != nil {
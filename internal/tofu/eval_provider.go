@@ -85,6 +85,14 @@ func resolveProviderInstance(keyExpr hcl.Expression, keyScope *lang.Scope, sourc
 			Extra:    evalchecks.DiagnosticCausedBySensitive(true),
 		})
 	}
+	if keyVal.HasMark(marks.Ephemeral) {
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider instance key",
+			Detail:   "A provider instance key must not be derived from an ephemeral resource, because it is saved in the state.",
+			Subject:  keyExpr.Range().Ptr(),
+		})
+	}
 	if keyVal.IsNull() {
 		return nil, diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
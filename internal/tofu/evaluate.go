@@ -69,6 +69,15 @@ type Evaluator struct {
 	Changes *plans.ChangesSync
 
 	PlanTimestamp time.Time
+
+	// EphemeralResources holds the results of any ephemeral resource
+	// instances opened so far during the current walk.
+	EphemeralResources *EphemeralResources
+
+	// InstanceExpander is the expander used during the current walk, which
+	// is consulted to distinguish disabled module calls from those whose
+	// instances have not produced any output values yet.
+	InstanceExpander *instances.Expander
 }
 
 // Scope creates an evaluation scope for the given module path and optional
@@ -542,6 +551,9 @@ func (d *evaluationStateData) GetModule(addr addrs.ModuleCall, rng tfdiags.Sourc
 			ret = cty.EmptyObjectVal
 		}
 
+	case callConfig.Enabled != nil && d.moduleCallDisabled(addr):
+		ret = cty.NullVal(cty.Object(unknownMap))
+
 	default:
 		val, ok := moduleInstances[addrs.NoKey]
 		if !ok {
@@ -581,6 +593,16 @@ func (d *evaluationStateData) GetModule(addr addrs.ModuleCall, rng tfdiags.Sourc
 	return ret, diags
 }
 
+// moduleCallDisabled returns true if the given module call has been expanded
+// using its "enabled" argument and turned out to have no instances.
+func (d *evaluationStateData) moduleCallDisabled(addr addrs.ModuleCall) bool {
+	if d.Evaluator.InstanceExpander == nil {
+		return false
+	}
+	allInstances := d.Evaluator.InstanceExpander.AllInstances()
+	return allInstances.HasModuleCall(addr.Absolute(d.ModulePath)) && !allInstances.HasModuleInstance(d.ModulePath.Child(addr.Name, addrs.NoKey))
+}
+
 func (d *evaluationStateData) GetPathAttr(addr addrs.PathAttr, rng tfdiags.SourceRange) (cty.Value, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	switch addr.Name {
@@ -691,6 +713,12 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 	}
 	ty := schema.ImpliedType()
 
+	if addr.Mode == addrs.EphemeralResourceMode {
+		// Ephemeral resources are never saved in the state, so their values
+		// come only from the instances opened during the current walk.
+		return d.getEphemeralResource(addr, config, ty), diags
+	}
+
 	rs := d.Evaluator.State.Resource(addr.Absolute(d.ModulePath))
 
 	if rs == nil {
@@ -704,6 +732,10 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 				return cty.EmptyTupleVal, diags
 			case config.ForEach != nil:
 				return cty.EmptyObjectVal, diags
+			case config.Enabled != nil:
+				// A disabled resource has no instances, and so its value
+				// is null.
+				return cty.NullVal(ty), diags
 			default:
 				// While we can reference an expanded resource with 0
 				// instances, we cannot reference instances that do not exist.
@@ -850,6 +882,30 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 		instances[key] = val
 	}
 
+	return resourceValueFromInstances(config, ty, instances), diags
+}
+
+// getEphemeralResource returns the value of the given ephemeral resource,
+// using the results of the instances opened so far during this walk.
+func (d *evaluationStateData) getEphemeralResource(addr addrs.Resource, config *configs.Resource, ty cty.Type) cty.Value {
+	if d.Evaluator.EphemeralResources == nil {
+		return cty.DynamicVal.Mark(marks.Ephemeral)
+	}
+	instances, ok := d.Evaluator.EphemeralResources.instanceValues(addr.Absolute(d.ModulePath))
+	if !ok {
+		// The resource hasn't been expanded yet, which is always the case
+		// during the validate walk.
+		return cty.DynamicVal.Mark(marks.Ephemeral)
+	}
+	return resourceValueFromInstances(config, ty, instances).Mark(marks.Ephemeral)
+}
+
+// resourceValueFromInstances builds the value for a whole resource from the
+// values of its instances, based on the repetition mode declared in its
+// configuration. Any instances that are missing are represented as unknown
+// values of the given type, except for the single instance of a resource
+// using the "enabled" argument, which is null when missing.
+func resourceValueFromInstances(config *configs.Resource, ty cty.Type, instances map[addrs.InstanceKey]cty.Value) cty.Value {
 	// ret should be populated with a valid value in all cases below
 	var ret cty.Value
 
@@ -911,6 +967,15 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 			ret = cty.EmptyObjectVal
 		}
 
+	case config.Enabled != nil:
+		val, ok := instances[addrs.NoKey]
+		if !ok {
+			// a disabled resource has no instance, and so is null
+			val = cty.NullVal(ty)
+		}
+
+		ret = val
+
 	default:
 		val, ok := instances[addrs.NoKey]
 		if !ok {
@@ -921,7 +986,7 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 		ret = val
 	}
 
-	return ret, diags
+	return ret
 }
 
 func (d *evaluationStateData) getResourceSchema(addr addrs.Resource, providerAddr addrs.Provider) *configschema.Block {
@@ -202,6 +202,8 @@ func (d *evaluationStateData) staticValidateResourceReference(modCfg *configs.Co
 		modeAdjective = "managed"
 	case addrs.DataResourceMode:
 		modeAdjective = "data"
+	case addrs.EphemeralResourceMode:
+		modeAdjective = "ephemeral"
 	default:
 		// should never happen
 		modeAdjective = "<invalid-mode>"
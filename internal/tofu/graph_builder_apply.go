@@ -89,6 +89,12 @@ func (b *ApplyGraphBuilder) Steps() []GraphTransformer {
 	}
 
 	concreteResource := func(a *NodeAbstractResource) dag.Vertex {
+		if a.Addr.Resource.Mode == addrs.EphemeralResourceMode {
+			return &nodeExpandEphemeralResource{
+				NodeAbstractResource: a,
+			}
+		}
+
 		return &nodeExpandApplyableResource{
 			NodeAbstractResource: a,
 		}
@@ -199,6 +205,9 @@ func (b *ApplyGraphBuilder) Steps() []GraphTransformer {
 		// Target
 		&TargetingTransformer{Targets: b.Targets, Excludes: b.Excludes},
 
+		// Close each ephemeral resource once nothing else needs it.
+		&ephemeralResourceCloseTransformer{},
+
 		// Close opened plugin connections
 		&CloseProviderTransformer{},
 
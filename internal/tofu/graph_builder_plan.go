@@ -243,6 +243,9 @@ func (b *PlanGraphBuilder) Steps() []GraphTransformer {
 		// Target
 		&TargetingTransformer{Targets: b.Targets, Excludes: b.Excludes},
 
+		// Close each ephemeral resource once nothing else needs it.
+		&ephemeralResourceCloseTransformer{},
+
 		// Detect when create_before_destroy must be forced on for a particular
 		// node due to dependency edges, to avoid graph cycles during apply.
 		&ForcedCBDTransformer{},
@@ -271,6 +274,12 @@ func (b *PlanGraphBuilder) initPlan() {
 	}
 
 	b.ConcreteResource = func(a *NodeAbstractResource) dag.Vertex {
+		if a.Addr.Resource.Mode == addrs.EphemeralResourceMode {
+			return &nodeExpandEphemeralResource{
+				NodeAbstractResource: a,
+			}
+		}
+
 		return &nodeExpandPlannableResource{
 			NodeAbstractResource: a,
 			skipRefresh:          b.skipRefresh,
@@ -341,6 +350,12 @@ func (b *PlanGraphBuilder) initImport() {
 	}
 
 	b.ConcreteResource = func(a *NodeAbstractResource) dag.Vertex {
+		if a.Addr.Resource.Mode == addrs.EphemeralResourceMode {
+			return &nodeExpandEphemeralResource{
+				NodeAbstractResource: a,
+			}
+		}
+
 		return &nodeExpandPlannableResource{
 			NodeAbstractResource: a,
 
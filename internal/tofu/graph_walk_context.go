@@ -39,6 +39,7 @@ type ContextGraphWalker struct {
 	Checks                  *checks.State           // Used for safe concurrent writes of checkable objects and their check results
 	InstanceExpander        *instances.Expander     // Tracks our gradual expansion of module and resource instances
 	ImportResolver          *ImportResolver         // Tracks import targets as they are being resolved
+	EphemeralResources      *EphemeralResources     // Tracks open ephemeral resource instances and their values
 	MoveResults             refactoring.MoveResults // Read-only record of earlier processing of move statements
 	Operation               walkOperation
 	StopContext             context.Context
@@ -99,6 +100,8 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		VariableValues:     w.variableValues,
 		VariableValuesLock: &w.variableValuesLock,
 		PlanTimestamp:      w.PlanTimestamp,
+		EphemeralResources: w.EphemeralResources,
+		InstanceExpander:   w.InstanceExpander,
 	}
 
 	ctx := &BuiltinEvalContext{
@@ -109,6 +112,7 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		Plugins:                 w.Context.plugins,
 		MoveResultsValue:        w.MoveResults,
 		ImportResolverValue:     w.ImportResolver,
+		EphemeralResourcesValue: w.EphemeralResources,
 		ProviderCache:           w.providerCache,
 		ProviderInputConfig:     w.Context.providerInputConfig,
 		ProviderLock:            &w.providerLock,
@@ -386,6 +386,17 @@ If you do intend to export this data, annotate the output value as sensitive by
 					Subject: n.Config.DeclRange.Ptr(),
 				})
 			}
+
+			// Root module output values are saved in the state, and so
+			// they can never include ephemeral values.
+			if marks.Contains(val, marks.Ephemeral) {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Output refers to ephemeral values",
+					Detail:   "Root module output values are saved in the state, so they cannot refer to values derived from ephemeral resources.",
+					Subject:  n.Config.DeclRange.Ptr(),
+				})
+			}
 		}
 	}
 
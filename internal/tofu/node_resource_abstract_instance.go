@@ -839,7 +839,7 @@ func (n *NodeAbstractResourceInstance) refresh(ctx context.Context, evalCtx Eval
 	}
 
 	ret := state.DeepCopy()
-	ret.Value = newState
+	ret.Value = schema.NullWriteOnly(newState)
 	ret.Private = resp.Private
 
 	// We have no way to exempt provider using the legacy SDK from this check,
@@ -954,6 +954,11 @@ func (n *NodeAbstractResourceInstance) plan(
 	if configDiags.HasErrors() {
 		return nil, nil, keyData, diags
 	}
+	ephemeralDiags := ephemeralValueDiags(n.Addr, schema, origConfigVal)
+	diags = diags.Append(ephemeralDiags.InConfigBody(config.Config, n.Addr.String()))
+	if ephemeralDiags.HasErrors() {
+		return nil, nil, keyData, diags
+	}
 
 	metaConfigVal, metaDiags := n.providerMetas(ctx, evalCtx)
 	diags = diags.Append(metaDiags)
@@ -1133,6 +1138,10 @@ func (n *NodeAbstractResourceInstance) plan(
 		plannedNewVal = plannedNewVal.MarkWithPaths(marks)
 	}
 
+	// Write-only attributes are never persisted in the plan, even if a legacy
+	// provider returned a value for them.
+	plannedNewVal = schema.NullWriteOnly(plannedNewVal)
+
 	// The test assertion error handling above could've changed the plannedNewVal
 	// so we should store the unmarked version before we go ahead and re-mark it again
 	unmarkedPlannedNewVal, _ = plannedNewVal.UnmarkDeep()
@@ -1295,6 +1304,7 @@ func (n *NodeAbstractResourceInstance) plan(
 		if len(unmarkedPaths) > 0 {
 			plannedNewVal = plannedNewVal.MarkWithPaths(unmarkedPaths)
 		}
+		plannedNewVal = schema.NullWriteOnly(plannedNewVal)
 
 		for _, err := range plannedNewVal.Type().TestConformance(schema.ImpliedType()) {
 			diags = diags.Append(tfdiags.Sourceless(
@@ -1845,6 +1855,11 @@ func (n *NodeAbstractResourceInstance) planDataSource(ctx context.Context, evalC
 	if configDiags.HasErrors() {
 		return nil, nil, keyData, diags
 	}
+	ephemeralDiags := ephemeralValueDiags(n.Addr, schema, configVal)
+	diags = diags.Append(ephemeralDiags.InConfigBody(config.Config, n.Addr.String()))
+	if ephemeralDiags.HasErrors() {
+		return nil, nil, keyData, diags
+	}
 
 	check, nested := n.nestedInCheckBlock()
 	if nested {
@@ -2133,6 +2148,11 @@ func (n *NodeAbstractResourceInstance) applyDataSource(ctx context.Context, eval
 	if configDiags.HasErrors() {
 		return nil, keyData, diags
 	}
+	ephemeralDiags := ephemeralValueDiags(n.Addr, schema, configVal)
+	diags = diags.Append(ephemeralDiags.InConfigBody(config.Config, n.Addr.String()))
+	if ephemeralDiags.HasErrors() {
+		return nil, keyData, diags
+	}
 
 	newVal, readDiags := n.readDataSource(ctx, evalCtx, configVal)
 	if check, nested := n.nestedInCheckBlock(); nested {
@@ -2495,6 +2515,11 @@ func (n *NodeAbstractResourceInstance) apply(
 		if configDiags.HasErrors() {
 			return nil, diags
 		}
+		ephemeralDiags := ephemeralValueDiags(n.Addr, schema, configVal)
+		diags = diags.Append(ephemeralDiags.InConfigBody(applyConfig.Config, n.Addr.String()))
+		if ephemeralDiags.HasErrors() {
+			return nil, diags
+		}
 	}
 
 	if !configVal.IsWhollyKnown() {
@@ -2628,6 +2653,10 @@ func (n *NodeAbstractResourceInstance) apply(
 		return nil, diags
 	}
 
+	// Write-only attributes are never persisted in the state, even if the
+	// provider returned a value for them.
+	newVal = schema.NullWriteOnly(newVal)
+
 	// After this point we have a type-conforming result object and so we
 	// must always run to completion to ensure it can be saved. If n.Error
 	// is set then we must not return a non-nil error, in order to allow
@@ -0,0 +1,378 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// nodeExpandEphemeralResource represents an ephemeral resource declared in
+// the configuration and implements DynamicExpand to open each of its
+// instances.
+//
+// The same node type is used for both the plan and apply walks, because
+// ephemeral resources are opened afresh in each phase and never have any
+// planned changes or state of their own.
+type nodeExpandEphemeralResource struct {
+	*NodeAbstractResource
+}
+
+var (
+	_ GraphNodeDynamicExpandable    = (*nodeExpandEphemeralResource)(nil)
+	_ GraphNodeReferenceable        = (*nodeExpandEphemeralResource)(nil)
+	_ GraphNodeReferencer           = (*nodeExpandEphemeralResource)(nil)
+	_ GraphNodeConfigResource       = (*nodeExpandEphemeralResource)(nil)
+	_ GraphNodeAttachResourceConfig = (*nodeExpandEphemeralResource)(nil)
+	_ GraphNodeTargetable           = (*nodeExpandEphemeralResource)(nil)
+)
+
+func (n *nodeExpandEphemeralResource) Name() string {
+	return n.NodeAbstractResource.Name() + " (expand)"
+}
+
+func (n *nodeExpandEphemeralResource) DynamicExpand(evalCtx EvalContext) (*Graph, error) {
+	var g Graph
+	var diags tfdiags.Diagnostics
+
+	expander := evalCtx.InstanceExpander()
+	instAddrs := addrs.MakeSet[addrs.Checkable]()
+	for _, module := range expander.ExpandModule(n.Addr.Module) {
+		resAddr := n.Addr.Resource.Absolute(module)
+		moduleCtx := evalCtx.WithPath(module)
+
+		moreDiags := n.recordExpansion(moduleCtx, resAddr)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		evalCtx.EphemeralResources().registerResource(resAddr)
+
+		for _, addr := range expander.ExpandResource(resAddr) {
+			instAddrs.Add(addr)
+
+			abs := NewNodeAbstractResourceInstance(addr)
+			abs.Config = n.Config
+			abs.ResolvedProvider = n.ResolvedProvider
+			abs.Schema = n.Schema
+			abs.SchemaVersion = n.SchemaVersion
+			g.Add(&nodeEphemeralResourceInstance{
+				NodeAbstractResourceInstance: abs,
+			})
+		}
+	}
+	if diags.HasErrors() {
+		return nil, diags.ErrWithWarnings()
+	}
+
+	if checkState := evalCtx.Checks(); checkState.ConfigHasChecks(n.NodeAbstractResource.Addr) {
+		checkState.ReportCheckableObjects(n.NodeAbstractResource.Addr, instAddrs)
+	}
+
+	addRootNodeToGraph(&g)
+
+	return &g, diags.ErrWithWarnings()
+}
+
+// recordExpansion evaluates the count, for_each or enabled argument of the
+// resource and records the result in the instance expander.
+//
+// Unlike writeResourceState, this intentionally doesn't create any record of
+// the resource in the state.
+func (n *nodeExpandEphemeralResource) recordExpansion(evalCtx EvalContext, addr addrs.AbsResource) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	expander := evalCtx.InstanceExpander()
+
+	switch {
+	case n.Config.Count != nil:
+		count, countDiags := evaluateCountExpression(n.Config.Count, evalCtx, addr)
+		diags = diags.Append(countDiags)
+		if countDiags.HasErrors() {
+			return diags
+		}
+		expander.SetResourceCount(addr.Module, n.Addr.Resource, count)
+
+	case n.Config.ForEach != nil:
+		forEach, forEachDiags := evaluateForEachExpression(n.Config.ForEach, evalCtx, addr)
+		diags = diags.Append(forEachDiags)
+		if forEachDiags.HasErrors() {
+			return diags
+		}
+		expander.SetResourceForEach(addr.Module, n.Addr.Resource, forEach)
+
+	case n.Config.Enabled != nil:
+		enabled, enabledDiags := evaluateEnabledExpression(n.Config.Enabled, evalCtx, addr)
+		diags = diags.Append(enabledDiags)
+		if enabledDiags.HasErrors() {
+			return diags
+		}
+		expander.SetResourceEnabled(addr.Module, n.Addr.Resource, enabled)
+
+	default:
+		expander.SetResourceSingle(addr.Module, n.Addr.Resource)
+	}
+
+	return diags
+}
+
+// nodeEphemeralResourceInstance opens a single instance of an ephemeral
+// resource and makes its result available for evaluation.
+type nodeEphemeralResourceInstance struct {
+	*NodeAbstractResourceInstance
+}
+
+var (
+	_ GraphNodeModuleInstance   = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeReferenceable    = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeReferencer       = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeConfigResource   = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeResourceInstance = (*nodeEphemeralResourceInstance)(nil)
+	_ GraphNodeExecutable       = (*nodeEphemeralResourceInstance)(nil)
+)
+
+func (n *nodeEphemeralResourceInstance) Execute(ctx context.Context, evalCtx EvalContext, op walkOperation) tfdiags.Diagnostics {
+	addr := n.ResourceInstanceAddr()
+
+	diags := n.resolveProvider(evalCtx, true, states.NotDeposed)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	provider, providerSchema, err := n.getProvider(ctx, evalCtx)
+	diags = diags.Append(err)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	schema, _ := providerSchema.SchemaForResourceAddr(addr.ContainingResource().Resource)
+	if schema == nil {
+		// Should be caught during validation, so we don't bother with a pretty error here
+		diags = diags.Append(fmt.Errorf("provider %q does not support ephemeral resource %q", n.ResolvedProvider.ProviderConfig.InstanceString(n.ResolvedProviderKey), addr.ContainingResource().Resource.Type))
+		return diags
+	}
+
+	diags = diags.Append(validateSelfRef(addr.Resource, n.Config.Config, providerSchema))
+	if diags.HasErrors() {
+		return diags
+	}
+
+	forEach, _ := evaluateForEachExpression(n.Config.ForEach, evalCtx, addr)
+	keyData := EvalDataForInstanceKey(addr.Resource.Key, forEach)
+
+	checkDiags := evalCheckRules(
+		addrs.ResourcePrecondition,
+		n.Config.Preconditions,
+		evalCtx, addr, keyData,
+		tfdiags.Error,
+	)
+	diags = diags.Append(checkDiags)
+	if diags.HasErrors() {
+		return diags // failed preconditions prevent further evaluation
+	}
+
+	configVal, _, configDiags := evalCtx.EvaluateBlock(n.Config.Config, schema, nil, keyData)
+	diags = diags.Append(configDiags)
+	if configDiags.HasErrors() {
+		return diags
+	}
+	unmarkedConfigVal, _ := configVal.UnmarkDeep()
+
+	ty := schema.ImpliedType()
+	inst := &ephemeralResourceInstance{
+		addr:     addr,
+		typeName: addr.Resource.Resource.Type,
+	}
+
+	if !unmarkedConfigVal.IsWhollyKnown() {
+		// We can't open the resource until its configuration is known, so
+		// anything that refers to it will see an unknown value for now.
+		log.Printf("[TRACE] nodeEphemeralResourceInstance: %s configuration not fully known yet, so not opening it", addr)
+		inst.value = cty.UnknownVal(ty).Mark(marks.Ephemeral)
+		evalCtx.EphemeralResources().setInstance(ctx, inst, time.Time{})
+		return diags
+	}
+
+	log.Printf("[TRACE] nodeEphemeralResourceInstance: opening %s", addr)
+	resp := provider.OpenEphemeralResource(ctx, providers.OpenEphemeralResourceRequest{
+		TypeName: addr.Resource.Resource.Type,
+		Config:   unmarkedConfigVal,
+	})
+	diags = diags.Append(resp.Diagnostics.InConfigBody(n.Config.Config, addr.String()))
+	if resp.Diagnostics.HasErrors() {
+		return diags
+	}
+
+	result := resp.Result
+	if result == cty.NilVal {
+		result = cty.NullVal(ty)
+	}
+	if errs := result.Type().TestConformance(ty); len(errs) > 0 {
+		for _, err := range errs {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Provider produced invalid object",
+				fmt.Sprintf(
+					"Provider %q produced an invalid value for %s.\n\nThis is a bug in the provider, which should be reported in the provider's own issue tracker.",
+					n.ResolvedProvider.ProviderConfig.InstanceString(n.ResolvedProviderKey), tfdiags.FormatErrorPrefixed(err, addr.String()),
+				),
+			))
+		}
+		// The provider did open the resource, so we still need to close it.
+		inst.provider = provider
+		inst.private = resp.Private
+		evalCtx.EphemeralResources().setInstance(ctx, inst, time.Time{})
+		return diags
+	}
+
+	if schema.ContainsSensitive() {
+		result = result.MarkWithPaths(schema.ValueMarks(result, nil))
+	}
+
+	inst.value = result.Mark(marks.Ephemeral)
+	inst.provider = provider
+	inst.private = resp.Private
+	evalCtx.EphemeralResources().setInstance(ctx, inst, resp.RenewAt)
+
+	checkDiags = evalCheckRules(
+		addrs.ResourcePostcondition,
+		n.Config.Postconditions,
+		evalCtx, addr, keyData,
+		tfdiags.Error,
+	)
+	diags = diags.Append(checkDiags)
+
+	return diags
+}
+
+// nodeEphemeralResourceClose closes all of the instances of an ephemeral
+// resource once everything that depends on them has completed.
+type nodeEphemeralResourceClose struct {
+	Addr             addrs.ConfigResource
+	ResolvedProvider ResolvedProvider
+}
+
+var (
+	_ GraphNodeModulePath       = (*nodeEphemeralResourceClose)(nil)
+	_ GraphNodeExecutable       = (*nodeEphemeralResourceClose)(nil)
+	_ GraphNodeProviderConsumer = (*nodeEphemeralResourceClose)(nil)
+)
+
+func (n *nodeEphemeralResourceClose) Name() string {
+	return n.Addr.String() + " (close)"
+}
+
+// GraphNodeModulePath
+func (n *nodeEphemeralResourceClose) ModulePath() addrs.Module {
+	return n.Addr.Module
+}
+
+// GraphNodeProviderConsumer
+func (n *nodeEphemeralResourceClose) ProvidedBy() RequestedProvider {
+	return RequestedProvider{
+		ProviderConfig: n.ResolvedProvider.ProviderConfig,
+	}
+}
+
+// GraphNodeProviderConsumer
+func (n *nodeEphemeralResourceClose) Provider() addrs.Provider {
+	return n.ResolvedProvider.ProviderConfig.Provider
+}
+
+// GraphNodeProviderConsumer
+func (n *nodeEphemeralResourceClose) SetProvider(p ResolvedProvider) {
+	n.ResolvedProvider = p
+}
+
+// GraphNodeExecutable
+func (n *nodeEphemeralResourceClose) Execute(ctx context.Context, evalCtx EvalContext, op walkOperation) tfdiags.Diagnostics {
+	return evalCtx.EphemeralResources().closeResource(ctx, n.Addr)
+}
+
+// ephemeralResourceCloseTransformer adds a node to close each ephemeral
+// resource, which runs only after everything that refers to the resource
+// either directly or indirectly has completed.
+//
+// This must run after the ProviderTransformer and ReferenceTransformer, so
+// that all of the dependencies are already in place, and before the
+// CloseProviderTransformer so that each provider remains open until all of
+// its ephemeral resources have been closed.
+type ephemeralResourceCloseTransformer struct{}
+
+func (t *ephemeralResourceCloseTransformer) Transform(_ context.Context, g *Graph) error {
+	for _, v := range g.Vertices() {
+		node, ok := v.(*nodeExpandEphemeralResource)
+		if !ok {
+			continue
+		}
+
+		dependents, err := g.Descendents(node)
+		if err != nil {
+			return err
+		}
+
+		closer := &nodeEphemeralResourceClose{
+			Addr:             node.Addr,
+			ResolvedProvider: node.ResolvedProvider,
+		}
+		log.Printf("[TRACE] ephemeralResourceCloseTransformer: adding %s", dag.VertexName(closer))
+		g.Add(closer)
+		g.Connect(dag.BasicEdge(closer, node))
+		for _, dep := range dependents {
+			g.Connect(dag.BasicEdge(closer, dep))
+		}
+
+		// The closer needs the same provider as the resource itself.
+		for _, p := range g.DownEdges(node) {
+			if _, ok := p.(GraphNodeProvider); ok {
+				g.Connect(dag.BasicEdge(closer, p))
+			}
+		}
+	}
+
+	return nil
+}
+
+// ephemeralValueDiags returns an error diagnostic for each part of the given
+// value that is derived from an ephemeral resource, since such values must
+// not be persisted as part of the given object's plan or state. Ephemeral
+// values are allowed in write-only attributes of the given schema, because
+// those are never persisted.
+//
+// The returned diagnostics are contextual, and so callers should use
+// InConfigBody to attach them to the relevant configuration body.
+func ephemeralValueDiags(addr fmt.Stringer, schema *configschema.Block, val cty.Value) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if !marks.Contains(val, marks.Ephemeral) {
+		return diags
+	}
+
+	_, pvms := val.UnmarkDeepWithPaths()
+	for _, pvm := range pvms {
+		if _, ok := pvm.Marks[marks.Ephemeral]; !ok {
+			continue
+		}
+		if schema.PathIsWriteOnly(pvm.Path) {
+			continue
+		}
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Invalid use of ephemeral value",
+			fmt.Sprintf("The configuration for %s refers to a value derived from an ephemeral resource. Ephemeral values are never saved in the plan or state, so they can only be used in the write-only arguments of managed resources.", addr),
+			pvm.Path,
+		))
+	}
+	return diags
+}
@@ -325,6 +325,12 @@ func (n *NodeValidatableResource) validateResource(ctx context.Context, evalCtx
 		// Evaluate the for_each expression here so we can expose the diagnostics
 		forEachDiags := validateForEach(evalCtx, n.Config.ForEach)
 		diags = diags.Append(forEachDiags)
+
+	case n.Config.Enabled != nil:
+		// Basic type-checking of the enabled argument. More complete validation
+		// of this will happen when we DynamicExpand during the plan walk.
+		enabledDiags := validateEnabled(evalCtx, n.Config.Enabled)
+		diags = diags.Append(enabledDiags)
 	}
 
 	diags = diags.Append(validateDependsOn(evalCtx, n.Config.DependsOn))
@@ -481,6 +487,45 @@ func (n *NodeValidatableResource) validateResource(ctx context.Context, evalCtx
 
 		resp := provider.ValidateDataResourceConfig(ctx, req)
 		diags = diags.Append(resp.Diagnostics.InConfigBody(n.Config.Config, n.Addr.String()))
+
+	case addrs.EphemeralResourceMode:
+		schema, _ := providerSchema.SchemaForResourceType(n.Config.Mode, n.Config.Type)
+		if schema == nil {
+			var suggestion string
+			if len(providerSchema.EphemeralResources) > 0 {
+				suggestions := make([]string, 0, len(providerSchema.EphemeralResources))
+				for name := range providerSchema.EphemeralResources {
+					suggestions = append(suggestions, name)
+				}
+				if suggestion = didyoumean.NameSuggestion(n.Config.Type, suggestions); suggestion != "" {
+					suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+				}
+			}
+
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid ephemeral resource type",
+				Detail:   fmt.Sprintf("The provider %s does not support ephemeral resource type %q.%s", n.Provider().ForDisplay(), n.Config.Type, suggestion),
+				Subject:  &n.Config.TypeRange,
+			})
+			return diags
+		}
+
+		configVal, _, valDiags := evalCtx.EvaluateBlock(n.Config.Config, schema, nil, keyData)
+		diags = diags.Append(valDiags)
+		if valDiags.HasErrors() {
+			return diags
+		}
+
+		// Use unmarked value for validate request
+		unmarkedConfigVal, _ := configVal.UnmarkDeep()
+		req := providers.ValidateEphemeralResourceConfigRequest{
+			TypeName: n.Config.Type,
+			Config:   unmarkedConfigVal,
+		}
+
+		resp := provider.ValidateEphemeralResourceConfig(ctx, req)
+		diags = diags.Append(resp.Diagnostics.InConfigBody(n.Config.Config, n.Addr.String()))
 	}
 
 	return diags
@@ -576,6 +621,19 @@ func validateCount(evalCtx EvalContext, expr hcl.Expression) (diags tfdiags.Diag
 	return diags
 }
 
+func validateEnabled(evalCtx EvalContext, expr hcl.Expression) (diags tfdiags.Diagnostics) {
+	val, enabledDiags := evaluateEnabledExpressionValue(expr, evalCtx)
+	// If the value isn't known then that's the best we can do for now, but
+	// we'll check more thoroughly during the plan walk
+	if !val.IsKnown() {
+		return diags
+	}
+
+	diags = diags.Append(enabledDiags)
+
+	return diags
+}
+
 func validateForEach(evalCtx EvalContext, expr hcl.Expression) (diags tfdiags.Diagnostics) {
 	const unknownsAllowed = true
 	const tupleNotAllowed = false
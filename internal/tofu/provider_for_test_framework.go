@@ -153,6 +153,22 @@ func (p providerForTest) ValidateDataResourceConfig(ctx context.Context, r provi
 	return p.internal.ValidateDataResourceConfig(ctx, r)
 }
 
+func (p providerForTest) ValidateEphemeralResourceConfig(ctx context.Context, r providers.ValidateEphemeralResourceConfigRequest) providers.ValidateEphemeralResourceConfigResponse {
+	return p.internal.ValidateEphemeralResourceConfig(ctx, r)
+}
+
+func (p providerForTest) OpenEphemeralResource(ctx context.Context, r providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse {
+	return p.internal.OpenEphemeralResource(ctx, r)
+}
+
+func (p providerForTest) RenewEphemeralResource(ctx context.Context, r providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse {
+	return p.internal.RenewEphemeralResource(ctx, r)
+}
+
+func (p providerForTest) CloseEphemeralResource(ctx context.Context, r providers.CloseEphemeralResourceRequest) providers.CloseEphemeralResourceResponse {
+	return p.internal.CloseEphemeralResource(ctx, r)
+}
+
 func (p providerForTest) UpgradeResourceState(ctx context.Context, r providers.UpgradeResourceStateRequest) providers.UpgradeResourceStateResponse {
 	return p.internal.UpgradeResourceState(ctx, r)
 }
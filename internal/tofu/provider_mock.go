@@ -48,6 +48,11 @@ type MockProvider struct {
 	ValidateDataResourceConfigRequest  providers.ValidateDataResourceConfigRequest
 	ValidateDataResourceConfigFn       func(providers.ValidateDataResourceConfigRequest) providers.ValidateDataResourceConfigResponse
 
+	ValidateEphemeralResourceConfigCalled   bool
+	ValidateEphemeralResourceConfigResponse *providers.ValidateEphemeralResourceConfigResponse
+	ValidateEphemeralResourceConfigRequest  providers.ValidateEphemeralResourceConfigRequest
+	ValidateEphemeralResourceConfigFn       func(providers.ValidateEphemeralResourceConfigRequest) providers.ValidateEphemeralResourceConfigResponse
+
 	UpgradeResourceStateCalled   bool
 	UpgradeResourceStateTypeName string
 	UpgradeResourceStateResponse *providers.UpgradeResourceStateResponse
@@ -94,6 +99,21 @@ type MockProvider struct {
 	ReadDataSourceRequest  providers.ReadDataSourceRequest
 	ReadDataSourceFn       func(providers.ReadDataSourceRequest) providers.ReadDataSourceResponse
 
+	OpenEphemeralResourceCalled   bool
+	OpenEphemeralResourceResponse *providers.OpenEphemeralResourceResponse
+	OpenEphemeralResourceRequest  providers.OpenEphemeralResourceRequest
+	OpenEphemeralResourceFn       func(providers.OpenEphemeralResourceRequest) providers.OpenEphemeralResourceResponse
+
+	RenewEphemeralResourceCalled   bool
+	RenewEphemeralResourceResponse *providers.RenewEphemeralResourceResponse
+	RenewEphemeralResourceRequest  providers.RenewEphemeralResourceRequest
+	RenewEphemeralResourceFn       func(providers.RenewEphemeralResourceRequest) providers.RenewEphemeralResourceResponse
+
+	CloseEphemeralResourceCalled   bool
+	CloseEphemeralResourceResponse *providers.CloseEphemeralResourceResponse
+	CloseEphemeralResourceRequest  providers.CloseEphemeralResourceRequest
+	CloseEphemeralResourceFn       func(providers.CloseEphemeralResourceRequest) providers.CloseEphemeralResourceResponse
+
 	GetFunctionsCalled   bool
 	GetFunctionsResponse *providers.GetFunctionsResponse
 	GetFunctionsFn       func() providers.GetFunctionsResponse
@@ -123,9 +143,10 @@ func (p *MockProvider) getProviderSchema() providers.GetProviderSchemaResponse {
 	}
 
 	return providers.GetProviderSchemaResponse{
-		Provider:      providers.Schema{},
-		DataSources:   map[string]providers.Schema{},
-		ResourceTypes: map[string]providers.Schema{},
+		Provider:           providers.Schema{},
+		DataSources:        map[string]providers.Schema{},
+		ResourceTypes:      map[string]providers.Schema{},
+		EphemeralResources: map[string]providers.Schema{},
 	}
 }
 
@@ -440,6 +461,10 @@ func (p *MockProvider) PlanResourceChange(_ context.Context, r providers.PlanRes
 		}
 
 		switch {
+		case attrSchema.WriteOnly:
+			// write-only values are never planned
+			return cty.NullVal(v.Type()), nil
+
 		case attrSchema.Computed && !attrSchema.Optional && v.IsNull():
 			// this is the easy path, this value is not yet set, and _must_ be computed
 			return cty.UnknownVal(v.Type()), nil
@@ -584,6 +609,99 @@ func (p *MockProvider) ReadDataSource(_ context.Context, r providers.ReadDataSou
 	return resp
 }
 
+func (p *MockProvider) ValidateEphemeralResourceConfig(_ context.Context, r providers.ValidateEphemeralResourceConfigRequest) (resp providers.ValidateEphemeralResourceConfigResponse) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.ValidateEphemeralResourceConfigCalled = true
+	p.ValidateEphemeralResourceConfigRequest = r
+
+	// Marshall the value to replicate behavior by the GRPC protocol
+	ephemeralSchema, ok := p.getProviderSchema().EphemeralResources[r.TypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("no schema found for %q", r.TypeName))
+		return resp
+	}
+	_, err := msgpack.Marshal(r.Config, ephemeralSchema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+
+	if p.ValidateEphemeralResourceConfigFn != nil {
+		return p.ValidateEphemeralResourceConfigFn(r)
+	}
+
+	if p.ValidateEphemeralResourceConfigResponse != nil {
+		return *p.ValidateEphemeralResourceConfigResponse
+	}
+
+	return resp
+}
+
+func (p *MockProvider) OpenEphemeralResource(_ context.Context, r providers.OpenEphemeralResourceRequest) (resp providers.OpenEphemeralResourceResponse) {
+	p.Lock()
+	defer p.Unlock()
+
+	if !p.ConfigureProviderCalled {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("Configure not called before OpenEphemeralResource %q", r.TypeName))
+		return resp
+	}
+
+	p.OpenEphemeralResourceCalled = true
+	p.OpenEphemeralResourceRequest = r
+
+	if p.OpenEphemeralResourceFn != nil {
+		return p.OpenEphemeralResourceFn(r)
+	}
+
+	if p.OpenEphemeralResourceResponse != nil {
+		return *p.OpenEphemeralResourceResponse
+	}
+
+	// If no response was set, we echo the configuration back as the result,
+	// which is the closest equivalent to a read-only object.
+	resp.Result = r.Config
+	return resp
+}
+
+func (p *MockProvider) RenewEphemeralResource(_ context.Context, r providers.RenewEphemeralResourceRequest) (resp providers.RenewEphemeralResourceResponse) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.RenewEphemeralResourceCalled = true
+	p.RenewEphemeralResourceRequest = r
+
+	if p.RenewEphemeralResourceFn != nil {
+		return p.RenewEphemeralResourceFn(r)
+	}
+
+	if p.RenewEphemeralResourceResponse != nil {
+		return *p.RenewEphemeralResourceResponse
+	}
+
+	resp.Private = r.Private
+	return resp
+}
+
+func (p *MockProvider) CloseEphemeralResource(_ context.Context, r providers.CloseEphemeralResourceRequest) (resp providers.CloseEphemeralResourceResponse) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.CloseEphemeralResourceCalled = true
+	p.CloseEphemeralResourceRequest = r
+
+	if p.CloseEphemeralResourceFn != nil {
+		return p.CloseEphemeralResourceFn(r)
+	}
+
+	if p.CloseEphemeralResourceResponse != nil {
+		return *p.CloseEphemeralResourceResponse
+	}
+
+	return resp
+}
+
 func (p *MockProvider) GetFunctions(_ context.Context) (resp providers.GetFunctionsResponse) {
 	p.Lock()
 	defer p.Unlock()
@@ -92,6 +92,7 @@ func (p *MockProvider) ProviderSchema() *ProviderSchema {
 		ProviderMeta:               resp.ProviderMeta.Block,
 		ResourceTypes:              map[string]*configschema.Block{},
 		DataSources:                map[string]*configschema.Block{},
+		EphemeralResources:         map[string]*configschema.Block{},
 		ResourceTypeSchemaVersions: map[string]uint64{},
 	}
 
@@ -104,6 +105,10 @@ func (p *MockProvider) ProviderSchema() *ProviderSchema {
 		schema.DataSources[dataSource] = s.Block
 	}
 
+	for ephemeralResource, s := range resp.EphemeralResources {
+		schema.EphemeralResources[ephemeralResource] = s.Block
+	}
+
 	return schema
 }
 
@@ -115,16 +120,18 @@ type ProviderSchema struct {
 	ResourceTypes              map[string]*configschema.Block
 	ResourceTypeSchemaVersions map[string]uint64
 	DataSources                map[string]*configschema.Block
+	EphemeralResources         map[string]*configschema.Block
 }
 
 // getProviderSchemaResponseFromProviderSchema is a test helper to convert a
 // ProviderSchema to a GetProviderSchemaResponse for use when building a mock provider.
 func getProviderSchemaResponseFromProviderSchema(providerSchema *ProviderSchema) *providers.GetProviderSchemaResponse {
 	resp := &providers.GetProviderSchemaResponse{
-		Provider:      providers.Schema{Block: providerSchema.Provider},
-		ProviderMeta:  providers.Schema{Block: providerSchema.ProviderMeta},
-		ResourceTypes: map[string]providers.Schema{},
-		DataSources:   map[string]providers.Schema{},
+		Provider:           providers.Schema{Block: providerSchema.Provider},
+		ProviderMeta:       providers.Schema{Block: providerSchema.ProviderMeta},
+		ResourceTypes:      map[string]providers.Schema{},
+		DataSources:        map[string]providers.Schema{},
+		EphemeralResources: map[string]providers.Schema{},
 	}
 
 	for name, schema := range providerSchema.ResourceTypes {
@@ -138,5 +145,9 @@ func getProviderSchemaResponseFromProviderSchema(providerSchema *ProviderSchema)
 		resp.DataSources[name] = providers.Schema{Block: schema}
 	}
 
+	for name, schema := range providerSchema.EphemeralResources {
+		resp.EphemeralResources[name] = providers.Schema{Block: schema}
+	}
+
 	return resp
 }
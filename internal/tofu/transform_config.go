@@ -98,13 +98,16 @@ func (t *ConfigTransformer) transformSingle(g *Graph, config *configs.Config, ge
 	module := config.Module
 	log.Printf("[TRACE] ConfigTransformer: Starting for path: %v", path)
 
-	allResources := make([]*configs.Resource, 0, len(module.ManagedResources)+len(module.DataResources))
+	allResources := make([]*configs.Resource, 0, len(module.ManagedResources)+len(module.DataResources)+len(module.EphemeralResources))
 	for _, r := range module.ManagedResources {
 		allResources = append(allResources, r)
 	}
 	for _, r := range module.DataResources {
 		allResources = append(allResources, r)
 	}
+	for _, r := range module.EphemeralResources {
+		allResources = append(allResources, r)
+	}
 
 	// Take a copy of the import targets, so we can edit them as we go.
 	// Only include import targets that are targeting the current module.
@@ -0,0 +1,23 @@
+terraform {
+  encryption {
+    key_provider "azure_keyvault" "my_vault" {
+      # Required. URI of the Key Vault holding the key.
+      vault_uri = "https://my-vault.vault.azure.net"
+
+      # Required. Name of the RSA key to wrap the data key with.
+      key_name = "tofu-state"
+
+      # Optional. Pin a key version. Default: the latest version.
+      key_version = "0123456789abcdef0123456789abcdef"
+
+      # Optional. Wrapping algorithm, RSA-OAEP or RSA-OAEP-256.
+      algorithm = "RSA-OAEP-256"
+
+      # Optional. Authentication options, identical to the
+      # azurerm backend. You can also set these using the
+      # ARM_* environment variables.
+      tenant_id = "00000000-0000-0000-0000-000000000000"
+      use_oidc  = true
+    }
+  }
+}
@@ -0,0 +1,31 @@
+terraform {
+  encryption {
+    key_provider "aws_kms" "old" {
+      kms_key_id = "a4f791e1-0d46-4c8e-b489-917e0bec05ef"
+      region     = "us-east-1"
+      key_spec   = "AES_256"
+    }
+
+    key_provider "aws_kms" "new" {
+      kms_key_id = "c8a51c1b-4b87-4e41-8a0a-d19d0d4f7b2a"
+      region     = "us-east-1"
+      key_spec   = "AES_256"
+    }
+
+    method "aes_gcm" "old" {
+      keys = key_provider.aws_kms.old
+    }
+
+    method "aes_gcm" "new" {
+      keys = key_provider.aws_kms.new
+    }
+
+    state {
+      envelope = true
+      method   = method.aes_gcm.new
+      fallback {
+        method = method.aes_gcm.old
+      }
+    }
+  }
+}
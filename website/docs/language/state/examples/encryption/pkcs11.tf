@@ -0,0 +1,21 @@
+terraform {
+  encryption {
+    key_provider "pkcs11" "my_hsm" {
+      # Required. Path to the PKCS#11 library of your HSM.
+      library = "/usr/lib/softhsm/libsofthsm2.so"
+
+      # Required. Label of the token holding the key.
+      token_label = "opentofu"
+
+      # Required. Label of the AES key to encrypt the data key with.
+      key_label = "tofu-state"
+
+      # Optional. User PIN of the token.
+      # You can also set this in the PKCS11_PIN environment variable.
+      pin = "1234"
+
+      # Optional. Number of bytes to generate as a key. Default: 32
+      key_length = 32
+    }
+  }
+}
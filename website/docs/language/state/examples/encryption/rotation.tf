@@ -0,0 +1,22 @@
+terraform {
+  encryption {
+    key_provider "aws_kms" "main" {
+      kms_key_id = "a4f791e1-0d46-4c8e-b489-917e0bec05ef"
+      region     = "us-east-1"
+      key_spec   = "AES_256"
+
+      rotation {
+        # 90 days
+        max_age = "2160h"
+      }
+    }
+
+    method "aes_gcm" "main" {
+      keys = key_provider.aws_kms.main
+    }
+
+    state {
+      method = method.aes_gcm.main
+    }
+  }
+}
@@ -0,0 +1,47 @@
+variable "break_glass_passphrase" {
+  type      = string
+  sensitive = true
+}
+
+terraform {
+  encryption {
+    key_provider "aws_kms" "us" {
+      kms_key_id = "a4f791e1-0d46-4c8e-b489-917e0bec05ef"
+      region     = "us-east-1"
+      key_spec   = "AES_256"
+    }
+
+    key_provider "aws_kms" "eu" {
+      kms_key_id = "0b3a1bfc-8a37-4a4e-9b8c-3cf1b5a04ffb"
+      region     = "eu-west-1"
+      key_spec   = "AES_256"
+    }
+
+    key_provider "pbkdf2" "break_glass" {
+      passphrase = var.break_glass_passphrase
+    }
+
+    key_provider "shamir" "two_of_three" {
+      # Required. The key providers that each protect one share of the key.
+      key_providers = [
+        key_provider.aws_kms.us,
+        key_provider.aws_kms.eu,
+        key_provider.pbkdf2.break_glass,
+      ]
+
+      # Required. Number of key providers needed to recover the key.
+      threshold = 2
+
+      # Optional. Number of bytes to generate as a key. Default: 32
+      key_length = 32
+    }
+
+    method "aes_gcm" "example" {
+      keys = key_provider.shamir.two_of_three
+    }
+
+    state {
+      method = method.aes_gcm.example
+    }
+  }
+}
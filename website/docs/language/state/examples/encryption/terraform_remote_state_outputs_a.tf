@@ -0,0 +1,39 @@
+terraform {
+  encryption {
+    key_provider "pbkdf2" "state" {
+      passphrase = var.state_passphrase
+    }
+
+    key_provider "pbkdf2" "outputs" {
+      passphrase = var.outputs_passphrase
+    }
+
+    method "aes_gcm" "state" {
+      keys = key_provider.pbkdf2.state
+    }
+
+    method "aes_gcm" "outputs" {
+      keys = key_provider.pbkdf2.outputs
+    }
+
+    state {
+      method = method.aes_gcm.state
+
+      outputs {
+        method = method.aes_gcm.outputs
+        # Leave out names to publish all root module outputs.
+        names = ["vpc_id", "subnet_ids"]
+      }
+    }
+  }
+}
+
+variable "state_passphrase" {
+  type      = string
+  sensitive = true
+}
+
+variable "outputs_passphrase" {
+  type      = string
+  sensitive = true
+}
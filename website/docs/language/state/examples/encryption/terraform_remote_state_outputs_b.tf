@@ -0,0 +1,30 @@
+terraform {
+  encryption {
+    key_provider "pbkdf2" "network_outputs" {
+      passphrase = var.network_outputs_passphrase
+    }
+
+    method "aes_gcm" "network_outputs" {
+      keys = key_provider.pbkdf2.network_outputs
+    }
+
+    remote_state_data_sources {
+      remote_state_data_source "network" {
+        method = method.aes_gcm.network_outputs
+      }
+    }
+  }
+}
+
+variable "network_outputs_passphrase" {
+  type      = string
+  sensitive = true
+}
+
+data "terraform_remote_state" "network" {
+  # ...
+}
+
+output "vpc_id" {
+  value = data.terraform_remote_state.network.outputs.vpc_id
+}
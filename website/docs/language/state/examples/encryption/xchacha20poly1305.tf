@@ -0,0 +1,9 @@
+terraform {
+  encryption {
+    # Key provider configuration here
+
+    method "xchacha20poly1305" "yourname" {
+      keys = key_provider.your_key_provider_type.your_key_provider_name
+    }
+  }
+}